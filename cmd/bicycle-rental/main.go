@@ -1,14 +1,61 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"os"
 	_ "sdt-bicycle-rental/docs"
 	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/health"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking"
+	"sdt-bicycle-rental/internal/http-server/handlers/health/live"
+	"sdt-bicycle-rental/internal/http-server/handlers/health/ready"
+	"sdt-bicycle-rental/internal/http-server/handlers/jwks"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental"
+	"sdt-bicycle-rental/internal/http-server/handlers/station"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff"
+	"sdt-bicycle-rental/internal/http-server/handlers/user"
+	"sdt-bicycle-rental/internal/http-server/middleware/instrument"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/notify/filesender"
+	"sdt-bicycle-rental/internal/notify/logsender"
+	"sdt-bicycle-rental/internal/payment/fake"
+	"sdt-bicycle-rental/internal/repository"
+	"sdt-bicycle-rental/internal/repository/memory"
+	"sdt-bicycle-rental/internal/repository/migrations"
 	"sdt-bicycle-rental/internal/repository/postgres"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	bicycle_service "sdt-bicycle-rental/internal/service/bicycle"
+	booking_service "sdt-bicycle-rental/internal/service/booking"
+	lockout_service "sdt-bicycle-rental/internal/service/lockout"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	oidc_service "sdt-bicycle-rental/internal/service/oidc"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	payment_service "sdt-bicycle-rental/internal/service/payment"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+	rental_service "sdt-bicycle-rental/internal/service/rental"
+	session_service "sdt-bicycle-rental/internal/service/session"
+	station_service "sdt-bicycle-rental/internal/service/station"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	user_service "sdt-bicycle-rental/internal/service/user"
+	verification_service "sdt-bicycle-rental/internal/service/verification"
+	"sdt-bicycle-rental/internal/tracing"
+	"sdt-bicycle-rental/internal/worker"
+	"sdt-bicycle-rental/lib/keyset"
+	"sdt-bicycle-rental/lib/lifecycle"
 	"sdt-bicycle-rental/lib/logger"
+	"sdt-bicycle-rental/lib/oidc"
 	"strconv"
+	"time"
+	_ "time/tzdata" // tariff hours may be in any zone, even without zoneinfo on the host
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,35 +64,223 @@ import (
 
 // @title           Swagger BicycleRental API
 // @version         1.0
-func main() { // go run ./cmd/bicycle-rental/main.go
+//
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Type "Bearer" followed by a space and the access token.
+func main() { // go run ./cmd/bicycle-rental [migrate up | down | to <version> | status]
+	os.Exit(run())
+}
+
+// run wires the application and blocks until it stops, the result is the process exit code
+func run() int {
 	// Load the configuration
 	cfg := config.MustLoad()
 	// Initialize the logger
 	log := logger.InitLogger(cfg.Env)
 	log.Info("Logger initialized", slog.String("env", cfg.Env))
 
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		log.Error("Failed to initialize tracing", slog.String("error", err.Error()))
+		return 1
+	}
+
 	// Initialize the database
 	db, err := postgres.New(cfg.Postgres)
 	if err != nil {
 		log.Error("Failed to initialize database", slog.String("error", err.Error()))
-		return
+		return 1
 	}
 	log.Info("Database initialized", slog.String("db_name", cfg.Postgres.DBName))
 
+	if err := errors.Join(db.Use(metrics.GormPlugin{}), db.Use(tracing.GormPlugin{})); err != nil {
+		log.Error("Failed to instrument database", slog.String("error", err.Error()))
+		return 1
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Error("Failed to get database pool", slog.String("error", err.Error()))
+		return 1
+	}
+	if err := metrics.RegisterDBStats(sqlDB, cfg.Postgres.DBName); err != nil {
+		log.Error("Failed to register database metrics", slog.String("error", err.Error()))
+		return 1
+	}
+
+	migrator, err := repository.NewMigrator(db, migrations.FS)
+	if err != nil {
+		log.Error("Failed to load migrations", slog.String("error", err.Error()))
+		return 1
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(migrator, os.Args[2:], os.Stdout); err != nil {
+			log.Error("Migration failed", slog.String("error", err.Error()))
+			return 1
+		}
+		return 0
+	}
+	// The schema is changed only by the migrate command, never by serving
+	if err := migrator.Check(); err != nil {
+		log.Error("Database schema mismatch", slog.String("error", err.Error()))
+		return 1
+	}
+
 	userRepo := postgres.NewUserRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	roleRepo := postgres.NewRoleRepository(db)
+	auditRepo := postgres.NewAuditRepository(db)
+	stationRepo := postgres.NewStationRepository(db)
+	bicycleRepo := postgres.NewBicycleRepository(db)
+	rentalRepo := postgres.NewRentalRepository(db)
+	bookingRepo := postgres.NewBookingRepository(db)
+	tariffRepo := postgres.NewTariffRepository(db)
+	paymentRepo := postgres.NewPaymentRepository(db)
+	passwordResetRepo := postgres.NewPasswordResetRepository(db)
+	verificationRepo := postgres.NewVerificationRepository(db)
+	mfaRepo := postgres.NewMFARepository(db)
+	oidcRepo := postgres.NewOIDCRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+
+	var paymentProvider payment_service.PaymentProvider
+	switch cfg.Payment.Provider {
+	case "fake":
+		paymentProvider = fake.New(cfg.Payment.DeclineAbove)
+	default:
+		log.Error("Unknown payment provider", slog.String("provider", cfg.Payment.Provider))
+		return 1
+	}
+
+	var notifier password_service.Notifier
+	switch cfg.Notify.Sender {
+	case "log":
+		notifier = logsender.New(log)
+	case "file":
+		notifier = filesender.New(cfg.Notify.Dir)
+	default:
+		log.Error("Unknown notification sender", slog.String("sender", cfg.Notify.Sender))
+		return 1
+	}
+
+	var loginAttempts lockout_service.Store
+	switch cfg.Lockout.Store {
+	case "memory":
+		loginAttempts = memory.NewLoginAttemptRepository(cfg.Lockout.ResetAfter)
+	case "postgres":
+		loginAttempts = postgres.NewLoginAttemptRepository(db)
+	default:
+		log.Error("Unknown lockout store", slog.String("store", cfg.Lockout.Store))
+		return 1
+	}
+
+	// Key pairs let other services validate access tokens from the JWKS, the shared secret is the fallback
+	var signingKeys []*keyset.Key
+	for _, k := range cfg.Signing.Keys {
+		key, err := keyset.LoadFile(k.File)
+		if err != nil {
+			log.Error("Failed to load signing key", slog.String("kid", k.ID), slog.String("error", err.Error()))
+			return 1
+		}
+		key.ID, key.SignFrom, key.VerifyUntil = k.ID, k.SignFrom, k.VerifyUntil
+		signingKeys = append(signingKeys, key)
+	}
+	if len(signingKeys) == 0 {
+		if cfg.JwtSecret == "" {
+			log.Error("No signing keys configured and JWT_SECRET is empty")
+			return 1
+		}
+		signingKeys = append(signingKeys, keyset.HMAC(cfg.JwtSecret))
+	}
+	keys, err := keyset.New(signingKeys...)
+	if err != nil {
+		log.Error("Invalid signing keys", slog.String("error", err.Error()))
+		return 1
+	}
+
+	// Discovery and the keys of a provider are fetched on the first login with it
+	oidcClient := &http.Client{Timeout: 10 * time.Second}
+	providers := make(map[string]oidc_service.Provider, len(cfg.OIDC.Providers))
+	for _, p := range cfg.OIDC.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, oidcClient)
+	}
+
+	passwordHasher, err := password_service.NewHasher(cfg.Password)
+	if err != nil {
+		log.Error("Invalid password config", slog.String("error", err.Error()))
+		return 1
+	}
+
+	accessService := access_service.New(roleRepo, userRepo, auditRepo, log)
+	lockoutService := lockout_service.New(loginAttempts, auditRepo, log, cfg.Lockout)
+	mfaService := mfa_service.New(mfaRepo, userRepo, accessService, notifier, log, cfg.MFA)
+	tokenService := token_service.New(refreshTokenRepo, userRepo, accessService, mfaService, log, keys, cfg.Auth)
+	verificationService := verification_service.New(verificationRepo, userRepo, notifier, log, cfg.Verify)
+	authService := auth_service.New(userRepo, tokenService, verificationService, lockoutService, mfaService, passwordHasher, log)
+	oidcService := oidc_service.New(oidcRepo, userRepo, providers, authService, log, cfg.OIDC)
+	passwordService := password_service.New(passwordResetRepo, userRepo, notifier, passwordHasher, lockoutService, log, cfg.Auth)
+	userService := user_service.New(userRepo, verificationService, log)
+	sessionService := session_service.New(sessionRepo, log)
+	stationService := station_service.New(stationRepo, log)
+	bicycleService := bicycle_service.New(bicycleRepo, log)
+	pricingService := pricing_service.New(tariffRepo, log, cfg.Pricing)
+	paymentService := payment_service.New(paymentRepo, paymentProvider, log, cfg.Payment, cfg.Pricing.Currency)
+	rentalService := rental_service.New(rentalRepo, pricingService, paymentService, userService, log)
+	bookingService := booking_service.New(bookingRepo, pricingService, paymentService, userService, log, cfg.Booking)
+
+	// Rides can't start without a tariff, seed one from the config on the first run
+	if err := pricingService.EnsureDefault(context.Background()); err != nil {
+		log.Error("Failed to create default tariff", slog.String("error", err.Error()))
+		return 1
+	}
+
+	authMiddleware := jwtauth.New(tokenService, log)
+
+	bookingExpiry := worker.NewBookingExpiry(bookingService, cfg.Booking.ExpiryInterval, log)
+
+	// Readiness checks, subsystems with their own dependencies register here
+	checks := health.NewRegistry(cfg.Health.CheckTimeout)
+	checks.Register("postgres", health.Database(db))
+	checks.Register("schema", health.Schema(migrator))
+	checks.Register("booking_expiry", bookingExpiry.Health())
 
 	// Initialize the HTTP server
 	router := chi.NewRouter()
 
 	// middleware
 	router.Use(middleware.RequestID)
+	// Lockouts count per client IP, forwarded addresses are only believed from a trusted proxy
+	if cfg.HTTPServer.TrustProxy {
+		router.Use(middleware.RealIP)
+	}
+	router.Use(instrument.New())
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
 	// routes
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
-	router.Route("/auth", auth.AuthRoute(log, userRepo, cfg.JwtSecret))
+	router.Get("/healthz", live.New())
+	router.Get("/readyz", ready.New(checks, log))
+	router.Get("/.well-known/jwks.json", jwks.New(keys))
+	if cfg.Metrics.Port == 0 {
+		router.Handle(cfg.Metrics.Path, metrics.Handler())
+	}
+	router.Route("/auth", auth.AuthRoute(log, authService, tokenService, passwordService, verificationService, mfaService, oidcService, authMiddleware))
+	router.Route("/admin", admin.AdminRoute(log, accessService, authMiddleware))
+	router.Route("/stations", station.StationRoute(log, stationService, authMiddleware))
+	router.Route("/bicycles", bicycle.BicycleRoute(log, bicycleService, authMiddleware))
+	router.Route("/tariffs", tariff.TariffRoute(log, pricingService, authMiddleware))
+	router.Route("/rentals", rental.RentalRoute(log, rentalService, authMiddleware))
+	router.Route("/bookings", booking.BookingRoute(log, bookingService, authMiddleware))
+	router.Route("/payments", payment.PaymentRoute(log, paymentService, authMiddleware))
+	router.Route("/users", user.UserRoute(log, userService, sessionService, authMiddleware))
 
 	// Start the server
 	httpAddr := ":" + strconv.Itoa(cfg.HTTPServer.Port)
@@ -59,9 +294,43 @@ func main() { // go run ./cmd/bicycle-rental/main.go
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Error("failed to start server")
+	// Servers drain before the workers stop, workers stop before the database pool they use is closed
+	lc := lifecycle.New(log, cfg.HTTPServer.DrainTimeout, cfg.HTTPServer.ShutdownTimeout)
+	lc.OnClose("tracing", func() error {
+		// flush spans recorded while draining
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+	lc.Serve("http server", srv)
+	// A separate listener keeps metrics off the public port
+	if cfg.Metrics.Port != 0 {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle(cfg.Metrics.Path, metrics.Handler())
+		metricsAddr := ":" + strconv.Itoa(cfg.Metrics.Port)
+		log.Info("starting metrics server", slog.String("address", metricsAddr))
+
+		lc.Serve("metrics server", &http.Server{
+			Addr:              metricsAddr,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: cfg.HTTPServer.Timeout,
+		})
 	}
+	lc.Go("booking expiry", func(ctx context.Context) error {
+		bookingExpiry.Run(ctx)
+		return nil
+	})
+	lc.OnClose("database", sqlDB.Close)
+	// closers run in reverse, the reset links being sent still have the database
+	lc.OnClose("password reset links", func() error {
+		passwordService.Wait()
+		return nil
+	})
 
-	log.Error("server stopped")
+	if err := lc.Run(context.Background()); err != nil {
+		log.Error("Server stopped with errors", slog.String("error", err.Error()))
+		return 1
+	}
+	log.Info("Server stopped")
+	return 0
 }
@@ -13,7 +13,21 @@ type Config struct {
 	Env        string     `yaml:"env" env-default:"local"`
 	HTTPServer HTTPServer `yaml:"http-server"`
 	Postgres   Postgres   `yaml:"postgres"`
-	JwtSecret  string     `env:"JWT_SECRET" env-required:"true"`
+	Auth       Auth       `yaml:"auth"`
+	Password   Password   `yaml:"password"`
+	Pricing    Pricing    `yaml:"pricing"`
+	Booking    Booking    `yaml:"booking"`
+	Payment    Payment    `yaml:"payment"`
+	Health     Health     `yaml:"health"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Notify     Notify     `yaml:"notify"`
+	Verify     Verify     `yaml:"verify"`
+	Lockout    Lockout    `yaml:"lockout"`
+	MFA        MFA        `yaml:"mfa"`
+	Signing    Signing    `yaml:"signing"`
+	OIDC       OIDC       `yaml:"oidc"`
+	JwtSecret  string     `env:"JWT_SECRET"` // HS256 signing when no keys are configured in signing
 }
 
 type HTTPServer struct {
@@ -21,6 +35,12 @@ type HTTPServer struct {
 	Port        int           `yaml:"port" env-default:"8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// On SIGINT/SIGTERM DrainTimeout bounds finishing in-flight requests, ShutdownTimeout then bounds
+	// stopping the workers, so a shutdown takes up to their sum
+	DrainTimeout    time.Duration `yaml:"drain-timeout" env-default:"10s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown-timeout" env-default:"15s"`
+	// TrustProxy takes the client IP from X-Forwarded-For / X-Real-IP, enable only behind a proxy that sets them
+	TrustProxy bool `yaml:"trust-proxy" env-default:"false"`
 }
 
 type Postgres struct {
@@ -35,6 +55,137 @@ type Postgres struct {
 	MaxIdleConns int    `yaml:"max-idle-conns" env-default:"10"`
 }
 
+type Auth struct {
+	AccessTokenTTL  time.Duration `yaml:"access-token-ttl" env-default:"15m"`
+	RefreshTokenTTL time.Duration `yaml:"refresh-token-ttl" env-default:"720h"`
+	// PasswordResetURL is the page of the client app that completes a reset, the token is appended as ?token=
+	PasswordResetURL string        `yaml:"password-reset-url" env-default:"http://localhost:3000/reset-password"`
+	PasswordResetTTL time.Duration `yaml:"password-reset-ttl" env-default:"30m"`
+	// MFAChallengeTTL is how long the second login step can be completed after the password
+	MFAChallengeTTL time.Duration `yaml:"mfa-challenge-ttl" env-default:"5m"`
+}
+
+// Password configures how passwords are hashed and which new ones are accepted. Only the length
+// is required, mixing character classes is not, and common passwords are refused.
+type Password struct {
+	Algorithm   string `yaml:"algorithm" env-default:"argon2id"` // argon2id / bcrypt, hashes of the other one still work and are replaced on login
+	Memory      uint32 `yaml:"memory" env-default:"65536"`       // argon2id, KiB
+	Iterations  uint32 `yaml:"iterations" env-default:"3"`       // argon2id
+	Parallelism uint8  `yaml:"parallelism" env-default:"2"`      // argon2id
+	Cost        int    `yaml:"cost" env-default:"12"`            // bcrypt, it also limits passwords to 72 bytes
+	MinLength   int    `yaml:"min-length" env-default:"8"`       // characters
+	MaxLength   int    `yaml:"max-length" env-default:"128"`     // characters
+	CommonFile  string `yaml:"common-file"`                      // more passwords to refuse besides the built in list, one per line
+}
+
+// Pricing amounts are in minor currency units (cents). They seed the default tariff
+// on the first start, later tariffs are managed through the API.
+type Pricing struct {
+	Currency    string `yaml:"currency" env-default:"UAH"`
+	TimeZone    string `yaml:"time-zone" env-default:"UTC"` // tariff hours are in this zone
+	UnlockFee   int64  `yaml:"unlock-fee" env-default:"1000"`
+	PerMinute   int64  `yaml:"per-minute" env-default:"200"`
+	DailyCap    int64  `yaml:"daily-cap" env-default:"30000"`
+	FreeMinutes int    `yaml:"free-minutes" env-default:"0"`
+}
+
+type Booking struct {
+	HoldDuration   time.Duration `yaml:"hold-duration" env-default:"15m"`
+	ExpiryInterval time.Duration `yaml:"expiry-interval" env-default:"30s"`
+}
+
+type Payment struct {
+	Provider     string `yaml:"provider" env-default:"fake"` // only the in-process fake for now
+	Method       string `yaml:"method" env-default:"card"`
+	BookingFee   int64  `yaml:"booking-fee" env-default:"0"`   // cents, authorized on reserve and captured when the booking expires unused
+	DeclineAbove int64  `yaml:"decline-above" env-default:"0"` // fake provider declines larger charges, 0 accepts all
+}
+
+type Health struct {
+	CheckTimeout time.Duration `yaml:"check-timeout" env-default:"2s"` // a slower dependency is reported as down
+}
+
+type Metrics struct {
+	Path string `yaml:"path" env-default:"/metrics"`
+	Port int    `yaml:"port" env-default:"0"` // 0 serves metrics on the API port, otherwise on a separate listener
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" env-default:"none"`           // none / stdout / otlp
+	Endpoint    string  `yaml:"endpoint" env-default:"localhost:4318"` // OTLP/HTTP collector host:port
+	Insecure    bool    `yaml:"insecure" env-default:"true"`
+	SampleRatio float64 `yaml:"sample-ratio" env-default:"1"` // share of new traces recorded, incoming sampled traces are always kept
+	ServiceName string  `yaml:"service-name" env-default:"bicycle-rental"`
+}
+
+type Notify struct {
+	Sender string `yaml:"sender" env-default:"log"`     // log / file, both for development only
+	Dir    string `yaml:"dir" env-default:"tmp/outbox"` // file sender writes one file per message here
+}
+
+// Verify configures the email link and the phone code sent to new accounts
+type Verify struct {
+	EmailURL       string        `yaml:"email-url" env-default:"http://localhost:3000/verify-email"` // the token is appended as ?token=
+	EmailTTL       time.Duration `yaml:"email-ttl" env-default:"24h"`
+	PhoneTTL       time.Duration `yaml:"phone-ttl" env-default:"10m"`
+	ResendInterval time.Duration `yaml:"resend-interval" env-default:"1m"`
+	MaxAttempts    int           `yaml:"max-attempts" env-default:"5"` // wrong phone codes before a new one must be sent
+}
+
+// Lockout throttles failed logins per account and per client IP, a threshold of 0 turns its check off
+type Lockout struct {
+	Store            string        `yaml:"store" env-default:"memory"`        // memory / postgres, postgres shares the counters between instances
+	AccountThreshold int           `yaml:"account-threshold" env-default:"5"` // failures before the account is locked
+	IPThreshold      int           `yaml:"ip-threshold" env-default:"20"`     // failures before the client IP is locked
+	Window           time.Duration `yaml:"window" env-default:"15m"`          // failures further apart start the count over
+	Cooldown         time.Duration `yaml:"cooldown" env-default:"1m"`         // the first lockout, each next one in a row doubles it
+	MaxCooldown      time.Duration `yaml:"max-cooldown" env-default:"1h"`
+	ResetAfter       time.Duration `yaml:"reset-after" env-default:"24h"` // without failures this long the next lockout is the first again
+}
+
+// MFA configures the authenticator app second factor
+type MFA struct {
+	Issuer           string `yaml:"issuer" env-default:"Bicycle Rental"`   // account label shown by authenticator apps
+	RequireForAdmins bool   `yaml:"require-for-admins" env-default:"true"` // admins enroll on their next login through an emailed link
+	Skew             int    `yaml:"skew" env-default:"1"`                  // 30 second steps accepted either way for clock drift
+	RecoveryCodes    int    `yaml:"recovery-codes" env-default:"10"`       // issued on confirmation
+	// SetupURL is the page of the client app that shows the secret to users who must enroll at login,
+	// the token of the emailed link is appended as ?token=
+	SetupURL string        `yaml:"setup-url" env-default:"http://localhost:3000/mfa-setup"`
+	SetupTTL time.Duration `yaml:"setup-ttl" env-default:"30m"`
+}
+
+// Signing lists the key pairs access tokens are signed with, their public keys are served
+// at /.well-known/jwks.json. Refresh tokens are opaque, so changing keys only expires access tokens.
+type Signing struct {
+	Keys []SigningKey `yaml:"keys"`
+}
+
+// SigningKey is a PEM file with an RSA (RS256) or Ed25519 (EdDSA) key, a public key only validates.
+// To rotate, add the new key with sign-from ahead of time so it is published before it signs,
+// and set verify-until of the old one at least one access token TTL after that.
+type SigningKey struct {
+	ID          string    `yaml:"kid"`
+	File        string    `yaml:"file"`
+	SignFrom    time.Time `yaml:"sign-from"`    // the newest key whose sign-from passed signs new tokens
+	VerifyUntil time.Time `yaml:"verify-until"` // tokens of the key are refused after it, zero keeps it
+}
+
+// OIDC lists the OpenID Connect providers users can sign in with, e.g. Google or Apple
+type OIDC struct {
+	StateTTL  time.Duration  `yaml:"state-ttl" env-default:"10m"` // time to finish signing in at the provider
+	Providers []OIDCProvider `yaml:"providers"`
+}
+
+type OIDCProvider struct {
+	Name         string   `yaml:"name"`   // in the paths /auth/oidc/{name} and /auth/oidc/{name}/callback
+	Issuer       string   `yaml:"issuer"` // discovered at {issuer}/.well-known/openid-configuration
+	ClientID     string   `yaml:"client-id"`
+	ClientSecret string   `yaml:"client-secret"`
+	RedirectURL  string   `yaml:"redirect-url"` // the callback as registered at the provider
+	Scopes       []string `yaml:"scopes"`       // openid email profile when empty
+}
+
 func MustLoad() *Config {
 	err := godotenv.Load()
 	if err != nil {
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotStarted = errors.New("not started")
+	ErrStalled    = errors.New("stalled")
+)
+
+// Database pings the connection pool behind db
+func Database(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// SchemaChecker is satisfied by repository.Migrator
+type SchemaChecker interface {
+	Check() error
+}
+
+// Schema reports whether the database schema matches the migrations the binary was built with
+func Schema(s SchemaChecker) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return s.Check()
+	})
+}
+
+// Heartbeat tracks a periodic background worker. The worker beats after every run,
+// the check fails until the first beat, when the last run failed or when no beat arrived within maxAge.
+type Heartbeat struct {
+	mu     sync.Mutex
+	maxAge time.Duration
+	last   time.Time
+	err    error
+}
+
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge}
+}
+
+// Beat records a finished run and its error
+func (h *Heartbeat) Beat(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last = time.Now()
+	h.err = err
+}
+
+func (h *Heartbeat) Check(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case h.last.IsZero():
+		return ErrNotStarted
+	case time.Since(h.last) > h.maxAge:
+		return fmt.Errorf("%w: last run %s ago", ErrStalled, time.Since(h.last).Round(time.Second))
+	case h.err != nil:
+		return fmt.Errorf("last run failed: %w", h.err)
+	}
+	return nil
+}
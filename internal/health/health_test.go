@@ -0,0 +1,101 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/health"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	up := health.CheckerFunc(func(ctx context.Context) error { return nil })
+	down := health.CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+	// ignores ctx on purpose, the registry must not wait for it
+	stuck := health.CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	tests := []struct {
+		name   string
+		checks map[string]health.Checker
+		want   *health.Report
+	}{
+		{
+			name: "no checks",
+			want: &health.Report{Status: health.StatusUp, Checks: []health.Result{}},
+		},
+		{
+			name:   "all up",
+			checks: map[string]health.Checker{"postgres": up, "schema": up},
+			want: &health.Report{Status: health.StatusUp, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusUp},
+				{Name: "schema", Status: health.StatusUp},
+			}},
+		},
+		{
+			name:   "one down",
+			checks: map[string]health.Checker{"postgres": down, "schema": up},
+			want: &health.Report{Status: health.StatusDown, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusDown, Error: "connection refused"},
+				{Name: "schema", Status: health.StatusUp},
+			}},
+		},
+		{
+			name:   "timed out",
+			checks: map[string]health.Checker{"worker": stuck},
+			want: &health.Report{Status: health.StatusDown, Checks: []health.Result{
+				{Name: "worker", Status: health.StatusDown, Error: health.ErrCheckTimeout.Error()},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := health.NewRegistry(50 * time.Millisecond)
+			for name, c := range tt.checks {
+				r.Register(name, c)
+			}
+
+			start := time.Now()
+			got := r.Check(context.Background())
+			require.Less(t, time.Since(start), 500*time.Millisecond)
+
+			for i := range got.Checks {
+				assert.GreaterOrEqual(t, got.Checks[i].LatencyMs, 0.0)
+				got.Checks[i].LatencyMs = 0
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistry_RegisterReplaces(t *testing.T) {
+	r := health.NewRegistry(time.Second)
+	r.Register("postgres", health.CheckerFunc(func(ctx context.Context) error { return errors.New("down") }))
+	r.Register("postgres", health.CheckerFunc(func(ctx context.Context) error { return nil }))
+
+	got := r.Check(context.Background())
+	require.Len(t, got.Checks, 1)
+	assert.True(t, got.Up())
+}
+
+func TestHeartbeat_Check(t *testing.T) {
+	h := health.NewHeartbeat(20 * time.Millisecond)
+	assert.ErrorIs(t, h.Check(context.Background()), health.ErrNotStarted)
+
+	h.Beat(nil)
+	assert.NoError(t, h.Check(context.Background()))
+
+	sweepErr := errors.New("connection refused")
+	h.Beat(sweepErr)
+	assert.ErrorIs(t, h.Check(context.Background()), sweepErr)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.ErrorIs(t, h.Check(context.Background()), health.ErrStalled)
+}
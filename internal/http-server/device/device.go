@@ -0,0 +1,26 @@
+// Package device tells which client a request comes from, for the session it signs in.
+package device
+
+import (
+	"net"
+	"net/http"
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// FromRequest returns the device of the request, the name is optional and given by the client
+func FromRequest(r *http.Request, name string) token_service.Device {
+	return token_service.Device{
+		Name:      name,
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+	}
+}
+
+// clientIP is the address of the peer, or of the client behind a trusted proxy once middleware.RealIP rewrote it
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
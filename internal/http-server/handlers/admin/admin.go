@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/grant"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/revoke"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/ban"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/unban"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	access_service "sdt-bicycle-rental/internal/service/access"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func AdminRoute(log *slog.Logger, accessService *access_service.AccessService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Use(authenticate)
+
+		r.Route("/users/{id}/roles", func(r chi.Router) {
+			r.Use(jwtauth.RequirePermission(access_service.PermManageRoles))
+
+			r.Get("/", list.New(accessService, log))
+			r.Post("/", grant.New(accessService, log))
+			r.Delete("/{role}", revoke.New(accessService, log))
+		})
+
+		r.Route("/users/{id}/ban", func(r chi.Router) {
+			r.Use(jwtauth.RequirePermission(access_service.PermBanUsers))
+
+			r.Post("/", ban.New(accessService, log))
+			r.Delete("/", unban.New(accessService, log))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package grant
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Role string `json:"role"`
+}
+
+//go:generate mockery --name=RoleGranter
+type RoleGranter interface {
+	Grant(ctx context.Context, actorID, userID uint64, role string) error
+}
+
+// New returns grant role handler
+//
+//	@Summary      Grant role
+//	@Description  grant a role to a user, the decision is written to the audit log
+//	@Tags         admin
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id      path 		int     true "User ID"
+//	@Param        request body 		Request true "Role"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /admin/users/{id}/roles [post]
+func New(s RoleGranter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.roles.grant.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actorID, _ := jwtauth.UserID(r.Context())
+
+		userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid user id"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.Grant(r.Context(), actorID, userID, req.Role); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("role granted", slog.Uint64("actor_id", actorID), slog.Uint64("user_id", userID), slog.String("role", req.Role))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
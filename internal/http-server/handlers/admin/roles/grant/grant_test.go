@@ -0,0 +1,108 @@
+package grant_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/grant"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/grant/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		userID    string
+		role      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			userID:   "2",
+			role:     "mechanic",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:   "invalid user id",
+			userID: "abc",
+			role:   "mechanic",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid user id"},
+		},
+		{
+			name:      "invalid role",
+			userID:    "2",
+			role:      "superuser",
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidRole.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidRole,
+		},
+		{
+			name:      "user not found",
+			userID:    "2",
+			role:      "mechanic",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUserNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrUserNotFound,
+		},
+		{
+			name:      "internal error",
+			userID:    "2",
+			role:      "mechanic",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			granterMock := mocks.NewRoleGranter(t)
+
+			if tc.mockCall {
+				granterMock.On("Grant", mock.Anything, uint64(1), uint64(2), tc.role).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/users/{id}/roles", grant.New(granterMock, slogdiscard.NewDiscardLogger()))
+
+			input := fmt.Sprintf(`{"role": "%s"}`, tc.role)
+
+			req, err := http.NewRequest(http.MethodPost, "/users/"+tc.userID+"/roles", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
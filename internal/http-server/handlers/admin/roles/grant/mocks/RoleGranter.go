@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleGranter is an autogenerated mock type for the RoleGranter type
+type RoleGranter struct {
+	mock.Mock
+}
+
+// Grant provides a mock function with given fields: ctx, actorID, userID, role
+func (_m *RoleGranter) Grant(ctx context.Context, actorID uint64, userID uint64, role string) error {
+	ret := _m.Called(ctx, actorID, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Grant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, string) error); ok {
+		r0 = rf(ctx, actorID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRoleGranter creates a new instance of RoleGranter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleGranter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleGranter {
+	mock := &RoleGranter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
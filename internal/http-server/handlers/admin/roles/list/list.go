@@ -0,0 +1,65 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type SuccessResponse struct {
+	Roles []string `json:"roles"`
+}
+
+//go:generate mockery --name=RolesGetter
+type RolesGetter interface {
+	Roles(ctx context.Context, userID uint64) ([]string, error)
+}
+
+// New returns list user roles handler
+//
+//	@Summary      User roles
+//	@Description  list roles of a user
+//	@Tags         admin
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "User ID"
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /admin/users/{id}/roles [get]
+func New(s RolesGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.roles.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid user id"))
+			return
+		}
+
+		roles, err := s.Roles(r.Context(), userID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{Roles: roles})
+	}
+}
@@ -0,0 +1,88 @@
+package list_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/list/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		userID    string
+		resp      resp
+		mockCall  bool
+		mockRoles []string
+		mockError error
+	}{
+		{
+			name:      "success",
+			userID:    "2",
+			resp:      resp{Code: http.StatusOK},
+			mockCall:  true,
+			mockRoles: []string{"rider", "mechanic"},
+		},
+		{
+			name:   "invalid user id",
+			userID: "two",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid user id"},
+		},
+		{
+			name:      "internal error",
+			userID:    "2",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewRolesGetter(t)
+
+			if tc.mockCall {
+				getterMock.On("Roles", mock.Anything, uint64(2)).Return(tc.mockRoles, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/users/{id}/roles", list.New(getterMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodGet, "/users/"+tc.userID+"/roles", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp list.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tc.mockRoles, resp.Roles)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package revoke
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=RoleRevoker
+type RoleRevoker interface {
+	Revoke(ctx context.Context, actorID, userID uint64, role string) error
+}
+
+// New returns revoke role handler
+//
+//	@Summary      Revoke role
+//	@Description  revoke a role from a user, the decision is written to the audit log
+//	@Tags         admin
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int    true "User ID"
+//	@Param        role path 		string true "Role"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /admin/users/{id}/roles/{role} [delete]
+func New(s RoleRevoker, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.roles.revoke.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actorID, _ := jwtauth.UserID(r.Context())
+		role := chi.URLParam(r, "role")
+
+		userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid user id"))
+			return
+		}
+
+		if err := s.Revoke(r.Context(), actorID, userID, role); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("role revoked", slog.Uint64("actor_id", actorID), slog.Uint64("user_id", userID), slog.String("role", role))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,96 @@
+package revoke_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/revoke"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/roles/revoke/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		userID    string
+		role      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			userID:   "2",
+			role:     "admin",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:   "invalid user id",
+			userID: "-1",
+			role:   "admin",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid user id"},
+		},
+		{
+			name:      "own admin role",
+			userID:    "2",
+			role:      "admin",
+			resp:      resp{Code: http.StatusForbidden, Error: service.ErrForbidden.Error()},
+			mockCall:  true,
+			mockError: service.ErrForbidden,
+		},
+		{
+			name:      "internal error",
+			userID:    "2",
+			role:      "mechanic",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			revokerMock := mocks.NewRoleRevoker(t)
+
+			if tc.mockCall {
+				revokerMock.On("Revoke", mock.Anything, uint64(1), uint64(2), tc.role).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/users/{id}/roles/{role}", revoke.New(revokerMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodDelete, "/users/"+tc.userID+"/roles/"+tc.role, nil)
+			require.NoError(t, err)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
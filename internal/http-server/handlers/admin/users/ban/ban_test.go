@@ -0,0 +1,98 @@
+package ban_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/ban"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/ban/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		userID    string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			userID:   "2",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:   "invalid user id",
+			userID: "-1",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid user id"},
+		},
+		{
+			name:      "themselves",
+			userID:    "2",
+			resp:      resp{Code: http.StatusForbidden, Error: service.ErrForbidden.Error()},
+			mockCall:  true,
+			mockError: service.ErrForbidden,
+		},
+		{
+			name:      "user not found",
+			userID:    "2",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUserNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrUserNotFound,
+		},
+		{
+			name:      "internal error",
+			userID:    "2",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			banMock := mocks.NewUserBanner(t)
+
+			if tc.mockCall {
+				banMock.On("Ban", mock.Anything, uint64(1), uint64(2)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/users/{id}/ban", ban.New(banMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodPost, "/users/"+tc.userID+"/ban", nil)
+			require.NoError(t, err)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserBanner is an autogenerated mock type for the UserBanner type
+type UserBanner struct {
+	mock.Mock
+}
+
+// Ban provides a mock function with given fields: ctx, actorID, userID
+func (_m *UserBanner) Ban(ctx context.Context, actorID uint64, userID uint64) error {
+	ret := _m.Called(ctx, actorID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ban")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, actorID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserBanner creates a new instance of UserBanner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserBanner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserBanner {
+	mock := &UserBanner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
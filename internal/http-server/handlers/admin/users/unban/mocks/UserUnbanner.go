@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserUnbanner is an autogenerated mock type for the UserUnbanner type
+type UserUnbanner struct {
+	mock.Mock
+}
+
+// Unban provides a mock function with given fields: ctx, actorID, userID
+func (_m *UserUnbanner) Unban(ctx context.Context, actorID uint64, userID uint64) error {
+	ret := _m.Called(ctx, actorID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unban")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, actorID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserUnbanner creates a new instance of UserUnbanner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserUnbanner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserUnbanner {
+	mock := &UserUnbanner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,64 @@
+package unban
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=UserUnbanner
+type UserUnbanner interface {
+	Unban(ctx context.Context, actorID, userID uint64) error
+}
+
+// New returns unban user handler
+//
+//	@Summary      Unban user
+//	@Description  lift the ban of a user, the decision is written to the audit log
+//	@Tags         admin
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int    true "User ID"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /admin/users/{id}/ban [delete]
+func New(s UserUnbanner, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.users.unban.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		actorID, _ := jwtauth.UserID(r.Context())
+
+		userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid user id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid user id"))
+			return
+		}
+
+		if err := s.Unban(r.Context(), actorID, userID); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("user unbanned", slog.Uint64("actor_id", actorID), slog.Uint64("user_id", userID))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
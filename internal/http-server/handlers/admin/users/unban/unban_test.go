@@ -0,0 +1,91 @@
+package unban_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/unban"
+	"sdt-bicycle-rental/internal/http-server/handlers/admin/users/unban/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbanHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		userID    string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			userID:   "2",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:   "invalid user id",
+			userID: "-1",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid user id"},
+		},
+		{
+			name:      "user not found",
+			userID:    "2",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUserNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrUserNotFound,
+		},
+		{
+			name:      "internal error",
+			userID:    "2",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			unbanMock := mocks.NewUserUnbanner(t)
+
+			if tc.mockCall {
+				unbanMock.On("Unban", mock.Anything, uint64(1), uint64(2)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/users/{id}/ban", unban.New(unbanMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodDelete, "/users/"+tc.userID+"/ban", nil)
+			require.NoError(t, err)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -2,18 +2,66 @@ package auth
 
 import (
 	"log/slog"
+	"net/http"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/login"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/logout"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/confirm"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/enroll"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/setup"
+	mfa_verify "sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/verify"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/callback"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/change"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/forgot"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/reset"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/refresh"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/register"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/email"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/phone"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/resend"
 	auth_service "sdt-bicycle-rental/internal/service/auth"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	oidc_service "sdt-bicycle-rental/internal/service/oidc"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	verification_service "sdt-bicycle-rental/internal/service/verification"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func AuthRoute(log *slog.Logger, userRepo auth_service.UserRepository, jwtSecret string) func(chi.Router) {
+func AuthRoute(
+	log *slog.Logger,
+	authService *auth_service.AuthService,
+	tokenService *token_service.TokenService,
+	passwordService *password_service.PasswordService,
+	verificationService *verification_service.VerificationService,
+	mfaService *mfa_service.MFAService,
+	oidcService *oidc_service.OIDCService,
+	authenticate func(http.Handler) http.Handler,
+) func(chi.Router) {
 	return func(r chi.Router) {
-		authService := auth_service.New(userRepo, log, jwtSecret)
-
 		r.Post("/register", register.New(authService, log))
 		r.Post("/login", login.New(authService, log))
+		r.Post("/refresh", refresh.New(tokenService, log))
+		r.Post("/logout", logout.New(tokenService, log))
+		r.Post("/password/forgot", forgot.New(passwordService, log))
+		r.Post("/password/reset", reset.New(passwordService, log))
+		r.With(authenticate).Post("/password/change", change.New(passwordService, log))
+
+		// the emailed link works without a session, the phone code is typed in by the signed in user
+		r.Post("/verify/email", email.New(verificationService, log))
+		r.With(authenticate).Post("/verify/phone", phone.New(verificationService, log))
+		r.With(authenticate).Post("/verify/resend", resend.New(verificationService, log))
+
+		// the challenge from the login stands in for a session until the code is checked,
+		// users who must enroll first get the secret through the emailed setup link
+		r.Post("/mfa/verify", mfa_verify.New(authService, log))
+		r.Post("/mfa/setup", setup.New(mfaService, log))
+		r.With(authenticate).Post("/mfa/enroll", enroll.New(mfaService, log))
+		r.With(authenticate).Post("/mfa/confirm", confirm.New(mfaService, log))
+
+		// sign in with an identity provider, the provider redirects the browser back to the callback
+		r.Get("/oidc/{provider}", start.New(oidcService, log))
+		r.Get("/oidc/{provider}/callback", callback.New(oidcService, log))
 	}
 }
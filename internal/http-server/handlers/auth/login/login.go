@@ -1,11 +1,15 @@
 package login
 
 import (
-	"errors"
+	"context"
 	"log/slog"
 	"net/http"
+	"sdt-bicycle-rental/internal/http-server/device"
+	"sdt-bicycle-rental/internal/http-server/problem"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	token_service "sdt-bicycle-rental/internal/service/token"
 	"sdt-bicycle-rental/lib/logger/sl"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -13,20 +17,30 @@ import (
 )
 
 type Request struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"` // optional, shown in the list of sessions
 }
 type SuccessResponse struct {
-	User  *models.User `json:"user"`
-	Token string       `json:"token"`
+	User         *models.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int64        `json:"expires_in"`
 }
-type ErrorResponse struct {
-	Error string `json:"error"`
+
+// ChallengeResponse is returned instead of the tokens when the login needs a second factor,
+// the login is completed at /auth/mfa/verify. SetupLinkSent is set when the user must use MFA
+// and has to add the authenticator first, from the link emailed to them.
+type ChallengeResponse struct {
+	MFARequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int64  `json:"expires_in"`
+	SetupLinkSent  bool   `json:"setup_link_sent,omitempty"`
 }
 
 //go:generate mockery --name=UserLoginer
 type UserLoginer interface {
-	Login(email, password string) (*models.User, string, error)
+	Login(ctx context.Context, email, password string, device token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error)
 }
 
 // New returns login handler
@@ -38,9 +52,14 @@ type UserLoginer interface {
 //	@Produce      json
 //	@Param        request body 		Request true "User login data"
 //	@Success      201  {object}   	SuccessResponse
-//	@Failure      400  {object}		ErrorResponse
-//	@Failure      409  {object}		ErrorResponse
-//	@Failure      500  {object}		ErrorResponse
+//	@Success      202  {object}   	ChallengeResponse	"a second factor is required"
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      429  {object}		problem.Problem	"locked out after failed attempts, see Retry-After"
+//	@Header       429  {integer}	Retry-After	"seconds until the next attempt is allowed"
+//	@Failure      500  {object}		problem.Problem
 //	@Router       /auth/login [post]
 func New(s UserLoginer, log *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -56,25 +75,39 @@ func New(s UserLoginer, log *slog.Logger) http.HandlerFunc {
 		if err := render.DecodeJSON(r.Body, &req); err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, ErrorResponse{Error: "invalid input"})
+			problem.Render(w, r, log, service.ErrInvalidInput)
 			return
 		}
 
-		user, token, err := s.Login(req.Email, req.Password)
+		user, tokens, challenge, err := s.Login(r.Context(), req.Email, req.Password, device.FromRequest(r, req.DeviceName))
 		if err != nil {
-			if errors.Is(err, service.ErrInternalError) {
-				w.WriteHeader(http.StatusInternalServerError)
-			} else {
-				w.WriteHeader(http.StatusBadRequest)
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if challenge != nil {
+			log.Info("second factor required", slog.Uint64("id", user.ID))
+
+			resp := ChallengeResponse{
+				MFARequired:    true,
+				ChallengeToken: challenge.Token,
+				ExpiresIn:      int64(challenge.ExpiresIn.Seconds()),
+				SetupLinkSent:  challenge.SetupLinkSent,
 			}
-			render.JSON(w, r, ErrorResponse{Error: err.Error()})
+
+			w.WriteHeader(http.StatusAccepted)
+			render.JSON(w, r, resp)
 			return
 		}
 
 		log.Info("user authorized", slog.Uint64("id", user.ID))
 
 		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, SuccessResponse{User: user, Token: token})
+		render.JSON(w, r, SuccessResponse{
+			User:         user,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		})
 	}
 }
@@ -3,26 +3,31 @@ package login_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/login"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/login/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	token_service "sdt-bicycle-rental/internal/service/token"
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
 	"sdt-bicycle-rental/lib/util"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestLoginHandler(t *testing.T) {
 	type resp struct {
-		Code  int
-		Error string
+		Code       int
+		Error      string
+		RetryAfter string
 	}
 
 	cases := []struct {
@@ -32,6 +37,7 @@ func TestLoginHandler(t *testing.T) {
 		resp      resp
 		mockError error
 		mockUser  *models.User
+		challenge *auth_service.Challenge
 	}{
 		{
 			name:     "success",
@@ -44,20 +50,46 @@ func TestLoginHandler(t *testing.T) {
 				Password: util.Ptr("hashed_password"),
 			},
 		},
+		{
+			name:     "second factor required",
+			email:    "valid@email.com",
+			password: "password",
+			resp:     resp{Code: http.StatusAccepted},
+			mockUser: &models.User{ID: 1, Email: util.Ptr("valid@email.com")},
+			challenge: &auth_service.Challenge{
+				Token:         "challenge",
+				ExpiresIn:     5 * time.Minute,
+				SetupLinkSent: true,
+			},
+		},
 		{
 			name:      "invalid password",
 			email:     "valid@email.com",
 			password:  "1234",
-			resp:      resp{Code: http.StatusBadRequest, Error: "field   is not valid"},
-			mockError: errors.New("field   is not valid"),
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockError: service.Invalid(),
 		},
 		{
 			name:      "incorrect password",
 			email:     "valid@email.com",
 			password:  "12345678",
-			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidCredentials.Error()},
+			resp:      resp{Code: http.StatusUnauthorized, Error: service.ErrInvalidCredentials.Error()},
 			mockError: service.ErrInvalidCredentials,
 		},
+		{
+			name:      "disabled account",
+			email:     "valid@email.com",
+			password:  "12345678",
+			resp:      resp{Code: http.StatusForbidden, Error: service.ErrAccountDisabled.Error()},
+			mockError: service.ErrAccountDisabled,
+		},
+		{
+			name:      "locked out",
+			email:     "valid@email.com",
+			password:  "12345678",
+			resp:      resp{Code: http.StatusTooManyRequests, Error: service.ErrTooManyAttempts.Error(), RetryAfter: "60"},
+			mockError: service.ErrTooManyAttempts.WithRetryAfter(time.Minute),
+		},
 		{
 			name:      "internal error",
 			email:     "valid@email.com",
@@ -74,34 +106,53 @@ func TestLoginHandler(t *testing.T) {
 			userLoginerMock := mocks.NewUserLoginer(t)
 
 			if tc.resp.Error == "" || tc.mockError != nil {
-				mockCall := userLoginerMock.On("Login", tc.email, tc.password)
-				mockCall.Return(tc.mockUser, "token", tc.mockError).Once()
+				mockCall := userLoginerMock.On("Login", mock.Anything, tc.email, tc.password, token_service.Device{Name: "Pixel 8", UserAgent: "RentalApp/1.0", IP: "192.0.2.1"})
+				pair := &token_service.Pair{AccessToken: "token", RefreshToken: "refresh"}
+				if tc.challenge != nil {
+					pair = nil
+				}
+				mockCall.Return(tc.mockUser, pair, tc.challenge, tc.mockError).Once()
 			}
 
 			handler := login.New(userLoginerMock, slogdiscard.NewDiscardLogger())
 
-			input := fmt.Sprintf(`{"email": "%s", "password": "%s"}`, tc.email, tc.password)
+			input := fmt.Sprintf(`{"email": "%s", "password": "%s", "device_name": "Pixel 8"}`, tc.email, tc.password)
 
 			req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewReader([]byte(input)))
 			require.NoError(t, err)
+			req.RemoteAddr = "192.0.2.1:41234"
+			req.Header.Set("User-Agent", "RentalApp/1.0")
 
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, rr.Code, tc.resp.Code)
+			assert.Equal(t, tc.resp.RetryAfter, rr.Header().Get("Retry-After"))
 			body := rr.Body.String()
 
+			if rr.Code == http.StatusAccepted {
+				var resp login.ChallengeResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+				assert.True(t, resp.MFARequired)
+				assert.Equal(t, "challenge", resp.ChallengeToken)
+				assert.Equal(t, int64(300), resp.ExpiresIn)
+				assert.True(t, resp.SetupLinkSent)
+				assert.NotContains(t, body, "secret")
+				return
+			}
+
 			if rr.Code == http.StatusCreated {
 				var resp login.SuccessResponse
 				require.NoError(t, json.Unmarshal([]byte(body), &resp))
 				assert.NotEqual(t, resp.User, nil)
 				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
 				return
 			}
 
-			var resp login.ErrorResponse
+			var resp problem.Problem
 			require.NoError(t, json.Unmarshal([]byte(body), &resp))
-			require.Equal(t, tc.resp.Error, resp.Error)
+			require.Equal(t, tc.resp.Error, resp.Detail)
 		})
 	}
 }
@@ -3,9 +3,14 @@
 package mocks
 
 import (
-	models "sdt-bicycle-rental/internal/models"
+	context "context"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
 )
 
 // UserLoginer is an autogenerated mock type for the UserLoginer type
@@ -13,41 +18,52 @@ type UserLoginer struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: email, password
-func (_m *UserLoginer) Login(email string, password string) (*models.User, string, error) {
-	ret := _m.Called(email, password)
+// Login provides a mock function with given fields: ctx, email, password, device
+func (_m *UserLoginer) Login(ctx context.Context, email string, password string, device token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error) {
+	ret := _m.Called(ctx, email, password, device)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
 	var r0 *models.User
-	var r1 string
-	var r2 error
-	if rf, ok := ret.Get(0).(func(string, string) (*models.User, string, error)); ok {
-		return rf(email, password)
+	var r1 *token_service.Pair
+	var r2 *auth_service.Challenge
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error)); ok {
+		return rf(ctx, email, password, device)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *models.User); ok {
-		r0 = rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, token_service.Device) *models.User); ok {
+		r0 = rf(ctx, email, password, device)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) string); ok {
-		r1 = rf(email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, token_service.Device) *token_service.Pair); ok {
+		r1 = rf(ctx, email, password, device)
 	} else {
-		r1 = ret.Get(1).(string)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, token_service.Device) *auth_service.Challenge); ok {
+		r2 = rf(ctx, email, password, device)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*auth_service.Challenge)
+		}
 	}
 
-	if rf, ok := ret.Get(2).(func(string, string) error); ok {
-		r2 = rf(email, password)
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, token_service.Device) error); ok {
+		r3 = rf(ctx, email, password, device)
 	} else {
-		r2 = ret.Error(2)
+		r3 = ret.Error(3)
 	}
 
-	return r0, r1, r2
+	return r0, r1, r2, r3
 }
 
 // NewUserLoginer creates a new instance of UserLoginer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
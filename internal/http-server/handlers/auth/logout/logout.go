@@ -0,0 +1,66 @@
+package logout
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+//go:generate mockery --name=TokenRevoker
+type TokenRevoker interface {
+	Revoke(ctx context.Context, token string) error
+}
+
+// New returns logout handler
+//
+//	@Summary      Logout
+//	@Description  revoke the refresh token and every token issued from it
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Refresh token"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/logout [post]
+func New(s TokenRevoker, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.logout.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil || req.RefreshToken == "" {
+			if err != nil {
+				log.Error("failed to decode request body", sl.Err(err))
+			}
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.Revoke(r.Context(), req.RefreshToken); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("user logged out")
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,87 @@
+package logout_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/logout"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/logout/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogoutHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name         string
+		refreshToken string
+		resp         resp
+		mockError    error
+	}{
+		{
+			name:         "success",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusNoContent},
+		},
+		{
+			name:         "empty token",
+			refreshToken: "",
+			resp:         resp{Code: http.StatusBadRequest, Error: "invalid input"},
+		},
+		{
+			name:         "unknown token",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusUnauthorized, Error: service.ErrInvalidToken.Error()},
+			mockError:    service.ErrInvalidToken,
+		},
+		{
+			name:         "internal error",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError:    service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			revokerMock := mocks.NewTokenRevoker(t)
+
+			if tc.refreshToken != "" {
+				revokerMock.On("Revoke", mock.Anything, tc.refreshToken).Return(tc.mockError).Once()
+			}
+
+			handler := logout.New(revokerMock, slogdiscard.NewDiscardLogger())
+
+			input := fmt.Sprintf(`{"refresh_token": "%s"}`, tc.refreshToken)
+
+			req, err := http.NewRequest(http.MethodPost, "/logout", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package confirm
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Code string `json:"code"`
+}
+type Response struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+//go:generate mockery --name=Confirmer
+type Confirmer interface {
+	Confirm(ctx context.Context, userID uint64, sessionID string, code string) ([]string, error)
+}
+
+// New returns mfa confirmation handler
+//
+//	@Summary      Confirm MFA enrollment
+//	@Description  enable the authenticator with its first code, the recovery codes are returned only this once
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Code from the authenticator"
+//	@Success      200  {object}   	Response
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/mfa/confirm [post]
+func New(s Confirmer, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.mfa.confirm.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := jwtauth.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		codes, err := s.Confirm(r.Context(), principal.UserID, principal.SessionID, req.Code)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("mfa enabled", slog.Uint64("id", principal.UserID))
+
+		render.JSON(w, r, Response{RecoveryCodes: codes})
+	}
+}
@@ -0,0 +1,106 @@
+package confirm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/confirm"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/confirm/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		anonymous bool
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"code": "123456"}`,
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name:      "no principal",
+			body:      `{"code": "123456"}`,
+			anonymous: true,
+			resp:      resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "not enrolled",
+			body:      `{"code": "123456"}`,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrMFANotEnrolled.Error()},
+			mockCall:  true,
+			mockError: service.ErrMFANotEnrolled,
+		},
+		{
+			name:      "wrong code",
+			body:      `{"code": "123456"}`,
+			resp:      resp{Code: http.StatusUnauthorized, Error: service.ErrInvalidMFACode.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidMFACode,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			confirmerMock := mocks.NewConfirmer(t)
+
+			if tc.mockCall {
+				var codes []string
+				if tc.mockError == nil {
+					codes = []string{"abcde-fghij", "klmno-pqrst"}
+				}
+				confirmerMock.On("Confirm", mock.Anything, uint64(1), "phone", "123456").Return(codes, tc.mockError).Once()
+			}
+
+			handler := confirm.New(confirmerMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/mfa/confirm", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			if !tc.anonymous {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1, SessionID: "phone"}))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp confirm.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.RecoveryCodes, 2)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Confirmer is an autogenerated mock type for the Confirmer type
+type Confirmer struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userID, sessionID, code
+func (_m *Confirmer) Confirm(ctx context.Context, userID uint64, sessionID string, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, sessionID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string) ([]string, error)); ok {
+		return rf(ctx, userID, sessionID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string) []string); ok {
+		r0 = rf(ctx, userID, sessionID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string, string) error); ok {
+		r1 = rf(ctx, userID, sessionID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewConfirmer creates a new instance of Confirmer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConfirmer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Confirmer {
+	mock := &Confirmer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
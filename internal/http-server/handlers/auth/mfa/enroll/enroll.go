@@ -0,0 +1,62 @@
+package enroll
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+//go:generate mockery --name=Enroller
+type Enroller interface {
+	Enroll(ctx context.Context, userID uint64) (*mfa_service.Enrollment, error)
+}
+
+// New returns mfa enrollment handler
+//
+//	@Summary      Start MFA enrollment
+//	@Description  generate an authenticator secret for the current user, the uri is meant to be shown as a QR code
+//	@Tags         auth
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Success      200  {object}   	Response
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/mfa/enroll [post]
+func New(s Enroller, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.mfa.enroll.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		enrollment, err := s.Enroll(r.Context(), userID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		render.JSON(w, r, Response{Secret: enrollment.Secret, URI: enrollment.URI})
+	}
+}
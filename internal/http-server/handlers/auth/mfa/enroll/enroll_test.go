@@ -0,0 +1,90 @@
+package enroll_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/enroll"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/enroll/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		anonymous bool
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name:      "no principal",
+			anonymous: true,
+			resp:      resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "already enabled",
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrMFAAlreadyEnabled.Error()},
+			mockCall:  true,
+			mockError: service.ErrMFAAlreadyEnabled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			enrollerMock := mocks.NewEnroller(t)
+
+			if tc.mockCall {
+				var enrollment *mfa_service.Enrollment
+				if tc.mockError == nil {
+					enrollment = &mfa_service.Enrollment{Secret: "SECRET", URI: "otpauth://totp/x"}
+				}
+				enrollerMock.On("Enroll", mock.Anything, uint64(1)).Return(enrollment, tc.mockError).Once()
+			}
+
+			handler := enroll.New(enrollerMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/mfa/enroll", nil)
+			require.NoError(t, err)
+			if !tc.anonymous {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp enroll.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, enroll.Response{Secret: "SECRET", URI: "otpauth://totp/x"}, resp)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
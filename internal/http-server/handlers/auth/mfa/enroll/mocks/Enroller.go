@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Enroller is an autogenerated mock type for the Enroller type
+type Enroller struct {
+	mock.Mock
+}
+
+// Enroll provides a mock function with given fields: ctx, userID
+func (_m *Enroller) Enroll(ctx context.Context, userID uint64) (*mfa_service.Enrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enroll")
+	}
+
+	var r0 *mfa_service.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*mfa_service.Enrollment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *mfa_service.Enrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mfa_service.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEnroller creates a new instance of Enroller. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEnroller(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Enroller {
+	mock := &Enroller{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SetupLinkUser is an autogenerated mock type for the SetupLinkUser type
+type SetupLinkUser struct {
+	mock.Mock
+}
+
+// Setup provides a mock function with given fields: ctx, token
+func (_m *SetupLinkUser) Setup(ctx context.Context, token string) (*mfa_service.Enrollment, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Setup")
+	}
+
+	var r0 *mfa_service.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*mfa_service.Enrollment, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *mfa_service.Enrollment); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mfa_service.Enrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSetupLinkUser creates a new instance of SetupLinkUser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSetupLinkUser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SetupLinkUser {
+	mock := &SetupLinkUser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+package setup
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Token string `json:"token"`
+}
+
+type Response struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+//go:generate mockery --name=SetupLinkUser
+type SetupLinkUser interface {
+	Setup(ctx context.Context, token string) (*mfa_service.Enrollment, error)
+}
+
+// New returns mfa setup link handler
+//
+//	@Summary      Open MFA setup link
+//	@Description  show the authenticator secret behind the link emailed at login to a user who must use MFA, the link works once. The uri is meant to be shown as a QR code, the login is completed at /auth/mfa/verify with a code from the authenticator
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Token from the setup link"
+//	@Success      200  {object}   	Response
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/mfa/setup [post]
+func New(s SetupLinkUser, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.mfa.setup.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		enrollment, err := s.Setup(r.Context(), req.Token)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		render.JSON(w, r, Response{Secret: enrollment.Secret, URI: enrollment.URI})
+	}
+}
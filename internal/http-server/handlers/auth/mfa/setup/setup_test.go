@@ -0,0 +1,89 @@
+package setup_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/setup"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/setup/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"token":"token"}`,
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name: "invalid body",
+			body: `{`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "used link",
+			body:      `{"token":"token"}`,
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidSetupLink.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidSetupLink,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setupMock := mocks.NewSetupLinkUser(t)
+
+			if tc.mockCall {
+				var enrollment *mfa_service.Enrollment
+				if tc.mockError == nil {
+					enrollment = &mfa_service.Enrollment{Secret: "SECRET", URI: "otpauth://totp/x"}
+				}
+				setupMock.On("Setup", mock.Anything, "token").Return(enrollment, tc.mockError).Once()
+			}
+
+			handler := setup.New(setupMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/mfa/setup", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp setup.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, setup.Response{Secret: "SECRET", URI: "otpauth://totp/x"}, resp)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// MFAVerifier is an autogenerated mock type for the MFAVerifier type
+type MFAVerifier struct {
+	mock.Mock
+}
+
+// VerifyMFA provides a mock function with given fields: ctx, challengeToken, code, device
+func (_m *MFAVerifier) VerifyMFA(ctx context.Context, challengeToken string, code string, device token_service.Device) (*models.User, *token_service.Pair, []string, error) {
+	ret := _m.Called(ctx, challengeToken, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFA")
+	}
+
+	var r0 *models.User
+	var r1 *token_service.Pair
+	var r2 []string
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, token_service.Device) (*models.User, *token_service.Pair, []string, error)); ok {
+		return rf(ctx, challengeToken, code, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, token_service.Device) *models.User); ok {
+		r0 = rf(ctx, challengeToken, code, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, token_service.Device) *token_service.Pair); ok {
+		r1 = rf(ctx, challengeToken, code, device)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, token_service.Device) []string); ok {
+		r2 = rf(ctx, challengeToken, code, device)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, token_service.Device) error); ok {
+		r3 = rf(ctx, challengeToken, code, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// NewMFAVerifier creates a new instance of MFAVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAVerifier {
+	mock := &MFAVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,87 @@
+package verify
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/device"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	DeviceName     string `json:"device_name"` // optional, shown in the list of sessions
+}
+type SuccessResponse struct {
+	User          *models.User `json:"user"`
+	Token         string       `json:"token"`
+	RefreshToken  string       `json:"refresh_token"`
+	ExpiresIn     int64        `json:"expires_in"`
+	RecoveryCodes []string     `json:"recovery_codes,omitempty"`
+}
+
+//go:generate mockery --name=MFAVerifier
+type MFAVerifier interface {
+	VerifyMFA(ctx context.Context, challengeToken, code string, device token_service.Device) (*models.User, *token_service.Pair, []string, error)
+}
+
+// New returns verify mfa handler
+//
+//	@Summary      Verify second factor
+//	@Description  complete a login with the challenge token and an authenticator or recovery code, recovery codes are returned once when the authenticator was added at this login
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Challenge from the login and the code"
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      429  {object}		problem.Problem	"locked out after failed attempts, see Retry-After"
+//	@Header       429  {integer}	Retry-After	"seconds until the next attempt is allowed"
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/mfa/verify [post]
+func New(s MFAVerifier, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.mfa.verify.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		user, tokens, recoveryCodes, err := s.VerifyMFA(r.Context(), req.ChallengeToken, req.Code, device.FromRequest(r, req.DeviceName))
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("user authorized", slog.Uint64("id", user.ID))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{
+			User:          user,
+			Token:         tokens.AccessToken,
+			RefreshToken:  tokens.RefreshToken,
+			ExpiresIn:     int64(tokens.ExpiresIn.Seconds()),
+			RecoveryCodes: recoveryCodes,
+		})
+	}
+}
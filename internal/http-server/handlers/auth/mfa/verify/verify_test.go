@@ -0,0 +1,122 @@
+package verify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/verify"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/verify/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHandler(t *testing.T) {
+	type resp struct {
+		Code       int
+		Error      string
+		RetryAfter string
+	}
+
+	cases := []struct {
+		name          string
+		body          string
+		resp          resp
+		mockCall      bool
+		mockError     error
+		recoveryCodes []string
+	}{
+		{
+			name:     "success",
+			body:     `{"challenge_token": "challenge", "code": "123456"}`,
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name:          "enrolled at login",
+			body:          `{"challenge_token": "challenge", "code": "123456"}`,
+			resp:          resp{Code: http.StatusOK},
+			mockCall:      true,
+			recoveryCodes: []string{"abcde-fghij"},
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "expired challenge",
+			body:      `{"challenge_token": "challenge", "code": "123456"}`,
+			resp:      resp{Code: http.StatusUnauthorized, Error: service.ErrExpiredToken.Error()},
+			mockCall:  true,
+			mockError: service.ErrExpiredToken,
+		},
+		{
+			name:      "wrong code",
+			body:      `{"challenge_token": "challenge", "code": "123456"}`,
+			resp:      resp{Code: http.StatusUnauthorized, Error: service.ErrInvalidMFACode.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidMFACode,
+		},
+		{
+			name:      "locked out",
+			body:      `{"challenge_token": "challenge", "code": "123456"}`,
+			resp:      resp{Code: http.StatusTooManyRequests, Error: service.ErrTooManyAttempts.Error(), RetryAfter: "60"},
+			mockCall:  true,
+			mockError: service.ErrTooManyAttempts.WithRetryAfter(time.Minute),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifierMock := mocks.NewMFAVerifier(t)
+
+			if tc.mockCall {
+				var user *models.User
+				var pair *token_service.Pair
+				if tc.mockError == nil {
+					user = &models.User{ID: 1}
+					pair = &token_service.Pair{AccessToken: "token", RefreshToken: "refresh", ExpiresIn: time.Minute}
+				}
+				verifierMock.On("VerifyMFA", mock.Anything, "challenge", "123456", token_service.Device{IP: "192.0.2.1"}).
+					Return(user, pair, tc.recoveryCodes, tc.mockError).Once()
+			}
+
+			handler := verify.New(verifierMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/mfa/verify", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			req.RemoteAddr = "192.0.2.1:41234"
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+			assert.Equal(t, tc.resp.RetryAfter, rr.Header().Get("Retry-After"))
+
+			if rr.Code == http.StatusOK {
+				var resp verify.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "token", resp.Token)
+				assert.Equal(t, "refresh", resp.RefreshToken)
+				assert.Equal(t, tc.recoveryCodes, resp.RecoveryCodes)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
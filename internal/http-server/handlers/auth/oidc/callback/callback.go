@@ -0,0 +1,114 @@
+package callback
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/device"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/login"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	token_service "sdt-bicycle-rental/internal/service/token"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=LoginFinisher
+type LoginFinisher interface {
+	Callback(ctx context.Context, provider, code, state string, device token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error)
+}
+
+// New returns external login callback handler
+//
+//	@Summary      Identity provider callback
+//	@Description  finish the login at the provider, the account is linked by a verified email or created; answers like /auth/login
+//	@Tags         auth
+//	@Produce      json
+//	@Param        provider path		string true "Provider name as configured, e.g. google"
+//	@Param        code     query	string false "Authorization code"
+//	@Param        state    query	string true "State from /auth/oidc/{provider}"
+//	@Param        error    query	string false "Set by the provider when the user did not sign in"
+//	@Success      200  {object}   	login.SuccessResponse
+//	@Success      202  {object}   	login.ChallengeResponse	"a second factor is required"
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem	"an account with the email exists and is not verified"
+//	@Failure      500  {object}		problem.Problem
+//	@Failure      502  {object}		problem.Problem
+//	@Router       /auth/oidc/{provider}/callback [get]
+func New(s LoginFinisher, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.oidc.callback.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		provider := chi.URLParam(r, "provider")
+		query := r.URL.Query()
+
+		// The state is good for one attempt whatever the outcome
+		http.SetCookie(w, &http.Cookie{
+			Name:     start.StateCookie,
+			Path:     start.CookiePath,
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		if reason := query.Get("error"); reason != "" {
+			log.Info("provider returned an error", slog.String("provider", provider), slog.String("error", reason))
+			problem.Render(w, r, log, service.ErrExternalLoginRejected)
+			return
+		}
+
+		state := query.Get("state")
+		cookie, err := r.Cookie(start.StateCookie)
+		if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+			log.Info("state does not match the cookie", slog.String("provider", provider))
+			problem.Render(w, r, log, service.ErrInvalidOIDCState)
+			return
+		}
+
+		user, tokens, challenge, err := s.Callback(r.Context(), provider, query.Get("code"), state, device.FromRequest(r, ""))
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if challenge != nil {
+			log.Info("second factor required", slog.Uint64("id", user.ID))
+
+			resp := login.ChallengeResponse{
+				MFARequired:    true,
+				ChallengeToken: challenge.Token,
+				ExpiresIn:      int64(challenge.ExpiresIn.Seconds()),
+				SetupLinkSent:  challenge.SetupLinkSent,
+			}
+
+			w.WriteHeader(http.StatusAccepted)
+			render.JSON(w, r, resp)
+			return
+		}
+
+		log.Info("user authorized", slog.Uint64("id", user.ID), slog.String("provider", provider))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, login.SuccessResponse{
+			User:         user,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		})
+	}
+}
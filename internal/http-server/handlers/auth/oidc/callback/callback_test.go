@@ -0,0 +1,139 @@
+package callback_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/login"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/callback"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/callback/mocks"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		cookie    string
+		resp      resp
+		mockCall  bool
+		mockError error
+		challenge *auth_service.Challenge
+	}{
+		{
+			name:     "success",
+			query:    "?code=code&state=abc",
+			cookie:   "abc",
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name:      "second factor required",
+			query:     "?code=code&state=abc",
+			cookie:    "abc",
+			resp:      resp{Code: http.StatusAccepted},
+			mockCall:  true,
+			challenge: &auth_service.Challenge{Token: "challenge", ExpiresIn: 5 * time.Minute},
+		},
+		{
+			name:   "state does not match cookie",
+			query:  "?code=code&state=abc",
+			cookie: "xyz",
+			resp:   resp{Code: http.StatusBadRequest, Error: service.ErrInvalidOIDCState.Error()},
+		},
+		{
+			name:  "no cookie",
+			query: "?code=code&state=abc",
+			resp:  resp{Code: http.StatusBadRequest, Error: service.ErrInvalidOIDCState.Error()},
+		},
+		{
+			name:   "denied at provider",
+			query:  "?error=access_denied&state=abc",
+			cookie: "abc",
+			resp:   resp{Code: http.StatusUnauthorized, Error: service.ErrExternalLoginRejected.Error()},
+		},
+		{
+			name:      "account not linked",
+			query:     "?code=code&state=abc",
+			cookie:    "abc",
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrAccountNotLinked.Error()},
+			mockCall:  true,
+			mockError: service.ErrAccountNotLinked,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			finisherMock := mocks.NewLoginFinisher(t)
+			if tc.mockCall {
+				var user *models.User
+				var pair *token_service.Pair
+				if tc.mockError == nil {
+					user = &models.User{ID: 1, Email: util.Ptr("valid@email.com")}
+				}
+				if tc.mockError == nil && tc.challenge == nil {
+					pair = &token_service.Pair{AccessToken: "token", RefreshToken: "refresh", ExpiresIn: time.Minute}
+				}
+				finisherMock.On("Callback", mock.Anything, "google", "code", "abc", token_service.Device{IP: "192.0.2.1"}).Return(user, pair, tc.challenge, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/auth/oidc/{provider}/callback", callback.New(finisherMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/oidc/google/callback"+tc.query, nil)
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: start.StateCookie, Value: tc.cookie})
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			// the state cookie is cleared whatever the outcome
+			cookies := rr.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, start.StateCookie, cookies[0].Name)
+			assert.Negative(t, cookies[0].MaxAge)
+
+			switch rr.Code {
+			case http.StatusOK:
+				var resp login.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "token", resp.Token)
+				assert.Equal(t, "refresh", resp.RefreshToken)
+				return
+			case http.StatusAccepted:
+				var resp login.ChallengeResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.True(t, resp.MFARequired)
+				assert.Equal(t, "challenge", resp.ChallengeToken)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// LoginFinisher is an autogenerated mock type for the LoginFinisher type
+type LoginFinisher struct {
+	mock.Mock
+}
+
+// Callback provides a mock function with given fields: ctx, provider, code, state, device
+func (_m *LoginFinisher) Callback(ctx context.Context, provider string, code string, state string, device token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error) {
+	ret := _m.Called(ctx, provider, code, state, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Callback")
+	}
+
+	var r0 *models.User
+	var r1 *token_service.Pair
+	var r2 *auth_service.Challenge
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error)); ok {
+		return rf(ctx, provider, code, state, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, token_service.Device) *models.User); ok {
+		r0 = rf(ctx, provider, code, state, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, token_service.Device) *token_service.Pair); ok {
+		r1 = rf(ctx, provider, code, state, device)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, token_service.Device) *auth_service.Challenge); ok {
+		r2 = rf(ctx, provider, code, state, device)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*auth_service.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, string, token_service.Device) error); ok {
+		r3 = rf(ctx, provider, code, state, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// NewLoginFinisher creates a new instance of LoginFinisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginFinisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginFinisher {
+	mock := &LoginFinisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
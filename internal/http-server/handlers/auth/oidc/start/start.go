@@ -0,0 +1,66 @@
+package start
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// StateCookie binds the login to the browser that started it, the callback checks it against the state
+	StateCookie = "oidc_state"
+	CookiePath  = "/auth/oidc"
+)
+
+//go:generate mockery --name=LoginStarter
+type LoginStarter interface {
+	Start(ctx context.Context, provider string) (authURL, state string, err error)
+}
+
+// New returns start external login handler
+//
+//	@Summary      Sign in with an identity provider
+//	@Description  redirect to the OpenID Connect provider, which redirects back to /auth/oidc/{provider}/callback
+//	@Tags         auth
+//	@Param        provider path		string true "Provider name as configured, e.g. google"
+//	@Success      302
+//	@Header       302  {string}		Location	"the authorization endpoint of the provider"
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Failure      502  {object}		problem.Problem
+//	@Router       /auth/oidc/{provider} [get]
+func New(s LoginStarter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.oidc.start.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		provider := chi.URLParam(r, "provider")
+
+		authURL, state, err := s.Start(r.Context(), provider)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("external login started", slog.String("provider", provider))
+
+		// Lax, the provider redirects back with a top level GET
+		http.SetCookie(w, &http.Cookie{
+			Name:     StateCookie,
+			Value:    state,
+			Path:     CookiePath,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
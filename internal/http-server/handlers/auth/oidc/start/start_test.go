@@ -0,0 +1,87 @@
+package start_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		provider  string
+		resp      resp
+		mockError error
+	}{
+		{
+			name:     "success",
+			provider: "google",
+			resp:     resp{Code: http.StatusFound},
+		},
+		{
+			name:      "unknown provider",
+			provider:  "myspace",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUnknownProvider.Error()},
+			mockError: service.ErrUnknownProvider,
+		},
+		{
+			name:      "provider unavailable",
+			provider:  "google",
+			resp:      resp{Code: http.StatusBadGateway, Error: service.ErrProviderUnavailable.Error()},
+			mockError: service.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			starterMock := mocks.NewLoginStarter(t)
+			if tc.mockError != nil {
+				starterMock.On("Start", mock.Anything, tc.provider).Return("", "", tc.mockError).Once()
+			} else {
+				starterMock.On("Start", mock.Anything, tc.provider).Return("https://accounts.example.com/auth?state=abc", "abc", nil).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/auth/oidc/{provider}", start.New(starterMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/oidc/"+tc.provider, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusFound {
+				assert.Equal(t, "https://accounts.example.com/auth?state=abc", rr.Header().Get("Location"))
+				cookies := rr.Result().Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, start.StateCookie, cookies[0].Name)
+				assert.Equal(t, "abc", cookies[0].Value)
+				assert.True(t, cookies[0].HttpOnly)
+				assert.True(t, cookies[0].Secure)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
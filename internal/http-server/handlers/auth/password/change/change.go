@@ -0,0 +1,79 @@
+package change
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/device"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+//go:generate mockery --name=PasswordChanger
+type PasswordChanger interface {
+	Change(ctx context.Context, userID uint64, sessionID, currentPassword, password, ip string) error
+}
+
+// New returns change password handler
+//
+//	@Summary      Change password
+//	@Description  set a new password with the current one and log out every other session; wrong current passwords count towards the login lockout
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Current and new password"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      429  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/password/change [post]
+func New(s PasswordChanger, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.password.change.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := jwtauth.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		ip := device.FromRequest(r, "").IP
+		if err := s.Change(r.Context(), principal.UserID, principal.SessionID, req.CurrentPassword, req.Password, ip); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("password changed", slog.Uint64("id", principal.UserID))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,114 @@
+package change_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/change"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/change/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	principal := &jwtauth.Principal{UserID: 7, SessionID: "phone"}
+
+	cases := []struct {
+		name      string
+		principal *jwtauth.Principal
+		body      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:      "success",
+			principal: principal,
+			body:      `{"current_password": "old-password", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusNoContent},
+			mockCall:  true,
+		},
+		{
+			name: "unauthenticated",
+			body: `{"current_password": "old-password", "password": "new-password"}`,
+			resp: resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "invalid body",
+			principal: principal,
+			body:      `not json`,
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "wrong current password",
+			principal: principal,
+			body:      `{"current_password": "old-password", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusForbidden, Error: service.ErrWrongPassword.Error()},
+			mockCall:  true,
+			mockError: service.ErrWrongPassword,
+		},
+		{
+			name:      "weak password",
+			principal: principal,
+			body:      `{"current_password": "old-password", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusBadRequest, Error: "password is too common"},
+			mockCall:  true,
+			mockError: service.ErrWeakPassword.WithDetail("password is too common"),
+		},
+		{
+			name:      "internal error",
+			principal: principal,
+			body:      `{"current_password": "old-password", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			changerMock := mocks.NewPasswordChanger(t)
+
+			if tc.mockCall {
+				changerMock.On("Change", mock.Anything, uint64(7), "phone", "old-password", "new-password", "192.0.2.1").
+					Return(tc.mockError).Once()
+			}
+
+			handler := change.New(changerMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/password/change", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			req.RemoteAddr = "192.0.2.1:54321"
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordChanger is an autogenerated mock type for the PasswordChanger type
+type PasswordChanger struct {
+	mock.Mock
+}
+
+// Change provides a mock function with given fields: ctx, userID, sessionID, currentPassword, password, ip
+func (_m *PasswordChanger) Change(ctx context.Context, userID uint64, sessionID string, currentPassword string, password string, ip string) error {
+	ret := _m.Called(ctx, userID, sessionID, currentPassword, password, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Change")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string, string, string) error); ok {
+		r0 = rf(ctx, userID, sessionID, currentPassword, password, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordChanger creates a new instance of PasswordChanger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordChanger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordChanger {
+	mock := &PasswordChanger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
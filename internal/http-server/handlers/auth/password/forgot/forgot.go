@@ -0,0 +1,69 @@
+package forgot
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Message is the same whether the email is registered or not
+const Message = "if the email is registered, a reset link has been sent to it"
+
+type Request struct {
+	Email string `json:"email"`
+}
+
+type SuccessResponse struct {
+	Message string `json:"message"`
+}
+
+//go:generate mockery --name=ResetRequester
+type ResetRequester interface {
+	Forgot(ctx context.Context, email string) error
+}
+
+// New returns forgot password handler
+//
+//	@Summary      Forgot password
+//	@Description  email a single-use password reset link, the response doesn't tell whether the email is registered
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Account email"
+//	@Success      202  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/password/forgot [post]
+func New(s ResetRequester, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.password.forgot.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.Forgot(r.Context(), req.Email); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		render.JSON(w, r, SuccessResponse{Message: Message})
+	}
+}
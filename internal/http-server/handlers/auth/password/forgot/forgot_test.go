@@ -0,0 +1,95 @@
+package forgot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/forgot"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/forgot/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgotHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		email     string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"email": "valid@email.com"}`,
+			email:    "valid@email.com",
+			resp:     resp{Code: http.StatusAccepted},
+			mockCall: true,
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "invalid email",
+			body:      `{"email": "invalid-email"}`,
+			email:     "invalid-email",
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockCall:  true,
+			mockError: service.Invalid(),
+		},
+		{
+			name:      "internal error",
+			body:      `{"email": "valid@email.com"}`,
+			email:     "valid@email.com",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			requesterMock := mocks.NewResetRequester(t)
+
+			if tc.mockCall {
+				requesterMock.On("Forgot", mock.Anything, tc.email).Return(tc.mockError).Once()
+			}
+
+			handler := forgot.New(requesterMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusAccepted {
+				var resp forgot.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, forgot.Message, resp.Message)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
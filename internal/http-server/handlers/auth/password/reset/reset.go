@@ -0,0 +1,64 @@
+package reset
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+//go:generate mockery --name=PasswordResetter
+type PasswordResetter interface {
+	Reset(ctx context.Context, token, password string) error
+}
+
+// New returns reset password handler
+//
+//	@Summary      Reset password
+//	@Description  set a new password with the token from the reset link and log out every session
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Reset token and new password"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/password/reset [post]
+func New(s PasswordResetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.password.reset.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.Reset(r.Context(), req.Token, req.Password); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("password reset")
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
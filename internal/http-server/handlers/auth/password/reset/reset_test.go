@@ -0,0 +1,88 @@
+package reset_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/reset"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/password/reset/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"token": "reset-token", "password": "new-password"}`,
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "used token",
+			body:      `{"token": "reset-token", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidResetToken.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidResetToken,
+		},
+		{
+			name:      "internal error",
+			body:      `{"token": "reset-token", "password": "new-password"}`,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resetterMock := mocks.NewPasswordResetter(t)
+
+			if tc.mockCall {
+				resetterMock.On("Reset", mock.Anything, "reset-token", "new-password").Return(tc.mockError).Once()
+			}
+
+			handler := reset.New(resetterMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// TokenRefresher is an autogenerated mock type for the TokenRefresher type
+type TokenRefresher struct {
+	mock.Mock
+}
+
+// Refresh provides a mock function with given fields: ctx, token
+func (_m *TokenRefresher) Refresh(ctx context.Context, token string) (*token_service.Pair, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *token_service.Pair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*token_service.Pair, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *token_service.Pair); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenRefresher creates a new instance of TokenRefresher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenRefresher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenRefresher {
+	mock := &TokenRefresher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+package refresh
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	RefreshToken string `json:"refresh_token"`
+}
+type SuccessResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+//go:generate mockery --name=TokenRefresher
+type TokenRefresher interface {
+	Refresh(ctx context.Context, token string) (*token_service.Pair, error)
+}
+
+// New returns refresh handler
+//
+//	@Summary      Refresh
+//	@Description  exchange a refresh token for a new token pair, the presented refresh token can not be used again
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Refresh token"
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/refresh [post]
+func New(s TokenRefresher, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.refresh.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil || req.RefreshToken == "" {
+			if err != nil {
+				log.Error("failed to decode request body", sl.Err(err))
+			}
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		tokens, err := s.Refresh(r.Context(), req.RefreshToken)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("tokens refreshed")
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		})
+	}
+}
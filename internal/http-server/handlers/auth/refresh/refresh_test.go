@@ -0,0 +1,103 @@
+package refresh_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/refresh"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/refresh/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name         string
+		refreshToken string
+		resp         resp
+		mockPair     *token_service.Pair
+		mockError    error
+	}{
+		{
+			name:         "success",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusOK},
+			mockPair:     &token_service.Pair{AccessToken: "access", RefreshToken: "next-refresh", ExpiresIn: 15 * time.Minute},
+		},
+		{
+			name:         "empty token",
+			refreshToken: "",
+			resp:         resp{Code: http.StatusBadRequest, Error: "invalid input"},
+		},
+		{
+			name:         "reused token",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusUnauthorized, Error: service.ErrTokenReused.Error()},
+			mockError:    service.ErrTokenReused,
+		},
+		{
+			name:         "expired token",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusUnauthorized, Error: service.ErrExpiredToken.Error()},
+			mockError:    service.ErrExpiredToken,
+		},
+		{
+			name:         "internal error",
+			refreshToken: "refresh-token",
+			resp:         resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError:    service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			refresherMock := mocks.NewTokenRefresher(t)
+
+			if tc.refreshToken != "" {
+				refresherMock.On("Refresh", mock.Anything, tc.refreshToken).Return(tc.mockPair, tc.mockError).Once()
+			}
+
+			handler := refresh.New(refresherMock, slogdiscard.NewDiscardLogger())
+
+			input := fmt.Sprintf(`{"refresh_token": "%s"}`, tc.refreshToken)
+
+			req, err := http.NewRequest(http.MethodPost, "/refresh", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp refresh.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tc.mockPair.AccessToken, resp.Token)
+				assert.Equal(t, tc.mockPair.RefreshToken, resp.RefreshToken)
+				assert.Equal(t, int64(900), resp.ExpiresIn)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -3,11 +3,14 @@
 package mocks
 
 import (
+	context "context"
 	dto "sdt-bicycle-rental/internal/repository/dto"
 
 	mock "github.com/stretchr/testify/mock"
 
 	models "sdt-bicycle-rental/internal/models"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
 )
 
 // UserRegisterer is an autogenerated mock type for the UserRegisterer type
@@ -15,36 +18,38 @@ type UserRegisterer struct {
 	mock.Mock
 }
 
-// Register provides a mock function with given fields: user
-func (_m *UserRegisterer) Register(user *dto.CreateUser) (*models.User, string, error) {
-	ret := _m.Called(user)
+// Register provides a mock function with given fields: ctx, user, device
+func (_m *UserRegisterer) Register(ctx context.Context, user *dto.CreateUser, device token_service.Device) (*models.User, *token_service.Pair, error) {
+	ret := _m.Called(ctx, user, device)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
 	}
 
 	var r0 *models.User
-	var r1 string
+	var r1 *token_service.Pair
 	var r2 error
-	if rf, ok := ret.Get(0).(func(*dto.CreateUser) (*models.User, string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser, token_service.Device) (*models.User, *token_service.Pair, error)); ok {
+		return rf(ctx, user, device)
 	}
-	if rf, ok := ret.Get(0).(func(*dto.CreateUser) *models.User); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateUser, token_service.Device) *models.User); ok {
+		r0 = rf(ctx, user, device)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*dto.CreateUser) string); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateUser, token_service.Device) *token_service.Pair); ok {
+		r1 = rf(ctx, user, device)
 	} else {
-		r1 = ret.Get(1).(string)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*token_service.Pair)
+		}
 	}
 
-	if rf, ok := ret.Get(2).(func(*dto.CreateUser) error); ok {
-		r2 = rf(user)
+	if rf, ok := ret.Get(2).(func(context.Context, *dto.CreateUser, token_service.Device) error); ok {
+		r2 = rf(ctx, user, device)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -1,12 +1,15 @@
 package register
 
 import (
-	"errors"
+	"context"
 	"log/slog"
 	"net/http"
+	"sdt-bicycle-rental/internal/http-server/device"
+	"sdt-bicycle-rental/internal/http-server/problem"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/dto"
 	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
 	"sdt-bicycle-rental/lib/logger/sl"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -14,19 +17,19 @@ import (
 )
 
 type Request struct {
-	User dto.CreateUser `json:"user"`
+	User       dto.CreateUser `json:"user"`
+	DeviceName string         `json:"device_name"` // optional, shown in the list of sessions
 }
 type SuccessResponse struct {
-	User  *models.User `json:"user"`
-	Token string       `json:"token"`
-}
-type ErrorResponse struct {
-	Error string `json:"error"`
+	User         *models.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int64        `json:"expires_in"`
 }
 
 //go:generate mockery --name=UserRegisterer
 type UserRegisterer interface {
-	Register(user *dto.CreateUser) (*models.User, string, error)
+	Register(ctx context.Context, user *dto.CreateUser, device token_service.Device) (*models.User, *token_service.Pair, error)
 }
 
 // New returns register handler
@@ -38,9 +41,9 @@ type UserRegisterer interface {
 //	@Produce      json
 //	@Param        request body 		Request true "User registration data"
 //	@Success      201  {object}   	SuccessResponse
-//	@Failure      400  {object}		ErrorResponse
-//	@Failure      409  {object}		ErrorResponse
-//	@Failure      500  {object}		ErrorResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
 //	@Router       /auth/register [post]
 func New(s UserRegisterer, log *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -56,31 +59,25 @@ func New(s UserRegisterer, log *slog.Logger) http.HandlerFunc {
 		if err := render.DecodeJSON(r.Body, &req); err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, ErrorResponse{Error: "invalid input"})
+			problem.Render(w, r, log, service.ErrInvalidInput)
 
 			return
 		}
 
-		user, token, err := s.Register(&req.User)
+		user, tokens, err := s.Register(r.Context(), &req.User, device.FromRequest(r, req.DeviceName))
 		if err != nil {
-			if errors.Is(err, service.ErrInternalError) {
-				// internal error
-				w.WriteHeader(http.StatusInternalServerError)
-			} else if errors.Is(err, service.ErrUserAlreadyExists) {
-				// user exists error
-				w.WriteHeader(http.StatusConflict)
-			} else {
-				// other errors
-				w.WriteHeader(http.StatusBadRequest)
-			}
-			render.JSON(w, r, ErrorResponse{Error: err.Error()})
+			problem.Render(w, r, log, err)
 			return
 		}
 
 		log.Info("user registered", slog.Uint64("id", user.ID))
 
 		w.WriteHeader(http.StatusCreated)
-		render.JSON(w, r, SuccessResponse{User: user, Token: token})
+		render.JSON(w, r, SuccessResponse{
+			User:         user,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		})
 	}
 }
@@ -3,18 +3,20 @@ package register_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/register"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/register/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
 	"sdt-bicycle-rental/internal/repository/dto"
 	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,8 +47,8 @@ func TestRegisterHandler(t *testing.T) {
 		{
 			name:      "invalid email",
 			user:      user{Name: "John", Lastname: "Doe", Email: "invalidemail.com", Phone: "1234567890", Password: "superpass"},
-			resp:      resp{Code: http.StatusBadRequest, Error: "field Email is not a valid email"},
-			mockError: errors.New("field Email is not a valid email"),
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockError: service.Invalid(),
 		},
 		{
 			name:      "user exists",
@@ -73,13 +75,13 @@ func TestRegisterHandler(t *testing.T) {
 			json.Unmarshal(inputUser, &userModel)
 
 			if tc.resp.Error == "" || tc.mockError != nil {
-				mockCall := userRegistererMock.On("Register", &userModel)
-				mockCall.Return(userModel.Model(), "token", tc.mockError).Once()
+				mockCall := userRegistererMock.On("Register", mock.Anything, &userModel, token_service.Device{Name: "Pixel 8"})
+				mockCall.Return(userModel.Model(), &token_service.Pair{AccessToken: "token", RefreshToken: "refresh"}, tc.mockError).Once()
 			}
 
 			handler := register.New(userRegistererMock, slogdiscard.NewDiscardLogger())
 
-			input := fmt.Sprintf(`{"user": %s}`, inputUser)
+			input := fmt.Sprintf(`{"user": %s, "device_name": "Pixel 8"}`, inputUser)
 
 			req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewReader([]byte(input)))
 			require.NoError(t, err)
@@ -95,12 +97,13 @@ func TestRegisterHandler(t *testing.T) {
 				require.NoError(t, json.Unmarshal([]byte(body), &resp))
 				assert.NotEqual(t, resp.User, nil)
 				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
 				return
 			}
 
-			var resp register.ErrorResponse
+			var resp problem.Problem
 			require.NoError(t, json.Unmarshal([]byte(body), &resp))
-			require.Equal(t, tc.resp.Error, resp.Error)
+			require.Equal(t, tc.resp.Error, resp.Detail)
 		})
 	}
 }
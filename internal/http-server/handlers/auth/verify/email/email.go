@@ -0,0 +1,61 @@
+package email
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Token string `json:"token"`
+}
+
+//go:generate mockery --name=EmailVerifier
+type EmailVerifier interface {
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+// New returns verify email handler
+//
+//	@Summary      Verify email
+//	@Description  confirm the email with the token from the emailed link
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Param        request body 		Request true "Token from the link"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/verify/email [post]
+func New(s EmailVerifier, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.verify.email.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.VerifyEmail(r.Context(), req.Token); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerifier is an autogenerated mock type for the PhoneVerifier type
+type PhoneVerifier struct {
+	mock.Mock
+}
+
+// VerifyPhone provides a mock function with given fields: ctx, userID, code
+func (_m *PhoneVerifier) VerifyPhone(ctx context.Context, userID uint64, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyPhone")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPhoneVerifier creates a new instance of PhoneVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerifier {
+	mock := &PhoneVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
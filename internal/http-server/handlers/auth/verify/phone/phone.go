@@ -0,0 +1,72 @@
+package phone
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Code string `json:"code"`
+}
+
+//go:generate mockery --name=PhoneVerifier
+type PhoneVerifier interface {
+	VerifyPhone(ctx context.Context, userID uint64, code string) error
+}
+
+// New returns verify phone handler
+//
+//	@Summary      Verify phone
+//	@Description  confirm the phone of the current user with the code sent by SMS
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Code from the SMS"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/verify/phone [post]
+func New(s PhoneVerifier, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.verify.phone.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.VerifyPhone(r.Context(), userID, req.Code); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
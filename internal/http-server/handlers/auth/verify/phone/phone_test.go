@@ -0,0 +1,92 @@
+package phone_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/phone"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/phone/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		anonymous bool
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"code": "123456"}`,
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:      "no principal",
+			body:      `{"code": "123456"}`,
+			anonymous: true,
+			resp:      resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "wrong code",
+			body:      `{"code": "123456"}`,
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrInvalidVerificationCode.Error()},
+			mockCall:  true,
+			mockError: service.ErrInvalidVerificationCode,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifierMock := mocks.NewPhoneVerifier(t)
+
+			if tc.mockCall {
+				verifierMock.On("VerifyPhone", mock.Anything, uint64(1), "123456").Return(tc.mockError).Once()
+			}
+
+			handler := phone.New(verifierMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/verify/phone", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			if !tc.anonymous {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CodeResender is an autogenerated mock type for the CodeResender type
+type CodeResender struct {
+	mock.Mock
+}
+
+// Resend provides a mock function with given fields: ctx, userID, channel
+func (_m *CodeResender) Resend(ctx context.Context, userID uint64, channel string) error {
+	ret := _m.Called(ctx, userID, channel)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, userID, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCodeResender creates a new instance of CodeResender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCodeResender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CodeResender {
+	mock := &CodeResender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,74 @@
+package resend
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Channel string `json:"channel" enums:"email,phone"`
+}
+
+//go:generate mockery --name=CodeResender
+type CodeResender interface {
+	Resend(ctx context.Context, userID uint64, channel string) error
+}
+
+// New returns resend verification handler
+//
+//	@Summary      Resend verification
+//	@Description  send a new email link or phone code to the current user, at most once a minute per channel
+//	@Tags         auth
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Channel to verify"
+//	@Success      202
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      429  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /auth/verify/resend [post]
+func New(s CodeResender, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.auth.verify.resend.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.Resend(r.Context(), userID, req.Channel); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,90 @@
+package resend_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/resend"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/verify/resend/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResendHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		body      string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     `{"channel": "phone"}`,
+			resp:     resp{Code: http.StatusAccepted},
+			mockCall: true,
+		},
+		{
+			name: "invalid body",
+			body: `not json`,
+			resp: resp{Code: http.StatusBadRequest, Error: service.ErrInvalidInput.Error()},
+		},
+		{
+			name:      "already verified",
+			body:      `{"channel": "phone"}`,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrAlreadyVerified.Error()},
+			mockCall:  true,
+			mockError: service.ErrAlreadyVerified,
+		},
+		{
+			name:      "too soon",
+			body:      `{"channel": "phone"}`,
+			resp:      resp{Code: http.StatusTooManyRequests, Error: service.ErrVerificationThrottled.Error()},
+			mockCall:  true,
+			mockError: service.ErrVerificationThrottled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resenderMock := mocks.NewCodeResender(t)
+
+			if tc.mockCall {
+				resenderMock.On("Resend", mock.Anything, uint64(1), "phone").Return(tc.mockError).Once()
+			}
+
+			handler := resend.New(resenderMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodPost, "/verify/resend", bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusAccepted {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
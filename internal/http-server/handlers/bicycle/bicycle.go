@@ -0,0 +1,39 @@
+package bicycle
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/move"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/register"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/retire"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/status"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	bicycle_service "sdt-bicycle-rental/internal/service/bicycle"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func BicycleRoute(log *slog.Logger, bicycleService *bicycle_service.BicycleService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", list.New(bicycleService, log))
+		r.Get("/{id}", get.New(bicycleService, log))
+
+		r.Group(func(r chi.Router) {
+			r.Use(authenticate)
+
+			r.Group(func(r chi.Router) {
+				r.Use(jwtauth.RequirePermission(access_service.PermManageBicycles))
+
+				r.Post("/", register.New(bicycleService, log))
+				r.Post("/{id}/move", move.New(bicycleService, log))
+				r.Delete("/{id}", retire.New(bicycleService, log))
+			})
+
+			r.With(jwtauth.RequirePermission(access_service.PermMaintainBicycle)).
+				Patch("/{id}/status", status.New(bicycleService, log))
+		})
+	}
+}
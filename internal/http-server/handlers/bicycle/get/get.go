@@ -0,0 +1,60 @@
+package get
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=BicycleGetter
+type BicycleGetter interface {
+	ByID(ctx context.Context, id uint64) (*models.Bicycle, error)
+}
+
+// New returns get bicycle handler
+//
+//	@Summary      Get bicycle
+//	@Description  get a bicycle by ID
+//	@Tags         bicycles
+//	@Produce      json
+//	@Param        id   path 		int true "Bicycle ID"
+//	@Success      200  {object}   	models.Bicycle
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles/{id} [get]
+func New(s BicycleGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid bicycle id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid bicycle id"))
+			return
+		}
+
+		bicycle, err := s.ByID(r.Context(), id)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, bicycle)
+	}
+}
@@ -0,0 +1,98 @@
+package get_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/get/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name: "invalid id",
+			id:   "first",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid bicycle id"},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrBicycleNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrBicycleNotFound.Error()},
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewBicycleGetter(t)
+
+			if tc.mockCall {
+				var bicycle *models.Bicycle
+				if tc.mockError == nil {
+					bicycle = &models.Bicycle{ID: 1, StationID: 2, Type: models.BicycleTypeElectric, Status: models.BicycleStatusAvailable}
+				}
+				getterMock.On("ByID", mock.Anything, uint64(1)).Return(bicycle, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/bicycles/{id}", get.New(getterMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodGet, "/bicycles/"+tc.id, nil)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Bicycle
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(1), resp.ID)
+				assert.Equal(t, models.BicycleTypeElectric, resp.Type)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
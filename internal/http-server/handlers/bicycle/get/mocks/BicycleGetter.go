@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BicycleGetter is an autogenerated mock type for the BicycleGetter type
+type BicycleGetter struct {
+	mock.Mock
+}
+
+// ByID provides a mock function with given fields: ctx, id
+func (_m *BicycleGetter) ByID(ctx context.Context, id uint64) (*models.Bicycle, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ByID")
+	}
+
+	var r0 *models.Bicycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Bicycle, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Bicycle); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Bicycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBicycleGetter creates a new instance of BicycleGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleGetter {
+	mock := &BicycleGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
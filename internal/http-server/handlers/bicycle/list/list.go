@@ -0,0 +1,99 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	bicycle_service "sdt-bicycle-rental/internal/service/bicycle"
+	"sdt-bicycle-rental/lib/util"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type SuccessResponse struct {
+	Bicycles []models.Bicycle `json:"bicycles"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+//go:generate mockery --name=BicycleLister
+type BicycleLister interface {
+	List(ctx context.Context, filter dto.BicycleFilter, page, limit int) ([]models.Bicycle, int64, error)
+}
+
+// New returns list bicycles handler
+//
+//	@Summary      List bicycles
+//	@Description  list bicycles page by page, optionally by station and status
+//	@Tags         bicycles
+//	@Produce      json
+//	@Param        station_id query 	int    false "Station ID"
+//	@Param        status     query 	string false "Bicycle status" Enums(available, reserved, rented, in_service, retired)
+//	@Param        page       query 	int    false "Page number, starts from 1" default(1)
+//	@Param        limit      query 	int    false "Page size, at most 100" default(20)
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles [get]
+func New(s BicycleLister, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var filter dto.BicycleFilter
+		if value := r.URL.Query().Get("station_id"); value != "" {
+			stationID, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid station id"))
+				return
+			}
+			filter.StationID = &stationID
+		}
+		if value := r.URL.Query().Get("status"); value != "" {
+			filter.Status = util.Ptr(value)
+		}
+
+		page, err := queryInt(r, "page", 1)
+		if err != nil || page < 1 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid page"))
+			return
+		}
+		limit, err := queryInt(r, "limit", bicycle_service.DefaultPageSize)
+		if err != nil || limit < 1 || limit > bicycle_service.MaxPageSize {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid limit"))
+			return
+		}
+
+		bicycles, total, err := s.List(r.Context(), filter, page, limit)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if bicycles == nil {
+			bicycles = []models.Bicycle{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{Bicycles: bicycles, Page: page, Limit: limit, Total: total})
+	}
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "sdt-bicycle-rental/internal/repository/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BicycleLister is an autogenerated mock type for the BicycleLister type
+type BicycleLister struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, filter, page, limit
+func (_m *BicycleLister) List(ctx context.Context, filter dto.BicycleFilter, page int, limit int) ([]models.Bicycle, int64, error) {
+	ret := _m.Called(ctx, filter, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.Bicycle
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter, int, int) ([]models.Bicycle, int64, error)); ok {
+		return rf(ctx, filter, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter, int, int) []models.Bicycle); ok {
+		r0 = rf(ctx, filter, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Bicycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.BicycleFilter, int, int) int64); ok {
+		r1 = rf(ctx, filter, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, dto.BicycleFilter, int, int) error); ok {
+		r2 = rf(ctx, filter, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewBicycleLister creates a new instance of BicycleLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleLister {
+	mock := &BicycleLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
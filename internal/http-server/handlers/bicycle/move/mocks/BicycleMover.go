@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BicycleMover is an autogenerated mock type for the BicycleMover type
+type BicycleMover struct {
+	mock.Mock
+}
+
+// Move provides a mock function with given fields: ctx, id, stationID
+func (_m *BicycleMover) Move(ctx context.Context, id uint64, stationID uint64) error {
+	ret := _m.Called(ctx, id, stationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, stationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBicycleMover creates a new instance of BicycleMover. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleMover(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleMover {
+	mock := &BicycleMover{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+package move
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	StationID uint64 `json:"station_id"`
+}
+
+//go:generate mockery --name=BicycleMover
+type BicycleMover interface {
+	Move(ctx context.Context, id uint64, stationID uint64) error
+}
+
+// New returns move bicycle handler
+//
+//	@Summary      Move bicycle
+//	@Description  reassign an available or in service bicycle to another station, station operators only
+//	@Tags         bicycles
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id      path 		int     true "Bicycle ID"
+//	@Param        request body 		Request true "Target station"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles/{id}/move [post]
+func New(s BicycleMover, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.move.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid bicycle id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid bicycle id"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+		if req.StationID == 0 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid station id"))
+			return
+		}
+
+		if err := s.Move(r.Context(), id, req.StationID); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("bicycle moved", slog.Uint64("id", id), slog.Uint64("station_id", req.StationID))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
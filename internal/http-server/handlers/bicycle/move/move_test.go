@@ -0,0 +1,101 @@
+package move_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/move"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/move/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		input     string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			input:    `{"station_id": 2}`,
+			mockCall: true,
+			resp:     resp{Code: http.StatusNoContent},
+		},
+		{
+			name:  "invalid id",
+			id:    "first",
+			input: `{"station_id": 2}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid bicycle id"},
+		},
+		{
+			name:  "missing station",
+			id:    "1",
+			input: `{}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid station id"},
+		},
+		{
+			name:      "station not found",
+			id:        "1",
+			input:     `{"station_id": 2}`,
+			mockCall:  true,
+			mockError: service.ErrStationNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrStationNotFound.Error()},
+		},
+		{
+			name:      "rented bike",
+			id:        "1",
+			input:     `{"station_id": 2}`,
+			mockCall:  true,
+			mockError: service.ErrBicycleUnavailable,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBicycleUnavailable.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			moverMock := mocks.NewBicycleMover(t)
+
+			if tc.mockCall {
+				moverMock.On("Move", mock.Anything, uint64(1), uint64(2)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/bicycles/{id}/move", move.New(moverMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodPost, "/bicycles/"+tc.id+"/move", bytes.NewReader([]byte(tc.input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "sdt-bicycle-rental/internal/repository/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BicycleRegisterer is an autogenerated mock type for the BicycleRegisterer type
+type BicycleRegisterer struct {
+	mock.Mock
+}
+
+// Register provides a mock function with given fields: ctx, bicycle
+func (_m *BicycleRegisterer) Register(ctx context.Context, bicycle *dto.CreateBicycle) (*models.Bicycle, error) {
+	ret := _m.Called(ctx, bicycle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 *models.Bicycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateBicycle) (*models.Bicycle, error)); ok {
+		return rf(ctx, bicycle)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateBicycle) *models.Bicycle); ok {
+		r0 = rf(ctx, bicycle)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Bicycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateBicycle) error); ok {
+		r1 = rf(ctx, bicycle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBicycleRegisterer creates a new instance of BicycleRegisterer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleRegisterer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleRegisterer {
+	mock := &BicycleRegisterer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,71 @@
+package register
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Bicycle dto.CreateBicycle `json:"bicycle"`
+}
+
+//go:generate mockery --name=BicycleRegisterer
+type BicycleRegisterer interface {
+	Register(ctx context.Context, bicycle *dto.CreateBicycle) (*models.Bicycle, error)
+}
+
+// New returns register bicycle handler
+//
+//	@Summary      Register bicycle
+//	@Description  add a new available bicycle to a station, station operators only
+//	@Tags         bicycles
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Bicycle data"
+//	@Success      201  {object}   	models.Bicycle
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles [post]
+func New(s BicycleRegisterer, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.register.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		bicycle, err := s.Register(r.Context(), &req.Bicycle)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("bicycle registered", slog.Uint64("id", bicycle.ID), slog.Uint64("station_id", bicycle.StationID))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, bicycle)
+	}
+}
@@ -0,0 +1,91 @@
+package register_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/register"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/register/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		bicycle   dto.CreateBicycle
+		mockError error
+		resp      resp
+	}{
+		{
+			name:    "success",
+			bicycle: dto.CreateBicycle{StationID: 1, Type: "electric"},
+			resp:    resp{Code: http.StatusCreated},
+		},
+		{
+			name:      "invalid type",
+			bicycle:   dto.CreateBicycle{StationID: 1, Type: "tandem"},
+			mockError: service.Invalid(),
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+		},
+		{
+			name:      "station not found",
+			bicycle:   dto.CreateBicycle{StationID: 404, Type: "standard"},
+			mockError: service.ErrStationNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrStationNotFound.Error()},
+		},
+		{
+			name:      "internal error",
+			bicycle:   dto.CreateBicycle{StationID: 1, Type: "standard"},
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			registererMock := mocks.NewBicycleRegisterer(t)
+			registererMock.On("Register", mock.Anything, &tc.bicycle).Return(tc.bicycle.Model(), tc.mockError).Once()
+
+			handler := register.New(registererMock, slogdiscard.NewDiscardLogger())
+
+			input := fmt.Sprintf(`{"bicycle": {"station_id": %d, "type": "%s"}}`, tc.bicycle.StationID, tc.bicycle.Type)
+
+			req, err := http.NewRequest(http.MethodPost, "/bicycles", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "available", resp["status"])
+				assert.Equal(t, tc.bicycle.Type, resp["type"])
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BicycleRetirer is an autogenerated mock type for the BicycleRetirer type
+type BicycleRetirer struct {
+	mock.Mock
+}
+
+// Retire provides a mock function with given fields: ctx, id
+func (_m *BicycleRetirer) Retire(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Retire")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBicycleRetirer creates a new instance of BicycleRetirer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleRetirer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleRetirer {
+	mock := &BicycleRetirer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
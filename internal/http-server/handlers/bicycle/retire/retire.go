@@ -0,0 +1,62 @@
+package retire
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=BicycleRetirer
+type BicycleRetirer interface {
+	Retire(ctx context.Context, id uint64) error
+}
+
+// New returns retire bicycle handler
+//
+//	@Summary      Retire bicycle
+//	@Description  take a bicycle out of the fleet, station operators only
+//	@Tags         bicycles
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "Bicycle ID"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles/{id} [delete]
+func New(s BicycleRetirer, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.retire.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid bicycle id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid bicycle id"))
+			return
+		}
+
+		if err := s.Retire(r.Context(), id); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("bicycle retired", slog.Uint64("id", id))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,95 @@
+package retire_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/retire"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/retire/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetireHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			mockCall: true,
+			resp:     resp{Code: http.StatusNoContent},
+		},
+		{
+			name: "invalid id",
+			id:   "first",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid bicycle id"},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrBicycleNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrBicycleNotFound.Error()},
+		},
+		{
+			name:      "rented bike",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrInvalidStatusTransition,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrInvalidStatusTransition.Error()},
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			retirerMock := mocks.NewBicycleRetirer(t)
+
+			if tc.mockCall {
+				retirerMock.On("Retire", mock.Anything, uint64(1)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/bicycles/{id}", retire.New(retirerMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodDelete, "/bicycles/"+tc.id, nil)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BicycleStatusUpdater is an autogenerated mock type for the BicycleStatusUpdater type
+type BicycleStatusUpdater struct {
+	mock.Mock
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, _a2
+func (_m *BicycleStatusUpdater) UpdateStatus(ctx context.Context, id uint64, _a2 string) error {
+	ret := _m.Called(ctx, id, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBicycleStatusUpdater creates a new instance of BicycleStatusUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleStatusUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleStatusUpdater {
+	mock := &BicycleStatusUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Status string `json:"status" enums:"available,in_service"`
+}
+
+//go:generate mockery --name=BicycleStatusUpdater
+type BicycleStatusUpdater interface {
+	UpdateStatus(ctx context.Context, id uint64, status string) error
+}
+
+// New returns update bicycle status handler
+//
+//	@Summary      Update bicycle status
+//	@Description  send a bicycle to maintenance or return it to the fleet, mechanics only
+//	@Tags         bicycles
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id      path 		int     true "Bicycle ID"
+//	@Param        request body 		Request true "New status"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bicycles/{id}/status [patch]
+func New(s BicycleStatusUpdater, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.bicycle.status.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid bicycle id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid bicycle id"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.UpdateStatus(r.Context(), id, req.Status); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("bicycle status updated", slog.Uint64("id", id), slog.String("status", req.Status))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
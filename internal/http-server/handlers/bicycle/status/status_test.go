@@ -0,0 +1,106 @@
+package status_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/status"
+	"sdt-bicycle-rental/internal/http-server/handlers/bicycle/status/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		status    string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			status:   "in_service",
+			mockCall: true,
+			resp:     resp{Code: http.StatusNoContent},
+		},
+		{
+			name:   "invalid id",
+			id:     "first",
+			status: "in_service",
+			resp:   resp{Code: http.StatusBadRequest, Error: "invalid bicycle id"},
+		},
+		{
+			name:      "illegal transition",
+			id:        "1",
+			status:    "rented",
+			mockCall:  true,
+			mockError: service.ErrInvalidStatusTransition,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrInvalidStatusTransition.Error()},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			status:    "available",
+			mockCall:  true,
+			mockError: service.ErrBicycleNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrBicycleNotFound.Error()},
+		},
+		{
+			name:      "changed concurrently",
+			id:        "1",
+			status:    "in_service",
+			mockCall:  true,
+			mockError: service.ErrBicycleUnavailable,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBicycleUnavailable.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			updaterMock := mocks.NewBicycleStatusUpdater(t)
+
+			if tc.mockCall {
+				updaterMock.On("UpdateStatus", mock.Anything, uint64(1), tc.status).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Patch("/bicycles/{id}/status", status.New(updaterMock, slogdiscard.NewDiscardLogger()))
+
+			input := fmt.Sprintf(`{"status": "%s"}`, tc.status)
+
+			req, err := http.NewRequest(http.MethodPatch, "/bicycles/"+tc.id+"/status", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package booking
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/cancel"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/convert"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/reserve"
+	booking_service "sdt-bicycle-rental/internal/service/booking"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func BookingRoute(log *slog.Logger, bookingService *booking_service.BookingService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Use(authenticate)
+
+		r.Post("/", reserve.New(bookingService, log))
+		r.Get("/{id}", get.New(bookingService, log))
+		r.Delete("/{id}", cancel.New(bookingService, log))
+		r.Post("/{id}/rental", convert.New(bookingService, log))
+	}
+}
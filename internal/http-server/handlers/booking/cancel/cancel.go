@@ -0,0 +1,70 @@
+package cancel
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=BookingCanceller
+type BookingCanceller interface {
+	Cancel(ctx context.Context, userID, bookingID uint64) error
+}
+
+// New returns cancel booking handler
+//
+//	@Summary      Cancel booking
+//	@Description  release the bicycle held by an active booking of the current user
+//	@Tags         bookings
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "Booking ID"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bookings/{id} [delete]
+func New(s BookingCanceller, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking.cancel.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid booking id"))
+			return
+		}
+
+		if err := s.Cancel(r.Context(), userID, id); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("booking cancelled", slog.Uint64("id", id))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,90 @@
+package cancel_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/cancel"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/cancel/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "10",
+			mockCall: true,
+			resp:     resp{Code: http.StatusNoContent},
+		},
+		{
+			name: "invalid id",
+			id:   "mine",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid booking id"},
+		},
+		{
+			name:      "not found",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrBookingNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrBookingNotFound.Error()},
+		},
+		{
+			name:      "already expired",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrBookingNotActive,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBookingNotActive.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cancellerMock := mocks.NewBookingCanceller(t)
+
+			if tc.mockCall {
+				cancellerMock.On("Cancel", mock.Anything, uint64(1), uint64(10)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/bookings/{id}", cancel.New(cancellerMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodDelete, "/bookings/"+tc.id, nil)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
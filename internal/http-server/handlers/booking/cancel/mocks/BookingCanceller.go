@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BookingCanceller is an autogenerated mock type for the BookingCanceller type
+type BookingCanceller struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: ctx, userID, bookingID
+func (_m *BookingCanceller) Cancel(ctx context.Context, userID uint64, bookingID uint64) error {
+	ret := _m.Called(ctx, userID, bookingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, userID, bookingID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBookingCanceller creates a new instance of BookingCanceller. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookingCanceller(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookingCanceller {
+	mock := &BookingCanceller{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
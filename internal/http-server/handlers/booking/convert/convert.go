@@ -0,0 +1,74 @@
+package convert
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=BookingConverter
+type BookingConverter interface {
+	Convert(ctx context.Context, userID, bookingID uint64) (*models.Rental, error)
+}
+
+// New returns convert booking handler
+//
+//	@Summary      Start ride from booking
+//	@Description  start a rental of the bicycle held by an active booking of the current user
+//	@Tags         bookings
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "Booking ID"
+//	@Success      201  {object}   	models.Rental
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bookings/{id}/rental [post]
+func New(s BookingConverter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking.convert.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid booking id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid booking id"))
+			return
+		}
+
+		rental, err := s.Convert(r.Context(), userID, id)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("booking converted to rental", slog.Uint64("id", id), slog.Uint64("rental_id", rental.ID))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, rental)
+	}
+}
@@ -0,0 +1,106 @@
+package convert_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/convert"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/convert/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "10",
+			mockCall: true,
+			resp:     resp{Code: http.StatusCreated},
+		},
+		{
+			name: "invalid id",
+			id:   "mine",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid booking id"},
+		},
+		{
+			name:      "hold expired",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrBookingNotActive,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBookingNotActive.Error()},
+		},
+		{
+			name:      "ride in progress",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrRentalInProgress,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrRentalInProgress.Error()},
+		},
+		{
+			name:      "internal error",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			converterMock := mocks.NewBookingConverter(t)
+
+			if tc.mockCall {
+				var rental *models.Rental
+				if tc.mockError == nil {
+					rental = &models.Rental{ID: 20, UserID: 1, BicycleID: 2, Status: models.RentalStatusActive}
+				}
+				converterMock.On("Convert", mock.Anything, uint64(1), uint64(10)).Return(rental, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/bookings/{id}/rental", convert.New(converterMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/"+tc.id+"/rental", nil)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp models.Rental
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(20), resp.ID)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
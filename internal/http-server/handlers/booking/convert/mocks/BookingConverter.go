@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BookingConverter is an autogenerated mock type for the BookingConverter type
+type BookingConverter struct {
+	mock.Mock
+}
+
+// Convert provides a mock function with given fields: ctx, userID, bookingID
+func (_m *BookingConverter) Convert(ctx context.Context, userID uint64, bookingID uint64) (*models.Rental, error) {
+	ret := _m.Called(ctx, userID, bookingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Convert")
+	}
+
+	var r0 *models.Rental
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*models.Rental, error)); ok {
+		return rf(ctx, userID, bookingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *models.Rental); ok {
+		r0 = rf(ctx, userID, bookingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rental)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, bookingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBookingConverter creates a new instance of BookingConverter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookingConverter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookingConverter {
+	mock := &BookingConverter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
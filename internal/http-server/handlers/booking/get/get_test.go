@@ -0,0 +1,88 @@
+package get_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/get/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "10",
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name: "invalid id",
+			id:   "mine",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid booking id"},
+		},
+		{
+			name:      "not found",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrBookingNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrBookingNotFound.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewBookingGetter(t)
+
+			if tc.mockCall {
+				var booking *models.Booking
+				if tc.mockError == nil {
+					booking = &models.Booking{ID: 10, UserID: 1}
+				}
+				getterMock.On("ByID", mock.Anything, uint64(1), uint64(10)).Return(booking, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/bookings/{id}", get.New(getterMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodGet, "/bookings/"+tc.id, nil)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BookingGetter is an autogenerated mock type for the BookingGetter type
+type BookingGetter struct {
+	mock.Mock
+}
+
+// ByID provides a mock function with given fields: ctx, userID, bookingID
+func (_m *BookingGetter) ByID(ctx context.Context, userID uint64, bookingID uint64) (*models.Booking, error) {
+	ret := _m.Called(ctx, userID, bookingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ByID")
+	}
+
+	var r0 *models.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*models.Booking, error)); ok {
+		return rf(ctx, userID, bookingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *models.Booking); ok {
+		r0 = rf(ctx, userID, bookingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, bookingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBookingGetter creates a new instance of BookingGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookingGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookingGetter {
+	mock := &BookingGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
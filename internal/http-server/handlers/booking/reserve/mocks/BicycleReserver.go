@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BicycleReserver is an autogenerated mock type for the BicycleReserver type
+type BicycleReserver struct {
+	mock.Mock
+}
+
+// Reserve provides a mock function with given fields: ctx, userID, bicycleID, stationID
+func (_m *BicycleReserver) Reserve(ctx context.Context, userID uint64, bicycleID uint64, stationID uint64) (*models.Booking, error) {
+	ret := _m.Called(ctx, userID, bicycleID, stationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 *models.Booking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) (*models.Booking, error)); ok {
+		return rf(ctx, userID, bicycleID, stationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) *models.Booking); ok {
+		r0 = rf(ctx, userID, bicycleID, stationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Booking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, bicycleID, stationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBicycleReserver creates a new instance of BicycleReserver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleReserver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleReserver {
+	mock := &BicycleReserver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
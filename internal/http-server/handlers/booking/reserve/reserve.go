@@ -0,0 +1,85 @@
+package reserve
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	BicycleID uint64 `json:"bicycle_id"`
+	StationID uint64 `json:"station_id"`
+}
+
+//go:generate mockery --name=BicycleReserver
+type BicycleReserver interface {
+	Reserve(ctx context.Context, userID, bicycleID, stationID uint64) (*models.Booking, error)
+}
+
+// New returns reserve bicycle handler
+//
+//	@Summary      Reserve bicycle
+//	@Description  hold an available bicycle at a station for a limited time, one active booking per user
+//	@Tags         bookings
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Bicycle and station"
+//	@Success      201  {object}   	models.Booking
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      402  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /bookings [post]
+func New(s BicycleReserver, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.booking.reserve.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+		if req.BicycleID == 0 || req.StationID == 0 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("bicycle_id and station_id are required"))
+			return
+		}
+
+		booking, err := s.Reserve(r.Context(), userID, req.BicycleID, req.StationID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("bicycle reserved", slog.Uint64("id", booking.ID), slog.Uint64("bicycle_id", req.BicycleID))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, booking)
+	}
+}
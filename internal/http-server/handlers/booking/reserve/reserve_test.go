@@ -0,0 +1,112 @@
+package reserve_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/reserve"
+	"sdt-bicycle-rental/internal/http-server/handlers/booking/reserve/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		input     string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			input:    `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall: true,
+			resp:     resp{Code: http.StatusCreated},
+		},
+		{
+			name:  "missing bicycle",
+			input: `{"station_id": 3}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "bicycle_id and station_id are required"},
+		},
+		{
+			name:      "bicycle taken",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrBicycleUnavailable,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBicycleUnavailable.Error()},
+		},
+		{
+			name:      "booking in progress",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrBookingInProgress,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBookingInProgress.Error()},
+		},
+		{
+			name:      "booking fee declined",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrPaymentDeclined,
+			resp:      resp{Code: http.StatusPaymentRequired, Error: service.ErrPaymentDeclined.Error()},
+		},
+		{
+			name:      "internal error",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reserverMock := mocks.NewBicycleReserver(t)
+
+			if tc.mockCall {
+				var booking *models.Booking
+				if tc.mockError == nil {
+					booking = &models.Booking{ID: 10, UserID: 1, BicycleID: 2, StationID: 3, Status: models.BookingStatusActive}
+				}
+				reserverMock.On("Reserve", mock.Anything, uint64(1), uint64(2), uint64(3)).Return(booking, tc.mockError).Once()
+			}
+
+			handler := reserve.New(reserverMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader([]byte(tc.input)))
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp models.Booking
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, models.BookingStatusActive, resp.Status)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
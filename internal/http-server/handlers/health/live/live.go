@@ -0,0 +1,27 @@
+package live
+
+import (
+	"net/http"
+	"sdt-bicycle-rental/internal/health"
+
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	Status string `json:"status"`
+}
+
+// New returns liveness handler, it checks no dependencies: if it answers, the process is alive
+//
+//	@Summary      Liveness probe
+//	@Description  report that the process is running
+//	@Tags         health
+//	@Produce      json
+//	@Success      200  {object}   	Response
+//	@Router       /healthz [get]
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, Response{Status: health.StatusUp})
+	}
+}
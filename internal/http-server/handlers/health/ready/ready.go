@@ -0,0 +1,51 @@
+package ready
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/health"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=ReadinessChecker
+type ReadinessChecker interface {
+	Check(ctx context.Context) *health.Report
+}
+
+// New returns readiness handler
+//
+//	@Summary      Readiness probe
+//	@Description  run the dependency checks (database, schema version, background workers), 503 if any of them fails
+//	@Tags         health
+//	@Produce      json
+//	@Success      200  {object}   	health.Report
+//	@Failure      503  {object}		health.Report
+//	@Router       /readyz [get]
+func New(c ReadinessChecker, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.ready.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		report := c.Check(r.Context())
+		if !report.Up() {
+			for _, res := range report.Checks {
+				if res.Status != health.StatusUp {
+					log.Warn("readiness check failed", slog.String("check", res.Name), slog.String("error", res.Error))
+				}
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			render.JSON(w, r, report)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, report)
+	}
+}
@@ -0,0 +1,63 @@
+package ready_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/health"
+	"sdt-bicycle-rental/internal/http-server/handlers/health/ready"
+	"sdt-bicycle-rental/internal/http-server/handlers/health/ready/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyHandler(t *testing.T) {
+	cases := []struct {
+		name   string
+		report *health.Report
+		code   int
+	}{
+		{
+			name: "ready",
+			report: &health.Report{Status: health.StatusUp, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusUp, LatencyMs: 1.5},
+				{Name: "schema", Status: health.StatusUp, LatencyMs: 0.7},
+			}},
+			code: http.StatusOK,
+		},
+		{
+			name: "database down",
+			report: &health.Report{Status: health.StatusDown, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusDown, LatencyMs: 2000, Error: health.ErrCheckTimeout.Error()},
+				{Name: "schema", Status: health.StatusUp, LatencyMs: 0.7},
+			}},
+			code: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkerMock := mocks.NewReadinessChecker(t)
+			checkerMock.On("Check", mock.Anything).Return(tc.report).Once()
+
+			handler := ready.New(checkerMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			var resp health.Report
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, *tc.report, resp)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package jwks
+
+import (
+	"net/http"
+	"sdt-bicycle-rental/lib/keyset"
+
+	"github.com/go-chi/render"
+)
+
+// cacheControl lets verifiers cache the keys, a key is published before it signs
+const cacheControl = "public, max-age=300"
+
+//go:generate mockery --name=KeyPublisher
+type KeyPublisher interface {
+	JWKS() keyset.JWKS
+}
+
+// New returns JWKS handler, other services validate access tokens with the keys it publishes
+//
+//	@Summary      Token signing keys
+//	@Description  public keys of the access token signatures as a JSON Web Key Set, selected by the kid header of a token
+//	@Tags         auth
+//	@Produce      json
+//	@Success      200  {object}   	keyset.JWKS
+//	@Router       /.well-known/jwks.json [get]
+func New(keys KeyPublisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, keys.JWKS())
+	}
+}
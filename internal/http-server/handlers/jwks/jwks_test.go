@@ -0,0 +1,42 @@
+package jwks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/jwks"
+	"sdt-bicycle-rental/internal/http-server/handlers/jwks/mocks"
+	"sdt-bicycle-rental/lib/keyset"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	publisherMock := mocks.NewKeyPublisher(t)
+	publisherMock.On("JWKS").Return(keyset.JWKS{Keys: []keyset.JWK{
+		{Kty: "OKP", Kid: "2025-01", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}}).Once()
+
+	req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	jwks.New(publisherMock).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+
+	var resp map[string][]map[string]string
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp["keys"], 1)
+	assert.Equal(t, map[string]string{
+		"kty": "OKP",
+		"kid": "2025-01",
+		"use": "sig",
+		"alg": "EdDSA",
+		"crv": "Ed25519",
+		"x":   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+	}, resp["keys"][0])
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	keyset "sdt-bicycle-rental/lib/keyset"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyPublisher is an autogenerated mock type for the KeyPublisher type
+type KeyPublisher struct {
+	mock.Mock
+}
+
+// JWKS provides a mock function with no fields
+func (_m *KeyPublisher) JWKS() keyset.JWKS {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for JWKS")
+	}
+
+	var r0 keyset.JWKS
+	if rf, ok := ret.Get(0).(func() keyset.JWKS); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(keyset.JWKS)
+	}
+
+	return r0
+}
+
+// NewKeyPublisher creates a new instance of KeyPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyPublisher {
+	mock := &KeyPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	payment_service "sdt-bicycle-rental/internal/service/payment"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type SuccessResponse struct {
+	Payments []models.Payment `json:"payments"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+//go:generate mockery --name=PaymentHistory
+type PaymentHistory interface {
+	History(ctx context.Context, userID uint64, page, limit int) ([]models.Payment, int64, error)
+}
+
+// New returns payment history handler
+//
+//	@Summary      Payment history
+//	@Description  list payments of the current user newest first, amounts are in cents
+//	@Tags         payments
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        page       query 	int    false "Page number, starts from 1" default(1)
+//	@Param        limit      query 	int    false "Page size, at most 100" default(20)
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /payments [get]
+func New(s PaymentHistory, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.payment.history.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		page, err := queryInt(r, "page", 1)
+		if err != nil || page < 1 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid page"))
+			return
+		}
+		limit, err := queryInt(r, "limit", payment_service.DefaultPageSize)
+		if err != nil || limit < 1 || limit > payment_service.MaxPageSize {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid limit"))
+			return
+		}
+
+		payments, total, err := s.History(r.Context(), userID, page, limit)
+		if err != nil {
+			log.Error("failed to list payments", sl.Err(err))
+
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if payments == nil {
+			payments = []models.Payment{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{Payments: payments, Page: page, Limit: limit, Total: total})
+	}
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,111 @@
+package history_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/history"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/history/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name        string
+		query       string
+		noPrincipal bool
+		page        int
+		limit       int
+		mockCall    bool
+		mockError   error
+		resp        resp
+	}{
+		{
+			name:     "success",
+			query:    "?page=2&limit=5",
+			page:     2,
+			limit:    5,
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name:     "defaults",
+			page:     1,
+			limit:    20,
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name:        "unauthenticated",
+			noPrincipal: true,
+			resp:        resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=1000",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid limit"},
+		},
+		{
+			name:      "internal error",
+			page:      1,
+			limit:     20,
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			historyMock := mocks.NewPaymentHistory(t)
+
+			if tc.mockCall {
+				historyMock.On("History", mock.Anything, uint64(1), tc.page, tc.limit).
+					Return([]models.Payment{{ID: 4, UserID: 1, Amount: 3200, Status: models.PaymentStatusCaptured}}, int64(6), tc.mockError).Once()
+			}
+
+			handler := history.New(historyMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/payments"+tc.query, nil)
+			if !tc.noPrincipal {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp history.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Payments, 1)
+				assert.Equal(t, int64(3200), resp.Payments[0].Amount)
+				assert.Equal(t, tc.page, resp.Page)
+				assert.Equal(t, tc.limit, resp.Limit)
+				assert.Equal(t, int64(6), resp.Total)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// PaymentHistory is an autogenerated mock type for the PaymentHistory type
+type PaymentHistory struct {
+	mock.Mock
+}
+
+// History provides a mock function with given fields: ctx, userID, page, limit
+func (_m *PaymentHistory) History(ctx context.Context, userID uint64, page int, limit int) ([]models.Payment, int64, error) {
+	ret := _m.Called(ctx, userID, page, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []models.Payment
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) ([]models.Payment, int64, error)); ok {
+		return rf(ctx, userID, page, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) []models.Payment); ok {
+		r0 = rf(ctx, userID, page, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int, int) int64); ok {
+		r1 = rf(ctx, userID, page, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, int, int) error); ok {
+		r2 = rf(ctx, userID, page, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewPaymentHistory creates a new instance of PaymentHistory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentHistory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentHistory {
+	mock := &PaymentHistory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/history"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/refund"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	payment_service "sdt-bicycle-rental/internal/service/payment"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func PaymentRoute(log *slog.Logger, paymentService *payment_service.PaymentService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Use(authenticate)
+
+		r.Get("/", history.New(paymentService, log))
+		r.With(jwtauth.RequirePermission(access_service.PermRefundPayments)).
+			Post("/{id}/refund", refund.New(paymentService, log))
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentRefunder is an autogenerated mock type for the PaymentRefunder type
+type PaymentRefunder struct {
+	mock.Mock
+}
+
+// Refund provides a mock function with given fields: ctx, id
+func (_m *PaymentRefunder) Refund(ctx context.Context, id uint64) (*models.Payment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 *models.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Payment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Payment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentRefunder creates a new instance of PaymentRefunder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentRefunder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentRefunder {
+	mock := &PaymentRefunder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
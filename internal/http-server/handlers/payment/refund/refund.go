@@ -0,0 +1,66 @@
+package refund
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=PaymentRefunder
+type PaymentRefunder interface {
+	Refund(ctx context.Context, id uint64) (*models.Payment, error)
+}
+
+// New returns refund payment handler
+//
+//	@Summary      Refund payment
+//	@Description  return a captured payment to the rider in full, admins only
+//	@Tags         payments
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "Payment ID"
+//	@Success      200  {object}   	models.Payment
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /payments/{id}/refund [post]
+func New(s PaymentRefunder, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.payment.refund.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid payment id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid payment id"))
+			return
+		}
+
+		payment, err := s.Refund(r.Context(), id)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("payment refunded", slog.Uint64("id", payment.ID), slog.Int64("amount", payment.Amount))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, payment)
+	}
+}
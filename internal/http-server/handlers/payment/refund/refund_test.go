@@ -0,0 +1,104 @@
+package refund_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/refund"
+	"sdt-bicycle-rental/internal/http-server/handlers/payment/refund/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name: "invalid id",
+			id:   "last",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid payment id"},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrPaymentNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrPaymentNotFound.Error()},
+		},
+		{
+			name:      "not captured",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrPaymentNotRefundable,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrPaymentNotRefundable.Error()},
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			refunderMock := mocks.NewPaymentRefunder(t)
+
+			if tc.mockCall {
+				var payment *models.Payment
+				if tc.mockError == nil {
+					payment = &models.Payment{ID: 1, Amount: 3200, Status: models.PaymentStatusRefunded}
+				}
+				refunderMock.On("Refund", mock.Anything, uint64(1)).Return(payment, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/payments/{id}/refund", refund.New(refunderMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/"+tc.id+"/refund", nil)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Payment
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, models.PaymentStatusRefunded, resp.Status)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package active
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=ActiveRentalGetter
+type ActiveRentalGetter interface {
+	Active(ctx context.Context, userID uint64) (*models.Rental, error)
+}
+
+// New returns active rental handler
+//
+//	@Summary      Active ride
+//	@Description  get the ride the current user has in progress
+//	@Tags         rentals
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Success      200  {object}   	models.Rental
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /rentals/active [get]
+func New(s ActiveRentalGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rental.active.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		rental, err := s.Active(r.Context(), userID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, rental)
+	}
+}
@@ -0,0 +1,82 @@
+package active_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/active"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/active/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		mockError error
+		resp      resp
+	}{
+		{
+			name: "success",
+			resp: resp{Code: http.StatusOK},
+		},
+		{
+			name:      "no active ride",
+			mockError: service.ErrRentalNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrRentalNotFound.Error()},
+		},
+		{
+			name:      "internal error",
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewActiveRentalGetter(t)
+
+			var rental *models.Rental
+			if tc.mockError == nil {
+				rental = &models.Rental{ID: 10, UserID: 1, Status: models.RentalStatusActive}
+			}
+			getterMock.On("Active", mock.Anything, uint64(1)).Return(rental, tc.mockError).Once()
+
+			handler := active.New(getterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/rentals/active", nil)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Rental
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, models.RentalStatusActive, resp.Status)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ActiveRentalGetter is an autogenerated mock type for the ActiveRentalGetter type
+type ActiveRentalGetter struct {
+	mock.Mock
+}
+
+// Active provides a mock function with given fields: ctx, userID
+func (_m *ActiveRentalGetter) Active(ctx context.Context, userID uint64) (*models.Rental, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Active")
+	}
+
+	var r0 *models.Rental
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Rental, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Rental); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rental)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewActiveRentalGetter creates a new instance of ActiveRentalGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewActiveRentalGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ActiveRentalGetter {
+	mock := &ActiveRentalGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
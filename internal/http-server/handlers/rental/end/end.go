@@ -0,0 +1,94 @@
+package end
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	StationID uint64 `json:"station_id"`
+}
+
+//go:generate mockery --name=RentalEnder
+type RentalEnder interface {
+	End(ctx context.Context, userID, rentalID, stationID uint64) (*models.Rental, error)
+}
+
+// New returns end rental handler
+//
+//	@Summary      End ride
+//	@Description  return the bicycle at any station and get the ride cost
+//	@Tags         rentals
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id      path 		int     true "Rental ID"
+//	@Param        request body 		Request true "Return station"
+//	@Success      200  {object}   	models.Rental
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /rentals/{id}/end [post]
+func New(s RentalEnder, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rental.end.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid rental id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid rental id"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+		if req.StationID == 0 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid station id"))
+			return
+		}
+
+		rental, err := s.End(r.Context(), userID, id, req.StationID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("rental ended", slog.Uint64("id", id), slog.Uint64("station_id", req.StationID))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, rental)
+	}
+}
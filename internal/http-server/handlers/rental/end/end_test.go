@@ -0,0 +1,113 @@
+package end_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/end"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/end/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		input     string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "10",
+			input:    `{"station_id": 4}`,
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name:  "invalid id",
+			id:    "last",
+			input: `{"station_id": 4}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid rental id"},
+		},
+		{
+			name:  "missing station",
+			id:    "10",
+			input: `{}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid station id"},
+		},
+		{
+			name:      "not found",
+			id:        "10",
+			input:     `{"station_id": 4}`,
+			mockCall:  true,
+			mockError: service.ErrRentalNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrRentalNotFound.Error()},
+		},
+		{
+			name:      "already ended",
+			id:        "10",
+			input:     `{"station_id": 4}`,
+			mockCall:  true,
+			mockError: service.ErrRentalNotActive,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrRentalNotActive.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			enderMock := mocks.NewRentalEnder(t)
+
+			if tc.mockCall {
+				var rental *models.Rental
+				if tc.mockError == nil {
+					rental = &models.Rental{ID: 10, Status: models.RentalStatusCompleted, StationEndID: util.Ptr(uint64(4)), TotalCost: util.Ptr(int64(4000))}
+				}
+				enderMock.On("End", mock.Anything, uint64(1), uint64(10), uint64(4)).Return(rental, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Post("/rentals/{id}/end", end.New(enderMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodPost, "/rentals/"+tc.id+"/end", bytes.NewReader([]byte(tc.input)))
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Rental
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, models.RentalStatusCompleted, resp.Status)
+				assert.Equal(t, int64(4000), *resp.TotalCost)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// RentalEnder is an autogenerated mock type for the RentalEnder type
+type RentalEnder struct {
+	mock.Mock
+}
+
+// End provides a mock function with given fields: ctx, userID, rentalID, stationID
+func (_m *RentalEnder) End(ctx context.Context, userID uint64, rentalID uint64, stationID uint64) (*models.Rental, error) {
+	ret := _m.Called(ctx, userID, rentalID, stationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 *models.Rental
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) (*models.Rental, error)); ok {
+		return rf(ctx, userID, rentalID, stationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) *models.Rental); ok {
+		r0 = rf(ctx, userID, rentalID, stationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rental)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, rentalID, stationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRentalEnder creates a new instance of RentalEnder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRentalEnder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RentalEnder {
+	mock := &RentalEnder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
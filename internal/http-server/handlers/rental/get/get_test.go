@@ -0,0 +1,95 @@
+package get_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/get/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			id:       "10",
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name: "invalid id",
+			id:   "last",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid rental id"},
+		},
+		{
+			name:      "not found",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrRentalNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrRentalNotFound.Error()},
+		},
+		{
+			name:      "internal error",
+			id:        "10",
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewRentalGetter(t)
+
+			if tc.mockCall {
+				var rental *models.Rental
+				if tc.mockError == nil {
+					rental = &models.Rental{ID: 10, UserID: 1}
+				}
+				getterMock.On("ByID", mock.Anything, uint64(1), uint64(10)).Return(rental, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/rentals/{id}", get.New(getterMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodGet, "/rentals/"+tc.id, nil)
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// RentalGetter is an autogenerated mock type for the RentalGetter type
+type RentalGetter struct {
+	mock.Mock
+}
+
+// ByID provides a mock function with given fields: ctx, userID, rentalID
+func (_m *RentalGetter) ByID(ctx context.Context, userID uint64, rentalID uint64) (*models.Rental, error) {
+	ret := _m.Called(ctx, userID, rentalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ByID")
+	}
+
+	var r0 *models.Rental
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*models.Rental, error)); ok {
+		return rf(ctx, userID, rentalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *models.Rental); ok {
+		r0 = rf(ctx, userID, rentalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rental)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, rentalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRentalGetter creates a new instance of RentalGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRentalGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RentalGetter {
+	mock := &RentalGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,24 @@
+package rental
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/active"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/end"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/start"
+	rental_service "sdt-bicycle-rental/internal/service/rental"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RentalRoute(log *slog.Logger, rentalService *rental_service.RentalService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Use(authenticate)
+
+		r.Post("/", start.New(rentalService, log))
+		r.Get("/active", active.New(rentalService, log))
+		r.Get("/{id}", get.New(rentalService, log))
+		r.Post("/{id}/end", end.New(rentalService, log))
+	}
+}
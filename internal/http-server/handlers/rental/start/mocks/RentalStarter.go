@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RentalStarter is an autogenerated mock type for the RentalStarter type
+type RentalStarter struct {
+	mock.Mock
+}
+
+// Start provides a mock function with given fields: ctx, userID, bicycleID, stationID
+func (_m *RentalStarter) Start(ctx context.Context, userID uint64, bicycleID uint64, stationID uint64) (*models.Rental, error) {
+	ret := _m.Called(ctx, userID, bicycleID, stationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 *models.Rental
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) (*models.Rental, error)); ok {
+		return rf(ctx, userID, bicycleID, stationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) *models.Rental); ok {
+		r0 = rf(ctx, userID, bicycleID, stationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rental)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, bicycleID, stationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRentalStarter creates a new instance of RentalStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRentalStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RentalStarter {
+	mock := &RentalStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+package start
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	BicycleID uint64 `json:"bicycle_id"`
+	StationID uint64 `json:"station_id"`
+}
+
+//go:generate mockery --name=RentalStarter
+type RentalStarter interface {
+	Start(ctx context.Context, userID, bicycleID, stationID uint64) (*models.Rental, error)
+}
+
+// New returns start rental handler
+//
+//	@Summary      Start ride
+//	@Description  rent an available bicycle at a station
+//	@Tags         rentals
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Bicycle and station"
+//	@Success      201  {object}   	models.Rental
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /rentals [post]
+func New(s RentalStarter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.rental.start.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+		if req.BicycleID == 0 || req.StationID == 0 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("bicycle_id and station_id are required"))
+			return
+		}
+
+		rental, err := s.Start(r.Context(), userID, req.BicycleID, req.StationID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("rental started", slog.Uint64("id", rental.ID), slog.Uint64("bicycle_id", req.BicycleID))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, rental)
+	}
+}
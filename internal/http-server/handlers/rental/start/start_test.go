@@ -0,0 +1,106 @@
+package start_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/start"
+	"sdt-bicycle-rental/internal/http-server/handlers/rental/start/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		input     string
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			input:    `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall: true,
+			resp:     resp{Code: http.StatusCreated},
+		},
+		{
+			name:  "missing station",
+			input: `{"bicycle_id": 2}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "bicycle_id and station_id are required"},
+		},
+		{
+			name:      "bicycle taken",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrBicycleUnavailable,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrBicycleUnavailable.Error()},
+		},
+		{
+			name:      "ride in progress",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrRentalInProgress,
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrRentalInProgress.Error()},
+		},
+		{
+			name:      "internal error",
+			input:     `{"bicycle_id": 2, "station_id": 3}`,
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			starterMock := mocks.NewRentalStarter(t)
+
+			if tc.mockCall {
+				var rental *models.Rental
+				if tc.mockError == nil {
+					rental = &models.Rental{ID: 10, UserID: 1, BicycleID: 2, StationStartID: 3, Status: models.RentalStatusActive}
+				}
+				starterMock.On("Start", mock.Anything, uint64(1), uint64(2), uint64(3)).Return(rental, tc.mockError).Once()
+			}
+
+			handler := start.New(starterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/rentals", bytes.NewReader([]byte(tc.input)))
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 1}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp models.Rental
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(10), resp.ID)
+				assert.Nil(t, resp.EndTime)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package create
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	LocationStreet string `json:"location_street"`
+}
+
+//go:generate mockery --name=StationCreator
+type StationCreator interface {
+	Create(ctx context.Context, station *models.Station) (*models.Station, error)
+}
+
+// New returns create station handler
+//
+//	@Summary      Create station
+//	@Description  create a station, admins only
+//	@Tags         stations
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Station data"
+//	@Success      201  {object}   	models.Station
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /stations [post]
+func New(s StationCreator, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.station.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		station, err := s.Create(r.Context(), &models.Station{LocationStreet: req.LocationStreet})
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("station created", slog.Uint64("id", station.ID))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, station)
+	}
+}
@@ -0,0 +1,91 @@
+package create_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/create"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/create/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		location  string
+		resp      resp
+		mockError error
+	}{
+		{
+			name:     "success",
+			location: "some street 8, house 4",
+			resp:     resp{Code: http.StatusCreated},
+		},
+		{
+			name:      "invalid location",
+			location:  "some",
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockError: service.Invalid(),
+		},
+		{
+			name:      "internal error",
+			location:  "some street 8, house 4",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			creatorMock := mocks.NewStationCreator(t)
+
+			arg := &models.Station{LocationStreet: tc.location}
+			var created *models.Station
+			if tc.mockError == nil {
+				created = &models.Station{ID: 1, LocationStreet: tc.location}
+			}
+			creatorMock.On("Create", mock.Anything, arg).Return(created, tc.mockError).Once()
+
+			handler := create.New(creatorMock, slogdiscard.NewDiscardLogger())
+
+			input := fmt.Sprintf(`{"location_street": "%s"}`, tc.location)
+
+			req, err := http.NewRequest(http.MethodPost, "/stations", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp models.Station
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(1), resp.ID)
+				assert.Equal(t, tc.location, resp.LocationStreet)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
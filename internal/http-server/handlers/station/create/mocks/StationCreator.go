@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// StationCreator is an autogenerated mock type for the StationCreator type
+type StationCreator struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, station
+func (_m *StationCreator) Create(ctx context.Context, station *models.Station) (*models.Station, error) {
+	ret := _m.Called(ctx, station)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *models.Station
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Station) (*models.Station, error)); ok {
+		return rf(ctx, station)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Station) *models.Station); ok {
+		r0 = rf(ctx, station)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Station)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Station) error); ok {
+		r1 = rf(ctx, station)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStationCreator creates a new instance of StationCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStationCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StationCreator {
+	mock := &StationCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
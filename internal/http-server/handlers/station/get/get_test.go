@@ -0,0 +1,96 @@
+package get_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/get/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name        string
+		id          string
+		resp        resp
+		mockCall    bool
+		mockStation *models.Station
+		mockError   error
+	}{
+		{
+			name:        "success",
+			id:          "1",
+			resp:        resp{Code: http.StatusOK},
+			mockCall:    true,
+			mockStation: &models.Station{ID: 1, LocationStreet: "some street 8, house 4", BikesTotal: 3},
+		},
+		{
+			name: "invalid id",
+			id:   "one",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid station id"},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrStationNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrStationNotFound,
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewStationGetter(t)
+
+			if tc.mockCall {
+				getterMock.On("ByID", mock.Anything, uint64(1)).Return(tc.mockStation, tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/stations/{id}", get.New(getterMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodGet, "/stations/"+tc.id, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Station
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, *tc.mockStation, resp)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
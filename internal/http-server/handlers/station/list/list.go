@@ -0,0 +1,85 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	station_service "sdt-bicycle-rental/internal/service/station"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type SuccessResponse struct {
+	Stations []models.Station `json:"stations"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+//go:generate mockery --name=StationLister
+type StationLister interface {
+	List(ctx context.Context, page, limit int) ([]models.Station, int64, error)
+}
+
+// New returns list stations handler
+//
+//	@Summary      List stations
+//	@Description  list stations page by page
+//	@Tags         stations
+//	@Produce      json
+//	@Param        page  query 		int false "Page number, starts from 1" default(1)
+//	@Param        limit query 		int false "Page size, at most 100" default(20)
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /stations [get]
+func New(s StationLister, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.station.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		page, err := queryInt(r, "page", 1)
+		if err != nil || page < 1 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid page"))
+			return
+		}
+		limit, err := queryInt(r, "limit", station_service.DefaultPageSize)
+		if err != nil || limit < 1 || limit > station_service.MaxPageSize {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid limit"))
+			return
+		}
+
+		stations, total, err := s.List(r.Context(), page, limit)
+		if err != nil {
+			log.Error("failed to list stations", sl.Err(err))
+
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if stations == nil {
+			stations = []models.Station{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{Stations: stations, Page: page, Limit: limit, Total: total})
+	}
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,109 @@
+package list_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/list/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name         string
+		query        string
+		page         int
+		limit        int
+		resp         resp
+		mockCall     bool
+		mockStations []models.Station
+		mockTotal    int64
+		mockError    error
+	}{
+		{
+			name:         "success",
+			query:        "?page=2&limit=5",
+			page:         2,
+			limit:        5,
+			resp:         resp{Code: http.StatusOK},
+			mockCall:     true,
+			mockStations: []models.Station{{ID: 6, LocationStreet: "some street 8, house 4"}},
+			mockTotal:    6,
+		},
+		{
+			name:     "defaults",
+			page:     1,
+			limit:    20,
+			resp:     resp{Code: http.StatusOK},
+			mockCall: true,
+		},
+		{
+			name:  "invalid page",
+			query: "?page=zero",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid page"},
+		},
+		{
+			name:  "limit too big",
+			query: "?limit=1000",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid limit"},
+		},
+		{
+			name:      "internal error",
+			page:      1,
+			limit:     20,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewStationLister(t)
+
+			if tc.mockCall {
+				listerMock.On("List", mock.Anything, tc.page, tc.limit).Return(tc.mockStations, tc.mockTotal, tc.mockError).Once()
+			}
+
+			handler := list.New(listerMock, slogdiscard.NewDiscardLogger())
+
+			req, err := http.NewRequest(http.MethodGet, "/stations"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp list.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Len(t, resp.Stations, len(tc.mockStations))
+				assert.Equal(t, tc.page, resp.Page)
+				assert.Equal(t, tc.limit, resp.Limit)
+				assert.Equal(t, tc.mockTotal, resp.Total)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
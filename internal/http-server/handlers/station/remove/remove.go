@@ -0,0 +1,62 @@
+package remove
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=StationDeleter
+type StationDeleter interface {
+	Delete(ctx context.Context, id uint64) error
+}
+
+// New returns delete station handler
+//
+//	@Summary      Delete station
+//	@Description  delete a station without bicycles, admins only
+//	@Tags         stations
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path 		int true "Station ID"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /stations/{id} [delete]
+func New(s StationDeleter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.station.remove.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid station id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid station id"))
+			return
+		}
+
+		if err := s.Delete(r.Context(), id); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("station deleted", slog.Uint64("id", id))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,96 @@
+package remove_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/remove"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/remove/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name: "invalid id",
+			id:   "1.5",
+			resp: resp{Code: http.StatusBadRequest, Error: "invalid station id"},
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrStationNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrStationNotFound,
+		},
+		{
+			name:      "station has bicycles",
+			id:        "1",
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrStationInUse.Error()},
+			mockCall:  true,
+			mockError: service.ErrStationInUse,
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deleterMock := mocks.NewStationDeleter(t)
+
+			if tc.mockCall {
+				deleterMock.On("Delete", mock.Anything, uint64(1)).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/stations/{id}", remove.New(deleterMock, slogdiscard.NewDiscardLogger()))
+
+			req, err := http.NewRequest(http.MethodDelete, "/stations/"+tc.id, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package station
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/create"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/remove"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/update"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	station_service "sdt-bicycle-rental/internal/service/station"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func StationRoute(log *slog.Logger, stationService *station_service.StationService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", list.New(stationService, log))
+		r.Get("/{id}", get.New(stationService, log))
+
+		r.Group(func(r chi.Router) {
+			r.Use(authenticate)
+			r.Use(jwtauth.RequirePermission(access_service.PermManageStations))
+
+			r.Post("/", create.New(stationService, log))
+			r.Patch("/{id}", update.New(stationService, log))
+			r.Delete("/{id}", remove.New(stationService, log))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StationLocationUpdater is an autogenerated mock type for the StationLocationUpdater type
+type StationLocationUpdater struct {
+	mock.Mock
+}
+
+// UpdateLocation provides a mock function with given fields: ctx, id, location
+func (_m *StationLocationUpdater) UpdateLocation(ctx context.Context, id uint64, location string) error {
+	ret := _m.Called(ctx, id, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, location)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStationLocationUpdater creates a new instance of StationLocationUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStationLocationUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StationLocationUpdater {
+	mock := &StationLocationUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
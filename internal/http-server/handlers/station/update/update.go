@@ -0,0 +1,78 @@
+package update
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	LocationStreet string `json:"location_street"`
+}
+
+//go:generate mockery --name=StationLocationUpdater
+type StationLocationUpdater interface {
+	UpdateLocation(ctx context.Context, id uint64, location string) error
+}
+
+// New returns update station location handler
+//
+//	@Summary      Update station location
+//	@Description  move a station to another street, admins only
+//	@Tags         stations
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id      path 		int     true "Station ID"
+//	@Param        request body 		Request true "Station location"
+//	@Success      204
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /stations/{id} [patch]
+func New(s StationLocationUpdater, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.station.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid station id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid station id"))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		if err := s.UpdateLocation(r.Context(), id, req.LocationStreet); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("station location updated", slog.Uint64("id", id))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,106 @@
+package update_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/update"
+	"sdt-bicycle-rental/internal/http-server/handlers/station/update/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		id        string
+		location  string
+		resp      resp
+		mockCall  bool
+		mockError error
+	}{
+		{
+			name:     "success",
+			id:       "1",
+			location: "another street 15",
+			resp:     resp{Code: http.StatusNoContent},
+			mockCall: true,
+		},
+		{
+			name:     "invalid id",
+			id:       "first",
+			location: "another street 15",
+			resp:     resp{Code: http.StatusBadRequest, Error: "invalid station id"},
+		},
+		{
+			name:      "invalid location",
+			id:        "1",
+			location:  "st",
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockCall:  true,
+			mockError: service.Invalid(),
+		},
+		{
+			name:      "not found",
+			id:        "1",
+			location:  "another street 15",
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrStationNotFound.Error()},
+			mockCall:  true,
+			mockError: service.ErrStationNotFound,
+		},
+		{
+			name:      "internal error",
+			id:        "1",
+			location:  "another street 15",
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			updaterMock := mocks.NewStationLocationUpdater(t)
+
+			if tc.mockCall {
+				updaterMock.On("UpdateLocation", mock.Anything, uint64(1), tc.location).Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Patch("/stations/{id}", update.New(updaterMock, slogdiscard.NewDiscardLogger()))
+
+			input := fmt.Sprintf(`{"location_street": "%s"}`, tc.location)
+
+			req, err := http.NewRequest(http.MethodPatch, "/stations/"+tc.id, bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
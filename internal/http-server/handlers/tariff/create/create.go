@@ -0,0 +1,70 @@
+package create
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Tariff dto.CreateTariff `json:"tariff"`
+}
+
+//go:generate mockery --name=TariffCreator
+type TariffCreator interface {
+	Create(ctx context.Context, tariff *dto.CreateTariff) (*models.Tariff, error)
+}
+
+// New returns create tariff handler
+//
+//	@Summary      Create tariff
+//	@Description  publish a new tariff version, it applies to rides started from active_from on (now by default), admins only
+//	@Tags         tariffs
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Tariff data, amounts in cents"
+//	@Success      201  {object}   	models.Tariff
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /tariffs [post]
+func New(s TariffCreator, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tariff.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		tariff, err := s.Create(r.Context(), &req.Tariff)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("tariff created", slog.Uint64("id", tariff.ID), slog.Time("active_from", *tariff.ActiveFrom))
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, tariff)
+	}
+}
@@ -0,0 +1,105 @@
+package create_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/create"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/create/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		tariff    dto.CreateTariff
+		mockError error
+		resp      resp
+	}{
+		{
+			name: "success",
+			tariff: dto.CreateTariff{
+				Name: "summer", UnlockFee: 1000, PerMinute: 200, DailyCap: 30000,
+				Rates: []dto.CreateTariffRate{{BicycleType: models.BicycleTypeElectric, StartHour: 7, EndHour: 10, PerMinute: 350}},
+			},
+			resp: resp{Code: http.StatusCreated},
+		},
+		{
+			name:      "negative price",
+			tariff:    dto.CreateTariff{Name: "broken", PerMinute: -1},
+			mockError: service.Invalid(),
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+		},
+		{
+			name:      "activation in the past",
+			tariff:    dto.CreateTariff{Name: "late", PerMinute: 200},
+			mockError: service.ErrTariffActiveFromPast,
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrTariffActiveFromPast.Error()},
+		},
+		{
+			name:      "internal error",
+			tariff:    dto.CreateTariff{Name: "summer", PerMinute: 200},
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			creatorMock := mocks.NewTariffCreator(t)
+
+			var tariff *models.Tariff
+			if tc.mockError == nil {
+				tariff = tc.tariff.Model()
+				tariff.ID = 2
+				tariff.ActiveFrom = util.Ptr(time.Now())
+			}
+			creatorMock.On("Create", mock.Anything, &tc.tariff).Return(tariff, tc.mockError).Once()
+
+			handler := create.New(creatorMock, slogdiscard.NewDiscardLogger())
+
+			input, err := json.Marshal(create.Request{Tariff: tc.tariff})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/tariffs", bytes.NewReader(input))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusCreated {
+				var resp models.Tariff
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(2), resp.ID)
+				assert.Equal(t, tc.tariff.Name, resp.Name)
+				assert.Len(t, resp.Rates, len(tc.tariff.Rates))
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "sdt-bicycle-rental/internal/repository/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// TariffCreator is an autogenerated mock type for the TariffCreator type
+type TariffCreator struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tariff
+func (_m *TariffCreator) Create(ctx context.Context, tariff *dto.CreateTariff) (*models.Tariff, error) {
+	ret := _m.Called(ctx, tariff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *models.Tariff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateTariff) (*models.Tariff, error)); ok {
+		return rf(ctx, tariff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CreateTariff) *models.Tariff); ok {
+		r0 = rf(ctx, tariff)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tariff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CreateTariff) error); ok {
+		r1 = rf(ctx, tariff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTariffCreator creates a new instance of TariffCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTariffCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TariffCreator {
+	mock := &TariffCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
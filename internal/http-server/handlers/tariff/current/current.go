@@ -0,0 +1,48 @@
+package current
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=CurrentTariffGetter
+type CurrentTariffGetter interface {
+	Current(ctx context.Context, now time.Time) (*models.Tariff, error)
+}
+
+// New returns current tariff handler
+//
+//	@Summary      Current tariff
+//	@Description  get the tariff new rides are priced under, amounts are in cents
+//	@Tags         tariffs
+//	@Produce      json
+//	@Success      200  {object}   	models.Tariff
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /tariffs/current [get]
+func New(s CurrentTariffGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tariff.current.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		tariff, err := s.Current(r.Context(), time.Now())
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, tariff)
+	}
+}
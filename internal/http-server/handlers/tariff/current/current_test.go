@@ -0,0 +1,81 @@
+package current_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/current"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/current/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrentHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		mockError error
+		resp      resp
+	}{
+		{
+			name: "success",
+			resp: resp{Code: http.StatusOK},
+		},
+		{
+			name:      "no tariff",
+			mockError: service.ErrTariffNotFound,
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrTariffNotFound.Error()},
+		},
+		{
+			name:      "internal error",
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewCurrentTariffGetter(t)
+
+			var tariff *models.Tariff
+			if tc.mockError == nil {
+				tariff = &models.Tariff{ID: 3, Name: "summer", UnlockFee: 1000, PerMinute: 200}
+			}
+			getterMock.On("Current", mock.Anything, mock.AnythingOfType("time.Time")).Return(tariff, tc.mockError).Once()
+
+			handler := current.New(getterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/tariffs/current", nil)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp models.Tariff
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(3), resp.ID)
+				assert.Equal(t, int64(200), resp.PerMinute)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
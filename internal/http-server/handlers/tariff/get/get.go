@@ -0,0 +1,60 @@
+package get
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=TariffGetter
+type TariffGetter interface {
+	ByID(ctx context.Context, id uint64) (*models.Tariff, error)
+}
+
+// New returns get tariff handler
+//
+//	@Summary      Get tariff
+//	@Description  get a tariff version by ID, e.g. the one a rental was priced under
+//	@Tags         tariffs
+//	@Produce      json
+//	@Param        id   path 		int true "Tariff ID"
+//	@Success      200  {object}   	models.Tariff
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /tariffs/{id} [get]
+func New(s TariffGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tariff.get.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Info("invalid tariff id", slog.String("id", chi.URLParam(r, "id")))
+
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid tariff id"))
+			return
+		}
+
+		tariff, err := s.ByID(r.Context(), id)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, tariff)
+	}
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// TariffGetter is an autogenerated mock type for the TariffGetter type
+type TariffGetter struct {
+	mock.Mock
+}
+
+// ByID provides a mock function with given fields: ctx, id
+func (_m *TariffGetter) ByID(ctx context.Context, id uint64) (*models.Tariff, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ByID")
+	}
+
+	var r0 *models.Tariff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Tariff, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Tariff); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tariff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTariffGetter creates a new instance of TariffGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTariffGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TariffGetter {
+	mock := &TariffGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
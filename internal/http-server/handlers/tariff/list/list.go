@@ -0,0 +1,88 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type SuccessResponse struct {
+	Tariffs []models.Tariff `json:"tariffs"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+	Total   int64           `json:"total"`
+}
+
+//go:generate mockery --name=TariffLister
+type TariffLister interface {
+	List(ctx context.Context, page, limit int) ([]models.Tariff, int64, error)
+}
+
+// New returns list tariffs handler
+//
+//	@Summary      List tariffs
+//	@Description  list all tariff versions newest first, admins only
+//	@Tags         tariffs
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        page       query 	int    false "Page number, starts from 1" default(1)
+//	@Param        limit      query 	int    false "Page size, at most 100" default(20)
+//	@Success      200  {object}   	SuccessResponse
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      403  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /tariffs [get]
+func New(s TariffLister, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tariff.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		page, err := queryInt(r, "page", 1)
+		if err != nil || page < 1 {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid page"))
+			return
+		}
+		limit, err := queryInt(r, "limit", pricing_service.DefaultPageSize)
+		if err != nil || limit < 1 || limit > pricing_service.MaxPageSize {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid limit"))
+			return
+		}
+
+		tariffs, total, err := s.List(r.Context(), page, limit)
+		if err != nil {
+			log.Error("failed to list tariffs", sl.Err(err))
+
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		if tariffs == nil {
+			tariffs = []models.Tariff{}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, SuccessResponse{Tariffs: tariffs, Page: page, Limit: limit, Total: total})
+	}
+}
+
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
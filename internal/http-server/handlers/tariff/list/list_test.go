@@ -0,0 +1,105 @@
+package list_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/list/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		page      int
+		limit     int
+		mockCall  bool
+		mockError error
+		resp      resp
+	}{
+		{
+			name:     "success",
+			query:    "?page=2&limit=5",
+			page:     2,
+			limit:    5,
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name:     "defaults",
+			page:     1,
+			limit:    20,
+			mockCall: true,
+			resp:     resp{Code: http.StatusOK},
+		},
+		{
+			name:  "invalid page",
+			query: "?page=0",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid page"},
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=1000",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid limit"},
+		},
+		{
+			name:      "internal error",
+			page:      1,
+			limit:     20,
+			mockCall:  true,
+			mockError: service.ErrInternalError,
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewTariffLister(t)
+
+			if tc.mockCall {
+				listerMock.On("List", mock.Anything, tc.page, tc.limit).
+					Return([]models.Tariff{{ID: 2, Name: "summer"}}, int64(6), tc.mockError).Once()
+			}
+
+			handler := list.New(listerMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/tariffs"+tc.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp list.SuccessResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Len(t, resp.Tariffs, 1)
+				assert.Equal(t, tc.page, resp.Page)
+				assert.Equal(t, tc.limit, resp.Limit)
+				assert.Equal(t, int64(6), resp.Total)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RideQuoter is an autogenerated mock type for the RideQuoter type
+type RideQuoter struct {
+	mock.Mock
+}
+
+// Quote provides a mock function with given fields: ctx, bicycleType, minutes
+func (_m *RideQuoter) Quote(ctx context.Context, bicycleType string, minutes int) (*pricing_service.Quote, error) {
+	ret := _m.Called(ctx, bicycleType, minutes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Quote")
+	}
+
+	var r0 *pricing_service.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*pricing_service.Quote, error)); ok {
+		return rf(ctx, bicycleType, minutes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *pricing_service.Quote); ok {
+		r0 = rf(ctx, bicycleType, minutes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pricing_service.Quote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, bicycleType, minutes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRideQuoter creates a new instance of RideQuoter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRideQuoter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RideQuoter {
+	mock := &RideQuoter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+package quote
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=RideQuoter
+type RideQuoter interface {
+	Quote(ctx context.Context, bicycleType string, minutes int) (*pricing_service.Quote, error)
+}
+
+// New returns quote handler
+//
+//	@Summary      Quote ride
+//	@Description  estimate the cost in cents of a ride of the given length starting now
+//	@Tags         tariffs
+//	@Produce      json
+//	@Param        bicycle_type query 	string true "Bicycle type" Enums(standard, electric)
+//	@Param        minutes      query 	int    true "Ride length in minutes, at most a week"
+//	@Success      200  {object}   	pricing_service.Quote
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /tariffs/quote [get]
+func New(s RideQuoter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tariff.quote.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		minutes, err := strconv.Atoi(r.URL.Query().Get("minutes"))
+		if err != nil || minutes < 0 || minutes > pricing_service.MaxQuoteMinutes {
+			problem.Render(w, r, log, service.ErrInvalidInput.WithDetail("invalid minutes"))
+			return
+		}
+
+		quote, err := s.Quote(r.Context(), r.URL.Query().Get("bicycle_type"), minutes)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, quote)
+	}
+}
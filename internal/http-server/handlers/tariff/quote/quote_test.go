@@ -0,0 +1,119 @@
+package quote_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/quote"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/quote/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name        string
+		query       string
+		bicycleType string
+		minutes     int
+		mockCall    bool
+		mockError   error
+		resp        resp
+	}{
+		{
+			name:        "success",
+			query:       "?bicycle_type=electric&minutes=30",
+			bicycleType: "electric",
+			minutes:     30,
+			mockCall:    true,
+			resp:        resp{Code: http.StatusOK},
+		},
+		{
+			name:  "missing minutes",
+			query: "?bicycle_type=electric",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid minutes"},
+		},
+		{
+			name:  "too long ride",
+			query: "?bicycle_type=electric&minutes=100000",
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid minutes"},
+		},
+		{
+			name:        "unknown bicycle type",
+			query:       "?bicycle_type=tandem&minutes=30",
+			bicycleType: "tandem",
+			minutes:     30,
+			mockCall:    true,
+			mockError:   service.ErrInvalidBicycleType,
+			resp:        resp{Code: http.StatusBadRequest, Error: service.ErrInvalidBicycleType.Error()},
+		},
+		{
+			name:        "no tariff",
+			query:       "?bicycle_type=standard&minutes=30",
+			bicycleType: "standard",
+			minutes:     30,
+			mockCall:    true,
+			mockError:   service.ErrTariffNotFound,
+			resp:        resp{Code: http.StatusNotFound, Error: service.ErrTariffNotFound.Error()},
+		},
+		{
+			name:        "internal error",
+			query:       "?bicycle_type=standard&minutes=30",
+			bicycleType: "standard",
+			minutes:     30,
+			mockCall:    true,
+			mockError:   service.ErrInternalError,
+			resp:        resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			quoterMock := mocks.NewRideQuoter(t)
+
+			if tc.mockCall {
+				var q *pricing_service.Quote
+				if tc.mockError == nil {
+					q = &pricing_service.Quote{TariffID: 2, BicycleType: tc.bicycleType, Minutes: tc.minutes, Amount: 7000, Currency: "UAH"}
+				}
+				quoterMock.On("Quote", mock.Anything, tc.bicycleType, tc.minutes).Return(q, tc.mockError).Once()
+			}
+
+			handler := quote.New(quoterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/tariffs/quote"+tc.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp pricing_service.Quote
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, uint64(2), resp.TariffID)
+				assert.Equal(t, int64(7000), resp.Amount)
+				assert.Equal(t, "UAH", resp.Currency)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package tariff
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/create"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/current"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/get"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/tariff/quote"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TariffRoute(log *slog.Logger, pricingService *pricing_service.PricingService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/current", current.New(pricingService, log))
+		r.Get("/quote", quote.New(pricingService, log))
+		r.Get("/{id}", get.New(pricingService, log))
+
+		r.Group(func(r chi.Router) {
+			r.Use(authenticate)
+			r.Use(jwtauth.RequirePermission(access_service.PermManageTariffs))
+
+			r.Get("/", list.New(pricingService, log))
+			r.Post("/", create.New(pricingService, log))
+		})
+	}
+}
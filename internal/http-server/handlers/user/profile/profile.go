@@ -0,0 +1,58 @@
+package profile
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate mockery --name=ProfileGetter
+type ProfileGetter interface {
+	ProfileByID(ctx context.Context, id uint64) (*models.User, error)
+}
+
+// New returns current user profile handler
+//
+//	@Summary      Get profile
+//	@Description  get the current user with recent bookings, payments and rentals
+//	@Tags         users
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Success      200  {object}   	models.User
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /users/me [get]
+func New(s ProfileGetter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.profile.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		user, err := s.ProfileByID(r.Context(), userID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, user)
+	}
+}
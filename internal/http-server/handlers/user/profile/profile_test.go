@@ -0,0 +1,102 @@
+package profile_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/profile"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/profile/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		principal *jwtauth.Principal
+		user      *models.User
+		resp      resp
+		mockError error
+	}{
+		{
+			name:      "success",
+			principal: &jwtauth.Principal{UserID: 7},
+			user: &models.User{
+				ID:       7,
+				Name:     util.Ptr("John"),
+				Password: util.Ptr("$2a$10$hash"),
+				Rentals:  []models.Rental{{ID: 1, UserID: 7}},
+			},
+			resp: resp{Code: http.StatusOK},
+		},
+		{
+			name: "unauthenticated",
+			resp: resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "not found",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUserNotFound.Error()},
+			mockError: service.ErrUserNotFound,
+		},
+		{
+			name:      "internal error",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getterMock := mocks.NewProfileGetter(t)
+
+			if tc.principal != nil {
+				getterMock.On("ProfileByID", mock.Anything, tc.principal.UserID).Return(tc.user, tc.mockError).Once()
+			}
+
+			handler := profile.New(getterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				assert.NotContains(t, rr.Body.String(), "password")
+				assert.NotContains(t, rr.Body.String(), *tc.user.Password)
+
+				var resp models.User
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tc.user.ID, resp.ID)
+				assert.Len(t, resp.Rentals, 1)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package remove
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=UserDeleter
+type UserDeleter interface {
+	Delete(ctx context.Context, id uint64) error
+}
+
+// New returns current user delete handler
+//
+//	@Summary      Delete account
+//	@Description  anonymize the current user and mark the account as deleted
+//	@Tags         users
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Success      204
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /users/me [delete]
+func New(s UserDeleter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.remove.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		if err := s.Delete(r.Context(), userID); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("user deleted", slog.Uint64("id", userID))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
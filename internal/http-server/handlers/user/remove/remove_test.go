@@ -0,0 +1,85 @@
+package remove_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/remove"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/remove/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		principal *jwtauth.Principal
+		resp      resp
+		mockError error
+	}{
+		{
+			name:      "success",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusNoContent},
+		},
+		{
+			name: "unauthenticated",
+			resp: resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "not found",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrUserNotFound.Error()},
+			mockError: service.ErrUserNotFound,
+		},
+		{
+			name:      "internal error",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deleterMock := mocks.NewUserDeleter(t)
+
+			if tc.principal != nil {
+				deleterMock.On("Delete", mock.Anything, tc.principal.UserID).Return(tc.mockError).Once()
+			}
+
+			handler := remove.New(deleterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/me", nil)
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
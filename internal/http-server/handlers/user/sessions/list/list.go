@@ -0,0 +1,67 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Session is a signed in device, Current marks the one making the request
+type Session struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+//go:generate mockery --name=SessionLister
+type SessionLister interface {
+	List(ctx context.Context, userID uint64) ([]models.Session, error)
+}
+
+// New returns current user sessions handler
+//
+//	@Summary      List sessions
+//	@Description  list the devices the current user is signed in on, last seen first
+//	@Tags         users
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Success      200  {array}   	Session
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /users/me/sessions [get]
+func New(s SessionLister, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.sessions.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := jwtauth.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		sessions, err := s.List(r.Context(), principal.UserID)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		resp := make([]Session, 0, len(sessions))
+		for _, session := range sessions {
+			resp = append(resp, Session{Session: session, Current: session.ID == principal.SessionID})
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,96 @@
+package list_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/list/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		principal *jwtauth.Principal
+		resp      resp
+		mockError error
+	}{
+		{
+			name:      "success",
+			principal: &jwtauth.Principal{UserID: 7, SessionID: "phone"},
+			resp:      resp{Code: http.StatusOK},
+		},
+		{
+			name: "unauthenticated",
+			resp: resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "internal error",
+			principal: &jwtauth.Principal{UserID: 7, SessionID: "phone"},
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewSessionLister(t)
+
+			if tc.principal != nil {
+				var sessions []models.Session
+				if tc.mockError == nil {
+					sessions = []models.Session{
+						{ID: "laptop", UserID: 7, DeviceName: util.Ptr("Laptop"), IP: util.Ptr("192.0.2.1")},
+						{ID: "phone", UserID: 7, DeviceName: util.Ptr("Pixel 8")},
+					}
+				}
+				listerMock.On("List", mock.Anything, tc.principal.UserID).Return(sessions, tc.mockError).Once()
+			}
+
+			handler := list.New(listerMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/users/me/sessions", nil)
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var resp []list.Session
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp, 2)
+				assert.Equal(t, "laptop", resp[0].ID)
+				assert.Equal(t, "Laptop", *resp[0].DeviceName)
+				assert.False(t, resp[0].Current)
+				assert.True(t, resp[1].Current)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package revoke
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate mockery --name=SessionRevoker
+type SessionRevoker interface {
+	Revoke(ctx context.Context, userID uint64, id string) error
+}
+
+// New returns revoke session handler
+//
+//	@Summary      Revoke session
+//	@Description  sign a device of the current user out, its refresh and access tokens stop working; revoking the current session logs out
+//	@Tags         users
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        id   path		string true "Session ID"
+//	@Success      204
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /users/me/sessions/{id} [delete]
+func New(s SessionRevoker, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.sessions.revoke.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		if err := s.Revoke(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("session revoked", slog.Uint64("id", userID))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
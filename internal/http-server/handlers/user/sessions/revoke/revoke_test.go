@@ -0,0 +1,81 @@
+package revoke_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/revoke"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/revoke/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		principal *jwtauth.Principal
+		resp      resp
+		mockError error
+	}{
+		{
+			name:      "success",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusNoContent},
+		},
+		{
+			name: "unauthenticated",
+			resp: resp{Code: http.StatusUnauthorized, Error: jwtauth.ErrMissingToken.Error()},
+		},
+		{
+			name:      "not found",
+			principal: &jwtauth.Principal{UserID: 7},
+			resp:      resp{Code: http.StatusNotFound, Error: service.ErrSessionNotFound.Error()},
+			mockError: service.ErrSessionNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			revokerMock := mocks.NewSessionRevoker(t)
+
+			if tc.principal != nil {
+				revokerMock.On("Revoke", mock.Anything, tc.principal.UserID, "laptop").Return(tc.mockError).Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/users/me/sessions/{id}", revoke.New(revokerMock, slogdiscard.NewDiscardLogger()))
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/me/sessions/laptop", nil)
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusNoContent {
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
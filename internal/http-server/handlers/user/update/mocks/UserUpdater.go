@@ -0,0 +1,61 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "sdt-bicycle-rental/internal/repository/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// UserUpdater is an autogenerated mock type for the UserUpdater type
+type UserUpdater struct {
+	mock.Mock
+}
+
+// Update provides a mock function with given fields: ctx, id, user
+func (_m *UserUpdater) Update(ctx context.Context, id uint64, user *dto.UpdateUser) (*models.User, error) {
+	ret := _m.Called(ctx, id, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *dto.UpdateUser) (*models.User, error)); ok {
+		return rf(ctx, id, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *dto.UpdateUser) *models.User); ok {
+		r0 = rf(ctx, id, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, *dto.UpdateUser) error); ok {
+		r1 = rf(ctx, id, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUserUpdater creates a new instance of UserUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserUpdater {
+	mock := &UserUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,80 @@
+package update
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	User dto.UpdateUser `json:"user"`
+}
+
+//go:generate mockery --name=UserUpdater
+type UserUpdater interface {
+	Update(ctx context.Context, id uint64, user *dto.UpdateUser) (*models.User, error)
+}
+
+// New returns current user update handler
+//
+//	@Summary      Update profile
+//	@Description  update name, lastname, email or phone of the current user, omitted fields are kept; a changed email or phone gets a new code and the account is pending until it is verified
+//	@Tags         users
+//	@Accept       json
+//	@Produce      json
+//	@Security     BearerAuth
+//	@Param        request body 		Request true "Fields to update"
+//	@Success      200  {object}   	models.User
+//	@Failure      400  {object}		problem.Problem
+//	@Failure      401  {object}		problem.Problem
+//	@Failure      404  {object}		problem.Problem
+//	@Failure      409  {object}		problem.Problem
+//	@Failure      500  {object}		problem.Problem
+//	@Router       /users/me [patch]
+func New(s UserUpdater, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := jwtauth.UserID(r.Context())
+		if !ok {
+			log.Error("no principal in context")
+
+			problem.Render(w, r, log, jwtauth.ErrMissingToken)
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			problem.Render(w, r, log, service.ErrInvalidInput)
+			return
+		}
+
+		user, err := s.Update(r.Context(), userID, &req.User)
+		if err != nil {
+			problem.Render(w, r, log, err)
+			return
+		}
+
+		log.Info("user updated", slog.Uint64("id", userID))
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, user)
+	}
+}
@@ -0,0 +1,109 @@
+package update_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/update"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/update/mocks"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	type resp struct {
+		Code  int
+		Error string
+	}
+
+	cases := []struct {
+		name      string
+		input     string
+		update    *dto.UpdateUser
+		resp      resp
+		mockError error
+	}{
+		{
+			name:   "success",
+			input:  `{"user": {"name": "Jane", "phone": "380501234567"}}`,
+			update: &dto.UpdateUser{Name: util.Ptr("Jane"), Phone: util.Ptr("380501234567")},
+			resp:   resp{Code: http.StatusOK},
+		},
+		{
+			name:  "invalid input",
+			input: `{"user": "Jane"}`,
+			resp:  resp{Code: http.StatusBadRequest, Error: "invalid input"},
+		},
+		{
+			name:      "invalid email",
+			input:     `{"user": {"email": "invalid@email"}}`,
+			update:    &dto.UpdateUser{Email: util.Ptr("invalid@email")},
+			resp:      resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error()},
+			mockError: service.Invalid(),
+		},
+		{
+			name:      "email taken",
+			input:     `{"user": {"email": "taken@email.com"}}`,
+			update:    &dto.UpdateUser{Email: util.Ptr("taken@email.com")},
+			resp:      resp{Code: http.StatusConflict, Error: service.ErrUserAlreadyExists.Error()},
+			mockError: service.ErrUserAlreadyExists,
+		},
+		{
+			name:      "internal error",
+			input:     `{"user": {"name": "Jane"}}`,
+			update:    &dto.UpdateUser{Name: util.Ptr("Jane")},
+			resp:      resp{Code: http.StatusInternalServerError, Error: service.ErrInternalError.Error()},
+			mockError: service.ErrInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			updaterMock := mocks.NewUserUpdater(t)
+
+			var user *models.User
+			if tc.mockError == nil {
+				user = &models.User{ID: 7, Name: util.Ptr("Jane"), Password: util.Ptr("$2a$10$hash")}
+			}
+			if tc.update != nil {
+				updaterMock.On("Update", mock.Anything, uint64(7), tc.update).Return(user, tc.mockError).Once()
+			}
+
+			handler := update.New(updaterMock, slogdiscard.NewDiscardLogger())
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewReader([]byte(tc.input)))
+			req = req.WithContext(jwtauth.WithPrincipal(req.Context(), &jwtauth.Principal{UserID: 7}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.resp.Code, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				assert.NotContains(t, rr.Body.String(), "password")
+
+				var resp models.User
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "Jane", *resp.Name)
+				return
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.resp.Error, resp.Detail)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/profile"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/remove"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/list"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/sessions/revoke"
+	"sdt-bicycle-rental/internal/http-server/handlers/user/update"
+	session_service "sdt-bicycle-rental/internal/service/session"
+	user_service "sdt-bicycle-rental/internal/service/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func UserRoute(log *slog.Logger, userService *user_service.UserService, sessionService *session_service.SessionService, authenticate func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Use(authenticate)
+
+		r.Get("/me", profile.New(userService, log))
+		r.Patch("/me", update.New(userService, log))
+		r.Delete("/me", remove.New(userService, log))
+
+		r.Get("/me/sessions", list.New(sessionService, log))
+		r.Delete("/me/sessions/{id}", revoke.New(sessionService, log))
+	}
+}
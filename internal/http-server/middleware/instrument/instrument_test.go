@@ -0,0 +1,89 @@
+package instrument_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/middleware/instrument"
+	"sdt-bicycle-rental/internal/metrics"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInstrument(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(instrument.New())
+	router.Route("/rentals", func(r chi.Router) {
+		r.Post("/{id}/end", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+	})
+
+	cases := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{
+			name:   "route pattern instead of path",
+			path:   "/rentals/42/end",
+			route:  "/rentals/{id}/end",
+			status: "404",
+		},
+		{
+			name:   "no matching route",
+			path:   "/unknown/42",
+			route:  "unmatched",
+			status: "404",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(http.MethodPost, tc.route, tc.status)
+			before := testutil.ToFloat64(counter)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tc.path, nil))
+
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestInstrument_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var handlerSpan trace.SpanContext
+	router := chi.NewRouter()
+	router.Use(instrument.New())
+	router.Get("/stations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/stations/3", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /stations/{id}", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+	assert.Equal(t, "Error", span.Status().Code.String())
+}
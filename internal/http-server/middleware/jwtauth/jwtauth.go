@@ -0,0 +1,168 @@
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var ErrMissingToken = service.ErrUnauthorized.WithDetail("missing token")
+
+//go:generate mockery --name=TokenValidator
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*token_service.Claims, error)
+}
+
+// Principal is the authenticated caller of the request.
+type Principal struct {
+	UserID    uint64
+	Email     string
+	Roles     []string
+	SessionID string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// New returns middleware that authenticates requests by the Bearer token
+// from the Authorization header and stores the Principal in the request context.
+func New(v TokenValidator, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.jwtauth.New"
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			token, ok := bearerToken(r)
+			if !ok {
+				log.Info("request without token")
+				unauthorized(w, r, ErrMissingToken)
+				return
+			}
+
+			claims, err := v.ValidateToken(r.Context(), token)
+			if err != nil {
+				// the session store being down is not a reason to sign the client out
+				if errors.Is(err, service.ErrInternalError) {
+					problem.Render(w, r, log, err)
+					return
+				}
+				log.Info("failed to validate token", sl.Err(err))
+				if !errors.Is(err, service.ErrExpiredToken) {
+					err = service.ErrInvalidToken
+				}
+				unauthorized(w, r, err)
+				return
+			}
+
+			ctx := WithPrincipal(r.Context(), &Principal{
+				UserID:    claims.UserID,
+				Email:     claims.Email,
+				Roles:     claims.Roles,
+				SessionID: claims.SessionID,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireRole allows the request only if the principal has any of the roles.
+// It must be mounted after New.
+func RequireRole(roles ...string) func(next http.Handler) http.Handler {
+	return require(func(p *Principal) bool {
+		for _, role := range roles {
+			if p.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequirePermission allows the request only if any role of the principal grants the permission.
+// It must be mounted after New.
+func RequirePermission(perm access_service.Permission) func(next http.Handler) http.Handler {
+	return require(func(p *Principal) bool {
+		return access_service.HasPermission(p.Roles, perm)
+	})
+}
+
+func require(allowed func(p *Principal) bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, ErrMissingToken)
+				return
+			}
+
+			if !allowed(p) {
+				problem.Write(w, r, service.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by the middleware.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// UserID returns the ID of the authenticated user.
+func UserID(ctx context.Context) (uint64, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return p.UserID, true
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer`)
+	problem.Write(w, r, err)
+}
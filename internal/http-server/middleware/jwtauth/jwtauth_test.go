@@ -0,0 +1,186 @@
+package jwtauth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth/mocks"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	cases := []struct {
+		name       string
+		header     string
+		token      string
+		mockClaims *token_service.Claims
+		mockError  error
+		wantCode   int
+		wantError  string
+	}{
+		{
+			name:       "success",
+			header:     "Bearer valid-token",
+			token:      "valid-token",
+			mockClaims: &token_service.Claims{UserID: 7, Email: "valid@email.com", Roles: []string{"rider"}, SessionID: "session"},
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:      "missing header",
+			wantCode:  http.StatusUnauthorized,
+			wantError: jwtauth.ErrMissingToken.Error(),
+		},
+		{
+			name:      "wrong scheme",
+			header:    "Basic dXNlcjpwYXNz",
+			wantCode:  http.StatusUnauthorized,
+			wantError: jwtauth.ErrMissingToken.Error(),
+		},
+		{
+			name:      "expired token",
+			header:    "Bearer expired-token",
+			token:     "expired-token",
+			mockError: service.ErrExpiredToken,
+			wantCode:  http.StatusUnauthorized,
+			wantError: service.ErrExpiredToken.Error(),
+		},
+		{
+			name:      "invalid token",
+			header:    "bearer invalid-token",
+			token:     "invalid-token",
+			mockError: service.ErrInvalidToken,
+			wantCode:  http.StatusUnauthorized,
+			wantError: service.ErrInvalidToken.Error(),
+		},
+		{
+			name:      "session store unavailable",
+			header:    "Bearer valid-token",
+			token:     "valid-token",
+			mockError: service.ErrInternalError,
+			wantCode:  http.StatusInternalServerError,
+			wantError: service.ErrInternalError.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			validatorMock := mocks.NewTokenValidator(t)
+			if tc.token != "" {
+				validatorMock.On("ValidateToken", mock.Anything, tc.token).Return(tc.mockClaims, tc.mockError).Once()
+			}
+
+			var principal *jwtauth.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = jwtauth.PrincipalFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := jwtauth.New(validatorMock, slogdiscard.NewDiscardLogger())(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.wantCode, rr.Code)
+
+			if tc.wantCode == http.StatusOK {
+				require.NotNil(t, principal)
+				assert.Equal(t, tc.mockClaims.UserID, principal.UserID)
+				assert.Equal(t, tc.mockClaims.Email, principal.Email)
+				assert.Equal(t, "session", principal.SessionID)
+				assert.True(t, principal.HasRole("rider"))
+				return
+			}
+
+			if tc.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+			}
+
+			var resp problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.wantError, resp.Detail)
+		})
+	}
+}
+
+func TestRequireRoleAndPermission(t *testing.T) {
+	cases := []struct {
+		name       string
+		principal  *jwtauth.Principal
+		middleware func(next http.Handler) http.Handler
+		wantCode   int
+	}{
+		{
+			name:       "role allowed",
+			principal:  &jwtauth.Principal{UserID: 1, Roles: []string{models.RoleRider, models.RoleAdmin}},
+			middleware: jwtauth.RequireRole(models.RoleAdmin),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "role denied",
+			principal:  &jwtauth.Principal{UserID: 1, Roles: []string{models.RoleRider}},
+			middleware: jwtauth.RequireRole(models.RoleAdmin, models.RoleStationOperator),
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "permission allowed",
+			principal:  &jwtauth.Principal{UserID: 1, Roles: []string{models.RoleRider, models.RoleMechanic}},
+			middleware: jwtauth.RequirePermission(access_service.PermMaintainBicycle),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "permission denied",
+			principal:  &jwtauth.Principal{UserID: 1, Roles: []string{models.RoleMechanic}},
+			middleware: jwtauth.RequirePermission(access_service.PermManageRoles),
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "unauthenticated",
+			middleware: jwtauth.RequirePermission(access_service.PermManageRoles),
+			wantCode:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tc.principal != nil {
+				req = req.WithContext(jwtauth.WithPrincipal(req.Context(), tc.principal))
+			}
+
+			rr := httptest.NewRecorder()
+			tc.middleware(next).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.wantCode, rr.Code)
+
+			if tc.wantCode == http.StatusForbidden {
+				var resp problem.Problem
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, service.ErrForbidden.Error(), resp.Detail)
+			}
+		})
+	}
+}
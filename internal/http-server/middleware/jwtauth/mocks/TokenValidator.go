@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// TokenValidator is an autogenerated mock type for the TokenValidator type
+type TokenValidator struct {
+	mock.Mock
+}
+
+// ValidateToken provides a mock function with given fields: ctx, token
+func (_m *TokenValidator) ValidateToken(ctx context.Context, token string) (*token_service.Claims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *token_service.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*token_service.Claims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *token_service.Claims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token_service.Claims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenValidator creates a new instance of TokenValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenValidator {
+	mock := &TokenValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
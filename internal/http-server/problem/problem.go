@@ -0,0 +1,86 @@
+// Package problem renders errors as RFC 7807 problem details (application/problem+json).
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/validation"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	ut "github.com/go-playground/universal-translator"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is the body of every error response. Code is the service.Error code,
+// clients should branch on it rather than on Detail.
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      string                  `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Errors    []validation.FieldError `json:"errors,omitempty"`
+}
+
+// From converts err to a problem with validation messages in the language of trans.
+// Errors other than *service.Error become internal errors, their message is never exposed.
+func From(err error, trans ut.Translator) *Problem {
+	var e *service.Error
+	if !errors.As(err, &e) {
+		e = service.ErrInternalError
+	}
+
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(e.Status),
+		Status: e.Status,
+		Detail: e.Detail,
+		Code:   e.Code,
+		Errors: validation.Fields(trans, e.Violations),
+	}
+}
+
+// Render writes err as a problem response. Server errors are logged with log,
+// client errors are expected and left to the caller to log.
+func Render(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	p := From(err, locale(r))
+	if p.Status >= http.StatusInternalServerError {
+		log.ErrorContext(r.Context(), "request failed", slog.String("code", p.Code), sl.Err(err))
+	}
+
+	write(w, r, p, err)
+}
+
+// Write writes err as a problem response without logging,
+// for middleware that only ever rejects with client errors.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	write(w, r, From(err, locale(r)), err)
+}
+
+func locale(r *http.Request) ut.Translator {
+	return service.Messages.Locale(r.Header.Get("Accept-Language"))
+}
+
+func write(w http.ResponseWriter, r *http.Request, p *Problem, err error) {
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	// whole seconds, rounded up so that a retry on time isn't refused again
+	var e *service.Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,114 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sdt-bicycle-rental/internal/http-server/problem"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/validation"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	type user struct {
+		Email string `json:"email" validate:"required,email"`
+		Name  string `json:"name" validate:"required"`
+	}
+	validationErr := service.Invalid(service.Validate.Struct(user{Email: "not-an-email"}).(validator.ValidationErrors))
+
+	cases := []struct {
+		name           string
+		acceptLanguage string
+		err            error
+		want           problem.Problem
+		wantRetryAfter string
+	}{
+		{
+			name: "domain error",
+			err:  service.ErrStationNotFound,
+			want: problem.Problem{
+				Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound,
+				Detail: "station not found", Code: "station_not_found",
+			},
+		},
+		{
+			name: "wrapped domain error with own detail",
+			err:  fmt.Errorf("handler: %w", service.ErrInvalidInput.WithDetail("invalid station id")),
+			want: problem.Problem{
+				Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest,
+				Detail: "invalid station id", Code: "invalid_input",
+			},
+		},
+		{
+			name: "validation error",
+			err:  validationErr,
+			want: problem.Problem{
+				Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest,
+				Detail: "validation failed", Code: "validation_failed",
+				Errors: []validation.FieldError{
+					{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+					{Field: "name", Rule: "required", Message: "name is a required field"},
+				},
+			},
+		},
+		{
+			name:           "validation error in accepted language",
+			acceptLanguage: "de;q=0.9, uk-UA;q=0.8, en;q=0.5",
+			err:            validationErr,
+			want: problem.Problem{
+				Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest,
+				Detail: "validation failed", Code: "validation_failed",
+				Errors: []validation.FieldError{
+					{Field: "email", Rule: "email", Message: "email має бути email адресою"},
+					{Field: "name", Rule: "required", Message: "name обов'язкове поле"},
+				},
+			},
+		},
+		{
+			name: "throttled error tells when to retry",
+			err:  service.ErrTooManyAttempts.WithRetryAfter(90*time.Second + time.Millisecond),
+			want: problem.Problem{
+				Type: "about:blank", Title: "Too Many Requests", Status: http.StatusTooManyRequests,
+				Detail: "too many failed login attempts, try again later", Code: "too_many_attempts",
+			},
+			wantRetryAfter: "91",
+		},
+		{
+			name: "unknown error is not exposed",
+			err:  errors.New("pq: connection refused"),
+			want: problem.Problem{
+				Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Detail: "internal server error", Code: "internal_error",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/stations/1", nil)
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+			rr := httptest.NewRecorder()
+			problem.Render(rr, req, slogdiscard.NewDiscardLogger(), tc.err)
+
+			require.Equal(t, tc.want.Status, rr.Code)
+			assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tc.wantRetryAfter, rr.Header().Get("Retry-After"))
+
+			var got problem.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			tc.want.Instance = "/stations/1"
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package metrics_test
+
+import (
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGormPlugin(t *testing.T) {
+	// DryRun builds statements without a database, the callbacks still run
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(metrics.GormPlugin{}))
+
+	before := testutil.CollectAndCount(metrics.DBQueryDuration)
+
+	var station models.Station
+	db.First(&station, 1)
+	db.Create(&models.Station{})
+
+	require.Equal(t, before+2, testutil.CollectAndCount(metrics.DBQueryDuration))
+}
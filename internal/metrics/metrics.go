@@ -0,0 +1,130 @@
+// Package metrics holds the Prometheus collectors of the service.
+// Collectors are package level so that any layer can record an event without extra wiring,
+// all of them are registered in Registry which /metrics exposes.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bicycle_rental"
+
+const (
+	LoginSucceeded = "succeeded"
+	LoginFailed    = "failed"
+	LoginLocked    = "locked"        // refused without checking the password
+	LoginChallenge = "mfa_challenge" // password accepted, waiting for the second factor
+)
+
+var Registry = prometheus.NewRegistry()
+
+// HTTP
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, chi route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Database
+var DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Database query latency by gorm operation and table.",
+	Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table"})
+
+// Business events
+var (
+	Registrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Users registered.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	Lockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_lockouts_total",
+		Help:      "Lockouts after repeated failed logins by scope, account or ip.",
+	}, []string{"scope"})
+
+	RidesStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rides_started_total",
+		Help:      "Rides started, directly or from a booking.",
+	})
+
+	RidesEnded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rides_ended_total",
+		Help:      "Rides ended.",
+	})
+
+	BookingsExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bookings_expired_total",
+		Help:      "Bookings released because the hold ended.",
+	})
+
+	Revenue = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "revenue_cents_total",
+		Help:      "Captured payments in minor currency units.",
+	}, []string{"currency"})
+
+	Refunds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "refunds_cents_total",
+		Help:      "Refunded payments in minor currency units.",
+	}, []string{"currency"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		DBQueryDuration,
+		Registrations,
+		Logins,
+		Lockouts,
+		RidesStarted,
+		RidesEnded,
+		BookingsExpired,
+		Revenue,
+		Refunds,
+	)
+}
+
+// RegisterDBStats exposes the connection pool stats (open, in use, idle, waits) of db
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves Registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	AuditActionRoleGranted  = "role.granted"
+	AuditActionRoleRevoked  = "role.revoked"
+	AuditActionLoginLocked  = "login.locked"
+	AuditActionUserBanned   = "user.banned"
+	AuditActionUserUnbanned = "user.unbanned"
+)
+
+type AuditEvent struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	ActorID      *uint64    `gorm:"type:BIGINT;index"`
+	Action       string     `gorm:"type:varchar(64);not null;index"`
+	TargetUserID *uint64    `gorm:"type:BIGINT;index"`
+	Details      string     `gorm:"type:text"`
+	CreatedAt    *time.Time `gorm:"type:timestamp;default:now()"`
+}
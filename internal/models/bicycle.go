@@ -4,15 +4,23 @@ import "time"
 
 const (
 	BicycleStatusAvailable = "available"
+	BicycleStatusReserved  = "reserved"
 	BicycleStatusRented    = "rented"
 	BicycleStatusInService = "in_service"
+	BicycleStatusRetired   = "retired"
+)
+
+const (
+	BicycleTypeStandard = "standard"
+	BicycleTypeElectric = "electric"
 )
 
 type Bicycle struct {
-	ID          uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
-	StationID   uint64     `gorm:"type:BIGINT;not null"`
-	Status      string     `gorm:"type:varchar(64);not null;"`
-	LastService *time.Time `gorm:"type:timestamp"`
+	ID          uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	StationID   uint64     `gorm:"type:BIGINT;not null;index" json:"station_id"`
+	Type        string     `gorm:"type:varchar(64);not null;default:standard" json:"type"`
+	Status      string     `gorm:"type:varchar(64);not null;index" json:"status"`
+	LastService *time.Time `gorm:"type:timestamp" json:"last_service"`
 
-	Station *Station `gorm:"foreignKey:StationID;references:ID"`
+	Station *Station `gorm:"foreignKey:StationID;references:ID" json:"station,omitempty"`
 }
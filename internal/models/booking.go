@@ -2,16 +2,28 @@ package models
 
 import "time"
 
+const (
+	BookingStatusActive    = "active"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusExpired   = "expired"
+	BookingStatusConverted = "converted"
+)
+
+// Booking holds a bicycle for a user until ExpiresAt.
+// Partial unique indexes allow at most one active booking per user and per bicycle.
 type Booking struct {
-	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
-	UserID    uint64     `gorm:"type:BIGINT;not null"`
-	BicycleID uint64     `gorm:"type:BIGINT;not null"`
-	StationID uint64     `gorm:"type:BIGINT;not null"`
-	PaymentID uint64     `gorm:"type:BIGINT;not null"`
-	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
-	ExpiresAt *time.Time `gorm:"type:timestamp"`
-	User      *User      `gorm:"foreignKey:UserID;references:ID"`
-	Bicycle   *Bicycle   `gorm:"foreignKey:BicycleID;references:ID"`
-	Station   *Station   `gorm:"foreignKey:StationID;references:ID"`
-	Payment   *Payment   `gorm:"foreignKey:PaymentID;references:ID"`
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	UserID    uint64     `gorm:"type:BIGINT;not null;index;uniqueIndex:idx_bookings_active_user,where:status = 'active'" json:"user_id"`
+	BicycleID uint64     `gorm:"type:BIGINT;not null;uniqueIndex:idx_bookings_active_bicycle,where:status = 'active'" json:"bicycle_id"`
+	StationID uint64     `gorm:"type:BIGINT;not null" json:"station_id"`
+	PaymentID *uint64    `gorm:"type:BIGINT" json:"payment_id"`
+	RentalID  *uint64    `gorm:"type:BIGINT" json:"rental_id"`
+	Status    string     `gorm:"type:varchar(64);not null;default:active;index" json:"status"`
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()" json:"created_at"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;not null" json:"expires_at"`
+	User      *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Bicycle   *Bicycle   `gorm:"foreignKey:BicycleID;references:ID" json:"bicycle,omitempty"`
+	Station   *Station   `gorm:"foreignKey:StationID;references:ID" json:"station,omitempty"`
+	Payment   *Payment   `gorm:"foreignKey:PaymentID;references:ID" json:"payment,omitempty"`
+	Rental    *Rental    `gorm:"foreignKey:RentalID;references:ID" json:"rental,omitempty"`
 }
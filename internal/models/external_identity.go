@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ExternalIdentity links a user to the account at an OpenID Connect provider,
+// the provider and its subject identify the account for good, the email may change
+type ExternalIdentity struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	UserID    uint64     `gorm:"type:BIGINT;not null;index"`
+	Provider  string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Subject   string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Email     *string    `gorm:"type:varchar(255)"` // as asserted by the provider when linked
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+// OIDCState is an external login waiting for the provider to redirect back.
+// The state sent to the provider is stored hashed, the nonce and the PKCE verifier
+// are needed as they are to check the answer.
+type OIDCState struct {
+	StateHash    string     `gorm:"primaryKey;type:varchar(64)"`
+	Provider     string     `gorm:"type:varchar(64);not null"`
+	Nonce        string     `gorm:"type:varchar(128);not null"`
+	CodeVerifier string     `gorm:"type:varchar(128);not null"`
+	ExpiresAt    *time.Time `gorm:"type:timestamp;not null"`
+	CreatedAt    *time.Time `gorm:"type:timestamp;default:now()"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginAttempt counts the recent failed logins of one key: an account email or a client IP
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey;type:varchar(320)"`
+	Failures      int        `gorm:"not null;default:0"` // since the last lockout
+	Lockouts      int        `gorm:"not null;default:0"` // in a row, each one doubles the cooldown
+	LastFailureAt *time.Time `gorm:"type:timestamp"`
+	LockedUntil   *time.Time `gorm:"type:timestamp"`
+}
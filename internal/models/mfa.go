@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TOTPSecret is the authenticator app secret of a user. It takes part in logins
+// only once confirmed with a first code.
+type TOTPSecret struct {
+	UserID      uint64     `gorm:"primaryKey;type:BIGINT"`
+	Secret      string     `gorm:"type:varchar(64);not null"`
+	ConfirmedAt *time.Time `gorm:"type:timestamp"`
+	LastStep    int64      `gorm:"type:BIGINT;not null;default:0"` // time step of the last accepted code, a code is never accepted twice
+	CreatedAt   *time.Time `gorm:"type:timestamp;default:now()"`
+	// SetupTokenHash is of the emailed link that shows the unconfirmed secret once
+	SetupTokenHash *string    `gorm:"type:varchar(64);uniqueIndex"`
+	SetupExpiresAt *time.Time `gorm:"type:timestamp"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+// RecoveryCode is a single-use code that replaces the authenticator when it is lost.
+// Only its hash is stored.
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	UserID    uint64     `gorm:"type:BIGINT;not null;index"`
+	CodeHash  string     `gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
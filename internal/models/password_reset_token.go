@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PasswordResetToken is a single-use token emailed to a user who forgot the password.
+// Only its hash is stored.
+type PasswordResetToken struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	UserID    uint64     `gorm:"type:BIGINT;not null;index"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -2,13 +2,28 @@ package models
 
 import "time"
 
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusRefunded   = "refunded"
+	PaymentStatusVoided     = "voided"
+	PaymentStatusFailed     = "failed"
+)
+
+// Payment is a charge through the payment provider for a rental or a booking.
+// Amount is in minor currency units (cents), a rental is charged at most once.
 type Payment struct {
-	ID            uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
-	UserID        uint64     `gorm:"type:BIGINT;not null"`
-	Method        string     `gorm:"type:varchar(64);not null"`
-	Amount        float64    `gorm:"type:decimal(10,2);not null"`
-	TransactionID string     `gorm:"type:varchar(255)"`
-	Status        string     `gorm:"type:varchar(64);not null"`
-	CreatedAt     *time.Time `gorm:"type:timestamp;default:now()"`
-	User          *User      `gorm:"foreignKey:UserID;references:ID"`
+	ID            uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	UserID        uint64     `gorm:"type:BIGINT;not null;index" json:"user_id"`
+	RentalID      *uint64    `gorm:"type:BIGINT;uniqueIndex" json:"rental_id,omitempty"`
+	BookingID     *uint64    `gorm:"type:BIGINT;index" json:"booking_id,omitempty"`
+	Method        string     `gorm:"type:varchar(64);not null" json:"method"`
+	Amount        int64      `gorm:"type:BIGINT;not null" json:"amount"` // in cents
+	Currency      string     `gorm:"type:varchar(3);not null" json:"currency"`
+	TransactionID string     `gorm:"type:varchar(255)" json:"transaction_id"`
+	Status        string     `gorm:"type:varchar(64);not null" json:"status"`
+	CreatedAt     *time.Time `gorm:"type:timestamp;default:now()" json:"created_at"`
+	UpdatedAt     *time.Time `gorm:"type:timestamp" json:"updated_at"`
+	User          *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
 }
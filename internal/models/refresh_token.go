@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	UserID       uint64     `gorm:"type:BIGINT;not null;index"`
+	FamilyID     string     `gorm:"type:varchar(64);not null;index"`
+	TokenHash    string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt    *time.Time `gorm:"type:timestamp;not null"`
+	UsedAt       *time.Time `gorm:"type:timestamp"`
+	RevokedAt    *time.Time `gorm:"type:timestamp"`
+	ReplacedByID *uint64    `gorm:"type:BIGINT"`
+	CreatedAt    *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
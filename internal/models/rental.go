@@ -2,17 +2,28 @@ package models
 
 import "time"
 
+const (
+	RentalStatusActive    = "active"
+	RentalStatusCompleted = "completed"
+)
+
+// Rental is a single ride. While it is active StationEndID, EndTime and TotalCost are nil.
+// TotalCost is in minor currency units (cents) and is computed with the tariff the ride started under.
+// Partial unique indexes allow at most one active rental per user and per bicycle.
 type Rental struct {
-	ID             uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
-	UserID         uint64     `gorm:"type:BIGINT;not null"`
-	BicycleID      uint64     `gorm:"type:BIGINT;not null"`
-	StationStartID uint64     `gorm:"type:BIGINT;not null"`
-	StationEndID   uint64     `gorm:"type:BIGINT;not null"`
-	StartTime      *time.Time `gorm:"type:TIMESTAMP;not null"`
-	EndTime        *time.Time `gorm:"type:TIMESTAMP;not null"`
-	TotalCost      float64    `gorm:"type:DECIMAL(10,2);not null"`
-	User           *User      `gorm:"foreignKey:UserID;references:ID"`
-	Bicycle        *Bicycle   `gorm:"foreignKey:BicycleID;references:ID"`
-	StationStart   *Station   `gorm:"foreignKey:StationStartID;references:ID"`
-	StationEnd     *Station   `gorm:"foreignKey:StationEndID;references:ID"`
+	ID             uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	UserID         uint64     `gorm:"type:BIGINT;not null;index;uniqueIndex:idx_rentals_active_user,where:status = 'active'" json:"user_id"`
+	BicycleID      uint64     `gorm:"type:BIGINT;not null;uniqueIndex:idx_rentals_active_bicycle,where:status = 'active'" json:"bicycle_id"`
+	StationStartID uint64     `gorm:"type:BIGINT;not null" json:"station_start_id"`
+	StationEndID   *uint64    `gorm:"type:BIGINT" json:"station_end_id"`
+	TariffID       *uint64    `gorm:"type:BIGINT" json:"tariff_id"`
+	Status         string     `gorm:"type:varchar(64);not null;default:active" json:"status"`
+	StartTime      *time.Time `gorm:"type:TIMESTAMP;not null" json:"start_time"`
+	EndTime        *time.Time `gorm:"type:TIMESTAMP" json:"end_time"`
+	TotalCost      *int64     `gorm:"type:BIGINT" json:"total_cost"`
+	User           *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Bicycle        *Bicycle   `gorm:"foreignKey:BicycleID;references:ID" json:"bicycle,omitempty"`
+	StationStart   *Station   `gorm:"foreignKey:StationStartID;references:ID" json:"station_start,omitempty"`
+	StationEnd     *Station   `gorm:"foreignKey:StationEndID;references:ID" json:"station_end,omitempty"`
+	Tariff         *Tariff    `gorm:"foreignKey:TariffID;references:ID" json:"tariff,omitempty"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	RoleRider           = "rider"
+	RoleStationOperator = "station_operator"
+	RoleMechanic        = "mechanic"
+	// RoleAdmin is granted by the presence of the user in the admins table
+	RoleAdmin = "admin"
+)
+
+type UserRole struct {
+	UserID    uint64     `gorm:"primaryKey;type:BIGINT"`
+	Role      string     `gorm:"primaryKey;type:varchar(64)"`
+	GrantedBy *uint64    `gorm:"type:BIGINT"`
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
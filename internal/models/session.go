@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Session is a signed in device. Its ID is the family of the refresh tokens issued to the device
+// and the sid claim of its access tokens, so revoking it stops both.
+type Session struct {
+	ID         string     `gorm:"primaryKey;type:varchar(64)" json:"id"`
+	UserID     uint64     `gorm:"type:BIGINT;not null;index" json:"-"`
+	DeviceName *string    `gorm:"type:varchar(64)" json:"device_name"`
+	UserAgent  *string    `gorm:"type:varchar(255)" json:"user_agent"`
+	IP         *string    `gorm:"type:varchar(64)" json:"ip"`
+	CreatedAt  *time.Time `gorm:"type:timestamp;default:now()" json:"created_at"`
+	LastSeenAt *time.Time `gorm:"type:timestamp" json:"last_seen_at"`        // last sign in or refresh
+	ExpiresAt  *time.Time `gorm:"type:timestamp;not null" json:"expires_at"` // of the latest refresh token
+	MFAAt      *time.Time `gorm:"type:timestamp" json:"mfa_at"`              // second factor passed, nil if signed in without it
+	RevokedAt  *time.Time `gorm:"type:timestamp" json:"-"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
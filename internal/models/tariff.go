@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Tariff is an immutable pricing version: changing prices means creating a new tariff.
+// The tariff in effect is the latest one with ActiveFrom in the past; rentals keep the tariff they started under.
+// All amounts are in minor currency units (cents).
+type Tariff struct {
+	ID          uint64       `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	Name        string       `gorm:"type:varchar(64);not null" json:"name"`
+	UnlockFee   int64        `gorm:"type:BIGINT;not null" json:"unlock_fee"`
+	PerMinute   int64        `gorm:"type:BIGINT;not null" json:"per_minute"`
+	DailyCap    int64        `gorm:"type:BIGINT;not null;default:0" json:"daily_cap"` // 0 means no cap
+	FreeMinutes int          `gorm:"type:int;not null;default:0" json:"free_minutes"`
+	ActiveFrom  *time.Time   `gorm:"type:timestamp;not null;index" json:"active_from"`
+	CreatedAt   *time.Time   `gorm:"type:timestamp;default:now()" json:"created_at"`
+	Rates       []TariffRate `gorm:"foreignKey:TariffID;references:ID" json:"rates"`
+}
+
+// TariffRate overrides the per-minute price for a bicycle type and/or an hour range.
+// An empty BicycleType matches every type, EndHour before StartHour wraps past midnight.
+type TariffRate struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"-"`
+	TariffID    uint64 `gorm:"type:BIGINT;not null;index" json:"-"`
+	BicycleType string `gorm:"type:varchar(64)" json:"bicycle_type,omitempty"`
+	StartHour   int    `gorm:"type:int;not null" json:"start_hour"`
+	EndHour     int    `gorm:"type:int;not null" json:"end_hour"`
+	PerMinute   int64  `gorm:"type:BIGINT;not null" json:"per_minute"`
+}
@@ -1,26 +1,41 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
 const (
+	UserStatusPending = "pending" // registered, email or phone not verified yet
 	UserStatusActive  = "active"
 	UserStatusDeleted = "deleted"
 	UserStatusBanned  = "banned"
 )
 
 type User struct {
-	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
-	Name      *string    `gorm:"type:varchar(64)" validate:"required,min=1,max=64"`
-	Lastname  *string    `gorm:"type:varchar(64)" validate:"required,min=1,max=64"`
-	Email     *string    `gorm:"type:varchar(255);uniqueIndex" validate:"required,email"`
-	Phone     *string    `gorm:"type:varchar(64);uniqueIndex" validate:"required,max=64"`
-	Status    *string    `gorm:"type:varchar(64)"`
-	Password  *string    `gorm:"type:varchar(255)" validate:"required,min=8,max=255"`
-	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT" json:"id"`
+	Name      *string    `gorm:"type:varchar(64)" validate:"required,min=1,max=64" json:"name"`
+	Lastname  *string    `gorm:"type:varchar(64)" validate:"required,min=1,max=64" json:"lastname"`
+	Email     *string    `gorm:"type:varchar(255);uniqueIndex" validate:"required,email" json:"email"`
+	Phone     *string    `gorm:"type:varchar(64);uniqueIndex" validate:"required,max=64" json:"phone"`
+	Status    *string    `gorm:"type:varchar(64)" json:"status"`
+	Password  *string    `gorm:"type:varchar(255)" validate:"required,min=8,max=255" json:"-"` // never serialized
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()" json:"created_at"`
 
-	Bookings []Booking `gorm:"foreignKey:UserID;references:ID"`
-	Payments []Payment `gorm:"foreignKey:UserID;references:ID"`
-	Rentals  []Rental  `gorm:"foreignKey:UserID;references:ID"`
+	EmailVerifiedAt *time.Time `gorm:"type:timestamp" json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `gorm:"type:timestamp" json:"phone_verified_at"`
+
+	Bookings []Booking `gorm:"foreignKey:UserID;references:ID" json:"bookings,omitempty"`
+	Payments []Payment `gorm:"foreignKey:UserID;references:ID" json:"payments,omitempty"`
+	Rentals  []Rental  `gorm:"foreignKey:UserID;references:ID" json:"rentals,omitempty"`
+}
+
+// NormalizeEmail is the form emails are stored and looked up in, they don't differ by case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CanSignIn tells whether the user may hold a session, pending users sign in to finish verification
+func (u *User) CanSignIn() bool {
+	return u.Status != nil && (*u.Status == UserStatusActive || *u.Status == UserStatusPending)
 }
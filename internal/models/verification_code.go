@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Verification channels, a code proves the user controls the email address or the phone number
+const (
+	VerificationChannelEmail = "email"
+	VerificationChannelPhone = "phone"
+)
+
+// VerificationCode is the secret sent to confirm an email (a link token) or a phone (a short OTP).
+// Only its hash is stored. Attempts counts wrong guesses of a phone code.
+type VerificationCode struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement;type:BIGINT"`
+	UserID    uint64     `gorm:"type:BIGINT;not null;index:idx_verification_codes_user_channel"`
+	Channel   string     `gorm:"type:varchar(16);not null;index:idx_verification_codes_user_channel"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;index"`
+	Attempts  int        `gorm:"type:INTEGER;not null;default:0"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt *time.Time `gorm:"type:timestamp;default:now()"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,55 @@
+// Package filesender stores every message as a text file in a directory instead of delivering it,
+// for local development and manual testing of emailed links.
+package filesender
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sdt-bicycle-rental/internal/notify"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type Sender struct {
+	dir string
+	seq atomic.Uint64
+}
+
+// New returns a sender writing to dir, the directory is created on the first message
+func New(dir string) *Sender {
+	return &Sender{dir: dir}
+}
+
+// Send writes the message to <dir>/<time>-<seq>-<channel>-<recipient>.txt
+func (s *Sender) Send(_ context.Context, msg notify.Message) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d-%s-%s.txt", time.Now().UTC().Format("20060102T150405"), s.seq.Add(1), fileSafe(msg.Channel), fileSafe(msg.To))
+	content := fmt.Sprintf("Channel: %s\nTo: %s\nSubject: %s\n\n%s\n", msg.Channel, msg.To, msg.Subject, msg.Body)
+
+	f, err := os.OpenFile(filepath.Join(s.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fileSafe keeps letters, digits and .@_- of the address
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune(".@_-", r):
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,33 @@
+package filesender_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/notify/filesender"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	s := filesender.New(dir)
+
+	msg := notify.Message{Channel: notify.Email, To: "john/../doe@example.com", Subject: "Reset", Body: "token: abc"}
+	require.NoError(t, s.Send(context.Background(), msg))
+	require.NoError(t, s.Send(context.Background(), msg))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2, "every message gets its own file")
+
+	for _, f := range files {
+		assert.NotContains(t, f.Name(), "/")
+		content, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, "Channel: email\nTo: john/../doe@example.com\nSubject: Reset\n\ntoken: abc\n", string(content))
+	}
+}
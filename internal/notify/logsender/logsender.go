@@ -0,0 +1,27 @@
+// Package logsender writes messages to the application log instead of delivering them.
+// Message bodies may carry secrets such as reset tokens, use it for local development only.
+package logsender
+
+import (
+	"context"
+	"log/slog"
+	"sdt-bicycle-rental/internal/notify"
+)
+
+type Sender struct {
+	log *slog.Logger
+}
+
+func New(log *slog.Logger) *Sender {
+	return &Sender{log: log}
+}
+
+func (s *Sender) Send(ctx context.Context, msg notify.Message) error {
+	s.log.InfoContext(ctx, "message sent",
+		slog.String("channel", msg.Channel),
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+		slog.String("body", msg.Body),
+	)
+	return nil
+}
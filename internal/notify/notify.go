@@ -0,0 +1,17 @@
+// Package notify describes messages sent to users, the senders delivering them live in subpackages.
+package notify
+
+// Channels a message is delivered through
+const (
+	Email = "email"
+	SMS   = "sms"
+)
+
+// Message is a plain text message to a user's email address or phone number.
+// Subject is not shown for SMS.
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,116 @@
+// Package fake is an in-process payment provider for development and tests.
+// It keeps transactions in memory and behaves deterministically: transaction IDs
+// are sequential and charges above DeclineAbove are declined.
+package fake
+
+import (
+	"fmt"
+	"sdt-bicycle-rental/internal/payment"
+	"sync"
+)
+
+const (
+	stateAuthorized = "authorized"
+	stateCaptured   = "captured"
+	stateRefunded   = "refunded"
+	stateVoided     = "voided"
+)
+
+type transaction struct {
+	state    string
+	amount   int64
+	captured int64
+	refunded int64
+}
+
+type Provider struct {
+	// DeclineAbove declines charges of a larger amount, 0 accepts everything
+	DeclineAbove int64
+
+	mu           sync.Mutex
+	seq          uint64
+	transactions map[string]*transaction
+}
+
+func New(declineAbove int64) *Provider {
+	return &Provider{DeclineAbove: declineAbove, transactions: make(map[string]*transaction)}
+}
+
+func (p *Provider) Authorize(charge payment.Charge) (string, error) {
+	if charge.Amount <= 0 {
+		return "", payment.ErrInvalidAmount
+	}
+	if p.DeclineAbove > 0 && charge.Amount > p.DeclineAbove {
+		return "", payment.ErrDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seq++
+	id := fmt.Sprintf("fake_%06d", p.seq)
+	p.transactions[id] = &transaction{state: stateAuthorized, amount: charge.Amount}
+
+	return id, nil
+}
+
+// Capture takes up to the authorized amount, the rest of the hold is released
+func (p *Provider) Capture(transactionID string, amount int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tx, err := p.get(transactionID, stateAuthorized)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || amount > tx.amount {
+		return payment.ErrInvalidAmount
+	}
+
+	tx.state, tx.captured = stateCaptured, amount
+	return nil
+}
+
+// Refund returns up to the captured amount, partial refunds add up
+func (p *Provider) Refund(transactionID string, amount int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tx, err := p.get(transactionID, stateCaptured)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || tx.refunded+amount > tx.captured {
+		return payment.ErrInvalidAmount
+	}
+
+	tx.refunded += amount
+	if tx.refunded == tx.captured {
+		tx.state = stateRefunded
+	}
+	return nil
+}
+
+func (p *Provider) Void(transactionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tx, err := p.get(transactionID, stateAuthorized)
+	if err != nil {
+		return err
+	}
+
+	tx.state = stateVoided
+	return nil
+}
+
+func (p *Provider) get(transactionID, state string) (*transaction, error) {
+	tx, ok := p.transactions[transactionID]
+	if !ok {
+		return nil, payment.ErrUnknownTransaction
+	}
+	if tx.state != state {
+		return nil, payment.ErrInvalidState
+	}
+	return tx, nil
+}
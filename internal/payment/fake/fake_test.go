@@ -0,0 +1,49 @@
+package fake_test
+
+import (
+	"errors"
+	"sdt-bicycle-rental/internal/payment"
+	"sdt-bicycle-rental/internal/payment/fake"
+	"testing"
+)
+
+func TestProvider(t *testing.T) {
+	p := fake.New(10000)
+
+	first, err := p.Authorize(payment.Charge{Amount: 2500, Currency: "UAH", Method: "card", Reference: "rental:1"})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	second, err := p.Authorize(payment.Charge{Amount: 500, Currency: "UAH", Method: "card", Reference: "booking:1"})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if first != "fake_000001" || second != "fake_000002" {
+		t.Errorf("Authorize() ids = %q, %q, want sequential ids", first, second)
+	}
+
+	steps := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "declined above limit", call: func() error { _, err := p.Authorize(payment.Charge{Amount: 10001}); return err }, wantErr: payment.ErrDeclined},
+		{name: "zero amount", call: func() error { _, err := p.Authorize(payment.Charge{}); return err }, wantErr: payment.ErrInvalidAmount},
+		{name: "capture over authorized", call: func() error { return p.Capture(first, 2501) }, wantErr: payment.ErrInvalidAmount},
+		{name: "refund before capture", call: func() error { return p.Refund(first, 100) }, wantErr: payment.ErrInvalidState},
+		{name: "capture", call: func() error { return p.Capture(first, 2000) }},
+		{name: "capture twice", call: func() error { return p.Capture(first, 2000) }, wantErr: payment.ErrInvalidState},
+		{name: "void captured", call: func() error { return p.Void(first) }, wantErr: payment.ErrInvalidState},
+		{name: "partial refund", call: func() error { return p.Refund(first, 1500) }},
+		{name: "refund over captured", call: func() error { return p.Refund(first, 600) }, wantErr: payment.ErrInvalidAmount},
+		{name: "refund rest", call: func() error { return p.Refund(first, 500) }},
+		{name: "void", call: func() error { return p.Void(second) }},
+		{name: "capture voided", call: func() error { return p.Capture(second, 500) }, wantErr: payment.ErrInvalidState},
+		{name: "unknown transaction", call: func() error { return p.Void("fake_999999") }, wantErr: payment.ErrUnknownTransaction},
+	}
+	for _, step := range steps {
+		if err := step.call(); !errors.Is(err, step.wantErr) {
+			t.Errorf("%s: error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Package payment holds the types shared by payment provider adapters.
+package payment
+
+import "errors"
+
+var (
+	ErrDeclined           = errors.New("payment declined")
+	ErrInvalidAmount      = errors.New("invalid amount")
+	ErrUnknownTransaction = errors.New("unknown transaction")
+	ErrInvalidState       = errors.New("transaction is in a wrong state")
+)
+
+// Charge is a request to reserve money on the customer's payment method.
+// Amount is in minor currency units (cents).
+type Charge struct {
+	Amount    int64
+	Currency  string
+	Method    string
+	Reference string // our side of the payment, e.g. "rental:42"
+}
@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"math"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+// Cost returns the price of a ride in cents under tariff t.
+// Every started minute is charged at the rate in effect when it starts, the first
+// FreeMinutes are free, usage within each 24h period from the start is capped at
+// DailyCap, and the unlock fee is charged once on top.
+// start and end must be in the time zone the tariff hours are defined in.
+func Cost(t *models.Tariff, bicycleType string, start, end time.Time) int64 {
+	minutes := 0
+	if end.After(start) {
+		minutes = int(math.Ceil(end.Sub(start).Minutes()))
+	}
+
+	var usage, day int64
+	for i := 0; i < minutes; i++ {
+		if i > 0 && i%minutesPerDay == 0 {
+			usage += capped(day, t.DailyCap)
+			day = 0
+		}
+		if i < t.FreeMinutes {
+			continue
+		}
+		day += RateAt(t, bicycleType, start.Add(time.Duration(i)*time.Minute))
+	}
+	usage += capped(day, t.DailyCap)
+
+	return t.UnlockFee + usage
+}
+
+// RateAt returns the per-minute price for a bicycle type at the given moment.
+// Rates for the exact bicycle type win over rates for any type, earlier rates win over later ones.
+func RateAt(t *models.Tariff, bicycleType string, at time.Time) int64 {
+	hour := at.Hour()
+
+	rate, generic := t.PerMinute, false
+	for _, r := range t.Rates {
+		if !inHours(hour, r.StartHour, r.EndHour) {
+			continue
+		}
+		if r.BicycleType == bicycleType {
+			return r.PerMinute
+		}
+		if r.BicycleType == "" && !generic {
+			rate, generic = r.PerMinute, true
+		}
+	}
+
+	return rate
+}
+
+func inHours(hour, start, end int) bool {
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return hour >= start && hour < end
+	default: // wraps past midnight
+		return hour >= start || hour < end
+	}
+}
+
+func capped(amount, limit int64) int64 {
+	if limit > 0 && amount > limit {
+		return limit
+	}
+	return amount
+}
@@ -0,0 +1,134 @@
+package pricing_test
+
+import (
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/pricing"
+	"testing"
+	"time"
+)
+
+func TestCost(t *testing.T) {
+	tariff := &models.Tariff{
+		UnlockFee:   1000,
+		PerMinute:   200,
+		DailyCap:    30000,
+		FreeMinutes: 5,
+		Rates: []models.TariffRate{
+			{BicycleType: models.BicycleTypeElectric, PerMinute: 400},
+			{StartHour: 22, EndHour: 6, PerMinute: 100},
+		},
+	}
+	noon := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		tariff      *models.Tariff
+		bicycleType string
+		start       time.Time
+		ride        time.Duration
+		want        int64
+	}{
+		{
+			name:        "unlock only",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeStandard,
+			start:       noon,
+			ride:        0,
+			want:        1000,
+		},
+		{
+			name:        "within free minutes",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeStandard,
+			start:       noon,
+			ride:        4*time.Minute + 30*time.Second,
+			want:        1000,
+		},
+		{
+			name:        "started minute is charged",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeStandard,
+			start:       noon,
+			ride:        10*time.Minute + time.Second,
+			want:        1000 + 6*200,
+		},
+		{
+			name:        "bicycle type rate",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeElectric,
+			start:       noon,
+			ride:        15 * time.Minute,
+			want:        1000 + 10*400,
+		},
+		{
+			name:        "night rate crossing the boundary",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeStandard,
+			start:       time.Date(2025, 5, 1, 21, 50, 0, 0, time.UTC),
+			ride:        20 * time.Minute,
+			want:        1000 + 5*200 + 10*100,
+		},
+		{
+			name:        "daily cap",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeElectric,
+			start:       noon,
+			ride:        5 * time.Hour,
+			want:        1000 + 30000,
+		},
+		{
+			name:        "cap applies per day",
+			tariff:      tariff,
+			bicycleType: models.BicycleTypeElectric,
+			start:       noon,
+			ride:        24*time.Hour + 10*time.Minute,
+			want:        1000 + 30000 + 10*400,
+		},
+		{
+			name:        "no cap",
+			tariff:      &models.Tariff{PerMinute: 200},
+			bicycleType: models.BicycleTypeStandard,
+			start:       noon,
+			ride:        5 * time.Hour,
+			want:        300 * 200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pricing.Cost(tt.tariff, tt.bicycleType, tt.start, tt.start.Add(tt.ride)); got != tt.want {
+				t.Errorf("Cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateAt(t *testing.T) {
+	tariff := &models.Tariff{
+		PerMinute: 200,
+		Rates: []models.TariffRate{
+			{BicycleType: models.BicycleTypeElectric, StartHour: 7, EndHour: 10, PerMinute: 500},
+			{StartHour: 7, EndHour: 10, PerMinute: 300},
+			{BicycleType: models.BicycleTypeElectric, PerMinute: 400},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		bicycleType string
+		hour        int
+		want        int64
+	}{
+		{name: "default", bicycleType: models.BicycleTypeStandard, hour: 12, want: 200},
+		{name: "rush hour", bicycleType: models.BicycleTypeStandard, hour: 8, want: 300},
+		{name: "electric rush hour", bicycleType: models.BicycleTypeElectric, hour: 8, want: 500},
+		{name: "electric", bicycleType: models.BicycleTypeElectric, hour: 12, want: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := time.Date(2025, 5, 1, tt.hour, 0, 0, 0, time.UTC)
+			if got := pricing.RateAt(tariff, tt.bicycleType, at); got != tt.want {
+				t.Errorf("RateAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "sdt-bicycle-rental/internal/models"
+
+type CreateBicycle struct {
+	StationID uint64 `json:"station_id" validate:"required"`
+	Type      string `json:"type" validate:"required,oneof=standard electric"`
+}
+
+func (dto *CreateBicycle) Model() *models.Bicycle {
+	return &models.Bicycle{
+		StationID: dto.StationID,
+		Type:      dto.Type,
+		Status:    models.BicycleStatusAvailable,
+	}
+}
+
+// BicycleFilter narrows bicycle listings, nil fields are not filtered on
+type BicycleFilter struct {
+	StationID *uint64
+	Status    *string
+}
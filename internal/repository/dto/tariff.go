@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"sdt-bicycle-rental/internal/models"
+	"time"
+)
+
+// CreateTariff describes a new tariff version, amounts are in cents
+type CreateTariff struct {
+	Name        string             `json:"name" validate:"required,max=64"`
+	UnlockFee   int64              `json:"unlock_fee" validate:"gte=0"`
+	PerMinute   int64              `json:"per_minute" validate:"gte=0"`
+	DailyCap    int64              `json:"daily_cap" validate:"gte=0"`
+	FreeMinutes int                `json:"free_minutes" validate:"gte=0"`
+	ActiveFrom  *time.Time         `json:"active_from,omitempty"` // defaults to now
+	Rates       []CreateTariffRate `json:"rates" validate:"dive"`
+}
+
+type CreateTariffRate struct {
+	BicycleType string `json:"bicycle_type,omitempty" validate:"omitempty,oneof=standard electric"`
+	StartHour   int    `json:"start_hour" validate:"gte=0,lte=23"`
+	EndHour     int    `json:"end_hour" validate:"gte=0,lte=23"`
+	PerMinute   int64  `json:"per_minute" validate:"gte=0"`
+}
+
+func (dto *CreateTariff) Model() *models.Tariff {
+	tariff := &models.Tariff{
+		Name:        dto.Name,
+		UnlockFee:   dto.UnlockFee,
+		PerMinute:   dto.PerMinute,
+		DailyCap:    dto.DailyCap,
+		FreeMinutes: dto.FreeMinutes,
+		ActiveFrom:  dto.ActiveFrom,
+	}
+	for _, rate := range dto.Rates {
+		tariff.Rates = append(tariff.Rates, models.TariffRate{
+			BicycleType: rate.BicycleType,
+			StartHour:   rate.StartHour,
+			EndHour:     rate.EndHour,
+			PerMinute:   rate.PerMinute,
+		})
+	}
+	return tariff
+}
@@ -3,11 +3,11 @@ package dto
 import "sdt-bicycle-rental/internal/models"
 
 type CreateUser struct {
-	Name     string `validate:"required,min=1,max=64"`
-	Lastname string `validate:"required,min=1,max=64"`
-	Email    string `validate:"required,email"`
-	Phone    string `validate:"required,max=64"`
-	Password string `validate:"required,min=8,max=255"`
+	Name     string `json:"name" validate:"required,min=1,max=64"`
+	Lastname string `json:"lastname" validate:"required,min=1,max=64"`
+	Email    string `json:"email" validate:"required,email"`
+	Phone    string `json:"phone" validate:"required,max=64"`
+	Password string `json:"password" validate:"required,min=8,max=255"`
 }
 
 func (dto *CreateUser) Model() *models.User {
@@ -21,8 +21,13 @@ func (dto *CreateUser) Model() *models.User {
 }
 
 type UpdateUser struct {
-	Name     *string `validate:"omitempty,min=1,max=64"`
-	Lastname *string `validate:"omitempty,min=1,max=64"`
-	Email    *string `validate:"omitempty,email"`
-	Phone    *string `validate:"omitempty,max=64"`
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=1,max=64"`
+	Lastname *string `json:"lastname,omitempty" validate:"omitempty,min=1,max=64"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,max=64"`
+}
+
+// IsEmpty reports whether the update does not change any field.
+func (dto *UpdateUser) IsEmpty() bool {
+	return dto.Name == nil && dto.Lastname == nil && dto.Email == nil && dto.Phone == nil
 }
@@ -0,0 +1,74 @@
+// Package memory keeps state in the process, for a single instance and for development.
+package memory
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository keeps the failed login counters in memory. Keys without failures
+// for longer than idle are dropped, so that probing many emails or addresses can't grow it forever.
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]models.LoginAttempt
+	idle     time.Duration
+	swept    time.Time
+}
+
+func NewLoginAttemptRepository(idle time.Duration) *LoginAttemptRepository {
+	return &LoginAttemptRepository{attempts: make(map[string]models.LoginAttempt), idle: idle, swept: time.Now()}
+}
+
+// Get returns the counters of key, zero ones if it has no failures recorded
+func (r *LoginAttemptRepository) Get(_ context.Context, key string) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = models.LoginAttempt{Key: key}
+	}
+	return &attempt, nil
+}
+
+// Update applies update to the counters of key, concurrent failures are counted one after another
+func (r *LoginAttemptRepository) Update(_ context.Context, key string, update func(attempt *models.LoginAttempt)) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(time.Now())
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = models.LoginAttempt{Key: key}
+	}
+	update(&attempt)
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Delete(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// sweep drops idle keys, at most once per idle period. The caller holds the lock.
+func (r *LoginAttemptRepository) sweep(now time.Time) {
+	if now.Sub(r.swept) < r.idle {
+		return
+	}
+	r.swept = now
+
+	for key, attempt := range r.attempts {
+		locked := attempt.LockedUntil != nil && attempt.LockedUntil.After(now)
+		if !locked && (attempt.LastFailureAt == nil || now.Sub(*attempt.LastFailureAt) >= r.idle) {
+			delete(r.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package memory_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/memory"
+	"sdt-bicycle-rental/lib/util"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewLoginAttemptRepository(time.Hour)
+
+	fail := func(a *models.LoginAttempt) {
+		a.Failures++
+		a.LastFailureAt = util.Ptr(time.Now())
+	}
+
+	t.Run("unknown key has no failures", func(t *testing.T) {
+		got, err := repo.Get(ctx, "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.Equal(t, &models.LoginAttempt{Key: "ip:192.0.2.1"}, got)
+	})
+
+	t.Run("concurrent updates are all counted", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Update(ctx, "account:a@example.com", fail)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		got, err := repo.Get(ctx, "account:a@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 50, got.Failures)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, "account:a@example.com"))
+
+		got, err := repo.Get(ctx, "account:a@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, got.Failures)
+	})
+}
+
+func TestLoginAttemptRepository_DropsIdleKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewLoginAttemptRepository(time.Millisecond)
+
+	_, err := repo.Update(ctx, "idle", func(a *models.LoginAttempt) {
+		a.Failures = 3
+		a.LastFailureAt = util.Ptr(time.Now().Add(-time.Hour))
+	})
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, "locked", func(a *models.LoginAttempt) {
+		a.Failures = 3
+		a.LastFailureAt = util.Ptr(time.Now().Add(-time.Hour))
+		a.LockedUntil = util.Ptr(time.Now().Add(time.Hour))
+	})
+	require.NoError(t, err)
+
+	time.Sleep(2 * time.Millisecond)
+	_, err = repo.Update(ctx, "other", func(*models.LoginAttempt) {})
+	require.NoError(t, err)
+
+	idle, err := repo.Get(ctx, "idle")
+	require.NoError(t, err)
+	assert.Zero(t, idle.Failures)
+
+	// a lockout outlives the idle period
+	locked, err := repo.Get(ctx, "locked")
+	require.NoError(t, err)
+	assert.Equal(t, 3, locked.Failures)
+}
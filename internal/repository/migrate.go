@@ -1,28 +1,295 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
-	"sdt-bicycle-rental/internal/models"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) error {
-	var modelsToMigrate = []any{
-		&models.User{},
-		&models.Admin{},
-		&models.Station{},
-		&models.Bicycle{},
-		&models.Payment{},
-		&models.Booking{},
-		&models.Rental{},
+var (
+	ErrSchemaOutdated = errors.New("database schema is older than the application expects, run migrate up")
+	ErrSchemaTooNew   = errors.New("database schema is newer than the application expects")
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// migrationLock serializes migrators running against the same database
+const migrationLock = 72_616_425
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   uint64     `gorm:"primaryKey;autoIncrement:false;type:BIGINT"`
+	Name      string     `gorm:"type:varchar(255);not null"`
+	AppliedAt *time.Time `gorm:"type:timestamp;not null;default:now()"`
+}
+
+// MigrationStatus tells whether a known migration is applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies versioned SQL migrations. Every step runs in its own transaction
+// together with its schema_migrations row, so a failed step leaves no trace.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration // ordered by version
+}
+
+// NewMigrator reads <version>_<name>.up.sql and <version>_<name>.down.sql pairs from fsys
+func NewMigrator(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
 	}
 
-	for _, model := range modelsToMigrate {
-		if err := db.AutoMigrate(model); err != nil {
-			return fmt.Errorf("failed to migrate model %T: %w", model, err)
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
 		}
 	}
 
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		if a.Version < b.Version {
+			return -1
+		}
+		if a.Version > b.Version {
+			return 1
+		}
+		return 0
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version the application expects
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the latest applied version, 0 for an empty database
+func (m *Migrator) Version() (uint64, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	return applied[len(applied)-1].Version, nil
+}
+
+// Check fails unless every known migration and no other is applied. It only reads,
+// a database that was never migrated counts as outdated.
+func (m *Migrator) Check() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	var missing, unknown []uint64
+	for _, migration := range m.migrations {
+		if !slices.ContainsFunc(applied, func(a SchemaMigration) bool { return a.Version == migration.Version }) {
+			missing = append(missing, migration.Version)
+		}
+	}
+	for _, a := range applied {
+		if !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == a.Version }) {
+			unknown = append(unknown, a.Version)
+		}
+	}
+
+	switch {
+	case len(unknown) > 0:
+		return fmt.Errorf("%w: versions %v are applied, application at %d", ErrSchemaTooNew, unknown, m.Latest())
+	case len(missing) > 0:
+		return fmt.Errorf("%w: versions %v are not applied, application at %d", ErrSchemaOutdated, missing, m.Latest())
+	}
+	return nil
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down() error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+
+	target := uint64(0)
+	for _, migration := range m.migrations {
+		if migration.Version < version {
+			target = migration.Version
+		}
+	}
+	return m.To(target)
+}
+
+// To migrates up or down to the given version, 0 rolls everything back
+func (m *Migrator) To(target uint64) error {
+	if target != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == target }) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= target {
+			if err := m.step(migration, true); err != nil {
+				return err
+			}
+		}
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if m.migrations[i].Version > target {
+			if err := m.step(m.migrations[i], false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Status lists the known migrations and when they were applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		for _, a := range applied {
+			if a.Version == migration.Version {
+				status.AppliedAt = a.AppliedAt
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// step applies (up) or rolls back (down) a single migration unless that's already done
+func (m *Migrator) step(migration Migration, up bool) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+
+		var count int64
+		if err := tx.Model(&SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if applied := count > 0; applied == up {
+			return nil
+		}
+
+		if up {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name}).Error
+		}
+
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+		}
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+}
+
+// applied lists the applied migrations by version without changing the schema,
+// none if schema_migrations doesn't exist yet
+func (m *Migrator) applied() ([]SchemaMigration, error) {
+	var exists bool
+	if err := m.db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var applied []SchemaMigration
+	if err := m.db.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT now()
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
 	return nil
 }
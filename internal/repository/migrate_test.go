@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"sdt-bicycle-rental/internal/repository"
+	"sdt-bicycle-rental/internal/repository/migrations"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []uint64
+		wantErr  bool
+	}{
+		{
+			name: "ordered by version",
+			fsys: fstest.MapFS{
+				"0010_add_index.up.sql":   {Data: []byte("CREATE INDEX ...")},
+				"0010_add_index.down.sql": {Data: []byte("DROP INDEX ...")},
+				"0002_init.up.sql":        {Data: []byte("CREATE TABLE ...")},
+				"0002_init.down.sql":      {Data: []byte("DROP TABLE ...")},
+				"README.md":               {Data: []byte("ignored")},
+			},
+			versions: []uint64{2, 10},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("CREATE TABLE ...")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("CREATE TABLE ...")},
+				"0001_other.down.sql": {Data: []byte("DROP TABLE ...")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero version",
+			fsys: fstest.MapFS{
+				"0000_init.up.sql":   {Data: []byte("CREATE TABLE ...")},
+				"0000_init.down.sql": {Data: []byte("DROP TABLE ...")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repository.LoadMigrations(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.versions) {
+				t.Fatalf("LoadMigrations() = %d migrations, want %d", len(got), len(tt.versions))
+			}
+			for i, m := range got {
+				if m.Version != tt.versions[i] {
+					t.Errorf("LoadMigrations()[%d].Version = %d, want %d", i, m.Version, tt.versions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := repository.LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("embedded migrations are invalid: %v", err)
+	}
+	if len(got) == 0 || got[0].Version != 1 {
+		t.Errorf("embedded migrations must start at version 1")
+	}
+}
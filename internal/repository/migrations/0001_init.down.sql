@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS rentals;
+DROP TABLE IF EXISTS tariff_rates;
+DROP TABLE IF EXISTS tariffs;
+DROP TABLE IF EXISTS bicycles;
+DROP TABLE IF EXISTS stations;
+DROP TABLE IF EXISTS admins;
+DROP TABLE IF EXISTS users;
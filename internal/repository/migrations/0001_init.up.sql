@@ -0,0 +1,158 @@
+-- Baseline schema. IF NOT EXISTS lets databases created by the old AutoMigrate startup adopt it.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(64),
+    lastname   VARCHAR(64),
+    email      VARCHAR(255),
+    phone      VARCHAR(64),
+    status     VARCHAR(64),
+    password   VARCHAR(255),
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone ON users (phone);
+
+CREATE TABLE IF NOT EXISTS admins (
+    user_id BIGINT PRIMARY KEY,
+    CONSTRAINT fk_admins_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS stations (
+    id              BIGSERIAL PRIMARY KEY,
+    location_street VARCHAR(255) NOT NULL,
+    bikes_available INT NOT NULL DEFAULT 0 CONSTRAINT chk_stations_bikes_available CHECK (bikes_available >= 0),
+    bikes_total     INT NOT NULL DEFAULT 0 CONSTRAINT chk_stations_bikes_total CHECK (bikes_total >= 0),
+    created_at      TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS bicycles (
+    id           BIGSERIAL PRIMARY KEY,
+    station_id   BIGINT NOT NULL,
+    type         VARCHAR(64) NOT NULL DEFAULT 'standard',
+    status       VARCHAR(64) NOT NULL,
+    last_service TIMESTAMP,
+    CONSTRAINT fk_bicycles_station FOREIGN KEY (station_id) REFERENCES stations (id)
+);
+CREATE INDEX IF NOT EXISTS idx_bicycles_station_id ON bicycles (station_id);
+CREATE INDEX IF NOT EXISTS idx_bicycles_status ON bicycles (status);
+
+CREATE TABLE IF NOT EXISTS tariffs (
+    id           BIGSERIAL PRIMARY KEY,
+    name         VARCHAR(64) NOT NULL,
+    unlock_fee   BIGINT NOT NULL,
+    per_minute   BIGINT NOT NULL,
+    daily_cap    BIGINT NOT NULL DEFAULT 0,
+    free_minutes INT NOT NULL DEFAULT 0,
+    active_from  TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_tariffs_active_from ON tariffs (active_from);
+
+CREATE TABLE IF NOT EXISTS tariff_rates (
+    id           BIGSERIAL PRIMARY KEY,
+    tariff_id    BIGINT NOT NULL,
+    bicycle_type VARCHAR(64),
+    start_hour   INT NOT NULL,
+    end_hour     INT NOT NULL,
+    per_minute   BIGINT NOT NULL,
+    CONSTRAINT fk_tariffs_rates FOREIGN KEY (tariff_id) REFERENCES tariffs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tariff_rates_tariff_id ON tariff_rates (tariff_id);
+
+CREATE TABLE IF NOT EXISTS rentals (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL,
+    bicycle_id       BIGINT NOT NULL,
+    station_start_id BIGINT NOT NULL,
+    station_end_id   BIGINT,
+    tariff_id        BIGINT,
+    status           VARCHAR(64) NOT NULL DEFAULT 'active',
+    start_time       TIMESTAMP NOT NULL,
+    end_time         TIMESTAMP,
+    total_cost       BIGINT,
+    CONSTRAINT fk_users_rentals FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_rentals_bicycle FOREIGN KEY (bicycle_id) REFERENCES bicycles (id),
+    CONSTRAINT fk_rentals_station_start FOREIGN KEY (station_start_id) REFERENCES stations (id),
+    CONSTRAINT fk_rentals_station_end FOREIGN KEY (station_end_id) REFERENCES stations (id),
+    CONSTRAINT fk_rentals_tariff FOREIGN KEY (tariff_id) REFERENCES tariffs (id)
+);
+CREATE INDEX IF NOT EXISTS idx_rentals_user_id ON rentals (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rentals_active_user ON rentals (user_id) WHERE status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rentals_active_bicycle ON rentals (bicycle_id) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS payments (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL,
+    rental_id      BIGINT,
+    booking_id     BIGINT,
+    method         VARCHAR(64) NOT NULL,
+    amount         BIGINT NOT NULL,
+    currency       VARCHAR(3) NOT NULL,
+    transaction_id VARCHAR(255),
+    status         VARCHAR(64) NOT NULL,
+    created_at     TIMESTAMP DEFAULT now(),
+    updated_at     TIMESTAMP,
+    CONSTRAINT fk_users_payments FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_rental_id ON payments (rental_id);
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments (booking_id);
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    bicycle_id BIGINT NOT NULL,
+    station_id BIGINT NOT NULL,
+    payment_id BIGINT,
+    rental_id  BIGINT,
+    status     VARCHAR(64) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_users_bookings FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_bookings_bicycle FOREIGN KEY (bicycle_id) REFERENCES bicycles (id),
+    CONSTRAINT fk_bookings_station FOREIGN KEY (station_id) REFERENCES stations (id),
+    CONSTRAINT fk_bookings_payment FOREIGN KEY (payment_id) REFERENCES payments (id),
+    CONSTRAINT fk_bookings_rental FOREIGN KEY (rental_id) REFERENCES rentals (id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings (user_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_active_user ON bookings (user_id) WHERE status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_active_bicycle ON bookings (bicycle_id) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL,
+    family_id      VARCHAR(64) NOT NULL,
+    token_hash     VARCHAR(64) NOT NULL,
+    expires_at     TIMESTAMP NOT NULL,
+    used_at        TIMESTAMP,
+    revoked_at     TIMESTAMP,
+    replaced_by_id BIGINT,
+    created_at     TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id    BIGINT NOT NULL,
+    role       VARCHAR(64) NOT NULL,
+    granted_by BIGINT,
+    created_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (user_id, role),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id             BIGSERIAL PRIMARY KEY,
+    actor_id       BIGINT,
+    action         VARCHAR(64) NOT NULL,
+    target_user_id BIGINT,
+    details        TEXT,
+    created_at     TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target_user_id ON audit_events (target_user_id);
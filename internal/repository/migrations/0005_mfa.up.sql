@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS totp_secrets (
+    user_id      BIGINT PRIMARY KEY,
+    secret       VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP,
+    last_step    BIGINT NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_totp_secrets_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
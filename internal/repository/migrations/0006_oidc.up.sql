@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS external_identities (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    provider   VARCHAR(64) NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    email      VARCHAR(255),
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_external_identities_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_identities_provider_subject ON external_identities (provider, subject);
+CREATE INDEX IF NOT EXISTS idx_external_identities_user_id ON external_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS oidc_states (
+    state_hash    VARCHAR(64) PRIMARY KEY,
+    provider      VARCHAR(64) NOT NULL,
+    nonce         VARCHAR(128) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at    TIMESTAMP NOT NULL,
+    created_at    TIMESTAMP DEFAULT now()
+);
@@ -0,0 +1,7 @@
+-- Emails are stored lowercased, rows from before are lowercased unless that would clash with another row.
+-- Two accounts whose emails differ only in case stop this migration, one of them has to be changed first.
+UPDATE users SET email = lower(email)
+WHERE email <> lower(email)
+  AND NOT EXISTS (SELECT 1 FROM users other WHERE other.email = lower(users.email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
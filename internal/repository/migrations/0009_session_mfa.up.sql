@@ -0,0 +1,3 @@
+-- When the session passed the second factor. Existing sessions have no record of it,
+-- they are refreshed only while the user doesn't need MFA.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS mfa_at TIMESTAMP;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_totp_secrets_setup_token_hash;
+ALTER TABLE totp_secrets DROP COLUMN IF EXISTS setup_expires_at;
+ALTER TABLE totp_secrets DROP COLUMN IF EXISTS setup_token_hash;
@@ -0,0 +1,5 @@
+-- Users who must use MFA before they enrolled get the secret through an emailed link,
+-- not in the login response. Only the hash of its token is stored, the link works once.
+ALTER TABLE totp_secrets ADD COLUMN IF NOT EXISTS setup_token_hash VARCHAR(64);
+ALTER TABLE totp_secrets ADD COLUMN IF NOT EXISTS setup_expires_at TIMESTAMP;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_totp_secrets_setup_token_hash ON totp_secrets (setup_token_hash);
@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema migrations.
+// Files are named <version>_<name>.up.sql and <version>_<name>.down.sql, versions only grow
+// and applied files are never edited: change the schema by adding the next version.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// BicycleRepository keeps station bike counters in sync with the bicycles
+// table: every write that changes where a bike is or whether it can be
+// rented adjusts Station.BikesTotal/BikesAvailable in the same transaction.
+type BicycleRepository struct {
+	db *gorm.DB
+}
+
+func NewBicycleRepository(db *gorm.DB) *BicycleRepository {
+	return &BicycleRepository{db: db}
+}
+
+func (r *BicycleRepository) Create(ctx context.Context, bicycle *models.Bicycle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(bicycle).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return gorm.ErrForeignKeyViolated // 23503 = foreign_key_violation
+			}
+			return err
+		}
+
+		stations := NewStationRepository(tx)
+		if err := stations.UpdateBikesTotal(bicycle.StationID, 1); err != nil {
+			return err
+		}
+		if bicycle.Status == models.BicycleStatusAvailable {
+			return stations.UpdateBikesAvailable(bicycle.StationID, 1)
+		}
+		return nil
+	})
+}
+
+func (r *BicycleRepository) GetByID(ctx context.Context, id uint64) (*models.Bicycle, error) {
+	var bicycle models.Bicycle
+	if err := r.db.WithContext(ctx).First(&bicycle, id).Error; err != nil {
+		return nil, err
+	}
+	return &bicycle, nil
+}
+
+func (r *BicycleRepository) List(ctx context.Context, filter dto.BicycleFilter, offset, limit int) ([]models.Bicycle, error) {
+	var bicycles []models.Bicycle
+	err := r.filtered(ctx, filter).Order("id").Offset(offset).Limit(limit).Find(&bicycles).Error
+	if err != nil {
+		return nil, err
+	}
+	return bicycles, nil
+}
+
+func (r *BicycleRepository) Count(ctx context.Context, filter dto.BicycleFilter) (int64, error) {
+	var count int64
+	if err := r.filtered(ctx, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateStatus saves bicycle.Status and bicycle.LastService if the bike is still in the from status
+// at bicycle.StationID. Returns gorm.ErrRecordNotFound if the bike does not exist,
+// or its status or station has changed meanwhile.
+func (r *BicycleRepository) UpdateStatus(ctx context.Context, bicycle *models.Bicycle, from string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Bicycle{}).
+			Where("id = ? AND station_id = ? AND status = ?", bicycle.ID, bicycle.StationID, from).
+			Updates(map[string]interface{}{
+				"status":       bicycle.Status,
+				"last_service": bicycle.LastService,
+			})
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		stations := NewStationRepository(tx)
+		if delta := availableDelta(from, bicycle.Status); delta != 0 {
+			if err := stations.UpdateBikesAvailable(bicycle.StationID, delta); err != nil {
+				return err
+			}
+		}
+		if bicycle.Status == models.BicycleStatusRetired {
+			return stations.UpdateBikesTotal(bicycle.StationID, -1)
+		}
+		return nil
+	})
+}
+
+// Move reassigns a bike in the given status from one station to another.
+// Returns gorm.ErrRecordNotFound if the bike is no longer at fromStationID in that status,
+// and gorm.ErrForeignKeyViolated if the target station does not exist.
+func (r *BicycleRepository) Move(ctx context.Context, id uint64, status string, fromStationID, toStationID uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Bicycle{}).
+			Where("id = ? AND station_id = ? AND status = ?", id, fromStationID, status).
+			Update("station_id", toStationID)
+		if err := res.Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return gorm.ErrForeignKeyViolated // 23503 = foreign_key_violation
+			}
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		stations := NewStationRepository(tx)
+		if err := stations.UpdateBikesTotal(fromStationID, -1); err != nil {
+			return err
+		}
+		if err := stations.UpdateBikesTotal(toStationID, 1); err != nil {
+			return err
+		}
+		if status == models.BicycleStatusAvailable {
+			if err := stations.UpdateBikesAvailable(fromStationID, -1); err != nil {
+				return err
+			}
+			return stations.UpdateBikesAvailable(toStationID, 1)
+		}
+		return nil
+	})
+}
+
+func (r *BicycleRepository) filtered(ctx context.Context, filter dto.BicycleFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Bicycle{})
+	if filter.StationID != nil {
+		query = query.Where("station_id = ?", *filter.StationID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	return query
+}
+
+// availableDelta returns how BikesAvailable changes when a bike goes from one status to another
+func availableDelta(from, to string) int {
+	switch {
+	case from == to:
+		return 0
+	case to == models.BicycleStatusAvailable:
+		return 1
+	case from == models.BicycleStatusAvailable:
+		return -1
+	}
+	return 0
+}
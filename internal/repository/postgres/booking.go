@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type BookingRepository struct {
+	db *gorm.DB
+}
+
+func NewBookingRepository(db *gorm.DB) *BookingRepository {
+	return &BookingRepository{db: db}
+}
+
+// Hold reserves an available bike at the booking's station and creates the active booking in one transaction.
+// Returns gorm.ErrRecordNotFound if the bike is not available at that station,
+// and gorm.ErrDuplicatedKey if the user already has an active booking.
+func (r *BookingRepository) Hold(ctx context.Context, booking *models.Booking) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Bicycle{}).
+			Where("id = ? AND station_id = ? AND status = ?", booking.BicycleID, booking.StationID, models.BicycleStatusAvailable).
+			Update("status", models.BicycleStatusReserved)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := NewStationRepository(tx).UpdateBikesAvailable(booking.StationID, -1); err != nil {
+			return err
+		}
+
+		if err := tx.Create(booking).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return gorm.ErrDuplicatedKey // 23505 = unique_violation
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Release ends an active booking with the given status and makes its bike available again.
+// Returns gorm.ErrRecordNotFound if the booking is not active anymore.
+func (r *BookingRepository) Release(ctx context.Context, booking *models.Booking, status string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ?", booking.ID, models.BookingStatusActive).
+			Update("status", status)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		res = tx.Model(&models.Bicycle{}).
+			Where("id = ? AND status = ?", booking.BicycleID, models.BicycleStatusReserved).
+			Update("status", models.BicycleStatusAvailable)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return NewStationRepository(tx).UpdateBikesAvailable(booking.StationID, 1)
+	})
+}
+
+// Convert turns an active, unexpired booking into a started rental in one transaction.
+// The reserved bike goes straight to rented, so station availability does not change.
+// Returns gorm.ErrRecordNotFound if the booking is not active or has expired,
+// and gorm.ErrDuplicatedKey if the user already has an active rental.
+func (r *BookingRepository) Convert(ctx context.Context, booking *models.Booking, rental *models.Rental) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ? AND expires_at > ?", booking.ID, models.BookingStatusActive, rental.StartTime).
+			Update("status", models.BookingStatusConverted)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		res = tx.Model(&models.Bicycle{}).
+			Where("id = ? AND status = ?", booking.BicycleID, models.BicycleStatusReserved).
+			Update("status", models.BicycleStatusRented)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Create(rental).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return gorm.ErrDuplicatedKey // 23505 = unique_violation
+			}
+			return err
+		}
+
+		return tx.Model(&models.Booking{}).Where("id = ?", booking.ID).Update("rental_id", rental.ID).Error
+	})
+}
+
+func (r *BookingRepository) GetByID(ctx context.Context, id uint64) (*models.Booking, error) {
+	var booking models.Booking
+	if err := r.db.WithContext(ctx).First(&booking, id).Error; err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// ListExpired returns up to limit active bookings whose hold ended before now
+func (r *BookingRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := r.db.WithContext(ctx).Where("status = ? AND expires_at <= ?", models.BookingStatusActive, now).
+		Order("expires_at").Limit(limit).Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+func (r *MFARepository) GetSecret(ctx context.Context, userID uint64) (*models.TOTPSecret, error) {
+	var secret models.TOTPSecret
+	if err := r.db.WithContext(ctx).First(&secret, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// SaveSecret stores a new unconfirmed secret, replacing an earlier unconfirmed one.
+// Returns gorm.ErrDuplicatedKey if the user already confirmed a secret.
+func (r *MFARepository) SaveSecret(ctx context.Context, secret *models.TOTPSecret) error {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"secret":           secret.Secret,
+			"last_step":        0,
+			"created_at":       time.Now(),
+			"setup_token_hash": nil,
+			"setup_expires_at": nil,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "totp_secrets.confirmed_at IS NULL"}}},
+	}).Create(secret)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+	return nil
+}
+
+// SaveSetupLink stores the token of a setup link for the unconfirmed secret of the user,
+// replacing an earlier link. The secret is kept if there is one already, so that an authenticator
+// added from an earlier link still works, otherwise secret is stored.
+// Returns gorm.ErrDuplicatedKey if the user already confirmed a secret.
+func (r *MFARepository) SaveSetupLink(ctx context.Context, secret *models.TOTPSecret) error {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"setup_token_hash": secret.SetupTokenHash,
+			"setup_expires_at": secret.SetupExpiresAt,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "totp_secrets.confirmed_at IS NULL"}}},
+	}).Create(secret)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+	return nil
+}
+
+// UseSetupLink consumes the token of an unexpired setup link and returns the unconfirmed secret.
+// Returns gorm.ErrRecordNotFound if the link is unknown, used or expired, or the secret was confirmed.
+func (r *MFARepository) UseSetupLink(ctx context.Context, tokenHash string) (*models.TOTPSecret, error) {
+	var secret models.TOTPSecret
+	res := r.db.WithContext(ctx).Model(&secret).
+		Clauses(clause.Returning{}).
+		Where("setup_token_hash = ? AND setup_expires_at > ? AND confirmed_at IS NULL", tokenHash, time.Now()).
+		Updates(map[string]any{"setup_token_hash": nil, "setup_expires_at": nil})
+	if err := res.Error; err != nil {
+		return nil, err
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &secret, nil
+}
+
+// Confirm enables the unconfirmed secret, accepting the code of step, marks the session
+// it was confirmed in as passed MFA and replaces the recovery codes of the user in one transaction.
+// Returns gorm.ErrRecordNotFound if there is no unconfirmed secret or the step was used.
+func (r *MFARepository) Confirm(ctx context.Context, userID uint64, sessionID string, step int64, recoveryHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.TOTPSecret{}).
+			Where("user_id = ? AND confirmed_at IS NULL AND last_step < ?", userID, step).
+			Updates(map[string]any{"confirmed_at": time.Now(), "last_step": step})
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if sessionID != "" {
+			err := tx.Model(&models.Session{}).
+				Where("id = ? AND user_id = ?", sessionID, userID).
+				Update("mfa_at", time.Now()).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(recoveryHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]models.RecoveryCode, len(recoveryHashes))
+		for i, hash := range recoveryHashes {
+			codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseStep accepts a code of the confirmed secret, only for a step later than the last accepted one.
+// Returns gorm.ErrRecordNotFound if the code was already used.
+func (r *MFARepository) UseStep(ctx context.Context, userID uint64, step int64) error {
+	res := r.db.WithContext(ctx).Model(&models.TOTPSecret{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_step < ?", userID, step).
+		Update("last_step", step)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UseRecoveryCode consumes an unused recovery code of the user.
+// Returns gorm.ErrRecordNotFound if there is none with the hash.
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	res := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
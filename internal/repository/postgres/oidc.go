@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OIDCRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) *OIDCRepository {
+	return &OIDCRepository{db: db}
+}
+
+// SaveState stores a started external login and drops the expired ones
+func (r *OIDCRepository) SaveState(ctx context.Context, state *models.OIDCState) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.OIDCState{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(state).Error
+	})
+}
+
+// TakeState removes the state and returns it, so a state is used once.
+// Returns gorm.ErrRecordNotFound if there is none with the hash.
+func (r *OIDCRepository) TakeState(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+	var state models.OIDCState
+	res := r.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&state)
+	if err := res.Error; err != nil {
+		return nil, err
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &state, nil
+}
+
+// GetIdentity returns the linked identity with its user
+func (r *OIDCRepository) GetIdentity(ctx context.Context, provider, subject string) (*models.ExternalIdentity, error) {
+	var identity models.ExternalIdentity
+	err := r.db.WithContext(ctx).Preload("User").
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Link links the identity to an existing user.
+// Returns gorm.ErrDuplicatedKey if the identity is linked already.
+func (r *OIDCRepository) Link(ctx context.Context, identity *models.ExternalIdentity) error {
+	return duplicated(r.db.WithContext(ctx).Create(identity).Error)
+}
+
+// CreateUser creates the user with the identity linked to it.
+// Returns gorm.ErrDuplicatedKey if the email is taken or the identity is linked already.
+func (r *OIDCRepository) CreateUser(ctx context.Context, user *models.User, identity *models.ExternalIdentity) error {
+	return duplicated(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	}))
+}
+
+func duplicated(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return gorm.ErrDuplicatedKey // 23505 = unique_violation
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create stores the token and retires the unused tokens issued to the user before it,
+// so only the latest emailed link works.
+func (r *PasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+}
+
+// GetValid returns the token with its user if it is neither used nor expired,
+// gorm.ErrRecordNotFound otherwise. Only Reset consumes it.
+func (r *PasswordResetRepository) GetValid(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.WithContext(ctx).Preload("User").
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Reset consumes the token, sets the new password of its user and revokes every refresh token
+// of the user in one transaction. It returns the user ID, or gorm.ErrRecordNotFound
+// if the token is unknown, already used or expired.
+func (r *PasswordResetRepository) Reset(ctx context.Context, tokenHash, passwordHash string) (uint64, error) {
+	var token models.PasswordResetToken
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&token).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "user_id"}}}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Update("used_at", now)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := tx.Model(&models.User{}).
+			Where("id = ?", token.UserID).
+			Update("password", passwordHash).Error
+		if err != nil {
+			return err
+		}
+
+		return revokeSessions(tx, "user_id = ?", token.UserID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return token.UserID, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// Create saves the payment and links it to its booking, if any
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return gorm.ErrDuplicatedKey // 23505 = unique_violation, the rental is already charged
+			}
+			return err
+		}
+		if payment.BookingID == nil {
+			return nil
+		}
+
+		res := tx.Model(&models.Booking{}).Where("id = ?", *payment.BookingID).Update("payment_id", payment.ID)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// UpdateStatus moves the payment from the given status to payment.Status
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, payment *models.Payment, from string) error {
+	res := r.db.WithContext(ctx).Model(&models.Payment{}).
+		Where("id = ? AND status = ?", payment.ID, from).
+		Updates(map[string]interface{}{
+			"status":         payment.Status,
+			"transaction_id": payment.TransactionID,
+			"updated_at":     time.Now(),
+		})
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *PaymentRepository) GetByID(ctx context.Context, id uint64) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.WithContext(ctx).First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// ListByUserID returns the user's payments, newest first
+func (r *PaymentRepository) ListByUserID(ctx context.Context, userID uint64, offset, limit int) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Offset(offset).Limit(limit).Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) CountByUserID(ctx context.Context, userID uint64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Payment{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"fmt"
 	"sdt-bicycle-rental/internal/config"
-	"sdt-bicycle-rental/internal/repository"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -26,9 +25,5 @@ func New(cfg config.Postgres) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to db: %w", err)
 	}
 
-	if err := repository.Migrate(db); err != nil {
-		return nil, fmt.Errorf("failed to migrate db: %w", err)
-	}
-
 	return db, nil
 }
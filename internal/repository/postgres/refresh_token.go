@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// CreateSession stores a new session with the first refresh token of its family
+func (r *RefreshTokenRepository) CreateSession(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.FamilyID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks the old token as used and stores the next one in a single transaction,
+// the session of the family is seen now and lasts as long as the next token.
+// It returns gorm.ErrRecordNotFound if the old token has already been used or revoked.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{
+				"used_at":        time.Now(),
+				"replaced_by_id": next.ID,
+			})
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&models.Session{}).
+			Where("id = ?", next.FamilyID).
+			Updates(map[string]interface{}{
+				"last_seen_at": time.Now(),
+				"expires_at":   next.ExpiresAt,
+			}).Error
+	})
+}
+
+// GetSession returns the session of the refresh token family, revoked or not
+func (r *RefreshTokenRepository) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SessionActive tells whether the session exists and is neither revoked nor expired
+func (r *RefreshTokenRepository) SessionActive(ctx context.Context, id string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// RevokeFamily revokes the refresh tokens of the family and its session
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "id = ?", familyID)
+	})
+}
+
+// RevokeAllForUser revokes every refresh token and session of the user
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "user_id = ?", userID)
+	})
+}
+
+// revokeSessions revokes the sessions matching the condition and their refresh tokens,
+// it has to run in a transaction
+func revokeSessions(tx *gorm.DB, query string, args ...interface{}) error {
+	now := time.Now()
+
+	err := tx.Model(&models.RefreshToken{}).
+		Where("family_id IN (?) AND revoked_at IS NULL", tx.Model(&models.Session{}).Select("id").Where(query, args...)).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Session{}).
+		Where(query, args...).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type RentalRepository struct {
+	db *gorm.DB
+}
+
+func NewRentalRepository(db *gorm.DB) *RentalRepository {
+	return &RentalRepository{db: db}
+}
+
+// Start takes an available bike from the rental's start station and creates the active rental in one transaction.
+// Returns gorm.ErrRecordNotFound if the bike is not available at that station,
+// and gorm.ErrDuplicatedKey if the user already has an active rental.
+func (r *RentalRepository) Start(ctx context.Context, rental *models.Rental) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The conditional update locks the bike row, so concurrent starts of the same bike serialize here
+		res := tx.Model(&models.Bicycle{}).
+			Where("id = ? AND station_id = ? AND status = ?", rental.BicycleID, rental.StationStartID, models.BicycleStatusAvailable).
+			Update("status", models.BicycleStatusRented)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := NewStationRepository(tx).UpdateBikesAvailable(rental.StationStartID, -1); err != nil {
+			return err
+		}
+
+		if err := tx.Create(rental).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return gorm.ErrDuplicatedKey // 23505 = unique_violation
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// End completes an active rental and returns its bike to rental.StationEndID in one transaction.
+// Returns gorm.ErrRecordNotFound if the rental is not active anymore,
+// and gorm.ErrForeignKeyViolated if the end station does not exist.
+func (r *RentalRepository) End(ctx context.Context, rental *models.Rental) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Rental{}).
+			Where("id = ? AND status = ?", rental.ID, models.RentalStatusActive).
+			Updates(map[string]interface{}{
+				"status":         models.RentalStatusCompleted,
+				"station_end_id": rental.StationEndID,
+				"end_time":       rental.EndTime,
+				"total_cost":     rental.TotalCost,
+			})
+		if err := res.Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return gorm.ErrForeignKeyViolated // 23503 = foreign_key_violation
+			}
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var bicycle models.Bicycle
+		if err := tx.First(&bicycle, rental.BicycleID).Error; err != nil {
+			return err
+		}
+
+		res = tx.Model(&bicycle).
+			Where("status = ?", models.BicycleStatusRented).
+			Updates(map[string]interface{}{
+				"status":     models.BicycleStatusAvailable,
+				"station_id": *rental.StationEndID,
+			})
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		stations := NewStationRepository(tx)
+		if bicycle.StationID != *rental.StationEndID {
+			if err := stations.UpdateBikesTotal(bicycle.StationID, -1); err != nil {
+				return err
+			}
+			if err := stations.UpdateBikesTotal(*rental.StationEndID, 1); err != nil {
+				return err
+			}
+		}
+		return stations.UpdateBikesAvailable(*rental.StationEndID, 1)
+	})
+}
+
+func (r *RentalRepository) GetByID(ctx context.Context, id uint64) (*models.Rental, error) {
+	var rental models.Rental
+	if err := r.db.WithContext(ctx).Preload("Bicycle").First(&rental, id).Error; err != nil {
+		return nil, err
+	}
+	return &rental, nil
+}
+
+func (r *RentalRepository) GetActiveByUserID(ctx context.Context, userID uint64) (*models.Rental, error) {
+	var rental models.Rental
+	err := r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, models.RentalStatusActive).First(&rental).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rental, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// GetByUserID returns the roles stored for the user. Admin role is read from the admins table.
+func (r *RoleRepository) GetByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	var roles []string
+	if err := r.db.WithContext(ctx).Model(&models.UserRole{}).Where("user_id = ?", userID).Order("role").Pluck("role", &roles).Error; err != nil {
+		return nil, err
+	}
+
+	var admins int64
+	if err := r.db.WithContext(ctx).Model(&models.Admin{}).Where("user_id = ?", userID).Count(&admins).Error; err != nil {
+		return nil, err
+	}
+	if admins > 0 {
+		roles = append(roles, models.RoleAdmin)
+	}
+
+	return roles, nil
+}
+
+// Grant stores the role and reports whether the user did not have it before.
+func (r *RoleRepository) Grant(ctx context.Context, userID uint64, role string, grantedBy uint64) (bool, error) {
+	var value any = &models.UserRole{UserID: userID, Role: role, GrantedBy: &grantedBy}
+	if role == models.RoleAdmin {
+		value = &models.Admin{UserID: userID}
+	}
+
+	tx := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(value)
+	if err := tx.Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return false, gorm.ErrForeignKeyViolated // 23503 = foreign_key_violation
+		}
+		return false, err
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// Revoke removes the role and reports whether the user had it.
+func (r *RoleRepository) Revoke(ctx context.Context, userID uint64, role string) (bool, error) {
+	var tx *gorm.DB
+	if role == models.RoleAdmin {
+		tx = r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.Admin{})
+	} else {
+		tx = r.db.WithContext(ctx).Where("user_id = ? AND role = ?", userID, role).Delete(&models.UserRole{})
+	}
+
+	if err := tx.Error; err != nil {
+		return false, err
+	}
+
+	return tx.RowsAffected > 0, nil
+}
@@ -1,8 +1,11 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"sdt-bicycle-rental/internal/models"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
@@ -14,20 +17,36 @@ func NewStationRepository(db *gorm.DB) *StationRepository {
 	return &StationRepository{db: db}
 }
 
-func (r *StationRepository) Create(station *models.Station) error {
-	return r.db.Create(station).Error
+func (r *StationRepository) Create(ctx context.Context, station *models.Station) error {
+	return r.db.WithContext(ctx).Create(station).Error
 }
 
-func (r *StationRepository) GetByID(id uint64) (*models.Station, error) {
+func (r *StationRepository) GetByID(ctx context.Context, id uint64) (*models.Station, error) {
 	var station models.Station
-	if err := r.db.First(&station, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&station, id).Error; err != nil {
 		return nil, err
 	}
 	return &station, nil
 }
 
-func (r *StationRepository) Update(station *models.Station) error {
-	tx := r.db.Updates(station)
+func (r *StationRepository) List(ctx context.Context, offset, limit int) ([]models.Station, error) {
+	var stations []models.Station
+	if err := r.db.WithContext(ctx).Order("id").Offset(offset).Limit(limit).Find(&stations).Error; err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
+
+func (r *StationRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Station{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *StationRepository) Update(ctx context.Context, station *models.Station) error {
+	tx := r.db.WithContext(ctx).Updates(station)
 	if err := tx.Error; err != nil {
 		return err
 	}
@@ -38,8 +57,20 @@ func (r *StationRepository) Update(station *models.Station) error {
 	return nil
 }
 
-func (r *StationRepository) Delete(id uint64) error {
-	return r.db.Delete(&models.Station{}, id).Error
+func (r *StationRepository) Delete(ctx context.Context, id uint64) error {
+	tx := r.db.WithContext(ctx).Delete(&models.Station{}, id)
+	if err := tx.Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return gorm.ErrForeignKeyViolated // 23503 = foreign_key_violation
+		}
+		return err
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
 
 func (r *StationRepository) UpdateBikesAvailable(id uint64, delta int) error {
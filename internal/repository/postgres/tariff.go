@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TariffRepository struct {
+	db *gorm.DB
+}
+
+func NewTariffRepository(db *gorm.DB) *TariffRepository {
+	return &TariffRepository{db: db}
+}
+
+// Create saves the tariff together with its rates
+func (r *TariffRepository) Create(ctx context.Context, tariff *models.Tariff) error {
+	return r.db.WithContext(ctx).Create(tariff).Error
+}
+
+func (r *TariffRepository) GetByID(ctx context.Context, id uint64) (*models.Tariff, error) {
+	var tariff models.Tariff
+	if err := r.db.WithContext(ctx).Preload("Rates", orderByID).First(&tariff, id).Error; err != nil {
+		return nil, err
+	}
+	return &tariff, nil
+}
+
+// GetCurrent returns the latest tariff that took effect at or before now
+func (r *TariffRepository) GetCurrent(ctx context.Context, now time.Time) (*models.Tariff, error) {
+	var tariff models.Tariff
+	err := r.db.WithContext(ctx).Preload("Rates", orderByID).
+		Where("active_from <= ?", now).
+		Order("active_from DESC, id DESC").
+		First(&tariff).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tariff, nil
+}
+
+func (r *TariffRepository) List(ctx context.Context, offset, limit int) ([]models.Tariff, error) {
+	var tariffs []models.Tariff
+	err := r.db.WithContext(ctx).Preload("Rates", orderByID).Order("id DESC").Offset(offset).Limit(limit).Find(&tariffs).Error
+	if err != nil {
+		return nil, err
+	}
+	return tariffs, nil
+}
+
+func (r *TariffRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Tariff{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// orderByID keeps rates in the order they were defined, the first matching rate wins
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
@@ -1,11 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"errors"
 	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/lib/util"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -16,8 +20,8 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(user *models.User) error {
-	if err := r.db.Create(user).Error; err != nil {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" {
@@ -29,17 +33,17 @@ func (r *UserRepository) Create(user *models.User) error {
 	return nil
 }
 
-func (r *UserRepository) GetByID(id uint64) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint64) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetByIDWithRelations(id uint64) (*models.User, error) {
+func (r *UserRepository) GetByIDWithRelations(ctx context.Context, id uint64) (*models.User, error) {
 	var user models.User
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Preload("Bookings", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC").Limit(10)
 		}).
@@ -58,17 +62,90 @@ func (r *UserRepository) GetByIDWithRelations(id uint64) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("lower(email) = lower(?)", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *models.User) error {
-	// Updates func ignore nil fields
-	tx := r.db.Updates(user)
+// Update applies the non-nil name, lastname, email and phone of user. A changed email or phone
+// is not verified anymore: its verified time is cleared, codes sent for it are void
+// and an active user is pending until it is verified again.
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var current models.User
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if user.Name != nil {
+			updates["name"] = *user.Name
+		}
+		if user.Lastname != nil {
+			updates["lastname"] = *user.Lastname
+		}
+		var unverified []string
+		if user.Email != nil && *user.Email != util.Deref(current.Email) {
+			updates["email"], updates["email_verified_at"] = *user.Email, nil
+			unverified = append(unverified, models.VerificationChannelEmail)
+		}
+		if user.Phone != nil && *user.Phone != util.Deref(current.Phone) {
+			updates["phone"], updates["phone_verified_at"] = *user.Phone, nil
+			unverified = append(unverified, models.VerificationChannelPhone)
+		}
+		if len(unverified) > 0 && util.Deref(current.Status) == models.UserStatusActive {
+			updates["status"] = models.UserStatusPending
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if err := db.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if len(unverified) == 0 {
+			return nil
+		}
+
+		return db.Model(&models.VerificationCode{}).
+			Where("user_id = ? AND channel IN ? AND used_at IS NULL", user.ID, unverified).
+			Update("used_at", time.Now()).Error
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return gorm.ErrDuplicatedKey // 23505 = unique_violation
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ChangePassword sets the password hash and signs the user out on every device but the one of keepSession
+func (r *UserRepository) ChangePassword(ctx context.Context, id uint64, passwordHash, keepSession string) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		tx := db.Model(&models.User{}).Where("id = ?", id).Update("password", passwordHash)
+		if err := tx.Error; err != nil {
+			return err
+		}
+		if tx.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return revokeSessions(db, "user_id = ? AND id <> ?", id, keepSession)
+	})
+}
+
+// UpdatePasswordHash replaces an outdated hash of the same password. The hash is kept
+// if it is no longer oldHash, a password changed meanwhile wins.
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, id uint64, oldHash, newHash string) error {
+	tx := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND password = ?", id, oldHash).
+		Update("password", newHash)
 	if err := tx.Error; err != nil {
 		return err
 	}
@@ -79,15 +156,80 @@ func (r *UserRepository) Update(user *models.User) error {
 	return nil
 }
 
-func (r *UserRepository) AnonymizeAndMarkDeleted(id uint64) error {
-	return r.db.Model(&models.User{}).Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"name":       nil,
-			"lastname":   nil,
-			"email":      nil,
-			"phone":      nil,
-			"password":   nil,
-			"created_at": nil,
-			"status":     models.UserStatusDeleted,
-		}).Error
+// AnonymizeAndMarkDeleted erases the personal data of the user and signs them out on every device
+func (r *UserRepository) AnonymizeAndMarkDeleted(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		tx := db.Model(&models.User{}).Where("id = ? AND status <> ?", id, models.UserStatusDeleted).
+			Updates(map[string]interface{}{
+				"name":       nil,
+				"lastname":   nil,
+				"email":      nil,
+				"phone":      nil,
+				"password":   nil,
+				"created_at": nil,
+				"status":     models.UserStatusDeleted,
+			})
+		if err := tx.Error; err != nil {
+			return err
+		}
+		if tx.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := revokeSessions(db, "user_id = ?", id); err != nil {
+			return err
+		}
+
+		// the identities hold the email at the provider and would sign in to the deleted account
+		return db.Where("user_id = ?", id).Delete(&models.ExternalIdentity{}).Error
+	})
+}
+
+// Ban bans the user and signs them out on every device. Returns false if the user is banned already
+// and gorm.ErrRecordNotFound if there is no such user or it is deleted.
+func (r *UserRepository) Ban(ctx context.Context, id uint64) (bool, error) {
+	var banned bool
+	err := r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		var user models.User
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ? AND status <> ?", id, models.UserStatusDeleted).Error; err != nil {
+			return err
+		}
+		if util.Deref(user.Status) == models.UserStatusBanned {
+			return nil
+		}
+
+		if err := db.Model(&models.User{}).Where("id = ?", id).Update("status", models.UserStatusBanned).Error; err != nil {
+			return err
+		}
+		banned = true
+
+		return revokeSessions(db, "user_id = ?", id)
+	})
+
+	return banned, err
+}
+
+// Unban lifts the ban: the user is active again if the email and phone are verified, pending otherwise.
+// Returns false if the user is not banned and gorm.ErrRecordNotFound if there is no such user.
+func (r *UserRepository) Unban(ctx context.Context, id uint64) (bool, error) {
+	tx := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND status = ?", id, models.UserStatusBanned).
+		Update("status", gorm.Expr("CASE WHEN email_verified_at IS NOT NULL AND phone_verified_at IS NOT NULL THEN ? ELSE ? END",
+			models.UserStatusActive, models.UserStatusPending))
+	if err := tx.Error; err != nil {
+		return false, err
+	}
+	if tx.RowsAffected > 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND status <> ?", id, models.UserStatusDeleted).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	return false, nil
 }
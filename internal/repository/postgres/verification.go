@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type VerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewVerificationRepository(db *gorm.DB) *VerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+// Create stores the code and retires the unused codes sent to the user on the same channel,
+// so only the latest one works.
+func (r *VerificationRepository) Create(ctx context.Context, code *models.VerificationCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.VerificationCode{}).
+			Where("user_id = ? AND channel = ? AND used_at IS NULL", code.UserID, code.Channel).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(code).Error
+	})
+}
+
+// Latest returns the last code sent to the user on the channel, used or not
+func (r *VerificationRepository) Latest(ctx context.Context, userID uint64, channel string) (*models.VerificationCode, error) {
+	var code models.VerificationCode
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND channel = ?", userID, channel).
+		Order("created_at DESC, id DESC").
+		First(&code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (r *VerificationRepository) GetByHash(ctx context.Context, channel, hash string) (*models.VerificationCode, error) {
+	var code models.VerificationCode
+	if err := r.db.WithContext(ctx).Where("channel = ? AND code_hash = ?", channel, hash).First(&code).Error; err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// AddAttempt records a wrong guess of the code
+func (r *VerificationRepository) AddAttempt(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Model(&models.VerificationCode{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// Verify consumes the code and marks its channel verified in one transaction. A pending user
+// with both the email and the phone verified becomes active.
+// Returns gorm.ErrRecordNotFound if the code has been used in the meantime.
+func (r *VerificationRepository) Verify(ctx context.Context, code *models.VerificationCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.VerificationCode{}).
+			Where("id = ? AND used_at IS NULL", code.ID).
+			Update("used_at", now)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		column := "email_verified_at"
+		if code.Channel == models.VerificationChannelPhone {
+			column = "phone_verified_at"
+		}
+		err := tx.Model(&models.User{}).
+			Where("id = ? AND "+column+" IS NULL", code.UserID).
+			Update(column, now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ? AND status = ? AND email_verified_at IS NOT NULL AND phone_verified_at IS NOT NULL", code.UserID, models.UserStatusPending).
+			Update("status", models.UserStatusActive).Error
+	})
+}
@@ -0,0 +1,174 @@
+package access_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=RoleRepository
+type RoleRepository interface {
+	GetByUserID(ctx context.Context, userID uint64) ([]string, error)
+	Grant(ctx context.Context, userID uint64, role string, grantedBy uint64) (bool, error)
+	Revoke(ctx context.Context, userID uint64, role string) (bool, error)
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	Ban(ctx context.Context, id uint64) (bool, error)
+	Unban(ctx context.Context, id uint64) (bool, error)
+}
+
+//go:generate mockery --name=AuditRepository
+type AuditRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+}
+
+type AccessService struct {
+	repo  RoleRepository
+	users UserRepository
+	audit AuditRepository
+	log   *slog.Logger
+}
+
+func New(repo RoleRepository, users UserRepository, audit AuditRepository, log *slog.Logger) *AccessService {
+	return &AccessService{repo: repo, users: users, audit: audit, log: log}
+}
+
+// Roles returns every role of the user. All users are riders.
+func (s *AccessService) Roles(ctx context.Context, userID uint64) ([]string, error) {
+	const op = "services.AccessService.Roles"
+
+	roles, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to get roles", slog.Uint64("user_id", userID), sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return append([]string{models.RoleRider}, roles...), nil
+}
+
+// Grant gives the role to the user on behalf of the actor.
+func (s *AccessService) Grant(ctx context.Context, actorID, userID uint64, role string) error {
+	const op = "services.AccessService.Grant"
+
+	if !IsKnownRole(role) || role == models.RoleRider {
+		s.log.InfoContext(ctx, op, "invalid role", slog.String("role", role))
+		return service.ErrInvalidRole
+	}
+
+	granted, err := s.repo.Grant(ctx, userID, role, actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("user_id", userID))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to grant role", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if granted {
+		s.record(ctx, actorID, userID, models.AuditActionRoleGranted, fmt.Sprintf(`{"role":%q}`, role))
+	}
+
+	return nil
+}
+
+// Revoke takes the role away from the user on behalf of the actor.
+// Admins can not revoke their own admin role so the system always keeps at least one admin.
+func (s *AccessService) Revoke(ctx context.Context, actorID, userID uint64, role string) error {
+	const op = "services.AccessService.Revoke"
+
+	if !IsKnownRole(role) || role == models.RoleRider {
+		s.log.InfoContext(ctx, op, "invalid role", slog.String("role", role))
+		return service.ErrInvalidRole
+	}
+
+	if role == models.RoleAdmin && actorID == userID {
+		s.log.InfoContext(ctx, op, "admin tried to revoke own admin role", slog.Uint64("user_id", userID))
+		return service.ErrForbidden
+	}
+
+	revoked, err := s.repo.Revoke(ctx, userID, role)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to revoke role", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if revoked {
+		s.record(ctx, actorID, userID, models.AuditActionRoleRevoked, fmt.Sprintf(`{"role":%q}`, role))
+	}
+
+	return nil
+}
+
+// Ban bans the user on behalf of the actor, the user is signed out on every device.
+// Admins can not ban themselves so the system always keeps at least one admin.
+func (s *AccessService) Ban(ctx context.Context, actorID, userID uint64) error {
+	const op = "services.AccessService.Ban"
+
+	if actorID == userID {
+		s.log.InfoContext(ctx, op, "admin tried to ban themselves", slog.Uint64("user_id", userID))
+		return service.ErrForbidden
+	}
+
+	banned, err := s.users.Ban(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("user_id", userID))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to ban user", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if banned {
+		s.record(ctx, actorID, userID, models.AuditActionUserBanned, "{}")
+	}
+
+	return nil
+}
+
+// Unban lifts the ban of the user on behalf of the actor.
+func (s *AccessService) Unban(ctx context.Context, actorID, userID uint64) error {
+	const op = "services.AccessService.Unban"
+
+	unbanned, err := s.users.Unban(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("user_id", userID))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to unban user", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if unbanned {
+		s.record(ctx, actorID, userID, models.AuditActionUserUnbanned, "{}")
+	}
+
+	return nil
+}
+
+// record saves the decision to the audit log. Failing to save it does not undo the decision.
+func (s *AccessService) record(ctx context.Context, actorID, userID uint64, action, details string) {
+	const op = "services.AccessService.record"
+
+	s.log.InfoContext(ctx, op, "audit", slog.String("action", action), slog.Uint64("actor_id", actorID), slog.Uint64("user_id", userID), slog.String("details", details))
+
+	event := &models.AuditEvent{
+		ActorID:      &actorID,
+		Action:       action,
+		TargetUserID: &userID,
+		Details:      details,
+	}
+	if err := s.audit.Create(ctx, event); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save audit event", sl.Err(err))
+	}
+}
@@ -0,0 +1,315 @@
+package access_service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	mocks "sdt-bicycle-rental/internal/service/access/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	adminID = uint64(1)
+	userID  = uint64(2)
+)
+
+func TestAccessService_Roles(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  []string
+		mockErr error
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "rider only",
+			want: []string{models.RoleRider},
+		},
+		{
+			name:   "staff",
+			stored: []string{models.RoleMechanic, models.RoleAdmin},
+			want:   []string{models.RoleRider, models.RoleMechanic, models.RoleAdmin},
+		},
+		{
+			name:    "repository error",
+			mockErr: errors.New("db is down"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRoleRepository(t)
+			repo.On("GetByUserID", mock.Anything, userID).Return(tt.stored, tt.mockErr).Once()
+
+			s := access_service.New(repo, mocks.NewUserRepository(t), mocks.NewAuditRepository(t), slogdiscard.NewDiscardLogger())
+
+			got, err := s.Roles(context.Background(), userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AccessService.Roles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AccessService.Roles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessService_Grant(t *testing.T) {
+	isGrantEvent := func(role string) any {
+		return mock.MatchedBy(func(e *models.AuditEvent) bool {
+			return e.Action == models.AuditActionRoleGranted && *e.ActorID == adminID && *e.TargetUserID == userID &&
+				strings.Contains(e.Details, role)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		role    string
+		setup   func(repo *mocks.RoleRepository, audit *mocks.AuditRepository)
+		wantErr error
+	}{
+		{
+			name: "success",
+			role: models.RoleMechanic,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Grant", mock.Anything, userID, models.RoleMechanic, adminID).Return(true, nil).Once()
+				audit.On("Create", mock.Anything, isGrantEvent(models.RoleMechanic)).Return(nil).Once()
+			},
+		},
+		{
+			name: "already granted is not audited",
+			role: models.RoleAdmin,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Grant", mock.Anything, userID, models.RoleAdmin, adminID).Return(false, nil).Once()
+			},
+		},
+		{
+			name: "audit failure does not fail grant",
+			role: models.RoleStationOperator,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Grant", mock.Anything, userID, models.RoleStationOperator, adminID).Return(true, nil).Once()
+				audit.On("Create", mock.Anything, mock.Anything).Return(errors.New("db is down")).Once()
+			},
+		},
+		{
+			name:    "unknown role",
+			role:    "superuser",
+			setup:   func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {},
+			wantErr: service.ErrInvalidRole,
+		},
+		{
+			name:    "implicit rider role",
+			role:    models.RoleRider,
+			setup:   func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {},
+			wantErr: service.ErrInvalidRole,
+		},
+		{
+			name: "user not found",
+			role: models.RoleMechanic,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Grant", mock.Anything, userID, models.RoleMechanic, adminID).Return(false, gorm.ErrForeignKeyViolated).Once()
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRoleRepository(t)
+			audit := mocks.NewAuditRepository(t)
+			tt.setup(repo, audit)
+
+			s := access_service.New(repo, mocks.NewUserRepository(t), audit, slogdiscard.NewDiscardLogger())
+
+			if err := s.Grant(context.Background(), adminID, userID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AccessService.Grant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessService_Revoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID uint64
+		role    string
+		setup   func(repo *mocks.RoleRepository, audit *mocks.AuditRepository)
+		wantErr error
+	}{
+		{
+			name:    "success",
+			actorID: adminID,
+			role:    models.RoleAdmin,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Revoke", mock.Anything, userID, models.RoleAdmin).Return(true, nil).Once()
+				audit.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditEvent) bool {
+					return e.Action == models.AuditActionRoleRevoked
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:    "own admin role",
+			actorID: userID,
+			role:    models.RoleAdmin,
+			setup:   func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {},
+			wantErr: service.ErrForbidden,
+		},
+		{
+			name:    "repository error",
+			actorID: adminID,
+			role:    models.RoleMechanic,
+			setup: func(repo *mocks.RoleRepository, audit *mocks.AuditRepository) {
+				repo.On("Revoke", mock.Anything, userID, models.RoleMechanic).Return(false, errors.New("db is down")).Once()
+			},
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRoleRepository(t)
+			audit := mocks.NewAuditRepository(t)
+			tt.setup(repo, audit)
+
+			s := access_service.New(repo, mocks.NewUserRepository(t), audit, slogdiscard.NewDiscardLogger())
+
+			if err := s.Revoke(context.Background(), tt.actorID, userID, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AccessService.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessService_Ban(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID uint64
+		setup   func(users *mocks.UserRepository, audit *mocks.AuditRepository)
+		wantErr error
+	}{
+		{
+			name:    "success",
+			actorID: adminID,
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Ban", mock.Anything, userID).Return(true, nil).Once()
+				audit.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditEvent) bool {
+					return e.Action == models.AuditActionUserBanned && *e.ActorID == adminID && *e.TargetUserID == userID
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:    "already banned is not audited",
+			actorID: adminID,
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Ban", mock.Anything, userID).Return(false, nil).Once()
+			},
+		},
+		{
+			name:    "themselves",
+			actorID: userID,
+			setup:   func(users *mocks.UserRepository, audit *mocks.AuditRepository) {},
+			wantErr: service.ErrForbidden,
+		},
+		{
+			name:    "user not found",
+			actorID: adminID,
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Ban", mock.Anything, userID).Return(false, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+		{
+			name:    "repository error",
+			actorID: adminID,
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Ban", mock.Anything, userID).Return(false, errors.New("db is down")).Once()
+			},
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			audit := mocks.NewAuditRepository(t)
+			tt.setup(users, audit)
+
+			s := access_service.New(mocks.NewRoleRepository(t), users, audit, slogdiscard.NewDiscardLogger())
+
+			if err := s.Ban(context.Background(), tt.actorID, userID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AccessService.Ban() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessService_Unban(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(users *mocks.UserRepository, audit *mocks.AuditRepository)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Unban", mock.Anything, userID).Return(true, nil).Once()
+				audit.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditEvent) bool {
+					return e.Action == models.AuditActionUserUnbanned
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "not banned is not audited",
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Unban", mock.Anything, userID).Return(false, nil).Once()
+			},
+		},
+		{
+			name: "user not found",
+			setup: func(users *mocks.UserRepository, audit *mocks.AuditRepository) {
+				users.On("Unban", mock.Anything, userID).Return(false, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			audit := mocks.NewAuditRepository(t)
+			tt.setup(users, audit)
+
+			s := access_service.New(mocks.NewRoleRepository(t), users, audit, slogdiscard.NewDiscardLogger())
+
+			if err := s.Unban(context.Background(), adminID, userID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AccessService.Unban() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		roles []string
+		perm  access_service.Permission
+		want  bool
+	}{
+		{roles: []string{models.RoleRider}, perm: access_service.PermManageStations, want: false},
+		{roles: []string{models.RoleRider, models.RoleStationOperator}, perm: access_service.PermManageBicycles, want: true},
+		{roles: []string{models.RoleStationOperator}, perm: access_service.PermManageStations, want: false},
+		{roles: []string{models.RoleMechanic}, perm: access_service.PermMaintainBicycle, want: true},
+		{roles: []string{models.RoleMechanic}, perm: access_service.PermBanUsers, want: false},
+		{roles: []string{models.RoleAdmin}, perm: access_service.PermManageRoles, want: true},
+		{roles: []string{"unknown"}, perm: access_service.PermManageRoles, want: false},
+	}
+	for _, tt := range tests {
+		if got := access_service.HasPermission(tt.roles, tt.perm); got != tt.want {
+			t.Errorf("HasPermission(%v, %v) = %v, want %v", tt.roles, tt.perm, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package access_service
+
+import (
+	"sdt-bicycle-rental/internal/models"
+	"slices"
+)
+
+type Permission string
+
+const (
+	PermManageStations  Permission = "stations:manage"
+	PermManageBicycles  Permission = "bicycles:manage"
+	PermMaintainBicycle Permission = "bicycles:maintain"
+	PermBanUsers        Permission = "users:ban"
+	PermManageRoles     Permission = "roles:manage"
+	PermManageTariffs   Permission = "tariffs:manage"
+	PermRefundPayments  Permission = "payments:refund"
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleRider:           {},
+	models.RoleStationOperator: {PermManageBicycles},
+	models.RoleMechanic:        {PermMaintainBicycle},
+	models.RoleAdmin: {
+		PermManageStations,
+		PermManageBicycles,
+		PermMaintainBicycle,
+		PermBanUsers,
+		PermManageRoles,
+		PermManageTariffs,
+		PermRefundPayments,
+	},
+}
+
+// IsKnownRole reports whether the role is defined by the access policy.
+func IsKnownRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether any of the roles grants the permission.
+func HasPermission(roles []string, perm Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(rolePermissions[role], perm) {
+			return true
+		}
+	}
+	return false
+}
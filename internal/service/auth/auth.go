@@ -1,196 +1,398 @@
 package auth_service
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"log/slog"
+	"sdt-bicycle-rental/internal/metrics"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/dto"
 	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/internal/tracing"
 	"sdt-bicycle-rental/lib/logger/sl"
 	"sdt-bicycle-rental/lib/util"
-	"sdt-bicycle-rental/lib/validation"
 	"time"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 //go:generate mockery --name=UserRepository
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByID(id uint64) (*models.User, error)
-	GetByIDWithRelations(id uint64) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
-	AnonymizeAndMarkDeleted(id uint64) error
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+	GetByIDWithRelations(ctx context.Context, id uint64) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdatePasswordHash(ctx context.Context, id uint64, oldHash, newHash string) error
+	AnonymizeAndMarkDeleted(ctx context.Context, id uint64) error
+}
+
+//go:generate mockery --name=TokenIssuer
+type TokenIssuer interface {
+	Issue(ctx context.Context, user *models.User, device token_service.Device, mfa bool) (*token_service.Pair, error)
+	IssueChallenge(user *models.User) (string, time.Duration, error)
+	ValidateChallenge(token string) (uint64, error)
+}
+
+// Verifier sends the email link and the phone code to a new user
+//
+//go:generate mockery --name=Verifier
+type Verifier interface {
+	Start(ctx context.Context, user *models.User) error
+}
+
+// Limiter throttles failed logins per account and per client IP
+//
+//go:generate mockery --name=Limiter
+type Limiter interface {
+	Check(ctx context.Context, email, ip string) error
+	Fail(ctx context.Context, email, ip string)
+	Succeed(ctx context.Context, email string)
+}
+
+// SecondFactor checks authenticator and recovery codes of users with MFA
+//
+//go:generate mockery --name=SecondFactor
+type SecondFactor interface {
+	Status(ctx context.Context, userID uint64) (enabled, required bool, err error)
+	SendSetupLink(ctx context.Context, userID uint64) error
+	Confirm(ctx context.Context, userID uint64, sessionID string, code string) ([]string, error)
+	Verify(ctx context.Context, userID uint64, code string) error
+}
+
+// PasswordHasher checks passwords of new users against the policy and verifies them at login
+//
+//go:generate mockery --name=PasswordHasher
+type PasswordHasher interface {
+	Check(password string, personal ...string) error
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (ok, rehash bool, err error)
+}
+
+// Challenge is returned by Login instead of tokens when the user has to present a second factor.
+// SetupLinkSent is set for users who must use MFA but have not set up an authenticator yet,
+// they add it from the emailed link and give its first code as the second factor.
+type Challenge struct {
+	Token         string
+	ExpiresIn     time.Duration
+	SetupLinkSent bool
 }
 
 type AuthService struct {
 	repo      UserRepository
+	tokens    TokenIssuer
+	verifier  Verifier
+	limiter   Limiter
+	mfa       SecondFactor
+	passwords PasswordHasher
 	log       *slog.Logger
-	jwtSecret string
 }
 
-func New(repo UserRepository, log *slog.Logger, jwtSecret string) *AuthService {
-	return &AuthService{repo: repo, log: log, jwtSecret: jwtSecret}
+func New(repo UserRepository, tokens TokenIssuer, verifier Verifier, limiter Limiter, mfa SecondFactor, passwords PasswordHasher, log *slog.Logger) *AuthService {
+	return &AuthService{repo: repo, tokens: tokens, verifier: verifier, limiter: limiter, mfa: mfa, passwords: passwords, log: log}
+}
+
+// credentials mirrors the login request for validation
+type credentials struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=255"`
 }
 
-func (s *AuthService) Register(userDto *dto.CreateUser) (*models.User, string, error) {
+func (s *AuthService) Register(ctx context.Context, userDto *dto.CreateUser, device token_service.Device) (*models.User, *token_service.Pair, error) {
 	const op = "services.AuthService.Register"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	userDto.Email = models.NormalizeEmail(userDto.Email)
+
 	// Validate user data
 	err := service.Validate.Struct(userDto)
 	if err != nil {
-		s.log.Info(op, "validation error", sl.Err(err))
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
 		var validateErrs validator.ValidationErrors
 		errors.As(err, &validateErrs)
-		return nil, "", validation.PrettyError(validateErrs)
+		return nil, nil, service.Invalid(validateErrs)
 	}
 
 	user := userDto.Model()
 
+	// The policy goes beyond the length checked above
+	if err := s.passwords.Check(userDto.Password, userDto.Email, userDto.Name, userDto.Lastname, userDto.Phone); err != nil {
+		s.log.InfoContext(ctx, op, "password refused", sl.Err(err))
+		return nil, nil, err
+	}
+
 	// Hash password
-	hashedPassword, err := s.hashPassword(*user.Password)
+	hashedPassword, err := s.passwords.Hash(*user.Password)
 	if err != nil {
-		s.log.Error(op, "failed to hash password", sl.Err(err))
-		return nil, "", service.ErrInternalError
+		s.log.ErrorContext(ctx, op, "failed to hash password", sl.Err(err))
+		return nil, nil, service.ErrInternalError
 	}
 	// Set hashed password
 	user.Password = &hashedPassword
 
-	// Set user status
-	user.Status = util.Ptr(models.UserStatusActive)
+	// The account is pending until the email and the phone are verified
+	user.Status = util.Ptr(models.UserStatusPending)
 
 	// Create new user
-	err = s.repo.Create(user)
+	err = s.repo.Create(ctx, user)
 	if err != nil {
 		// Сheck if user already exists
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			s.log.Info(op, "user already exists", slog.String("email", *user.Email))
-			return nil, "", service.ErrUserAlreadyExists
+			s.log.InfoContext(ctx, op, "user already exists", slog.String("email", *user.Email))
+			return nil, nil, service.ErrUserAlreadyExists
 		}
-		s.log.Error(op, "failed to create user", sl.Err(err))
-		return nil, "", service.ErrInternalError
+		s.log.ErrorContext(ctx, op, "failed to create user", sl.Err(err))
+		return nil, nil, service.ErrInternalError
+	}
+	metrics.Registrations.Inc()
+
+	// The user can ask for the codes again, a failed delivery doesn't fail the registration
+	if err := s.verifier.Start(ctx, user); err != nil {
+		s.log.WarnContext(ctx, op, "verification not sent", slog.Uint64("user_id", user.ID), sl.Err(err))
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user)
+	// Issue access and refresh tokens
+	tokens, err := s.tokens.Issue(ctx, user, device, false)
 	if err != nil {
-		s.log.Error(op, "failed to generate token", sl.Err(err))
-		return nil, "", service.ErrInternalError
+		s.log.ErrorContext(ctx, op, "failed to issue tokens", sl.Err(err))
+		return nil, nil, service.ErrInternalError
 	}
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
-func (s *AuthService) Login(email, password string) (*models.User, string, error) {
+// Login checks the credentials of a login from the device. While the account or the ip of the device
+// is locked out after repeated failures the password is not checked at all.
+// Users with MFA get a challenge instead of tokens, to be completed with VerifyMFA.
+func (s *AuthService) Login(ctx context.Context, email, password string, device token_service.Device) (*models.User, *token_service.Pair, *Challenge, error) {
 	const op = "services.AuthService.Login"
 
-	// Validate email and password
-	emailErr := service.Validate.Var(email, "required,email")
-	passErr := service.Validate.Var(password, "required,min=8,max=255")
-	if emailErr != nil || passErr != nil {
-		s.log.Info(op, "validation error", slog.String("error", "invalid email or password"))
-
-		var errs []validator.ValidationErrors
-		if emailErr != nil && passErr != nil {
-			errs = append(errs, emailErr.(validator.ValidationErrors), passErr.(validator.ValidationErrors))
-		} else if emailErr != nil {
-			errs = append(errs, emailErr.(validator.ValidationErrors))
-		} else {
-			errs = append(errs, passErr.(validator.ValidationErrors))
-		}
-		return nil, "", validation.PrettyError(errs...)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	// Validate email and password, as a struct so that errors name the request fields
+	email = models.NormalizeEmail(email)
+	err := service.Validate.Struct(credentials{Email: email, Password: password})
+	if err != nil {
+		s.log.InfoContext(ctx, op, "validation error", slog.String("error", "invalid email or password"))
+		return nil, nil, nil, service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	if err := s.limiter.Check(ctx, email, device.IP); err != nil {
+		metrics.Logins.WithLabelValues(metrics.LoginLocked).Inc()
+		return nil, nil, nil, err
 	}
 
 	// Get user by email
-	user, err := s.repo.GetByEmail(email)
+	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.log.Info(op, "user not found", slog.String("email", email))
-			fmt.Println("User not found:", err)
-			return nil, "", service.ErrInvalidCredentials
+			s.log.InfoContext(ctx, op, "user not found", slog.String("email", email))
+			metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+			s.limiter.Fail(ctx, email, device.IP)
+			return nil, nil, nil, service.ErrInvalidCredentials
 		}
 		// Handle other errors
-		s.log.Error(op, "failed to get user", sl.Err(err))
-		return nil, "", service.ErrInternalError
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, nil, nil, service.ErrInternalError
 	}
 
-	// Check password
-	if !s.checkPassword(*user.Password, password) {
-		return nil, "", service.ErrInvalidCredentials
+	// Check password, users who only sign in with an identity provider have none
+	if user.Password == nil || !s.checkPassword(ctx, user, password) {
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+		s.limiter.Fail(ctx, email, device.IP)
+		return nil, nil, nil, service.ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user)
+	// Only after the password, so that the status of an account is not disclosed to a guess
+	if !user.CanSignIn() {
+		s.limiter.Succeed(ctx, email)
+		s.log.InfoContext(ctx, op, "user is not allowed to sign in", slog.Uint64("id", user.ID), slog.String("status", util.Deref(user.Status)))
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+		return nil, nil, nil, service.ErrAccountDisabled
+	}
+
+	enabled, required, err := s.mfa.Status(ctx, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if required {
+		// The failures are kept until the second factor is passed too,
+		// otherwise knowing the password would let codes be guessed without a lockout
+		challenge, err := s.challenge(ctx, user, enabled)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		s.log.InfoContext(ctx, op, "mfa challenge issued", slog.Uint64("id", user.ID), slog.Bool("enrollment", !enabled))
+		metrics.Logins.WithLabelValues(metrics.LoginChallenge).Inc()
+		return user, nil, challenge, nil
+	}
+	s.limiter.Succeed(ctx, email)
+
+	// Issue access and refresh tokens
+	tokens, err := s.tokens.Issue(ctx, user, device, false)
 	if err != nil {
-		s.log.Error(op, "failed to generate token", sl.Err(err))
-		return nil, "", service.ErrInternalError
+		s.log.ErrorContext(ctx, op, "failed to issue tokens", sl.Err(err))
+		return nil, nil, nil, service.ErrInternalError
 	}
+	metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
 
-	return user, token, nil
+	return user, tokens, nil, nil
 }
 
-func (s *AuthService) generateToken(user *models.User) (string, error) {
-	// Define expiration time for the token
-	expirationTime := time.Now().Add(24 * time.Hour)
+// SignIn starts a session for a user authenticated elsewhere, e.g. by an identity provider.
+// Users with MFA get a challenge as after the password.
+func (s *AuthService) SignIn(ctx context.Context, user *models.User, device token_service.Device) (*token_service.Pair, *Challenge, error) {
+	const op = "services.AuthService.SignIn"
 
-	// Create claims (payload) for the token
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"exp":     expirationTime.Unix(),
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if !user.CanSignIn() {
+		s.log.InfoContext(ctx, op, "user is not allowed to sign in", slog.Uint64("id", user.ID), slog.String("status", util.Deref(user.Status)))
+		metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+		return nil, nil, service.ErrAccountDisabled
 	}
 
-	// Create a new token with the claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	enabled, required, err := s.mfa.Status(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if required {
+		challenge, err := s.challenge(ctx, user, enabled)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.log.InfoContext(ctx, op, "mfa challenge issued", slog.Uint64("id", user.ID), slog.Bool("enrollment", !enabled))
+		metrics.Logins.WithLabelValues(metrics.LoginChallenge).Inc()
+		return nil, challenge, nil
+	}
 
-	// Sign the token with the secret key
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	tokens, err := s.tokens.Issue(ctx, user, device, false)
 	if err != nil {
-		s.log.Error("failed to sign token", sl.Err(err))
-		return "", err
+		s.log.ErrorContext(ctx, op, "failed to issue tokens", sl.Err(err))
+		return nil, nil, service.ErrInternalError
 	}
+	metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
 
-	return tokenString, nil
+	return tokens, nil, nil
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	// Parse the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Check if the signing method is valid
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			s.log.Error("unexpected signing method", slog.String("method", token.Header["alg"].(string)))
-			return nil, service.ErrInvalidToken
+// VerifyMFA completes a login with the challenge token and a code from the authenticator
+// or a recovery code. A user enrolling at login confirms the authenticator added from
+// the setup link with the code and gets the recovery codes back.
+func (s *AuthService) VerifyMFA(ctx context.Context, challengeToken, code string, device token_service.Device) (*models.User, *token_service.Pair, []string, error) {
+	const op = "services.AuthService.VerifyMFA"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	userID, err := s.tokens.ValidateChallenge(challengeToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, service.ErrInvalidToken
 		}
-		return []byte(s.jwtSecret), nil
-	})
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, nil, nil, service.ErrInternalError
+	}
+	if !user.CanSignIn() {
+		s.log.InfoContext(ctx, op, "user is not allowed to sign in", slog.Uint64("id", user.ID), slog.String("status", util.Deref(user.Status)))
+		return nil, nil, nil, service.ErrAccountDisabled
+	}
+
+	email := util.Deref(user.Email)
+	if err := s.limiter.Check(ctx, email, device.IP); err != nil {
+		metrics.Logins.WithLabelValues(metrics.LoginLocked).Inc()
+		return nil, nil, nil, err
+	}
 
+	enabled, _, err := s.mfa.Status(ctx, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var recoveryCodes []string
+	if enabled {
+		err = s.mfa.Verify(ctx, user.ID, code)
+	} else {
+		// no session yet, the one issued below passed MFA
+		recoveryCodes, err = s.mfa.Confirm(ctx, user.ID, "", code)
+	}
 	if err != nil {
-		s.log.Error("failed to parse token", sl.Err(err))
-		return nil, service.ErrInvalidToken
+		if errors.Is(err, service.ErrInvalidMFACode) {
+			metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+			s.limiter.Fail(ctx, email, device.IP)
+		}
+		return nil, nil, nil, err
 	}
+	s.limiter.Succeed(ctx, email)
 
-	if !token.Valid {
-		s.log.Info("invalid token", slog.String("token", tokenString))
-		return nil, service.ErrInvalidToken
+	tokens, err := s.tokens.Issue(ctx, user, device, true)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to issue tokens", sl.Err(err))
+		return nil, nil, nil, service.ErrInternalError
 	}
+	metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
 
-	return token, nil
+	return user, tokens, recoveryCodes, nil
 }
 
-func (s *AuthService) hashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func (s *AuthService) challenge(ctx context.Context, user *models.User, enabled bool) (*Challenge, error) {
+	const op = "services.AuthService.challenge"
+
+	challenge := &Challenge{}
+	if !enabled {
+		// Not in the response, knowing the password must not be enough to add an authenticator
+		if err := s.mfa.SendSetupLink(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		challenge.SetupLinkSent = true
+	}
+
+	token, expiresIn, err := s.tokens.IssueChallenge(user)
 	if err != nil {
-		return "", err
+		s.log.ErrorContext(ctx, op, "failed to issue challenge", sl.Err(err))
+		return nil, service.ErrInternalError
 	}
-	return string(hashedPassword), nil
+	challenge.Token, challenge.ExpiresIn = token, expiresIn
+
+	return challenge, nil
 }
 
-func (s *AuthService) checkPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+// checkPassword verifies the password of the user, a hash made with outdated parameters
+// is replaced while the password is at hand
+func (s *AuthService) checkPassword(ctx context.Context, user *models.User, password string) bool {
+	const op = "services.AuthService.checkPassword"
+
+	ok, rehash, err := s.passwords.Verify(*user.Password, password)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "unreadable password hash", slog.Uint64("id", user.ID), sl.Err(err))
+		return false
+	}
+	if !ok || !rehash {
+		return ok
+	}
+
+	// The login goes on with the old hash, it is replaced on a later one
+	hashedPassword, err := s.passwords.Hash(password)
+	if err != nil {
+		s.log.WarnContext(ctx, op, "failed to rehash password", slog.Uint64("id", user.ID), sl.Err(err))
+		return true
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, user.ID, *user.Password, hashedPassword); err != nil {
+		s.log.WarnContext(ctx, op, "failed to save rehashed password", slog.Uint64("id", user.ID), sl.Err(err))
+		return true
+	}
+	user.Password = &hashedPassword
+	s.log.InfoContext(ctx, op, "password rehashed", slog.Uint64("id", user.ID))
+
+	return true
 }
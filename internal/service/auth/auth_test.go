@@ -1,20 +1,29 @@
 package auth_service_test
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"reflect"
+	"sdt-bicycle-rental/internal/config"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/dto"
 	"sdt-bicycle-rental/internal/service"
 	auth_service "sdt-bicycle-rental/internal/service/auth"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	token_service "sdt-bicycle-rental/internal/service/token"
 
 	mocks "sdt-bicycle-rental/internal/service/auth/mocks"
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/passhash"
 	"sdt-bicycle-rental/lib/util"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 const (
@@ -22,19 +31,185 @@ const (
 	invalidEmail = "invalid-email"
 )
 
+var device = token_service.Device{Name: "Pixel 8", UserAgent: "Mozilla/5.0", IP: "192.0.2.1"}
+
+// bcrypt at the cost of the stored hashes below, so that only an outdated one is replaced
+var passwords = func() *password_service.Hasher {
+	h, err := password_service.NewHasher(config.Password{Algorithm: passhash.Bcrypt, Cost: 10, MinLength: 8, MaxLength: 128})
+	if err != nil {
+		panic(err)
+	}
+	return h
+}()
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	pair := &token_service.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}
+	user := func(status string) *models.User {
+		return &models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(status)}
+	}
+
+	tests := []struct {
+		name      string
+		challenge error
+		user      *models.User
+		checkErr  error
+		enabled   bool
+		codeErr   error
+		wantCodes []string
+		wantErr   error
+	}{
+		{
+			name:    "success",
+			user:    user(models.UserStatusActive),
+			enabled: true,
+		},
+		{
+			name:      "enrollment confirmed",
+			user:      user(models.UserStatusActive),
+			wantCodes: []string{"abcde-fghij"},
+		},
+		{
+			name:      "invalid challenge",
+			challenge: service.ErrExpiredToken,
+			wantErr:   service.ErrExpiredToken,
+		},
+		{
+			name:    "banned user",
+			user:    user(models.UserStatusBanned),
+			wantErr: service.ErrAccountDisabled,
+		},
+		{
+			name:     "locked out",
+			user:     user(models.UserStatusActive),
+			checkErr: service.ErrTooManyAttempts.WithRetryAfter(time.Minute),
+			wantErr:  service.ErrTooManyAttempts,
+		},
+		{
+			name:    "wrong code counts a failure",
+			user:    user(models.UserStatusActive),
+			enabled: true,
+			codeErr: service.ErrInvalidMFACode,
+			wantErr: service.ErrInvalidMFACode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewUserRepository(t)
+			tokens := mocks.NewTokenIssuer(t)
+			limiter := mocks.NewLimiter(t)
+			mfa := mocks.NewSecondFactor(t)
+			s := auth_service.New(repo, tokens, mocks.NewVerifier(t), limiter, mfa, passwords, slogdiscard.NewDiscardLogger())
+
+			tokens.On("ValidateChallenge", "challenge").Return(uint64(1), tt.challenge).Once()
+			if tt.user != nil {
+				repo.On("GetByID", mock.Anything, uint64(1)).Return(tt.user, nil).Once()
+			}
+			if tt.user != nil && tt.user.CanSignIn() {
+				limiter.On("Check", mock.Anything, validEmail, "192.0.2.1").Return(tt.checkErr).Once()
+			}
+			if tt.user != nil && tt.user.CanSignIn() && tt.checkErr == nil {
+				mfa.On("Status", mock.Anything, uint64(1)).Return(tt.enabled, true, nil).Once()
+				if tt.enabled {
+					mfa.On("Verify", mock.Anything, uint64(1), "123456").Return(tt.codeErr).Once()
+				} else {
+					mfa.On("Confirm", mock.Anything, uint64(1), "", "123456").Return(tt.wantCodes, tt.codeErr).Once()
+				}
+				if tt.codeErr != nil {
+					limiter.On("Fail", mock.Anything, validEmail, "192.0.2.1").Once()
+				} else {
+					limiter.On("Succeed", mock.Anything, validEmail).Once()
+					// the session is issued as passed MFA
+					tokens.On("Issue", mock.Anything, tt.user, device, true).Return(pair, nil).Once()
+				}
+			}
+
+			_, got, codes, err := s.VerifyMFA(context.Background(), "challenge", "123456", device)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuthService.VerifyMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got != pair || !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("AuthService.VerifyMFA() = %v, %v, want %v, %v", got, codes, pair, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestAuthService_SignIn(t *testing.T) {
+	pair := &token_service.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}
+
+	tests := []struct {
+		name          string
+		status        string
+		required      bool
+		wantChallenge bool
+		wantErr       error
+	}{
+		{
+			name:   "success",
+			status: models.UserStatusActive,
+		},
+		{
+			name:          "mfa challenge",
+			status:        models.UserStatusActive,
+			required:      true,
+			wantChallenge: true,
+		},
+		{
+			name:    "banned user",
+			status:  models.UserStatusBanned,
+			wantErr: service.ErrAccountDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := mocks.NewTokenIssuer(t)
+			mfa := mocks.NewSecondFactor(t)
+			s := auth_service.New(mocks.NewUserRepository(t), tokens, mocks.NewVerifier(t), mocks.NewLimiter(t), mfa, passwords, slogdiscard.NewDiscardLogger())
+			user := &models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(tt.status)}
+
+			if tt.wantErr == nil {
+				mfa.On("Status", mock.Anything, uint64(1)).Return(tt.required, tt.required, nil).Once()
+				if tt.required {
+					tokens.On("IssueChallenge", user).Return("challenge", 5*time.Minute, nil).Once()
+				} else {
+					tokens.On("Issue", mock.Anything, user, device, false).Return(pair, nil).Once()
+				}
+			}
+
+			got, challenge, err := s.SignIn(context.Background(), user, device)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuthService.SignIn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if tt.wantChallenge != (challenge != nil) || tt.wantChallenge == (got == pair) {
+				t.Errorf("AuthService.SignIn() = %v, %v, want challenge %v", got, challenge, tt.wantChallenge)
+			}
+		})
+	}
+}
+
 func TestAuthService_Register(t *testing.T) {
 	type fields struct {
-		repo      auth_service.UserRepository
-		log       *slog.Logger
-		jwtSecret string
+		repo     auth_service.UserRepository
+		tokens   auth_service.TokenIssuer
+		verifier auth_service.Verifier
+		log      *slog.Logger
 	}
 
 	defaultFields := fields{
-		repo:      mocks.NewUserRepository(t),
-		log:       slogdiscard.NewDiscardLogger(),
-		jwtSecret: "secret",
+		repo:     mocks.NewUserRepository(t),
+		tokens:   mocks.NewTokenIssuer(t),
+		verifier: mocks.NewVerifier(t),
+		log:      slogdiscard.NewDiscardLogger(),
 	}
 
+	pair := &token_service.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}
+
 	tests := []struct {
 		name    string
 		fields  fields
@@ -50,14 +225,54 @@ func TestAuthService_Register(t *testing.T) {
 				Lastname: "Doe",
 				Email:    validEmail,
 				Phone:    "1234567890",
-				Password: "password",
+				Password: "correct-horse-battery",
 			},
 			want: &models.User{
 				Name:     util.Ptr("John"),
 				Lastname: util.Ptr("Doe"),
 				Email:    util.Ptr(validEmail),
 				Phone:    util.Ptr("1234567890"),
-				Status:   util.Ptr(models.UserStatusActive),
+				Status:   util.Ptr(models.UserStatusPending),
+				Password: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:   "verification not sent",
+			fields: defaultFields,
+			argUser: &dto.CreateUser{
+				Name:     "John",
+				Lastname: "Doe",
+				Email:    validEmail,
+				Phone:    "1234567890",
+				Password: "correct-horse-battery",
+			},
+			want: &models.User{
+				Name:     util.Ptr("John"),
+				Lastname: util.Ptr("Doe"),
+				Email:    util.Ptr(validEmail),
+				Phone:    util.Ptr("1234567890"),
+				Status:   util.Ptr(models.UserStatusPending),
+				Password: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:   "mixed case email",
+			fields: defaultFields,
+			argUser: &dto.CreateUser{
+				Name:     "John",
+				Lastname: "Doe",
+				Email:    " Valid@EMAIL.com",
+				Phone:    "1234567890",
+				Password: "correct-horse-battery",
+			},
+			want: &models.User{
+				Name:     util.Ptr("John"),
+				Lastname: util.Ptr("Doe"),
+				Email:    util.Ptr(validEmail),
+				Phone:    util.Ptr("1234567890"),
+				Status:   util.Ptr(models.UserStatusPending),
 				Password: nil,
 			},
 			wantErr: false,
@@ -70,7 +285,7 @@ func TestAuthService_Register(t *testing.T) {
 				Lastname: "Doe",
 				Email:    validEmail,
 				Phone:    "1234567890",
-				Password: "password",
+				Password: "correct-horse-battery",
 			},
 			want:    nil,
 			wantErr: true,
@@ -83,7 +298,7 @@ func TestAuthService_Register(t *testing.T) {
 				Lastname: "Doe",
 				Email:    "invalid-emal",
 				Phone:    "1234567890",
-				Password: "password",
+				Password: "correct-horse-battery",
 			},
 			want:    nil,
 			wantErr: true,
@@ -101,6 +316,19 @@ func TestAuthService_Register(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:   "common password",
+			fields: defaultFields,
+			argUser: &dto.CreateUser{
+				Name:     "John",
+				Lastname: "Doe",
+				Email:    validEmail,
+				Phone:    "1234567890",
+				Password: "password123",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:   "create error",
 			fields: defaultFields,
@@ -109,7 +337,7 @@ func TestAuthService_Register(t *testing.T) {
 				Lastname: "Doe",
 				Email:    validEmail,
 				Phone:    "1234567890",
-				Password: "password",
+				Password: "correct-horse-battery",
 			},
 			want:    nil,
 			wantErr: true,
@@ -117,21 +345,34 @@ func TestAuthService_Register(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := auth_service.New(tt.fields.repo, tt.fields.log, tt.fields.jwtSecret)
+			s := auth_service.New(tt.fields.repo, tt.fields.tokens, tt.fields.verifier, mocks.NewLimiter(t), mocks.NewSecondFactor(t), passwords, tt.fields.log)
 
 			switch tt.name {
-			case "success":
+			case "success", "verification not sent", "mixed case email":
+				var sendErr error
+				if tt.name == "verification not sent" {
+					sendErr = errors.New("smtp unavailable")
+				}
 				tt.fields.repo.(*mocks.UserRepository).
-					On("Create", mock.MatchedBy(func(u *models.User) bool { return true })).
+					On("Create", mock.Anything, mock.MatchedBy(func(u *models.User) bool { return true })).
 					Return(nil).Once()
+				tt.fields.verifier.(*mocks.Verifier).
+					On("Start", mock.Anything, mock.AnythingOfType("*models.User")).
+					Return(sendErr).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).
+					On("Issue", mock.Anything, mock.MatchedBy(func(u *models.User) bool { return true }), device, false).
+					Return(pair, nil).Once()
 			case "create error":
 				tt.fields.repo.(*mocks.UserRepository).
-					On("Create", mock.MatchedBy(func(u *models.User) bool { return true })).
+					On("Create", mock.Anything, mock.MatchedBy(func(u *models.User) bool { return true })).
 					Return(service.ErrInternalError).Once()
 			}
 
-			got, got1, err := s.Register(tt.argUser)
+			got, got1, err := s.Register(context.Background(), tt.argUser, device)
 			isErr := err != nil
+			if tt.name == "common password" {
+				assert.ErrorIs(t, err, service.ErrWeakPassword)
+			}
 
 			if isErr != tt.wantErr {
 				t.Errorf("UserService.Register() error = %v, wantErr %v", err, tt.wantErr)
@@ -139,7 +380,7 @@ func TestAuthService_Register(t *testing.T) {
 			}
 
 			if !isErr {
-				if bcrypt.CompareHashAndPassword([]byte(*got.Password), []byte("password")) != nil {
+				if ok, _ := passhash.Verify(*got.Password, "correct-horse-battery"); !ok {
 					t.Errorf("UserService.Register() password hash mismatch")
 				}
 
@@ -148,9 +389,8 @@ func TestAuthService_Register(t *testing.T) {
 					t.Errorf("UserService.Register() got = %v, want %v", got, tt.want)
 				}
 
-				token, err := s.ValidateToken(got1)
-				if err != nil || token == nil {
-					t.Errorf("UserService.Register() token validation error = %v", err)
+				if got1 != pair {
+					t.Errorf("UserService.Register() tokens = %v, want %v", got1, pair)
 				}
 			}
 		})
@@ -159,16 +399,26 @@ func TestAuthService_Register(t *testing.T) {
 
 func TestAuthService_Login(t *testing.T) {
 	type fields struct {
-		repo      auth_service.UserRepository
-		log       *slog.Logger
-		jwtSecret string
+		repo     auth_service.UserRepository
+		tokens   auth_service.TokenIssuer
+		verifier auth_service.Verifier
+		log      *slog.Logger
 	}
 
 	defaultFields := fields{
-		repo:      mocks.NewUserRepository(t),
-		log:       slogdiscard.NewDiscardLogger(),
-		jwtSecret: "secret",
+		repo:     mocks.NewUserRepository(t),
+		tokens:   mocks.NewTokenIssuer(t),
+		verifier: mocks.NewVerifier(t),
+		log:      slogdiscard.NewDiscardLogger(),
+	}
+
+	pair := &token_service.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}
+	outdatedHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
 	}
+	outdated := string(outdatedHash)
+
 	type args struct {
 		email    string
 		password string
@@ -193,7 +443,93 @@ func TestAuthService_Login(t *testing.T) {
 				Email:    util.Ptr(validEmail),
 				Phone:    util.Ptr("1234567890"),
 				Status:   util.Ptr(models.UserStatusActive),
-				Password: util.Ptr("$2a$10$Qz4ERCPWmdyNe7DR5H19RubOlA7drtlD9VCVYl8N9QjcqhueonsM6"),
+				Password: util.Ptr("$bcrypt$v=97$r=10$S16GTERYof0Pg9FT7J3/Tw$dQnC9ftvnF/XEXan+P/SlesjwgqpuO8"),
+			},
+			wantErr: false,
+		},
+		{
+			name:   "mixed case email",
+			fields: defaultFields,
+			args: args{
+				email:    "VALID@email.com",
+				password: "password",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "wrong password",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "wrong-password",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "outdated hash is replaced",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "no password",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "unknown email",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "locked out",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "banned user",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "mfa challenge",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "admin enrolls at login",
+			fields: defaultFields,
+			args: args{
+				email:    validEmail,
+				password: "password",
 			},
 			wantErr: false,
 		},
@@ -220,29 +556,128 @@ func TestAuthService_Login(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := auth_service.New(tt.fields.repo, tt.fields.log, tt.fields.jwtSecret)
+			limiter := mocks.NewLimiter(t)
+			mfa := mocks.NewSecondFactor(t)
+			s := auth_service.New(tt.fields.repo, tt.fields.tokens, tt.fields.verifier, limiter, mfa, passwords, tt.fields.log)
+
+			stored := func(status string) *models.User {
+				return &models.User{
+					ID:       1,
+					Email:    util.Ptr(validEmail),
+					Status:   util.Ptr(status),
+					Password: util.Ptr("$bcrypt$v=97$r=10$S16GTERYof0Pg9FT7J3/Tw$dQnC9ftvnF/XEXan+P/SlesjwgqpuO8"),
+				}
+			}
 
 			switch tt.name {
 			case "success":
-				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", tt.args.email).Return(tt.want, nil).Once()
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(tt.want, nil).Once()
+				mfa.On("Status", mock.Anything, mock.Anything).Return(false, false, nil).Once()
+				limiter.On("Succeed", mock.Anything, tt.args.email).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).On("Issue", mock.Anything, tt.want, device, false).Return(pair, nil).Once()
+			case "wrong password":
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(stored(models.UserStatusActive), nil).Once()
+				limiter.On("Fail", mock.Anything, tt.args.email, "192.0.2.1").Once()
+			case "mixed case email":
+				// the lockout and the lookup both go by the stored form
+				user := stored(models.UserStatusActive)
+				limiter.On("Check", mock.Anything, validEmail, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, validEmail).Return(user, nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(false, false, nil).Once()
+				limiter.On("Succeed", mock.Anything, validEmail).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).On("Issue", mock.Anything, user, device, false).Return(pair, nil).Once()
+			case "outdated hash is replaced":
+				// hashed by bcrypt itself at a lower cost than the hasher uses now
+				user := stored(models.UserStatusActive)
+				user.Password = util.Ptr(outdated)
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(user, nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("UpdatePasswordHash", mock.Anything, uint64(1), outdated, mock.MatchedBy(func(hash string) bool {
+					ok, rehash, err := passwords.Verify(hash, "password")
+					return err == nil && ok && !rehash
+				})).Return(nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(false, false, nil).Once()
+				limiter.On("Succeed", mock.Anything, tt.args.email).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).On("Issue", mock.Anything, user, device, false).Return(pair, nil).Once()
+			case "no password":
+				// signs in only with an identity provider
+				user := stored(models.UserStatusActive)
+				user.Password = nil
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(user, nil).Once()
+				limiter.On("Fail", mock.Anything, tt.args.email, "192.0.2.1").Once()
+			case "unknown email":
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(nil, gorm.ErrRecordNotFound).Once()
+				limiter.On("Fail", mock.Anything, tt.args.email, "192.0.2.1").Once()
+			case "locked out":
+				// the password is not checked at all
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(service.ErrTooManyAttempts.WithRetryAfter(time.Minute)).Once()
+			case "banned user":
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(stored(models.UserStatusBanned), nil).Once()
+				limiter.On("Succeed", mock.Anything, tt.args.email).Once()
+			case "mfa challenge":
+				// no tokens and the failures are kept until the code is checked
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(stored(models.UserStatusActive), nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(true, true, nil).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).On("IssueChallenge", mock.Anything).Return("challenge", 5*time.Minute, nil).Once()
+			case "admin enrolls at login":
+				limiter.On("Check", mock.Anything, tt.args.email, "192.0.2.1").Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByEmail", mock.Anything, tt.args.email).Return(stored(models.UserStatusActive), nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(false, true, nil).Once()
+				// the secret goes by email, not in the response
+				mfa.On("SendSetupLink", mock.Anything, uint64(1)).Return(nil).Once()
+				tt.fields.tokens.(*mocks.TokenIssuer).On("IssueChallenge", mock.Anything).Return("challenge", 5*time.Minute, nil).Once()
 			}
 
-			got, got1, err := s.Login(tt.args.email, tt.args.password)
+			got, got1, challenge, err := s.Login(context.Background(), tt.args.email, tt.args.password, device)
 			t.Logf("Error Message: %v", err)
+			switch tt.name {
+			case "wrong password", "no password", "unknown email":
+				assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+			case "locked out":
+				assert.ErrorIs(t, err, service.ErrTooManyAttempts)
+			case "banned user":
+				assert.ErrorIs(t, err, service.ErrAccountDisabled)
+			}
 			isErr := err != nil
 			if isErr != tt.wantErr {
 				t.Errorf("AuthService.Login() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
+			switch tt.name {
+			case "mfa challenge", "admin enrolls at login":
+				if got1 != nil || challenge == nil || challenge.Token != "challenge" {
+					t.Errorf("AuthService.Login() tokens = %v, challenge = %v", got1, challenge)
+				}
+				if enroll := tt.name == "admin enrolls at login"; enroll != (challenge != nil && challenge.SetupLinkSent) {
+					t.Errorf("AuthService.Login() setup link sent = %v, want %v", challenge.SetupLinkSent, enroll)
+				}
+				return
+			case "mixed case email":
+				if got1 != pair {
+					t.Errorf("AuthService.Login() tokens = %v, want %v", got1, pair)
+				}
+				return
+			case "outdated hash is replaced":
+				if got1 != pair || *got.Password == outdated {
+					t.Errorf("AuthService.Login() tokens = %v, password hash kept = %v", got1, *got.Password == outdated)
+				}
+				return
+			}
+
 			if !isErr {
 				if !reflect.DeepEqual(got, tt.want) {
 					t.Errorf("AuthService.Login() got = %v, want %v", got, tt.want)
 				}
 
-				token, err := s.ValidateToken(got1)
-				if err != nil || token == nil {
-					t.Errorf("AuthService.Login() token validation error = %v", err)
+				if got1 != pair {
+					t.Errorf("AuthService.Login() tokens = %v, want %v", got1, pair)
 				}
 			}
 		})
@@ -0,0 +1,126 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// TokenIssuer is an autogenerated mock type for the TokenIssuer type
+type TokenIssuer struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, user, device, mfa
+func (_m *TokenIssuer) Issue(ctx context.Context, user *models.User, device token_service.Device, mfa bool) (*token_service.Pair, error) {
+	ret := _m.Called(ctx, user, device, mfa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 *token_service.Pair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, token_service.Device, bool) (*token_service.Pair, error)); ok {
+		return rf(ctx, user, device, mfa)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, token_service.Device, bool) *token_service.Pair); ok {
+		r0 = rf(ctx, user, device, mfa)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, token_service.Device, bool) error); ok {
+		r1 = rf(ctx, user, device, mfa)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueChallenge provides a mock function with given fields: user
+func (_m *TokenIssuer) IssueChallenge(user *models.User) (string, time.Duration, error) {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueChallenge")
+	}
+
+	var r0 string
+	var r1 time.Duration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*models.User) (string, time.Duration, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(*models.User) string); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*models.User) time.Duration); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Get(1).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(2).(func(*models.User) error); ok {
+		r2 = rf(user)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ValidateChallenge provides a mock function with given fields: token
+func (_m *TokenIssuer) ValidateChallenge(token string) (uint64, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateChallenge")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (uint64, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) uint64); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenIssuer creates a new instance of TokenIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenIssuer {
+	mock := &TokenIssuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
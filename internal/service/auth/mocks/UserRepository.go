@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	models "sdt-bicycle-rental/internal/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,17 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// AnonymizeAndMarkDeleted provides a mock function with given fields: id
-func (_m *UserRepository) AnonymizeAndMarkDeleted(id uint64) error {
-	ret := _m.Called(id)
+// AnonymizeAndMarkDeleted provides a mock function with given fields: ctx, id
+func (_m *UserRepository) AnonymizeAndMarkDeleted(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AnonymizeAndMarkDeleted")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,17 +32,17 @@ func (_m *UserRepository) AnonymizeAndMarkDeleted(id uint64) error {
 	return r0
 }
 
-// Create provides a mock function with given fields: user
-func (_m *UserRepository) Create(user *models.User) error {
-	ret := _m.Called(user)
+// Create provides a mock function with given fields: ctx, user
+func (_m *UserRepository) Create(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,9 +50,9 @@ func (_m *UserRepository) Create(user *models.User) error {
 	return r0
 }
 
-// GetByEmail provides a mock function with given fields: email
-func (_m *UserRepository) GetByEmail(email string) (*models.User, error) {
-	ret := _m.Called(email)
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	ret := _m.Called(ctx, email)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByEmail")
@@ -59,19 +60,19 @@ func (_m *UserRepository) GetByEmail(email string) (*models.User, error) {
 
 	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*models.User, error)); ok {
-		return rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.User, error)); ok {
+		return rf(ctx, email)
 	}
-	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(email)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,9 +80,9 @@ func (_m *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *UserRepository) GetByID(id uint64) (*models.User, error) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *UserRepository) GetByID(ctx context.Context, id uint64) (*models.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -89,19 +90,19 @@ func (_m *UserRepository) GetByID(id uint64) (*models.User, error) {
 
 	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (*models.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) *models.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,9 +110,9 @@ func (_m *UserRepository) GetByID(id uint64) (*models.User, error) {
 	return r0, r1
 }
 
-// GetByIDWithRelations provides a mock function with given fields: id
-func (_m *UserRepository) GetByIDWithRelations(id uint64) (*models.User, error) {
-	ret := _m.Called(id)
+// GetByIDWithRelations provides a mock function with given fields: ctx, id
+func (_m *UserRepository) GetByIDWithRelations(ctx context.Context, id uint64) (*models.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByIDWithRelations")
@@ -119,19 +120,19 @@ func (_m *UserRepository) GetByIDWithRelations(id uint64) (*models.User, error)
 
 	var r0 *models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (*models.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) *models.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,17 +140,35 @@ func (_m *UserRepository) GetByIDWithRelations(id uint64) (*models.User, error)
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: user
-func (_m *UserRepository) Update(user *models.User) error {
-	ret := _m.Called(user)
+// Update provides a mock function with given fields: ctx, user
+func (_m *UserRepository) Update(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePasswordHash provides a mock function with given fields: ctx, id, oldHash, newHash
+func (_m *UserRepository) UpdatePasswordHash(ctx context.Context, id uint64, oldHash string, newHash string) error {
+	ret := _m.Called(ctx, id, oldHash, newHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string) error); ok {
+		r0 = rf(ctx, id, oldHash, newHash)
 	} else {
 		r0 = ret.Error(0)
 	}
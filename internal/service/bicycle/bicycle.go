@@ -0,0 +1,217 @@
+package bicycle_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/util"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=BicycleRepository
+type BicycleRepository interface {
+	Create(ctx context.Context, bicycle *models.Bicycle) error
+	GetByID(ctx context.Context, id uint64) (*models.Bicycle, error)
+	List(ctx context.Context, filter dto.BicycleFilter, offset, limit int) ([]models.Bicycle, error)
+	Count(ctx context.Context, filter dto.BicycleFilter) (int64, error)
+	UpdateStatus(ctx context.Context, bicycle *models.Bicycle, from string) error
+	Move(ctx context.Context, id uint64, status string, fromStationID, toStationID uint64) error
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// transitions lists the statuses a bicycle may move to from each status.
+// Reserved and rented bikes only come back through the booking and rental flows, retired bikes never come back.
+var transitions = map[string][]string{
+	models.BicycleStatusAvailable: {models.BicycleStatusReserved, models.BicycleStatusRented, models.BicycleStatusInService, models.BicycleStatusRetired},
+	models.BicycleStatusReserved:  {models.BicycleStatusAvailable, models.BicycleStatusRented},
+	models.BicycleStatusRented:    {models.BicycleStatusAvailable},
+	models.BicycleStatusInService: {models.BicycleStatusAvailable, models.BicycleStatusRetired},
+	models.BicycleStatusRetired:   {},
+}
+
+type BicycleService struct {
+	repo BicycleRepository
+	log  *slog.Logger
+}
+
+func New(repo BicycleRepository, log *slog.Logger) *BicycleService {
+	return &BicycleService{repo: repo, log: log}
+}
+
+// Register adds a new available bicycle to a station
+func (s *BicycleService) Register(ctx context.Context, bicycleDto *dto.CreateBicycle) (*models.Bicycle, error) {
+	const op = "services.BicycleService.Register"
+
+	// Validate bicycle data
+	err := service.Validate.Struct(bicycleDto)
+	if err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return nil, service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	bicycle := bicycleDto.Model()
+
+	err = s.repo.Create(ctx, bicycle)
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) || errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("station_id", bicycleDto.StationID))
+			return nil, service.ErrStationNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to create bicycle", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return bicycle, nil
+}
+
+func (s *BicycleService) ByID(ctx context.Context, id uint64) (*models.Bicycle, error) {
+	const op = "services.BicycleService.ByID"
+
+	bicycle, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "bicycle not found", slog.Uint64("id", id))
+			return nil, service.ErrBicycleNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get bicycle", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return bicycle, nil
+}
+
+// List returns a page of bicycles matching filter ordered by ID and the total number of matches.
+// Pages start at 1, limit is clamped to MaxPageSize.
+func (s *BicycleService) List(ctx context.Context, filter dto.BicycleFilter, page, limit int) ([]models.Bicycle, int64, error) {
+	const op = "services.BicycleService.List"
+
+	if filter.Status != nil && !isKnownStatus(*filter.Status) {
+		s.log.InfoContext(ctx, op, "unknown status", slog.String("status", *filter.Status))
+		return nil, 0, service.ErrInvalidBicycleStatus
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	bicycles, err := s.repo.List(ctx, filter, (page-1)*limit, limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list bicycles", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to count bicycles", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	return bicycles, total, nil
+}
+
+// UpdateStatus sends a bicycle to maintenance or returns it from there.
+// Only available and in_service can be set here: reserved and rented are managed by bookings and rentals, retired by Retire.
+func (s *BicycleService) UpdateStatus(ctx context.Context, id uint64, status string) error {
+	const op = "services.BicycleService.UpdateStatus"
+
+	if status != models.BicycleStatusAvailable && status != models.BicycleStatusInService {
+		s.log.InfoContext(ctx, op, "status can not be set directly", slog.String("status", status))
+		return service.ErrInvalidStatusTransition
+	}
+
+	return s.transition(ctx, op, id, status)
+}
+
+// Retire takes a bicycle out of the fleet for good
+func (s *BicycleService) Retire(ctx context.Context, id uint64) error {
+	const op = "services.BicycleService.Retire"
+
+	return s.transition(ctx, op, id, models.BicycleStatusRetired)
+}
+
+// Move reassigns an available or in_service bicycle to another station
+func (s *BicycleService) Move(ctx context.Context, id uint64, stationID uint64) error {
+	const op = "services.BicycleService.Move"
+
+	bicycle, err := s.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if bicycle.Status != models.BicycleStatusAvailable && bicycle.Status != models.BicycleStatusInService {
+		s.log.InfoContext(ctx, op, "bicycle can not be moved", slog.Uint64("id", id), slog.String("status", bicycle.Status))
+		return service.ErrBicycleUnavailable
+	}
+	if bicycle.StationID == stationID {
+		return nil
+	}
+
+	err = s.repo.Move(ctx, id, bicycle.Status, bicycle.StationID, stationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("station_id", stationID))
+			return service.ErrStationNotFound
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "bicycle changed concurrently", slog.Uint64("id", id))
+			return service.ErrBicycleUnavailable
+		}
+		s.log.ErrorContext(ctx, op, "failed to move bicycle", slog.Uint64("id", id), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+func (s *BicycleService) transition(ctx context.Context, op string, id uint64, to string) error {
+	bicycle, err := s.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	from := bicycle.Status
+	if !slices.Contains(transitions[from], to) {
+		s.log.InfoContext(ctx, op, "illegal status transition", slog.String("from", from), slog.String("to", to))
+		return service.ErrInvalidStatusTransition
+	}
+
+	bicycle.Status = to
+	if from == models.BicycleStatusInService {
+		bicycle.LastService = util.Ptr(time.Now())
+	}
+
+	err = s.repo.UpdateStatus(ctx, bicycle, from)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "bicycle changed concurrently", slog.Uint64("id", id))
+			return service.ErrBicycleUnavailable
+		}
+		s.log.ErrorContext(ctx, op, "failed to update bicycle status", slog.Uint64("id", id), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+func isKnownStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
@@ -0,0 +1,268 @@
+package bicycle_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	bicycle_service "sdt-bicycle-rental/internal/service/bicycle"
+	mocks "sdt-bicycle-rental/internal/service/bicycle/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestBicycleService_Register(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      *dto.CreateBicycle
+		mockCall bool
+		mockErr  error
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			arg:      &dto.CreateBicycle{StationID: 1, Type: models.BicycleTypeElectric},
+			mockCall: true,
+		},
+		{
+			name: "unknown type",
+			arg:  &dto.CreateBicycle{StationID: 1, Type: "tandem"},
+		},
+		{
+			name:     "station not found",
+			arg:      &dto.CreateBicycle{StationID: 404, Type: models.BicycleTypeStandard},
+			mockCall: true,
+			mockErr:  gorm.ErrForeignKeyViolated,
+			wantErr:  service.ErrStationNotFound,
+		},
+		{
+			name:     "unexpected error",
+			arg:      &dto.CreateBicycle{StationID: 1, Type: models.BicycleTypeStandard},
+			mockCall: true,
+			mockErr:  errors.New("unexpected error"),
+			wantErr:  service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBicycleRepository(t)
+			s := bicycle_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			if tt.mockCall {
+				repo.On("Create", mock.Anything, tt.arg.Model()).Return(tt.mockErr).Once()
+			}
+
+			got, err := s.Register(context.Background(), tt.arg)
+			if !tt.mockCall {
+				if err == nil {
+					t.Errorf("BicycleService.Register() expected validation error")
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BicycleService.Register() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Status != models.BicycleStatusAvailable {
+				t.Errorf("BicycleService.Register() status = %v, want %v", got.Status, models.BicycleStatusAvailable)
+			}
+		})
+	}
+}
+
+func TestBicycleService_List(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     dto.BicycleFilter
+		page       int
+		limit      int
+		wantOffset int
+		wantLimit  int
+		wantErr    error
+	}{
+		{
+			name:       "by station and status",
+			filter:     dto.BicycleFilter{StationID: util.Ptr(uint64(1)), Status: util.Ptr(models.BicycleStatusAvailable)},
+			page:       2,
+			limit:      10,
+			wantOffset: 10,
+			wantLimit:  10,
+		},
+		{
+			name:       "defaults",
+			wantOffset: 0,
+			wantLimit:  bicycle_service.DefaultPageSize,
+		},
+		{
+			name:    "unknown status",
+			filter:  dto.BicycleFilter{Status: util.Ptr("stolen")},
+			wantErr: service.ErrInvalidBicycleStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBicycleRepository(t)
+			s := bicycle_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			if tt.wantErr == nil {
+				repo.On("List", mock.Anything, tt.filter, tt.wantOffset, tt.wantLimit).Return([]models.Bicycle{{ID: 1}}, nil).Once()
+				repo.On("Count", mock.Anything, tt.filter).Return(int64(11), nil).Once()
+			}
+
+			_, total, err := s.List(context.Background(), tt.filter, tt.page, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BicycleService.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && total != 11 {
+				t.Errorf("BicycleService.List() total = %v, want %v", total, 11)
+			}
+		})
+	}
+}
+
+func TestBicycleService_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		mockErr  error
+		noUpdate bool
+		wantErr  error
+	}{
+		{
+			name: "to maintenance",
+			from: models.BicycleStatusAvailable,
+			to:   models.BicycleStatusInService,
+		},
+		{
+			name: "back from maintenance",
+			from: models.BicycleStatusInService,
+			to:   models.BicycleStatusAvailable,
+		},
+		{
+			name:     "rented bike to maintenance",
+			from:     models.BicycleStatusRented,
+			to:       models.BicycleStatusInService,
+			noUpdate: true,
+			wantErr:  service.ErrInvalidStatusTransition,
+		},
+		{
+			name:     "retired bike back to fleet",
+			from:     models.BicycleStatusRetired,
+			to:       models.BicycleStatusAvailable,
+			noUpdate: true,
+			wantErr:  service.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "changed concurrently",
+			from:    models.BicycleStatusAvailable,
+			to:      models.BicycleStatusInService,
+			mockErr: gorm.ErrRecordNotFound,
+			wantErr: service.ErrBicycleUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBicycleRepository(t)
+			s := bicycle_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			repo.On("GetByID", mock.Anything, uint64(1)).Return(&models.Bicycle{ID: 1, StationID: 1, Status: tt.from}, nil).Once()
+			if !tt.noUpdate {
+				repo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(b *models.Bicycle) bool {
+					serviced := tt.from == models.BicycleStatusInService
+					return b.Status == tt.to && (b.LastService != nil) == serviced
+				}), tt.from).Return(tt.mockErr).Once()
+			}
+
+			if err := s.UpdateStatus(context.Background(), 1, tt.to); !errors.Is(err, tt.wantErr) {
+				t.Errorf("BicycleService.UpdateStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("rented can not be set directly", func(t *testing.T) {
+		s := bicycle_service.New(mocks.NewBicycleRepository(t), slogdiscard.NewDiscardLogger())
+
+		if err := s.UpdateStatus(context.Background(), 1, models.BicycleStatusRented); !errors.Is(err, service.ErrInvalidStatusTransition) {
+			t.Errorf("BicycleService.UpdateStatus() error = %v, wantErr %v", err, service.ErrInvalidStatusTransition)
+		}
+	})
+}
+
+func TestBicycleService_Retire(t *testing.T) {
+	repo := mocks.NewBicycleRepository(t)
+	s := bicycle_service.New(repo, slogdiscard.NewDiscardLogger())
+
+	repo.On("GetByID", mock.Anything, uint64(1)).Return(&models.Bicycle{ID: 1, StationID: 1, Status: models.BicycleStatusInService}, nil).Once()
+	repo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(b *models.Bicycle) bool {
+		return b.Status == models.BicycleStatusRetired
+	}), models.BicycleStatusInService).Return(nil).Once()
+
+	if err := s.Retire(context.Background(), 1); err != nil {
+		t.Errorf("BicycleService.Retire() error = %v", err)
+	}
+
+	repo.On("GetByID", mock.Anything, uint64(404)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+	if err := s.Retire(context.Background(), 404); !errors.Is(err, service.ErrBicycleNotFound) {
+		t.Errorf("BicycleService.Retire() error = %v, wantErr %v", err, service.ErrBicycleNotFound)
+	}
+}
+
+func TestBicycleService_Move(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		toStation uint64
+		mockCall  bool
+		mockErr   error
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			status:    models.BicycleStatusAvailable,
+			toStation: 2,
+			mockCall:  true,
+		},
+		{
+			name:      "same station",
+			status:    models.BicycleStatusAvailable,
+			toStation: 1,
+		},
+		{
+			name:      "rented bike",
+			status:    models.BicycleStatusRented,
+			toStation: 2,
+			wantErr:   service.ErrBicycleUnavailable,
+		},
+		{
+			name:      "station not found",
+			status:    models.BicycleStatusInService,
+			toStation: 404,
+			mockCall:  true,
+			mockErr:   gorm.ErrForeignKeyViolated,
+			wantErr:   service.ErrStationNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBicycleRepository(t)
+			s := bicycle_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			repo.On("GetByID", mock.Anything, uint64(1)).Return(&models.Bicycle{ID: 1, StationID: 1, Status: tt.status}, nil).Once()
+			if tt.mockCall {
+				repo.On("Move", mock.Anything, uint64(1), tt.status, uint64(1), tt.toStation).Return(tt.mockErr).Once()
+			}
+
+			if err := s.Move(context.Background(), 1, tt.toStation); !errors.Is(err, tt.wantErr) {
+				t.Errorf("BicycleService.Move() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "sdt-bicycle-rental/internal/repository/dto"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// BicycleRepository is an autogenerated mock type for the BicycleRepository type
+type BicycleRepository struct {
+	mock.Mock
+}
+
+// Count provides a mock function with given fields: ctx, filter
+func (_m *BicycleRepository) Count(ctx context.Context, filter dto.BicycleFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.BicycleFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, bicycle
+func (_m *BicycleRepository) Create(ctx context.Context, bicycle *models.Bicycle) error {
+	ret := _m.Called(ctx, bicycle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Bicycle) error); ok {
+		r0 = rf(ctx, bicycle)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *BicycleRepository) GetByID(ctx context.Context, id uint64) (*models.Bicycle, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *models.Bicycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Bicycle, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Bicycle); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Bicycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, filter, offset, limit
+func (_m *BicycleRepository) List(ctx context.Context, filter dto.BicycleFilter, offset int, limit int) ([]models.Bicycle, error) {
+	ret := _m.Called(ctx, filter, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.Bicycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter, int, int) ([]models.Bicycle, error)); ok {
+		return rf(ctx, filter, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BicycleFilter, int, int) []models.Bicycle); ok {
+		r0 = rf(ctx, filter, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Bicycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.BicycleFilter, int, int) error); ok {
+		r1 = rf(ctx, filter, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: ctx, id, status, fromStationID, toStationID
+func (_m *BicycleRepository) Move(ctx context.Context, id uint64, status string, fromStationID uint64, toStationID uint64) error {
+	ret := _m.Called(ctx, id, status, fromStationID, toStationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, status, fromStationID, toStationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, bicycle, from
+func (_m *BicycleRepository) UpdateStatus(ctx context.Context, bicycle *models.Bicycle, from string) error {
+	ret := _m.Called(ctx, bicycle, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Bicycle, string) error); ok {
+		r0 = rf(ctx, bicycle, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBicycleRepository creates a new instance of BicycleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBicycleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BicycleRepository {
+	mock := &BicycleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
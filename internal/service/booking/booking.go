@@ -0,0 +1,249 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/util"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=BookingRepository
+type BookingRepository interface {
+	Hold(ctx context.Context, booking *models.Booking) error
+	Release(ctx context.Context, booking *models.Booking, status string) error
+	Convert(ctx context.Context, booking *models.Booking, rental *models.Rental) error
+	GetByID(ctx context.Context, id uint64) (*models.Booking, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Booking, error)
+}
+
+// TariffProvider resolves the tariff a ride started from a booking is priced under
+//
+//go:generate mockery --name=TariffProvider
+type TariffProvider interface {
+	Current(ctx context.Context, now time.Time) (*models.Tariff, error)
+}
+
+// PaymentHolder holds the booking fee while a booking is active and settles it when the booking ends
+//
+//go:generate mockery --name=PaymentHolder
+type PaymentHolder interface {
+	AuthorizeBooking(ctx context.Context, booking *models.Booking) (*models.Payment, error)
+	SettleBooking(ctx context.Context, booking *models.Booking, capture bool) error
+}
+
+// AccountPolicy decides whether the user may book a ride
+//
+//go:generate mockery --name=AccountPolicy
+type AccountPolicy interface {
+	RequireVerified(ctx context.Context, userID uint64) error
+}
+
+// expireBatchSize bounds how many bookings one ExpireDue call releases
+const expireBatchSize = 100
+
+type BookingService struct {
+	repo     BookingRepository
+	tariffs  TariffProvider
+	payments PaymentHolder
+	accounts AccountPolicy
+	log      *slog.Logger
+	hold     time.Duration
+}
+
+func New(repo BookingRepository, tariffs TariffProvider, payments PaymentHolder, accounts AccountPolicy, log *slog.Logger, cfg config.Booking) *BookingService {
+	return &BookingService{repo: repo, tariffs: tariffs, payments: payments, accounts: accounts, log: log, hold: cfg.HoldDuration}
+}
+
+// Reserve holds an available bicycle at the station for the user for the configured window
+func (s *BookingService) Reserve(ctx context.Context, userID, bicycleID, stationID uint64) (*models.Booking, error) {
+	const op = "services.BookingService.Reserve"
+
+	if err := s.accounts.RequireVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	booking := &models.Booking{
+		UserID:    userID,
+		BicycleID: bicycleID,
+		StationID: stationID,
+		Status:    models.BookingStatusActive,
+		ExpiresAt: util.Ptr(time.Now().Add(s.hold)),
+	}
+
+	err := s.repo.Hold(ctx, booking)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "bicycle is not available at station", slog.Uint64("bicycle_id", bicycleID), slog.Uint64("station_id", stationID))
+			return nil, service.ErrBicycleUnavailable
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			s.log.InfoContext(ctx, op, "user already has an active booking", slog.Uint64("user_id", userID))
+			return nil, service.ErrBookingInProgress
+		}
+		s.log.ErrorContext(ctx, op, "failed to hold bicycle", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	payment, err := s.payments.AuthorizeBooking(ctx, booking)
+	if err != nil {
+		// No fee, no hold: give the bicycle back
+		if err := s.repo.Release(ctx, booking, models.BookingStatusCancelled); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to release unpaid booking", slog.Uint64("id", booking.ID), sl.Err(err))
+		}
+		return nil, err
+	}
+	if payment != nil {
+		booking.PaymentID = &payment.ID
+	}
+
+	return booking, nil
+}
+
+// Cancel releases the user's active booking
+func (s *BookingService) Cancel(ctx context.Context, userID, bookingID uint64) error {
+	const op = "services.BookingService.Cancel"
+
+	booking, err := s.ByID(ctx, userID, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking.Status != models.BookingStatusActive {
+		s.log.InfoContext(ctx, op, "booking is not active", slog.Uint64("id", bookingID), slog.String("status", booking.Status))
+		return service.ErrBookingNotActive
+	}
+
+	err = s.repo.Release(ctx, booking, models.BookingStatusCancelled)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "booking released concurrently", slog.Uint64("id", bookingID))
+			return service.ErrBookingNotActive
+		}
+		s.log.ErrorContext(ctx, op, "failed to cancel booking", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.settle(ctx, op, booking, false)
+
+	return nil
+}
+
+// Convert starts a rental of the booked bicycle, the rider has arrived at the station
+func (s *BookingService) Convert(ctx context.Context, userID, bookingID uint64) (*models.Rental, error) {
+	const op = "services.BookingService.Convert"
+
+	booking, err := s.ByID(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if booking.Status != models.BookingStatusActive || !booking.ExpiresAt.After(now) {
+		s.log.InfoContext(ctx, op, "booking is not active", slog.Uint64("id", bookingID), slog.String("status", booking.Status))
+		return nil, service.ErrBookingNotActive
+	}
+
+	tariff, err := s.tariffs.Current(ctx, now)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to resolve tariff", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	rental := &models.Rental{
+		UserID:         userID,
+		BicycleID:      booking.BicycleID,
+		StationStartID: booking.StationID,
+		TariffID:       &tariff.ID,
+		Status:         models.RentalStatusActive,
+		StartTime:      &now,
+	}
+
+	err = s.repo.Convert(ctx, booking, rental)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "booking released concurrently", slog.Uint64("id", bookingID))
+			return nil, service.ErrBookingNotActive
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			s.log.InfoContext(ctx, op, "user already has an active rental", slog.Uint64("user_id", userID))
+			return nil, service.ErrRentalInProgress
+		}
+		s.log.ErrorContext(ctx, op, "failed to convert booking", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	metrics.RidesStarted.Inc()
+
+	s.settle(ctx, op, booking, false)
+
+	return rental, nil
+}
+
+// ByID returns the user's booking, bookings of other users are reported as not found
+func (s *BookingService) ByID(ctx context.Context, userID, bookingID uint64) (*models.Booking, error) {
+	const op = "services.BookingService.ByID"
+
+	booking, err := s.repo.GetByID(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "booking not found", slog.Uint64("id", bookingID))
+			return nil, service.ErrBookingNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get booking", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if booking.UserID != userID {
+		s.log.InfoContext(ctx, op, "booking belongs to another user", slog.Uint64("id", bookingID), slog.Uint64("user_id", userID))
+		return nil, service.ErrBookingNotFound
+	}
+
+	return booking, nil
+}
+
+// ExpireDue releases bookings whose hold ended before now and returns how many were released.
+// Bookings cancelled or converted meanwhile are skipped.
+func (s *BookingService) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "services.BookingService.ExpireDue"
+
+	bookings, err := s.repo.ListExpired(ctx, now, expireBatchSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list expired bookings", sl.Err(err))
+		return 0, service.ErrInternalError
+	}
+
+	expired := 0
+	for i := range bookings {
+		err := s.repo.Release(ctx, &bookings[i], models.BookingStatusExpired)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			s.log.ErrorContext(ctx, op, "failed to expire booking", slog.Uint64("id", bookings[i].ID), sl.Err(err))
+			return expired, service.ErrInternalError
+		}
+		// The rider didn't show up, the fee is kept
+		s.settle(ctx, op, &bookings[i], true)
+		expired++
+	}
+
+	if expired > 0 {
+		s.log.InfoContext(ctx, op, "bookings expired", slog.Int("count", expired))
+		metrics.BookingsExpired.Add(float64(expired))
+	}
+
+	return expired, nil
+}
+
+// settle captures or releases the booking fee. Failures leave the payment authorized
+// and don't undo the booking change, they are logged for follow-up.
+func (s *BookingService) settle(ctx context.Context, op string, booking *models.Booking, capture bool) {
+	if err := s.payments.SettleBooking(ctx, booking, capture); err != nil {
+		s.log.WarnContext(ctx, op, "booking fee not settled", slog.Uint64("id", booking.ID), sl.Err(err))
+	}
+}
@@ -0,0 +1,262 @@
+package booking_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	booking_service "sdt-bicycle-rental/internal/service/booking"
+	mocks "sdt-bicycle-rental/internal/service/booking/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var cfg = config.Booking{HoldDuration: 15 * time.Minute}
+
+func TestBookingService_Reserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		policyErr  error
+		mockErr    error
+		paymentErr error
+		wantErr    error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:      "account not verified",
+			policyErr: service.ErrAccountNotVerified,
+			wantErr:   service.ErrAccountNotVerified,
+		},
+		{
+			name:       "booking fee declined",
+			paymentErr: service.ErrPaymentDeclined,
+			wantErr:    service.ErrPaymentDeclined,
+		},
+		{
+			name:    "bicycle not available",
+			mockErr: gorm.ErrRecordNotFound,
+			wantErr: service.ErrBicycleUnavailable,
+		},
+		{
+			name:    "booking in progress",
+			mockErr: gorm.ErrDuplicatedKey,
+			wantErr: service.ErrBookingInProgress,
+		},
+		{
+			name:    "unexpected error",
+			mockErr: errors.New("unexpected error"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBookingRepository(t)
+			payments := mocks.NewPaymentHolder(t)
+			accounts := mocks.NewAccountPolicy(t)
+			s := booking_service.New(repo, mocks.NewTariffProvider(t), payments, accounts, slogdiscard.NewDiscardLogger(), cfg)
+
+			accounts.On("RequireVerified", mock.Anything, uint64(1)).Return(tt.policyErr).Once()
+			if tt.policyErr != nil {
+				if _, err := s.Reserve(context.Background(), 1, 2, 3); !errors.Is(err, tt.wantErr) {
+					t.Errorf("BookingService.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			repo.On("Hold", mock.Anything, mock.MatchedBy(func(b *models.Booking) bool {
+				window := time.Until(*b.ExpiresAt)
+				return b.UserID == 1 && b.BicycleID == 2 && b.StationID == 3 &&
+					b.Status == models.BookingStatusActive && window > 14*time.Minute && window <= 15*time.Minute
+			})).Return(tt.mockErr).Once()
+			if tt.mockErr == nil {
+				var payment *models.Payment
+				if tt.paymentErr == nil {
+					payment = &models.Payment{ID: 5, Status: models.PaymentStatusAuthorized}
+				} else {
+					repo.On("Release", mock.Anything, mock.AnythingOfType("*models.Booking"), models.BookingStatusCancelled).Return(nil).Once()
+				}
+				payments.On("AuthorizeBooking", mock.Anything, mock.AnythingOfType("*models.Booking")).Return(payment, tt.paymentErr).Once()
+			}
+
+			got, err := s.Reserve(context.Background(), 1, 2, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookingService.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && *got.PaymentID != 5 {
+				t.Errorf("BookingService.Reserve() payment = %v, want %v", *got.PaymentID, 5)
+			}
+		})
+	}
+}
+
+func TestBookingService_Cancel(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   uint64
+		booking  *models.Booking
+		getErr   error
+		mockCall bool
+		mockErr  error
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			userID:   1,
+			booking:  &models.Booking{ID: 10, UserID: 1, Status: models.BookingStatusActive},
+			mockCall: true,
+		},
+		{
+			name:    "not found",
+			userID:  1,
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrBookingNotFound,
+		},
+		{
+			name:    "other user's booking",
+			userID:  2,
+			booking: &models.Booking{ID: 10, UserID: 1, Status: models.BookingStatusActive},
+			wantErr: service.ErrBookingNotFound,
+		},
+		{
+			name:    "already expired",
+			userID:  1,
+			booking: &models.Booking{ID: 10, UserID: 1, Status: models.BookingStatusExpired},
+			wantErr: service.ErrBookingNotActive,
+		},
+		{
+			name:     "released concurrently",
+			userID:   1,
+			booking:  &models.Booking{ID: 10, UserID: 1, Status: models.BookingStatusActive},
+			mockCall: true,
+			mockErr:  gorm.ErrRecordNotFound,
+			wantErr:  service.ErrBookingNotActive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBookingRepository(t)
+			payments := mocks.NewPaymentHolder(t)
+			s := booking_service.New(repo, mocks.NewTariffProvider(t), payments, mocks.NewAccountPolicy(t), slogdiscard.NewDiscardLogger(), cfg)
+
+			repo.On("GetByID", mock.Anything, uint64(10)).Return(tt.booking, tt.getErr).Once()
+			if tt.mockCall {
+				repo.On("Release", mock.Anything, tt.booking, models.BookingStatusCancelled).Return(tt.mockErr).Once()
+			}
+			if tt.wantErr == nil {
+				payments.On("SettleBooking", mock.Anything, tt.booking, false).Return(nil).Once()
+			}
+
+			if err := s.Cancel(context.Background(), tt.userID, 10); !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookingService.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookingService_Convert(t *testing.T) {
+	active := func() *models.Booking {
+		return &models.Booking{
+			ID:        10,
+			UserID:    1,
+			BicycleID: 2,
+			StationID: 3,
+			Status:    models.BookingStatusActive,
+			ExpiresAt: util.Ptr(time.Now().Add(5 * time.Minute)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		booking  *models.Booking
+		mockCall bool
+		mockErr  error
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			booking:  active(),
+			mockCall: true,
+		},
+		{
+			name: "hold ended",
+			booking: &models.Booking{
+				ID: 10, UserID: 1, Status: models.BookingStatusActive,
+				ExpiresAt: util.Ptr(time.Now().Add(-time.Minute)),
+			},
+			wantErr: service.ErrBookingNotActive,
+		},
+		{
+			name:     "ride in progress",
+			booking:  active(),
+			mockCall: true,
+			mockErr:  gorm.ErrDuplicatedKey,
+			wantErr:  service.ErrRentalInProgress,
+		},
+		{
+			name:     "expired concurrently",
+			booking:  active(),
+			mockCall: true,
+			mockErr:  gorm.ErrRecordNotFound,
+			wantErr:  service.ErrBookingNotActive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewBookingRepository(t)
+			tariffs := mocks.NewTariffProvider(t)
+			payments := mocks.NewPaymentHolder(t)
+			s := booking_service.New(repo, tariffs, payments, mocks.NewAccountPolicy(t), slogdiscard.NewDiscardLogger(), cfg)
+
+			repo.On("GetByID", mock.Anything, uint64(10)).Return(tt.booking, nil).Once()
+			if tt.mockCall {
+				tariffs.On("Current", mock.Anything, mock.AnythingOfType("time.Time")).Return(&models.Tariff{ID: 7}, nil).Once()
+				repo.On("Convert", mock.Anything, tt.booking, mock.MatchedBy(func(r *models.Rental) bool {
+					return r.UserID == 1 && r.BicycleID == 2 && r.StationStartID == 3 && *r.TariffID == 7 &&
+						r.Status == models.RentalStatusActive
+				})).Return(tt.mockErr).Once()
+			}
+			if tt.wantErr == nil {
+				payments.On("SettleBooking", mock.Anything, tt.booking, false).Return(nil).Once()
+			}
+
+			if _, err := s.Convert(context.Background(), 1, 10); !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookingService.Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookingService_ExpireDue(t *testing.T) {
+	repo := mocks.NewBookingRepository(t)
+	payments := mocks.NewPaymentHolder(t)
+	s := booking_service.New(repo, mocks.NewTariffProvider(t), payments, mocks.NewAccountPolicy(t), slogdiscard.NewDiscardLogger(), cfg)
+
+	now := time.Now()
+	bookings := []models.Booking{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	repo.On("ListExpired", mock.Anything, now, mock.AnythingOfType("int")).Return(bookings, nil).Once()
+	repo.On("Release", mock.Anything, &bookings[0], models.BookingStatusExpired).Return(nil).Once()
+	// cancelled by the rider in the meantime
+	repo.On("Release", mock.Anything, &bookings[1], models.BookingStatusExpired).Return(gorm.ErrRecordNotFound).Once()
+	repo.On("Release", mock.Anything, &bookings[2], models.BookingStatusExpired).Return(nil).Once()
+	// no-show fees are kept, a failed capture doesn't stop the sweep
+	payments.On("SettleBooking", mock.Anything, &bookings[0], true).Return(service.ErrInternalError).Once()
+	payments.On("SettleBooking", mock.Anything, &bookings[2], true).Return(nil).Once()
+
+	expired, err := s.ExpireDue(context.Background(), now)
+	if err != nil {
+		t.Fatalf("BookingService.ExpireDue() error = %v", err)
+	}
+	if expired != 2 {
+		t.Errorf("BookingService.ExpireDue() = %v, want %v", expired, 2)
+	}
+}
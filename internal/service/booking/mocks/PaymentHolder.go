@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentHolder is an autogenerated mock type for the PaymentHolder type
+type PaymentHolder struct {
+	mock.Mock
+}
+
+// AuthorizeBooking provides a mock function with given fields: ctx, booking
+func (_m *PaymentHolder) AuthorizeBooking(ctx context.Context, booking *models.Booking) (*models.Payment, error) {
+	ret := _m.Called(ctx, booking)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizeBooking")
+	}
+
+	var r0 *models.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Booking) (*models.Payment, error)); ok {
+		return rf(ctx, booking)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Booking) *models.Payment); ok {
+		r0 = rf(ctx, booking)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Booking) error); ok {
+		r1 = rf(ctx, booking)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettleBooking provides a mock function with given fields: ctx, booking, capture
+func (_m *PaymentHolder) SettleBooking(ctx context.Context, booking *models.Booking, capture bool) error {
+	ret := _m.Called(ctx, booking, capture)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SettleBooking")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Booking, bool) error); ok {
+		r0 = rf(ctx, booking, capture)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPaymentHolder creates a new instance of PaymentHolder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentHolder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentHolder {
+	mock := &PaymentHolder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,61 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TariffProvider is an autogenerated mock type for the TariffProvider type
+type TariffProvider struct {
+	mock.Mock
+}
+
+// Current provides a mock function with given fields: ctx, now
+func (_m *TariffProvider) Current(ctx context.Context, now time.Time) (*models.Tariff, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Current")
+	}
+
+	var r0 *models.Tariff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*models.Tariff, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *models.Tariff); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tariff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTariffProvider creates a new instance of TariffProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTariffProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TariffProvider {
+	mock := &TariffProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
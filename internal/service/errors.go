@@ -1,14 +1,137 @@
 package service
 
-import "errors"
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Error is a domain error: a stable machine readable Code, the HTTP status it maps to
+// and a human readable Detail. Errors compare by Code, so errors.Is(err, ErrStationNotFound)
+// matches any *Error with the station_not_found code, whatever its detail.
+type Error struct {
+	Code   string
+	Status int
+	Detail string
+	// Violations are kept untranslated, the messages depend on the locale of the request
+	Violations validator.ValidationErrors
+	// RetryAfter tells a throttled client when to try again, sent as the Retry-After header
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail returns a copy of e with another detail, e.g. naming the invalid parameter
+func (e *Error) WithDetail(detail string) *Error {
+	c := *e
+	c.Detail = detail
+	return &c
+}
+
+// WithRetryAfter returns a copy of e asking the client to wait d before retrying
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	c := *e
+	c.RetryAfter = d
+	return &c
+}
+
+// Invalid reports failed struct or field validation as ErrValidation
+func Invalid(errs ...validator.ValidationErrors) *Error {
+	e := *ErrValidation
+	for _, err := range errs {
+		e.Violations = append(e.Violations, err...)
+	}
+	return &e
+}
+
+func newError(code string, status int, detail string) *Error {
+	return &Error{Code: code, Status: status, Detail: detail}
+}
 
 var (
 	// Common
-	ErrInternalError = errors.New("internal server error")
+	ErrInternalError = newError("internal_error", http.StatusInternalServerError, "internal server error")
+	ErrInvalidInput  = newError("invalid_input", http.StatusBadRequest, "invalid input")
+	ErrValidation    = newError("validation_failed", http.StatusBadRequest, "validation failed")
 
 	// Auth
-	ErrExpiredToken       = errors.New("token expired")
-	ErrInvalidToken       = errors.New("invalid token")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUnauthorized       = newError("unauthorized", http.StatusUnauthorized, "missing token")
+	ErrExpiredToken       = newError("token_expired", http.StatusUnauthorized, "token expired")
+	ErrInvalidToken       = newError("invalid_token", http.StatusUnauthorized, "invalid token")
+	ErrTokenReused        = newError("token_reused", http.StatusUnauthorized, "refresh token reuse detected")
+	ErrUserAlreadyExists  = newError("user_already_exists", http.StatusConflict, "user already exists")
+	ErrInvalidCredentials = newError("invalid_credentials", http.StatusUnauthorized, "invalid credentials")
+	ErrInvalidResetToken  = newError("invalid_reset_token", http.StatusBadRequest, "reset token is invalid or expired")
+	ErrTooManyAttempts    = newError("too_many_attempts", http.StatusTooManyRequests, "too many failed login attempts, try again later")
+	ErrAccountDisabled    = newError("account_disabled", http.StatusForbidden, "account is disabled")
+
+	// Passwords
+	ErrWeakPassword  = newError("weak_password", http.StatusBadRequest, "password is too weak")
+	ErrWrongPassword = newError("wrong_password", http.StatusForbidden, "current password is wrong")
+
+	// Sessions
+	ErrSessionNotFound = newError("session_not_found", http.StatusNotFound, "session not found")
+
+	// External login
+	ErrUnknownProvider          = newError("unknown_provider", http.StatusNotFound, "unknown identity provider")
+	ErrInvalidOIDCState         = newError("invalid_oidc_state", http.StatusBadRequest, "sign-in expired or was started in another browser, start again")
+	ErrExternalLoginRejected    = newError("external_login_rejected", http.StatusUnauthorized, "the identity provider did not sign you in")
+	ErrExternalEmailNotVerified = newError("external_email_not_verified", http.StatusForbidden, "the identity provider has not verified your email")
+	ErrAccountNotLinked         = newError("account_not_linked", http.StatusConflict, "an account with this email exists, sign in with the password and verify the email to link it")
+	ErrProviderUnavailable      = newError("provider_unavailable", http.StatusBadGateway, "the identity provider is unavailable, try again later")
+
+	// MFA
+	ErrInvalidMFACode    = newError("invalid_mfa_code", http.StatusUnauthorized, "authentication code is invalid")
+	ErrMFAAlreadyEnabled = newError("mfa_already_enabled", http.StatusConflict, "two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = newError("mfa_not_enrolled", http.StatusConflict, "start the authenticator enrollment first")
+	ErrInvalidSetupLink  = newError("invalid_mfa_setup_link", http.StatusBadRequest, "setup link is invalid or expired, sign in again for a new one")
+
+	// Verification
+	ErrInvalidVerificationCode = newError("invalid_verification_code", http.StatusBadRequest, "verification code is invalid or expired")
+	ErrAlreadyVerified         = newError("already_verified", http.StatusConflict, "already verified")
+	ErrVerificationThrottled   = newError("verification_throttled", http.StatusTooManyRequests, "a code was sent recently, try again later")
+	ErrAccountNotVerified      = newError("account_not_verified", http.StatusForbidden, "verify your email and phone first")
+
+	// Access
+	ErrForbidden    = newError("forbidden", http.StatusForbidden, "forbidden")
+	ErrInvalidRole  = newError("invalid_role", http.StatusBadRequest, "invalid role")
+	ErrUserNotFound = newError("user_not_found", http.StatusNotFound, "user not found")
+
+	// Station
+	ErrStationNotFound = newError("station_not_found", http.StatusNotFound, "station not found")
+	ErrStationInUse    = newError("station_in_use", http.StatusConflict, "station has bicycles assigned")
+
+	// Bicycle
+	ErrBicycleNotFound         = newError("bicycle_not_found", http.StatusNotFound, "bicycle not found")
+	ErrBicycleUnavailable      = newError("bicycle_unavailable", http.StatusConflict, "bicycle is not available")
+	ErrInvalidBicycleStatus    = newError("invalid_bicycle_status", http.StatusBadRequest, "invalid bicycle status")
+	ErrInvalidBicycleType      = newError("invalid_bicycle_type", http.StatusBadRequest, "invalid bicycle type")
+	ErrInvalidStatusTransition = newError("invalid_status_transition", http.StatusConflict, "invalid bicycle status transition")
+
+	// Rental
+	ErrRentalNotFound   = newError("rental_not_found", http.StatusNotFound, "rental not found")
+	ErrRentalInProgress = newError("rental_in_progress", http.StatusConflict, "user already has an active rental")
+	ErrRentalNotActive  = newError("rental_not_active", http.StatusConflict, "rental is not active")
+
+	// Booking
+	ErrBookingNotFound   = newError("booking_not_found", http.StatusNotFound, "booking not found")
+	ErrBookingInProgress = newError("booking_in_progress", http.StatusConflict, "user already has an active booking")
+	ErrBookingNotActive  = newError("booking_not_active", http.StatusConflict, "booking is not active")
+
+	// Payment
+	ErrPaymentNotFound      = newError("payment_not_found", http.StatusNotFound, "payment not found")
+	ErrPaymentDeclined      = newError("payment_declined", http.StatusPaymentRequired, "payment declined")
+	ErrPaymentNotRefundable = newError("payment_not_refundable", http.StatusConflict, "payment can not be refunded")
+
+	// Pricing
+	ErrTariffNotFound       = newError("tariff_not_found", http.StatusNotFound, "tariff not found")
+	ErrTariffActiveFromPast = newError("tariff_active_from_past", http.StatusBadRequest, "tariff can not take effect in the past")
 )
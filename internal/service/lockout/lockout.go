@@ -0,0 +1,178 @@
+package lockout_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"time"
+)
+
+// Scopes a login is throttled in
+const (
+	ScopeAccount = "account"
+	ScopeIP      = "ip"
+)
+
+// Store keeps the failed login counters, in memory or shared between instances
+//
+//go:generate mockery --name=Store
+type Store interface {
+	Get(ctx context.Context, key string) (*models.LoginAttempt, error)
+	Update(ctx context.Context, key string, update func(attempt *models.LoginAttempt)) (*models.LoginAttempt, error)
+	Delete(ctx context.Context, key string) error
+}
+
+//go:generate mockery --name=AuditRepository
+type AuditRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+}
+
+type LockoutService struct {
+	store Store
+	audit AuditRepository
+	log   *slog.Logger
+	cfg   config.Lockout
+}
+
+func New(store Store, audit AuditRepository, log *slog.Logger, cfg config.Lockout) *LockoutService {
+	return &LockoutService{store: store, audit: audit, log: log, cfg: cfg}
+}
+
+// counter is the key of one scope a login attempt counts against
+type counter struct {
+	scope     string
+	key       string
+	threshold int
+}
+
+// Check refuses a login while its account or its client IP is locked out,
+// the error tells how long the longer of the two lockouts lasts
+func (s *LockoutService) Check(ctx context.Context, email, ip string) error {
+	const op = "services.LockoutService.Check"
+
+	var wait time.Duration
+	for _, c := range s.counters(email, ip) {
+		attempt, err := s.store.Get(ctx, c.key)
+		if err != nil {
+			s.log.ErrorContext(ctx, op, "failed to get login attempts", slog.String("scope", c.scope), sl.Err(err))
+			return service.ErrInternalError
+		}
+		if attempt.LockedUntil != nil {
+			wait = max(wait, time.Until(*attempt.LockedUntil))
+		}
+	}
+
+	if wait > 0 {
+		s.log.InfoContext(ctx, op, "login locked out", slog.String("email", email), slog.String("ip", ip), slog.Duration("retry_after", wait))
+		return service.ErrTooManyAttempts.WithRetryAfter(wait)
+	}
+	return nil
+}
+
+// Fail counts a failed login against its account and its client IP
+// and locks out whichever of them reached its threshold
+func (s *LockoutService) Fail(ctx context.Context, email, ip string) {
+	const op = "services.LockoutService.Fail"
+
+	for _, c := range s.counters(email, ip) {
+		var locked bool
+		attempt, err := s.store.Update(ctx, c.key, func(attempt *models.LoginAttempt) {
+			locked = s.fail(attempt, c.threshold, time.Now())
+		})
+		if err != nil {
+			s.log.ErrorContext(ctx, op, "failed to count login failure", slog.String("scope", c.scope), sl.Err(err))
+			continue
+		}
+
+		if locked {
+			metrics.Lockouts.WithLabelValues(c.scope).Inc()
+			s.record(ctx, c, attempt)
+		}
+	}
+}
+
+// Succeed clears the failures of the account. The client IP keeps its count,
+// otherwise signing in to an own account would reset it between guesses at others.
+func (s *LockoutService) Succeed(ctx context.Context, email string) {
+	const op = "services.LockoutService.Succeed"
+
+	if s.cfg.AccountThreshold <= 0 {
+		return
+	}
+	if err := s.store.Delete(ctx, accountKey(email)); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to clear login failures", slog.String("email", email), sl.Err(err))
+	}
+}
+
+// fail counts one failure at now and reports whether it locked the key out.
+// Each lockout in a row doubles the cooldown, a quiet period starts over from the first one.
+func (s *LockoutService) fail(attempt *models.LoginAttempt, threshold int, now time.Time) bool {
+	if attempt.LastFailureAt != nil {
+		quiet := now.Sub(*attempt.LastFailureAt)
+		if quiet > s.cfg.Window {
+			attempt.Failures = 0
+		}
+		if quiet > s.cfg.ResetAfter {
+			attempt.Lockouts = 0
+		}
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = &now
+	if attempt.Failures < threshold {
+		return false
+	}
+
+	attempt.Failures = 0
+	attempt.Lockouts++
+	attempt.LockedUntil = util.Ptr(now.Add(s.cooldown(attempt.Lockouts)))
+	return true
+}
+
+func (s *LockoutService) cooldown(lockouts int) time.Duration {
+	d := s.cfg.Cooldown
+	for i := 1; i < lockouts && d < s.cfg.MaxCooldown; i++ {
+		d *= 2
+	}
+	return min(d, s.cfg.MaxCooldown)
+}
+
+// counters lists the scopes the attempt counts against, a threshold of 0 turns a scope off
+func (s *LockoutService) counters(email, ip string) []counter {
+	var counters []counter
+	if s.cfg.AccountThreshold > 0 && email != "" {
+		counters = append(counters, counter{scope: ScopeAccount, key: accountKey(email), threshold: s.cfg.AccountThreshold})
+	}
+	if s.cfg.IPThreshold > 0 && ip != "" {
+		counters = append(counters, counter{scope: ScopeIP, key: ScopeIP + ":" + ip, threshold: s.cfg.IPThreshold})
+	}
+	return counters
+}
+
+func accountKey(email string) string {
+	return ScopeAccount + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// record saves the lockout to the audit log. Failing to save it does not lift the lockout.
+func (s *LockoutService) record(ctx context.Context, c counter, attempt *models.LoginAttempt) {
+	const op = "services.LockoutService.record"
+
+	s.log.WarnContext(ctx, op, "audit", slog.String("action", models.AuditActionLoginLocked), slog.String("key", c.key),
+		slog.Int("lockouts", attempt.Lockouts), slog.Time("locked_until", *attempt.LockedUntil))
+
+	event := &models.AuditEvent{
+		Action: models.AuditActionLoginLocked,
+		Details: fmt.Sprintf(`{"scope":%q,"key":%q,"lockouts":%d,"locked_until":%q}`,
+			c.scope, c.key, attempt.Lockouts, attempt.LockedUntil.UTC().Format(time.RFC3339)),
+	}
+	if err := s.audit.Create(ctx, event); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save audit event", sl.Err(err))
+	}
+}
@@ -0,0 +1,149 @@
+package lockout_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/memory"
+	"sdt-bicycle-rental/internal/service"
+	lockout_service "sdt-bicycle-rental/internal/service/lockout"
+	mocks "sdt-bicycle-rental/internal/service/lockout/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	email = "valid@email.com"
+	ip    = "192.0.2.1"
+)
+
+var lockoutConfig = config.Lockout{
+	AccountThreshold: 3,
+	IPThreshold:      5,
+	Window:           15 * time.Minute,
+	Cooldown:         time.Minute,
+	MaxCooldown:      3 * time.Minute,
+	ResetAfter:       24 * time.Hour,
+}
+
+// retryAfter returns how long Check asks to wait, 0 if the login is allowed
+func retryAfter(t *testing.T, s *lockout_service.LockoutService, email, ip string) time.Duration {
+	t.Helper()
+
+	err := s.Check(context.Background(), email, ip)
+	if err == nil {
+		return 0
+	}
+	var e *service.Error
+	require.ErrorAs(t, err, &e)
+	require.ErrorIs(t, err, service.ErrTooManyAttempts)
+	return e.RetryAfter
+}
+
+func TestLockoutService_Account(t *testing.T) {
+	ctx := context.Background()
+	audit := mocks.NewAuditRepository(t)
+	s := lockout_service.New(memory.NewLoginAttemptRepository(time.Hour), audit, slogdiscard.NewDiscardLogger(), lockoutConfig)
+
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionLoginLocked && e.ActorID == nil
+	})).Return(nil).Once()
+	locked := testutil.ToFloat64(metrics.Lockouts.WithLabelValues(lockout_service.ScopeAccount))
+
+	for range 2 {
+		s.Fail(ctx, email, ip)
+	}
+	assert.Zero(t, retryAfter(t, s, email, ip))
+
+	// the threshold locks the account whatever the case of the email and the client IP
+	s.Fail(ctx, "Valid@Email.com", ip)
+	wait := retryAfter(t, s, email, "198.51.100.7")
+	assert.InDelta(t, time.Minute, wait, float64(time.Second))
+	assert.Equal(t, locked+1, testutil.ToFloat64(metrics.Lockouts.WithLabelValues(lockout_service.ScopeAccount)))
+
+	// other accounts are not affected
+	assert.Zero(t, retryAfter(t, s, "other@email.com", "198.51.100.7"))
+
+	// success clears the account
+	s.Succeed(ctx, email)
+	assert.Zero(t, retryAfter(t, s, email, "198.51.100.7"))
+}
+
+func TestLockoutService_IP(t *testing.T) {
+	ctx := context.Background()
+	audit := mocks.NewAuditRepository(t)
+	s := lockout_service.New(memory.NewLoginAttemptRepository(time.Hour), audit, slogdiscard.NewDiscardLogger(), lockoutConfig)
+
+	audit.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+
+	// a different account each time, only the IP reaches its threshold
+	for _, e := range []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com", "e@email.com"} {
+		s.Fail(ctx, e, ip)
+	}
+	assert.NotZero(t, retryAfter(t, s, "f@email.com", ip))
+	assert.Zero(t, retryAfter(t, s, "f@email.com", "198.51.100.7"))
+
+	// signing in to an own account doesn't clear the IP
+	s.Succeed(ctx, "f@email.com")
+	assert.NotZero(t, retryAfter(t, s, "f@email.com", ip))
+}
+
+func TestLockoutService_ProgressiveCooldown(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewLoginAttemptRepository(time.Hour)
+	audit := mocks.NewAuditRepository(t)
+	s := lockout_service.New(store, audit, slogdiscard.NewDiscardLogger(), lockoutConfig)
+
+	audit.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+
+	// lift each lockout by hand instead of waiting for it
+	lift := func() {
+		_, err := store.Update(ctx, "account:"+email, func(a *models.LoginAttempt) { a.LockedUntil = nil })
+		require.NoError(t, err)
+	}
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		for range lockoutConfig.AccountThreshold {
+			s.Fail(ctx, email, "")
+		}
+		assert.InDelta(t, want, retryAfter(t, s, email, ""), float64(time.Second))
+		lift()
+	}
+
+	// after a quiet period the next lockout is the first one again
+	_, err := store.Update(ctx, "account:"+email, func(a *models.LoginAttempt) {
+		a.LastFailureAt = util.Ptr(time.Now().Add(-25 * time.Hour))
+	})
+	require.NoError(t, err)
+	for range lockoutConfig.AccountThreshold {
+		s.Fail(ctx, email, "")
+	}
+	assert.InDelta(t, time.Minute, retryAfter(t, s, email, ""), float64(time.Second))
+}
+
+func TestLockoutService_StoreError(t *testing.T) {
+	store := mocks.NewStore(t)
+	s := lockout_service.New(store, mocks.NewAuditRepository(t), slogdiscard.NewDiscardLogger(), lockoutConfig)
+
+	store.On("Get", mock.Anything, "account:"+email).Return(nil, errors.New("connection refused")).Once()
+
+	assert.ErrorIs(t, s.Check(context.Background(), email, ip), service.ErrInternalError)
+}
+
+func TestLockoutService_Disabled(t *testing.T) {
+	// no scope is checked, the store is never called
+	s := lockout_service.New(mocks.NewStore(t), mocks.NewAuditRepository(t), slogdiscard.NewDiscardLogger(), config.Lockout{})
+
+	s.Fail(context.Background(), email, ip)
+	s.Succeed(context.Background(), email)
+	assert.NoError(t, s.Check(context.Background(), email, ip))
+}
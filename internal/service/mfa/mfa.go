@@ -0,0 +1,364 @@
+package mfa_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/totp"
+	"sdt-bicycle-rental/lib/util"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	// recoveryCodeSize is the number of random bytes behind one recovery code
+	recoveryCodeSize = 7
+	setupTokenSize   = 32
+)
+
+//go:generate mockery --name=SecretRepository
+type SecretRepository interface {
+	GetSecret(ctx context.Context, userID uint64) (*models.TOTPSecret, error)
+	SaveSecret(ctx context.Context, secret *models.TOTPSecret) error
+	SaveSetupLink(ctx context.Context, secret *models.TOTPSecret) error
+	UseSetupLink(ctx context.Context, tokenHash string) (*models.TOTPSecret, error)
+	Confirm(ctx context.Context, userID uint64, sessionID string, step int64, recoveryHashes []string) error
+	UseStep(ctx context.Context, userID uint64, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+}
+
+//go:generate mockery --name=RoleProvider
+type RoleProvider interface {
+	Roles(ctx context.Context, userID uint64) ([]string, error)
+}
+
+// Notifier delivers the setup link to the user
+//
+//go:generate mockery --name=Notifier
+type Notifier interface {
+	Send(ctx context.Context, msg notify.Message) error
+}
+
+// Enrollment is what the user adds to an authenticator app, URI is meant for a QR code
+type Enrollment struct {
+	Secret string
+	URI    string
+}
+
+type MFAService struct {
+	repo     SecretRepository
+	users    UserRepository
+	roles    RoleProvider
+	notifier Notifier
+	log      *slog.Logger
+	cfg      config.MFA
+}
+
+func New(repo SecretRepository, users UserRepository, roles RoleProvider, notifier Notifier, log *slog.Logger, cfg config.MFA) *MFAService {
+	return &MFAService{repo: repo, users: users, roles: roles, notifier: notifier, log: log, cfg: cfg}
+}
+
+type codeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// Status reports whether the user confirmed an authenticator and whether logins need a code,
+// which admins do when the policy requires it even before they enrolled
+func (s *MFAService) Status(ctx context.Context, userID uint64) (enabled, required bool, err error) {
+	const op = "services.MFAService.Status"
+
+	secret, err := s.repo.GetSecret(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log.ErrorContext(ctx, op, "failed to get secret", slog.Uint64("user_id", userID), sl.Err(err))
+		return false, false, service.ErrInternalError
+	}
+	enabled = secret != nil && secret.ConfirmedAt != nil
+	if enabled || !s.cfg.RequireForAdmins {
+		return enabled, enabled, nil
+	}
+
+	roles, err := s.roles.Roles(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to get roles", slog.Uint64("user_id", userID), sl.Err(err))
+		return false, false, service.ErrInternalError
+	}
+	return false, slices.Contains(roles, models.RoleAdmin), nil
+}
+
+// Enroll starts a new authenticator for the user, it takes part in logins once confirmed.
+// Starting again before the confirmation replaces the secret.
+func (s *MFAService) Enroll(ctx context.Context, userID uint64) (*Enrollment, error) {
+	const op = "services.MFAService.Enroll"
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate secret", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	if err := s.repo.SaveSecret(ctx, &models.TOTPSecret{UserID: userID, Secret: secret}); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			s.log.InfoContext(ctx, op, "mfa already enabled", slog.Uint64("user_id", userID))
+			return nil, service.ErrMFAAlreadyEnabled
+		}
+		s.log.ErrorContext(ctx, op, "failed to save secret", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "mfa enrollment started", slog.Uint64("user_id", userID))
+	return &Enrollment{Secret: secret, URI: totp.URI(s.cfg.Issuer, util.Deref(user.Email), secret)}, nil
+}
+
+// SendSetupLink emails a link to the secret to a user who must use MFA but has not enrolled,
+// the login only asks for the code from it. The password alone must not be enough to add
+// an authenticator, so the secret is never returned at login.
+func (s *MFAService) SendSetupLink(ctx context.Context, userID uint64) error {
+	const op = "services.MFAService.SendSetupLink"
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate secret", sl.Err(err))
+		return service.ErrInternalError
+	}
+	raw, err := secure.RandomToken(setupTokenSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate setup token", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	err = s.repo.SaveSetupLink(ctx, &models.TOTPSecret{
+		UserID:         userID,
+		Secret:         secret,
+		SetupTokenHash: util.Ptr(secure.HashToken(raw)),
+		SetupExpiresAt: util.Ptr(time.Now().Add(s.cfg.SetupTTL)),
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return service.ErrMFAAlreadyEnabled
+		}
+		s.log.ErrorContext(ctx, op, "failed to save setup link", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if err := s.notifier.Send(ctx, s.setupMessage(util.Deref(user.Email), raw)); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to send setup link", slog.Uint64("user_id", userID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "mfa setup link sent", slog.Uint64("user_id", userID))
+	return nil
+}
+
+// Setup returns the secret behind an emailed setup link, the link works once.
+// The authenticator is then confirmed with a code at the login that sent the link.
+func (s *MFAService) Setup(ctx context.Context, token string) (*Enrollment, error) {
+	const op = "services.MFAService.Setup"
+
+	if token == "" {
+		return nil, service.ErrInvalidSetupLink
+	}
+
+	secret, err := s.repo.UseSetupLink(ctx, secure.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "setup link is invalid", slog.String("reason", "unknown, used or expired"))
+			return nil, service.ErrInvalidSetupLink
+		}
+		s.log.ErrorContext(ctx, op, "failed to use setup link", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	user, err := s.users.GetByID(ctx, secret.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrInvalidSetupLink
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "mfa setup link used", slog.Uint64("user_id", secret.UserID))
+	return &Enrollment{Secret: secret.Secret, URI: totp.URI(s.cfg.Issuer, util.Deref(user.Email), secret.Secret)}, nil
+}
+
+// Confirm enables the enrolled authenticator with its first code and returns the recovery codes,
+// they are shown to the user this once. The session the code was given in counts as passed MFA.
+func (s *MFAService) Confirm(ctx context.Context, userID uint64, sessionID string, code string) ([]string, error) {
+	const op = "services.MFAService.Confirm"
+
+	if err := service.Validate.Struct(codeRequest{Code: code}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return nil, service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	secret, err := s.repo.GetSecret(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "no enrollment started", slog.Uint64("user_id", userID))
+			return nil, service.ErrMFANotEnrolled
+		}
+		s.log.ErrorContext(ctx, op, "failed to get secret", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if secret.ConfirmedAt != nil {
+		return nil, service.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(secret.Secret, code, time.Now(), s.cfg.Skew)
+	if !ok {
+		s.log.InfoContext(ctx, op, "wrong code", slog.Uint64("user_id", userID))
+		return nil, service.ErrInvalidMFACode
+	}
+
+	codes := make([]string, s.cfg.RecoveryCodes)
+	hashes := make([]string, s.cfg.RecoveryCodes)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to generate recovery code", sl.Err(err))
+			return nil, service.ErrInternalError
+		}
+		hashes[i] = secure.HashToken(normalize(codes[i]))
+	}
+
+	if err := s.repo.Confirm(ctx, userID, sessionID, step, hashes); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// confirmed by a concurrent request
+			return nil, service.ErrInvalidMFACode
+		}
+		s.log.ErrorContext(ctx, op, "failed to confirm secret", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "mfa enabled", slog.Uint64("user_id", userID))
+	return codes, nil
+}
+
+// Verify checks a code from the authenticator or an unused recovery code.
+// Either is accepted once: the same authenticator code can't complete a second login.
+func (s *MFAService) Verify(ctx context.Context, userID uint64, code string) error {
+	const op = "services.MFAService.Verify"
+
+	if err := service.Validate.Struct(codeRequest{Code: code}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	secret, err := s.repo.GetSecret(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return service.ErrMFANotEnrolled
+		}
+		s.log.ErrorContext(ctx, op, "failed to get secret", sl.Err(err))
+		return service.ErrInternalError
+	}
+	if secret.ConfirmedAt == nil {
+		return service.ErrMFANotEnrolled
+	}
+
+	if !isTOTP(code) {
+		err = s.repo.UseRecoveryCode(ctx, userID, secure.HashToken(normalize(code)))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "wrong recovery code", slog.Uint64("user_id", userID))
+			return service.ErrInvalidMFACode
+		}
+		if err != nil {
+			s.log.ErrorContext(ctx, op, "failed to use recovery code", sl.Err(err))
+			return service.ErrInternalError
+		}
+		s.log.InfoContext(ctx, op, "recovery code used", slog.Uint64("user_id", userID))
+		return nil
+	}
+
+	step, ok := totp.Validate(secret.Secret, code, time.Now(), s.cfg.Skew)
+	if !ok {
+		s.log.InfoContext(ctx, op, "wrong code", slog.Uint64("user_id", userID))
+		return service.ErrInvalidMFACode
+	}
+	if err := s.repo.UseStep(ctx, userID, step); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "code replayed", slog.Uint64("user_id", userID))
+			return service.ErrInvalidMFACode
+		}
+		s.log.ErrorContext(ctx, op, "failed to record code", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+func (s *MFAService) setupMessage(email, token string) notify.Message {
+	link := s.cfg.SetupURL + "?token=" + url.QueryEscape(token)
+
+	return notify.Message{
+		Channel: notify.Email,
+		To:      email,
+		Subject: "Set up two-factor authentication",
+		Body: fmt.Sprintf("Your account needs an authenticator app to sign in.\n\n"+
+			"Follow the link within %s to add it, the link works once:\n%s\n\n"+
+			"If you didn't just sign in, someone knows your password: change it.", s.cfg.SetupTTL, link),
+	}
+}
+
+// newRecoveryCode returns a code like "k7x2m-qp4va", easy to copy by hand
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+// normalize lets a recovery code be typed without the dash and in any case
+func normalize(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func isTOTP(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
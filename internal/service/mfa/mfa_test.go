@@ -0,0 +1,411 @@
+package mfa_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	mocks "sdt-bicycle-rental/internal/service/mfa/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/totp"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var mfaConfig = config.MFA{
+	Issuer:           "Bicycle Rental",
+	RequireForAdmins: true,
+	Skew:             1,
+	RecoveryCodes:    3,
+	SetupURL:         "http://localhost:3000/mfa-setup",
+	SetupTTL:         30 * time.Minute,
+}
+
+const testSecret = "JBSWY3DPEHPK3PXP"
+
+func codeAt(t *testing.T, at time.Time) string {
+	code, err := totp.Code(testSecret, at)
+	if err != nil {
+		t.Fatalf("totp.Code() error = %v", err)
+	}
+	return code
+}
+
+func pending() *models.TOTPSecret {
+	return &models.TOTPSecret{UserID: 1, Secret: testSecret}
+}
+
+func confirmed() *models.TOTPSecret {
+	return &models.TOTPSecret{UserID: 1, Secret: testSecret, ConfirmedAt: util.Ptr(time.Now())}
+}
+
+func TestMFAService_Status(t *testing.T) {
+	tests := []struct {
+		name         string
+		secret       *models.TOTPSecret
+		roles        []string
+		cfg          config.MFA
+		wantEnabled  bool
+		wantRequired bool
+	}{
+		{
+			name:         "enabled",
+			secret:       confirmed(),
+			cfg:          mfaConfig,
+			wantEnabled:  true,
+			wantRequired: true,
+		},
+		{
+			name:  "regular user",
+			roles: []string{},
+			cfg:   mfaConfig,
+		},
+		{
+			name:   "enrollment not confirmed",
+			secret: pending(),
+			roles:  []string{},
+			cfg:    mfaConfig,
+		},
+		{
+			name:         "admin must enroll",
+			roles:        []string{models.RoleAdmin},
+			cfg:          mfaConfig,
+			wantRequired: true,
+		},
+		{
+			name: "admin policy off",
+			cfg:  config.MFA{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			roles := mocks.NewRoleProvider(t)
+			s := mfa_service.New(repo, mocks.NewUserRepository(t), roles, mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), tt.cfg)
+
+			var getErr error
+			if tt.secret == nil {
+				getErr = gorm.ErrRecordNotFound
+			}
+			repo.On("GetSecret", mock.Anything, uint64(1)).Return(tt.secret, getErr).Once()
+			if tt.roles != nil {
+				roles.On("Roles", mock.Anything, uint64(1)).Return(tt.roles, nil).Once()
+			}
+
+			enabled, required, err := s.Status(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("MFAService.Status() error = %v", err)
+			}
+			if enabled != tt.wantEnabled || required != tt.wantRequired {
+				t.Errorf("MFAService.Status() = %v, %v, want %v, %v", enabled, required, tt.wantEnabled, tt.wantRequired)
+			}
+		})
+	}
+}
+
+func TestMFAService_Enroll(t *testing.T) {
+	tests := []struct {
+		name    string
+		saveErr error
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "already enabled",
+			saveErr: gorm.ErrDuplicatedKey,
+			wantErr: service.ErrMFAAlreadyEnabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			users := mocks.NewUserRepository(t)
+			s := mfa_service.New(repo, users, mocks.NewRoleProvider(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), mfaConfig)
+
+			users.On("GetByID", mock.Anything, uint64(1)).Return(&models.User{ID: 1, Email: util.Ptr("valid@email.com")}, nil).Once()
+			var saved *models.TOTPSecret
+			repo.On("SaveSecret", mock.Anything, mock.AnythingOfType("*models.TOTPSecret")).
+				Run(func(args mock.Arguments) { saved = args.Get(1).(*models.TOTPSecret) }).
+				Return(tt.saveErr).Once()
+
+			got, err := s.Enroll(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MFAService.Enroll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if saved.UserID != 1 || saved.Secret != got.Secret {
+				t.Errorf("MFAService.Enroll() saved %+v, returned secret %q", saved, got.Secret)
+			}
+			if !strings.Contains(got.URI, "secret="+got.Secret) || !strings.Contains(got.URI, "valid@email.com") {
+				t.Errorf("MFAService.Enroll() URI = %q", got.URI)
+			}
+		})
+	}
+}
+
+func TestMFAService_SendSetupLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		saveErr error
+		sendErr error
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "already enabled",
+			saveErr: gorm.ErrDuplicatedKey,
+			wantErr: service.ErrMFAAlreadyEnabled,
+		},
+		{
+			name:    "not sent",
+			sendErr: errors.New("smtp unavailable"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			users := mocks.NewUserRepository(t)
+			notifier := mocks.NewNotifier(t)
+			s := mfa_service.New(repo, users, mocks.NewRoleProvider(t), notifier, slogdiscard.NewDiscardLogger(), mfaConfig)
+
+			users.On("GetByID", mock.Anything, uint64(1)).Return(&models.User{ID: 1, Email: util.Ptr("valid@email.com")}, nil).Once()
+			var saved *models.TOTPSecret
+			repo.On("SaveSetupLink", mock.Anything, mock.AnythingOfType("*models.TOTPSecret")).
+				Run(func(args mock.Arguments) { saved = args.Get(1).(*models.TOTPSecret) }).
+				Return(tt.saveErr).Once()
+			var sent notify.Message
+			if tt.saveErr == nil {
+				notifier.On("Send", mock.Anything, mock.AnythingOfType("notify.Message")).
+					Run(func(args mock.Arguments) { sent = args.Get(1).(notify.Message) }).
+					Return(tt.sendErr).Once()
+			}
+
+			err := s.SendSetupLink(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MFAService.SendSetupLink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// only the hash of the emailed token is stored, the secret is not in the message
+			_, link, _ := strings.Cut(sent.Body, "?token=")
+			token := strings.Fields(link)[0]
+			if sent.To != "valid@email.com" || saved.SetupTokenHash == nil || *saved.SetupTokenHash != secure.HashToken(token) {
+				t.Errorf("MFAService.SendSetupLink() sent %+v, saved %+v", sent, saved)
+			}
+			if strings.Contains(sent.Body, saved.Secret) {
+				t.Error("MFAService.SendSetupLink() emailed the secret")
+			}
+		})
+	}
+}
+
+func TestMFAService_Setup(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		useErr  error
+		wantErr error
+	}{
+		{
+			name:  "success",
+			token: "token",
+		},
+		{
+			name:    "used or expired",
+			token:   "token",
+			useErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrInvalidSetupLink,
+		},
+		{
+			name:    "no token",
+			wantErr: service.ErrInvalidSetupLink,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			users := mocks.NewUserRepository(t)
+			s := mfa_service.New(repo, users, mocks.NewRoleProvider(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), mfaConfig)
+
+			if tt.token != "" {
+				var secret *models.TOTPSecret
+				if tt.useErr == nil {
+					secret = pending()
+					users.On("GetByID", mock.Anything, uint64(1)).Return(&models.User{ID: 1, Email: util.Ptr("valid@email.com")}, nil).Once()
+				}
+				repo.On("UseSetupLink", mock.Anything, secure.HashToken(tt.token)).Return(secret, tt.useErr).Once()
+			}
+
+			got, err := s.Setup(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MFAService.Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.Secret != testSecret || !strings.Contains(got.URI, "valid@email.com")) {
+				t.Errorf("MFAService.Setup() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestMFAService_Confirm(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		secret    *models.TOTPSecret
+		getErr    error
+		confirmed bool
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			code:      codeAt(t, time.Now()),
+			secret:    pending(),
+			confirmed: true,
+		},
+		{
+			name:    "missing code",
+			wantErr: service.ErrValidation,
+		},
+		{
+			name:    "not enrolled",
+			code:    "123456",
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrMFANotEnrolled,
+		},
+		{
+			name:    "already enabled",
+			code:    "123456",
+			secret:  confirmed(),
+			wantErr: service.ErrMFAAlreadyEnabled,
+		},
+		{
+			name:    "wrong code",
+			code:    codeAt(t, time.Now().Add(-time.Hour)),
+			secret:  pending(),
+			wantErr: service.ErrInvalidMFACode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			s := mfa_service.New(repo, mocks.NewUserRepository(t), mocks.NewRoleProvider(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), mfaConfig)
+
+			if tt.wantErr != service.ErrValidation {
+				repo.On("GetSecret", mock.Anything, uint64(1)).Return(tt.secret, tt.getErr).Once()
+			}
+			var hashes []string
+			if tt.confirmed {
+				repo.On("Confirm", mock.Anything, uint64(1), "phone", mock.AnythingOfType("int64"), mock.Anything).
+					Run(func(args mock.Arguments) { hashes = args.Get(4).([]string) }).
+					Return(nil).Once()
+			}
+
+			codes, err := s.Confirm(context.Background(), 1, "phone", tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MFAService.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			// only the hashes are stored, the codes can be typed without the dash
+			if len(codes) != mfaConfig.RecoveryCodes || len(hashes) != len(codes) {
+				t.Fatalf("MFAService.Confirm() returned %d codes, stored %d", len(codes), len(hashes))
+			}
+			for i, code := range codes {
+				if hashes[i] != secure.HashToken(strings.ReplaceAll(code, "-", "")) {
+					t.Errorf("MFAService.Confirm() hash of %q doesn't match", code)
+				}
+			}
+		})
+	}
+}
+
+func TestMFAService_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		secret      *models.TOTPSecret
+		useStep     bool
+		stepErr     error
+		recoveryErr error
+		useRecovery string
+		wantErr     error
+	}{
+		{
+			name:    "authenticator code",
+			code:    codeAt(t, time.Now()),
+			secret:  confirmed(),
+			useStep: true,
+		},
+		{
+			name:    "replayed code",
+			code:    codeAt(t, time.Now()),
+			secret:  confirmed(),
+			useStep: true,
+			stepErr: gorm.ErrRecordNotFound,
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name:    "wrong code",
+			code:    codeAt(t, time.Now().Add(-time.Hour)),
+			secret:  confirmed(),
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name:        "recovery code",
+			code:        "ABCDE-fghij",
+			secret:      confirmed(),
+			useRecovery: "abcdefghij",
+		},
+		{
+			name:        "used recovery code",
+			code:        "abcde-fghij",
+			secret:      confirmed(),
+			useRecovery: "abcdefghij",
+			recoveryErr: gorm.ErrRecordNotFound,
+			wantErr:     service.ErrInvalidMFACode,
+		},
+		{
+			name:    "enrollment not confirmed",
+			code:    "123456",
+			secret:  pending(),
+			wantErr: service.ErrMFANotEnrolled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewSecretRepository(t)
+			s := mfa_service.New(repo, mocks.NewUserRepository(t), mocks.NewRoleProvider(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), mfaConfig)
+
+			repo.On("GetSecret", mock.Anything, uint64(1)).Return(tt.secret, nil).Once()
+			if tt.useStep {
+				repo.On("UseStep", mock.Anything, uint64(1), mock.AnythingOfType("int64")).Return(tt.stepErr).Once()
+			}
+			if tt.useRecovery != "" {
+				repo.On("UseRecoveryCode", mock.Anything, uint64(1), secure.HashToken(tt.useRecovery)).Return(tt.recoveryErr).Once()
+			}
+
+			if err := s.Verify(context.Background(), 1, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("MFAService.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+)
+
+// SecretRepository is an autogenerated mock type for the SecretRepository type
+type SecretRepository struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userID, sessionID, step, recoveryHashes
+func (_m *SecretRepository) Confirm(ctx context.Context, userID uint64, sessionID string, step int64, recoveryHashes []string) error {
+	ret := _m.Called(ctx, userID, sessionID, step, recoveryHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, int64, []string) error); ok {
+		r0 = rf(ctx, userID, sessionID, step, recoveryHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecret provides a mock function with given fields: ctx, userID
+func (_m *SecretRepository) GetSecret(ctx context.Context, userID uint64) (*models.TOTPSecret, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecret")
+	}
+
+	var r0 *models.TOTPSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.TOTPSecret, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.TOTPSecret); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TOTPSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSecret provides a mock function with given fields: ctx, secret
+func (_m *SecretRepository) SaveSecret(ctx context.Context, secret *models.TOTPSecret) error {
+	ret := _m.Called(ctx, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TOTPSecret) error); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSetupLink provides a mock function with given fields: ctx, secret
+func (_m *SecretRepository) SaveSetupLink(ctx context.Context, secret *models.TOTPSecret) error {
+	ret := _m.Called(ctx, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSetupLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TOTPSecret) error); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *SecretRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseSetupLink provides a mock function with given fields: ctx, tokenHash
+func (_m *SecretRepository) UseSetupLink(ctx context.Context, tokenHash string) (*models.TOTPSecret, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseSetupLink")
+	}
+
+	var r0 *models.TOTPSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.TOTPSecret, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.TOTPSecret); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TOTPSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseStep provides a mock function with given fields: ctx, userID, step
+func (_m *SecretRepository) UseStep(ctx context.Context, userID uint64, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSecretRepository creates a new instance of SecretRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSecretRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SecretRepository {
+	mock := &SecretRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
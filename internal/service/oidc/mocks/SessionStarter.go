@@ -0,0 +1,72 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "sdt-bicycle-rental/internal/models"
+
+	token_service "sdt-bicycle-rental/internal/service/token"
+)
+
+// SessionStarter is an autogenerated mock type for the SessionStarter type
+type SessionStarter struct {
+	mock.Mock
+}
+
+// SignIn provides a mock function with given fields: ctx, user, device
+func (_m *SessionStarter) SignIn(ctx context.Context, user *models.User, device token_service.Device) (*token_service.Pair, *auth_service.Challenge, error) {
+	ret := _m.Called(ctx, user, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignIn")
+	}
+
+	var r0 *token_service.Pair
+	var r1 *auth_service.Challenge
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, token_service.Device) (*token_service.Pair, *auth_service.Challenge, error)); ok {
+		return rf(ctx, user, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User, token_service.Device) *token_service.Pair); ok {
+		r0 = rf(ctx, user, device)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token_service.Pair)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User, token_service.Device) *auth_service.Challenge); ok {
+		r1 = rf(ctx, user, device)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*auth_service.Challenge)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *models.User, token_service.Device) error); ok {
+		r2 = rf(ctx, user, device)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewSessionStarter creates a new instance of SessionStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionStarter {
+	mock := &SessionStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,289 @@
+package oidc_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/internal/tracing"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/oidc"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	stateSize = 32
+
+	nameLength = 64
+)
+
+//go:generate mockery --name=Repository
+type Repository interface {
+	SaveState(ctx context.Context, state *models.OIDCState) error
+	TakeState(ctx context.Context, stateHash string) (*models.OIDCState, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*models.ExternalIdentity, error)
+	Link(ctx context.Context, identity *models.ExternalIdentity) error
+	CreateUser(ctx context.Context, user *models.User, identity *models.ExternalIdentity) error
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+}
+
+// Provider is an OpenID Connect provider as configured under oidc.providers
+//
+//go:generate mockery --name=Provider
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Identify(ctx context.Context, code, verifier, nonce string) (*oidc.Identity, error)
+}
+
+// SessionStarter signs in the user once the provider has identified them
+//
+//go:generate mockery --name=SessionStarter
+type SessionStarter interface {
+	SignIn(ctx context.Context, user *models.User, device token_service.Device) (*token_service.Pair, *auth_service.Challenge, error)
+}
+
+type OIDCService struct {
+	repo      Repository
+	users     UserRepository
+	providers map[string]Provider
+	sessions  SessionStarter
+	log       *slog.Logger
+	stateTTL  time.Duration
+}
+
+func New(repo Repository, users UserRepository, providers map[string]Provider, sessions SessionStarter, log *slog.Logger, cfg config.OIDC) *OIDCService {
+	return &OIDCService{
+		repo:      repo,
+		users:     users,
+		providers: providers,
+		sessions:  sessions,
+		log:       log,
+		stateTTL:  cfg.StateTTL,
+	}
+}
+
+// Start begins a login at the provider. The returned state has to come back with the callback
+// from the same browser, the handler keeps it in a cookie.
+func (s *OIDCService) Start(ctx context.Context, providerName string) (authURL, state string, err error) {
+	const op = "services.OIDCService.Start"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", service.ErrUnknownProvider
+	}
+
+	state, err = secure.RandomToken(stateSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate state", sl.Err(err))
+		return "", "", service.ErrInternalError
+	}
+	nonce, err := secure.RandomToken(stateSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate nonce", sl.Err(err))
+		return "", "", service.ErrInternalError
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate code verifier", sl.Err(err))
+		return "", "", service.ErrInternalError
+	}
+
+	authURL, err = provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "provider discovery failed", slog.String("provider", providerName), sl.Err(err))
+		return "", "", service.ErrProviderUnavailable
+	}
+
+	err = s.repo.SaveState(ctx, &models.OIDCState{
+		StateHash:    secure.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    util.Ptr(time.Now().Add(s.stateTTL)),
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save state", sl.Err(err))
+		return "", "", service.ErrInternalError
+	}
+
+	return authURL, state, nil
+}
+
+// Callback finishes the login with the code the provider redirected back with.
+// A known identity signs in its user. Otherwise the identity is linked to the user with
+// the same email when both the provider and we have verified it, or a new user is created.
+func (s *OIDCService) Callback(ctx context.Context, providerName, code, state string, device token_service.Device) (*models.User, *token_service.Pair, *auth_service.Challenge, error) {
+	const op = "services.OIDCService.Callback"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, nil, nil, service.ErrUnknownProvider
+	}
+
+	saved, err := s.repo.TakeState(ctx, secure.HashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "unknown state", slog.String("provider", providerName))
+			return nil, nil, nil, service.ErrInvalidOIDCState
+		}
+		s.log.ErrorContext(ctx, op, "failed to take state", sl.Err(err))
+		return nil, nil, nil, service.ErrInternalError
+	}
+	if saved.Provider != providerName || saved.ExpiresAt == nil || time.Now().After(*saved.ExpiresAt) {
+		s.log.InfoContext(ctx, op, "state expired or of another provider", slog.String("provider", providerName))
+		return nil, nil, nil, service.ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Identify(ctx, code, saved.CodeVerifier, saved.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrRejected) {
+			s.log.InfoContext(ctx, op, "provider rejected the login", slog.String("provider", providerName), sl.Err(err))
+			return nil, nil, nil, service.ErrExternalLoginRejected
+		}
+		s.log.ErrorContext(ctx, op, "provider unavailable", slog.String("provider", providerName), sl.Err(err))
+		return nil, nil, nil, service.ErrProviderUnavailable
+	}
+
+	user, err := s.resolve(ctx, providerName, identity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tokens, challenge, err := s.sessions.SignIn(ctx, user, device)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, tokens, challenge, nil
+}
+
+// resolve finds or creates the user of the identity
+func (s *OIDCService) resolve(ctx context.Context, providerName string, identity *oidc.Identity) (*models.User, error) {
+	const op = "services.OIDCService.resolve"
+
+	linked, err := s.repo.GetIdentity(ctx, providerName, identity.Subject)
+	if err == nil {
+		return linked.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log.ErrorContext(ctx, op, "failed to get identity", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	// An unverified email could belong to anyone, it neither links nor creates an account
+	if identity.Email == "" || !identity.EmailVerified {
+		s.log.InfoContext(ctx, op, "email not verified by the provider", slog.String("provider", providerName))
+		return nil, service.ErrExternalEmailNotVerified
+	}
+	email := models.NormalizeEmail(identity.Email)
+
+	external := &models.ExternalIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    util.Ptr(email),
+	}
+
+	user, err := s.users.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// Whoever registered the email before us must have proven they own it,
+		// otherwise they could take over the account of the real owner once they sign in.
+		// Changing the email clears the verification, so it is always about the current email.
+		if user.EmailVerifiedAt == nil {
+			s.log.InfoContext(ctx, op, "account email not verified", slog.Uint64("user_id", user.ID))
+			return nil, service.ErrAccountNotLinked
+		}
+		external.UserID = user.ID
+		if err := s.repo.Link(ctx, external); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				// linked by a concurrent callback
+				return s.linked(ctx, providerName, identity.Subject)
+			}
+			s.log.ErrorContext(ctx, op, "failed to link identity", sl.Err(err))
+			return nil, service.ErrInternalError
+		}
+		s.log.InfoContext(ctx, op, "identity linked", slog.Uint64("user_id", user.ID), slog.String("provider", providerName))
+		return user, nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	// The phone is still to be added and verified, so the account starts pending
+	now := time.Now()
+	user = &models.User{
+		Name:            util.Ptr(truncate(firstName(identity, email), nameLength)),
+		Lastname:        util.Ptr(truncate(identity.FamilyName, nameLength)),
+		Email:           util.Ptr(email),
+		Status:          util.Ptr(models.UserStatusPending),
+		EmailVerifiedAt: &now,
+	}
+	if err := s.repo.CreateUser(ctx, user, external); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return s.linked(ctx, providerName, identity.Subject)
+		}
+		s.log.ErrorContext(ctx, op, "failed to create user", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	metrics.Registrations.Inc()
+	s.log.InfoContext(ctx, op, "user created", slog.Uint64("user_id", user.ID), slog.String("provider", providerName))
+
+	return user, nil
+}
+
+// linked gets the user of an identity created concurrently. If the email was taken
+// by a registration instead, it is not verified yet and can't be linked.
+func (s *OIDCService) linked(ctx context.Context, providerName, subject string) (*models.User, error) {
+	const op = "services.OIDCService.linked"
+
+	identity, err := s.repo.GetIdentity(ctx, providerName, subject)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrAccountNotLinked
+		}
+		s.log.ErrorContext(ctx, op, "failed to get identity", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	return identity.User, nil
+}
+
+func firstName(identity *oidc.Identity, email string) string {
+	switch {
+	case identity.GivenName != "":
+		return identity.GivenName
+	case identity.Name != "":
+		return identity.Name
+	default:
+		local, _, _ := strings.Cut(email, "@")
+		return local
+	}
+}
+
+// truncate cuts the value to max runes, the claims of the provider are not limited in length
+func truncate(value string, max int) string {
+	if r := []rune(value); len(r) > max {
+		return string(r[:max])
+	}
+	return value
+}
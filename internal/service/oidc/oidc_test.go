@@ -0,0 +1,281 @@
+package oidc_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	oidc_service "sdt-bicycle-rental/internal/service/oidc"
+	mocks "sdt-bicycle-rental/internal/service/oidc/mocks"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/oidc"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var oidcConfig = config.OIDC{StateTTL: 10 * time.Minute}
+
+var device = token_service.Device{UserAgent: "Mozilla/5.0", IP: "192.0.2.1"}
+
+func savedState(provider string, expiresIn time.Duration) *models.OIDCState {
+	return &models.OIDCState{
+		StateHash:    secure.HashToken("state"),
+		Provider:     provider,
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    util.Ptr(time.Now().Add(expiresIn)),
+	}
+}
+
+func TestOIDCService_Start(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		urlErr   error
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			provider: "google",
+		},
+		{
+			name:     "unknown provider",
+			provider: "myspace",
+			wantErr:  service.ErrUnknownProvider,
+		},
+		{
+			name:     "provider unavailable",
+			provider: "google",
+			urlErr:   oidc.ErrUnavailable,
+			wantErr:  service.ErrProviderUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			provider := mocks.NewProvider(t)
+			s := oidc_service.New(repo, mocks.NewUserRepository(t), map[string]oidc_service.Provider{"google": provider},
+				mocks.NewSessionStarter(t), slogdiscard.NewDiscardLogger(), oidcConfig)
+
+			var sentState string
+			if tt.provider == "google" {
+				provider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) { sentState = args.String(1) }).
+					Return("https://accounts.example.com/auth", tt.urlErr).Once()
+			}
+			if tt.wantErr == nil {
+				repo.On("SaveState", mock.Anything, mock.MatchedBy(func(s *models.OIDCState) bool {
+					// the state is stored hashed, the nonce and the verifier as they are
+					return s.StateHash == secure.HashToken(sentState) && s.Provider == "google" &&
+						s.Nonce != "" && s.CodeVerifier != "" && time.Until(*s.ExpiresAt) > 9*time.Minute
+				})).Return(nil).Once()
+			}
+
+			authURL, state, err := s.Start(context.Background(), tt.provider)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OIDCService.Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if authURL != "https://accounts.example.com/auth" || state != sentState {
+				t.Errorf("OIDCService.Start() = %q, %q, want the url and the state sent %q", authURL, state, sentState)
+			}
+		})
+	}
+}
+
+func TestOIDCService_Callback(t *testing.T) {
+	pair := &token_service.Pair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute}
+	verified := &models.User{ID: 1, Email: util.Ptr("john@example.com"), Status: util.Ptr(models.UserStatusActive), EmailVerifiedAt: util.Ptr(time.Now())}
+	unverified := &models.User{ID: 2, Email: util.Ptr("john@example.com"), Status: util.Ptr(models.UserStatusPending)}
+	identity := func(verifiedEmail bool) *oidc.Identity {
+		return &oidc.Identity{Subject: "sub-1", Email: "John@Example.com", EmailVerified: verifiedEmail, GivenName: "John", FamilyName: "Doe"}
+	}
+
+	tests := []struct {
+		name        string
+		state       *models.OIDCState
+		identity    *oidc.Identity
+		identifyErr error
+		linked      *models.User
+		existing    *models.User
+		duplicate   bool
+		challenge   bool
+		wantUser    func(t *testing.T, u *models.User)
+		wantErr     error
+	}{
+		{
+			name:     "linked identity signs in",
+			state:    savedState("google", time.Minute),
+			identity: identity(false),
+			linked:   verified,
+		},
+		{
+			name:     "links to verified account",
+			state:    savedState("google", time.Minute),
+			identity: identity(true),
+			existing: verified,
+		},
+		{
+			name:     "creates account",
+			state:    savedState("google", time.Minute),
+			identity: identity(true),
+			wantUser: func(t *testing.T, u *models.User) {
+				if *u.Email != "john@example.com" || *u.Name != "John" || *u.Lastname != "Doe" ||
+					*u.Status != models.UserStatusPending || u.EmailVerifiedAt == nil || u.Password != nil {
+					t.Errorf("created user = %+v", u)
+				}
+			},
+		},
+		{
+			name:  "creates account with long names",
+			state: savedState("google", time.Minute),
+			identity: &oidc.Identity{Subject: "sub-1", Email: "john@example.com", EmailVerified: true,
+				GivenName: strings.Repeat("й", 100), FamilyName: strings.Repeat("d", 100)},
+			wantUser: func(t *testing.T, u *models.User) {
+				if *u.Name != strings.Repeat("й", 64) || *u.Lastname != strings.Repeat("d", 64) {
+					t.Errorf("created user = %+v", u)
+				}
+			},
+		},
+		{
+			name:      "mfa challenge",
+			state:     savedState("google", time.Minute),
+			identity:  identity(true),
+			linked:    verified,
+			challenge: true,
+		},
+		{
+			name:      "concurrent link",
+			state:     savedState("google", time.Minute),
+			identity:  identity(true),
+			existing:  verified,
+			duplicate: true,
+		},
+		{
+			name:     "unverified account is not linked",
+			state:    savedState("google", time.Minute),
+			identity: identity(true),
+			existing: unverified,
+			wantErr:  service.ErrAccountNotLinked,
+		},
+		{
+			name:     "email not verified by provider",
+			state:    savedState("google", time.Minute),
+			identity: identity(false),
+			wantErr:  service.ErrExternalEmailNotVerified,
+		},
+		{
+			name:    "unknown state",
+			wantErr: service.ErrInvalidOIDCState,
+		},
+		{
+			name:    "expired state",
+			state:   savedState("google", -time.Second),
+			wantErr: service.ErrInvalidOIDCState,
+		},
+		{
+			name:    "state of another provider",
+			state:   savedState("apple", time.Minute),
+			wantErr: service.ErrInvalidOIDCState,
+		},
+		{
+			name:        "rejected by provider",
+			state:       savedState("google", time.Minute),
+			identifyErr: oidc.ErrRejected,
+			wantErr:     service.ErrExternalLoginRejected,
+		},
+		{
+			name:        "provider unavailable",
+			state:       savedState("google", time.Minute),
+			identifyErr: oidc.ErrUnavailable,
+			wantErr:     service.ErrProviderUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			users := mocks.NewUserRepository(t)
+			provider := mocks.NewProvider(t)
+			sessions := mocks.NewSessionStarter(t)
+			s := oidc_service.New(repo, users, map[string]oidc_service.Provider{"google": provider},
+				sessions, slogdiscard.NewDiscardLogger(), oidcConfig)
+
+			if tt.state == nil {
+				repo.On("TakeState", mock.Anything, secure.HashToken("state")).Return(nil, gorm.ErrRecordNotFound).Once()
+			} else {
+				repo.On("TakeState", mock.Anything, secure.HashToken("state")).Return(tt.state, nil).Once()
+			}
+			if tt.identity != nil || tt.identifyErr != nil {
+				provider.On("Identify", mock.Anything, "code", "verifier", "nonce").Return(tt.identity, tt.identifyErr).Once()
+			}
+
+			var signedIn *models.User
+			if tt.identity != nil {
+				switch {
+				case tt.linked != nil:
+					repo.On("GetIdentity", mock.Anything, "google", "sub-1").Return(&models.ExternalIdentity{UserID: tt.linked.ID, User: tt.linked}, nil).Once()
+					signedIn = tt.linked
+				case !tt.identity.EmailVerified:
+					repo.On("GetIdentity", mock.Anything, "google", "sub-1").Return(nil, gorm.ErrRecordNotFound).Once()
+				case tt.existing != nil:
+					repo.On("GetIdentity", mock.Anything, "google", "sub-1").Return(nil, gorm.ErrRecordNotFound).Once()
+					users.On("GetByEmail", mock.Anything, "john@example.com").Return(tt.existing, nil).Once()
+					if tt.existing.EmailVerifiedAt != nil {
+						var linkErr error
+						if tt.duplicate {
+							linkErr = gorm.ErrDuplicatedKey
+							repo.On("GetIdentity", mock.Anything, "google", "sub-1").Return(&models.ExternalIdentity{UserID: tt.existing.ID, User: tt.existing}, nil).Once()
+						}
+						repo.On("Link", mock.Anything, mock.MatchedBy(func(i *models.ExternalIdentity) bool {
+							return i.UserID == tt.existing.ID && i.Provider == "google" && i.Subject == "sub-1"
+						})).Return(linkErr).Once()
+						signedIn = tt.existing
+					}
+				default:
+					repo.On("GetIdentity", mock.Anything, "google", "sub-1").Return(nil, gorm.ErrRecordNotFound).Once()
+					users.On("GetByEmail", mock.Anything, "john@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+					repo.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).
+						Run(func(args mock.Arguments) {
+							signedIn = args.Get(1).(*models.User)
+							signedIn.ID = 3
+						}).Return(nil).Once()
+				}
+			}
+			if tt.wantErr == nil {
+				if tt.challenge {
+					sessions.On("SignIn", mock.Anything, mock.Anything, device).Return(nil, &auth_service.Challenge{Token: "challenge"}, nil).Once()
+				} else {
+					sessions.On("SignIn", mock.Anything, mock.Anything, device).Return(pair, nil, nil).Once()
+				}
+			}
+
+			user, tokens, challenge, err := s.Callback(context.Background(), "google", "code", "state", device)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OIDCService.Callback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if user != signedIn {
+				t.Errorf("OIDCService.Callback() user = %+v, want %+v", user, signedIn)
+			}
+			if tt.wantUser != nil {
+				tt.wantUser(t, user)
+			}
+			if tt.challenge != (challenge != nil) || tt.challenge == (tokens == pair) {
+				t.Errorf("OIDCService.Callback() tokens = %v, challenge = %v", tokens, challenge)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package password_service
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/passhash"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed common.txt
+var commonPasswords string
+
+// Hasher checks new passwords against the policy and hashes them with the configured algorithm.
+// Stored hashes are verified whatever algorithm they were made with.
+type Hasher struct {
+	hasher    *passhash.Hasher
+	minLength int
+	maxLength int
+	common    map[string]struct{}
+}
+
+func NewHasher(cfg config.Password) (*Hasher, error) {
+	hasher, err := passhash.New(passhash.Params{
+		Algorithm:   cfg.Algorithm,
+		Memory:      cfg.Memory,
+		Iterations:  cfg.Iterations,
+		Parallelism: cfg.Parallelism,
+		Cost:        cfg.Cost,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MinLength < 1 || cfg.MaxLength < cfg.MinLength {
+		return nil, fmt.Errorf("invalid password length limits %d..%d", cfg.MinLength, cfg.MaxLength)
+	}
+
+	common := make(map[string]struct{})
+	addCommon(common, commonPasswords)
+	if cfg.CommonFile != "" {
+		list, err := os.ReadFile(cfg.CommonFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read common passwords: %w", err)
+		}
+		addCommon(common, string(list))
+	}
+
+	return &Hasher{hasher: hasher, minLength: cfg.MinLength, maxLength: cfg.MaxLength, common: common}, nil
+}
+
+// Check tells why a new password is refused, as ErrWeakPassword. Only the length counts,
+// not the kinds of characters. Personal are the email, name etc. of the user, which the password must not be.
+func (h *Hasher) Check(password string, personal ...string) error {
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length < h.minLength:
+		return service.ErrWeakPassword.WithDetail(fmt.Sprintf("password must be at least %d characters long", h.minLength))
+	case length > h.maxLength:
+		return service.ErrWeakPassword.WithDetail(fmt.Sprintf("password must be at most %d characters long", h.maxLength))
+	case h.hasher.MaxBytes() > 0 && len(password) > h.hasher.MaxBytes():
+		return service.ErrWeakPassword.WithDetail(fmt.Sprintf("password must be at most %d bytes long", h.hasher.MaxBytes()))
+	}
+
+	normalized := strings.ToLower(password)
+	if _, found := h.common[normalized]; found {
+		return service.ErrWeakPassword.WithDetail("password is too common")
+	}
+	for _, p := range personal {
+		p = strings.ToLower(p)
+		local, _, _ := strings.Cut(p, "@")
+		if p != "" && (normalized == p || normalized == local) {
+			return service.ErrWeakPassword.WithDetail("password must not be your email, name or phone")
+		}
+	}
+
+	return nil
+}
+
+// Hash hashes a password that passed Check, or a known one to replace an outdated hash
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.hasher.Hash(password)
+}
+
+// Verify checks the password against a stored hash, rehash tells that the hash is outdated
+// and should be replaced with Hash of the password. An error means the stored hash is unreadable.
+func (h *Hasher) Verify(encoded, password string) (ok, rehash bool, err error) {
+	ok, err = passhash.Verify(encoded, password)
+	if err != nil || !ok {
+		return false, false, err
+	}
+	return true, h.hasher.NeedsRehash(encoded), nil
+}
+
+// addCommon adds the lines of list to set, skipping blank lines and # comments
+func addCommon(set map[string]struct{}, list string) {
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+}
@@ -0,0 +1,101 @@
+package password_service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/service"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	"sdt-bicycle-rental/lib/passhash"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHasher_Check(t *testing.T) {
+	bcryptConfig := passwordConfig
+	bcryptConfig.Algorithm, bcryptConfig.Cost = passhash.Bcrypt, bcrypt.MinCost
+
+	list := filepath.Join(t.TempDir(), "common.txt")
+	if err := os.WriteFile(list, []byte("# leaked\nrental-2025\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	listConfig := passwordConfig
+	listConfig.CommonFile = list
+
+	tests := []struct {
+		name     string
+		cfg      config.Password
+		password string
+		personal []string
+		wantErr  error
+	}{
+		{name: "long enough", cfg: passwordConfig, password: "correct horse battery staple"},
+		{name: "no character classes needed", cfg: passwordConfig, password: "aaaabbbbccccdddd"},
+		{name: "short", cfg: passwordConfig, password: "abc123", wantErr: service.ErrWeakPassword},
+		{name: "length in characters", cfg: passwordConfig, password: "пароль!!"},
+		{name: "too long", cfg: passwordConfig, password: strings.Repeat("a", 129), wantErr: service.ErrWeakPassword},
+		{name: "too long for bcrypt", cfg: bcryptConfig, password: strings.Repeat("ї", 40), wantErr: service.ErrWeakPassword},
+		{name: "common", cfg: passwordConfig, password: "Password123", wantErr: service.ErrWeakPassword},
+		{name: "common from file", cfg: listConfig, password: "Rental-2025", wantErr: service.ErrWeakPassword},
+		{name: "the email", cfg: passwordConfig, password: "john.doe@example.com", personal: []string{"john.doe@example.com"}, wantErr: service.ErrWeakPassword},
+		{name: "the email name", cfg: passwordConfig, password: "John.Doe", personal: []string{"john.doe@example.com"}, wantErr: service.ErrWeakPassword},
+		{name: "contains the name", cfg: passwordConfig, password: "john.doe rides bikes", personal: []string{"john.doe@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := password_service.NewHasher(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHasher() error = %v", err)
+			}
+
+			if err := h.Check(tt.password, tt.personal...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Hasher.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasher_Verify(t *testing.T) {
+	h := newHasher(t)
+
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash, err := h.Verify(hash, "correct horse"); !ok || rehash || err != nil {
+		t.Errorf("Hasher.Verify() = %v, %v, %v, want true, false, nil", ok, rehash, err)
+	}
+	if ok, _, _ := h.Verify(hash, "wrong horse"); ok {
+		t.Errorf("Hasher.Verify() accepted a wrong password")
+	}
+
+	// bcrypt hashes from before argon2id still work and are due for a rehash
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash, err := h.Verify(string(legacy), "correct horse"); !ok || !rehash || err != nil {
+		t.Errorf("Hasher.Verify(bcrypt) = %v, %v, %v, want true, true, nil", ok, rehash, err)
+	}
+
+	if _, _, err := h.Verify("not a hash", "correct horse"); err == nil {
+		t.Errorf("Hasher.Verify() of an unreadable hash should fail")
+	}
+}
+
+func TestNewHasher(t *testing.T) {
+	invalid := []config.Password{
+		{Algorithm: "md5", MinLength: 8, MaxLength: 128},
+		{Algorithm: passhash.Argon2id, MinLength: 8, MaxLength: 128},
+		{Algorithm: passhash.Bcrypt, Cost: 10, MinLength: 16, MaxLength: 8},
+		{Algorithm: passhash.Bcrypt, Cost: 10, MinLength: 8, MaxLength: 128, CommonFile: filepath.Join(t.TempDir(), "missing.txt")},
+	}
+	for _, cfg := range invalid {
+		if _, err := password_service.NewHasher(cfg); err == nil {
+			t.Errorf("NewHasher(%+v) should fail", cfg)
+		}
+	}
+}
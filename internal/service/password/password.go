@@ -0,0 +1,291 @@
+package password_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const resetTokenSize = 32
+
+//go:generate mockery --name=ResetRepository
+type ResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetValid(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	Reset(ctx context.Context, tokenHash, passwordHash string) (userID uint64, err error)
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	ChangePassword(ctx context.Context, id uint64, passwordHash, keepSession string) error
+}
+
+// Notifier delivers the reset link to the user
+//
+//go:generate mockery --name=Notifier
+type Notifier interface {
+	Send(ctx context.Context, msg notify.Message) error
+}
+
+// Limiter throttles guesses of the current password like failed logins
+//
+//go:generate mockery --name=Limiter
+type Limiter interface {
+	Check(ctx context.Context, email, ip string) error
+	Fail(ctx context.Context, email, ip string)
+	Succeed(ctx context.Context, email string)
+}
+
+type PasswordService struct {
+	repo     ResetRepository
+	users    UserRepository
+	notifier Notifier
+	hasher   *Hasher
+	limiter  Limiter
+	log      *slog.Logger
+	resetURL string
+	resetTTL time.Duration
+	// sending counts the reset links still being sent after Forgot returned
+	sending sync.WaitGroup
+}
+
+func New(repo ResetRepository, users UserRepository, notifier Notifier, hasher *Hasher, limiter Limiter, log *slog.Logger, cfg config.Auth) *PasswordService {
+	return &PasswordService{
+		repo:     repo,
+		users:    users,
+		notifier: notifier,
+		hasher:   hasher,
+		limiter:  limiter,
+		log:      log,
+		resetURL: cfg.PasswordResetURL,
+		resetTTL: cfg.PasswordResetTTL,
+	}
+}
+
+type forgotRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=255"`
+}
+
+type changeRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required,min=8,max=255"`
+}
+
+// Forgot emails a reset link to the user with the email. Unknown and inactive accounts
+// are answered the same way as active ones, so the caller can't tell whether the email is registered:
+// the link is sent after Forgot returned, so that the time of the answer doesn't tell either.
+func (s *PasswordService) Forgot(ctx context.Context, email string) error {
+	const op = "services.PasswordService.Forgot"
+
+	email = models.NormalizeEmail(email)
+	if err := service.Validate.Struct(forgotRequest{Email: email}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "reset requested for unknown email", slog.String("email", email))
+			return nil
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return service.ErrInternalError
+	}
+	if !user.CanSignIn() {
+		s.log.InfoContext(ctx, op, "reset requested for inactive user", slog.Uint64("user_id", user.ID))
+		return nil
+	}
+
+	// Outlives the request, a client that hangs up must not cancel the delivery
+	s.sending.Add(1)
+	go func() {
+		defer s.sending.Done()
+		s.sendResetLink(context.WithoutCancel(ctx), user.ID, email)
+	}()
+
+	return nil
+}
+
+// Wait blocks until the reset links requested so far are sent or have failed
+func (s *PasswordService) Wait() {
+	s.sending.Wait()
+}
+
+// sendResetLink stores a new reset token of the user and emails the link with it.
+// Failures are only logged, they would only happen for registered emails.
+func (s *PasswordService) sendResetLink(ctx context.Context, userID uint64, email string) {
+	const op = "services.PasswordService.sendResetLink"
+
+	raw, err := secure.RandomToken(resetTokenSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate reset token", sl.Err(err))
+		return
+	}
+
+	token := &models.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: secure.HashToken(raw),
+		ExpiresAt: util.Ptr(time.Now().Add(s.resetTTL)),
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save reset token", slog.Uint64("user_id", userID), sl.Err(err))
+		return
+	}
+
+	if err := s.notifier.Send(ctx, s.resetMessage(email, raw)); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to send reset link", slog.Uint64("user_id", userID), sl.Err(err))
+		return
+	}
+
+	s.log.InfoContext(ctx, op, "reset link sent", slog.Uint64("user_id", userID))
+}
+
+// Reset sets a new password with a token from the reset link. The token works once,
+// and every session of the user is revoked, so a stolen refresh token stops working too.
+func (s *PasswordService) Reset(ctx context.Context, token, password string) error {
+	const op = "services.PasswordService.Reset"
+
+	if err := service.Validate.Struct(resetRequest{Token: token, Password: password}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	tokenHash := secure.HashToken(token)
+	resetToken, err := s.repo.GetValid(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "reset token is invalid", slog.String("reason", "unknown, used or expired"))
+			return service.ErrInvalidResetToken
+		}
+		s.log.ErrorContext(ctx, op, "failed to get reset token", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if err := s.hasher.Check(password, personal(resetToken.User)...); err != nil {
+		s.log.InfoContext(ctx, op, "password refused", sl.Err(err))
+		return err
+	}
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to hash password", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	// The token is consumed only here, a concurrent reset may have used it meanwhile
+	userID, err := s.repo.Reset(ctx, tokenHash, hashedPassword)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "reset token is invalid", slog.String("reason", "unknown, used or expired"))
+			return service.ErrInvalidResetToken
+		}
+		s.log.ErrorContext(ctx, op, "failed to reset password", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "password reset", slog.Uint64("user_id", userID))
+	return nil
+}
+
+// Change sets a new password of a signed in user, who has to know the current one. Wrong guesses
+// count towards the login lockout of the account. Every other session of the user is revoked,
+// the session the change is made from stays signed in.
+func (s *PasswordService) Change(ctx context.Context, userID uint64, sessionID, currentPassword, password, ip string) error {
+	const op = "services.PasswordService.Change"
+
+	err := service.Validate.Struct(changeRequest{CurrentPassword: currentPassword, Password: password})
+	if err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("user_id", userID))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	email := util.Deref(user.Email)
+	if err := s.limiter.Check(ctx, email, ip); err != nil {
+		return err
+	}
+
+	// Users who only sign in with an identity provider have no password to change, they can reset one
+	ok := false
+	if user.Password != nil {
+		ok, _, err = s.hasher.Verify(*user.Password, currentPassword)
+		if err != nil {
+			s.log.ErrorContext(ctx, op, "unreadable password hash", slog.Uint64("user_id", userID), sl.Err(err))
+		}
+	}
+	if !ok {
+		s.log.InfoContext(ctx, op, "wrong current password", slog.Uint64("user_id", userID))
+		s.limiter.Fail(ctx, email, ip)
+		return service.ErrWrongPassword
+	}
+	s.limiter.Succeed(ctx, email)
+
+	if password == currentPassword {
+		return service.ErrWeakPassword.WithDetail("new password must differ from the current one")
+	}
+	if err := s.hasher.Check(password, personal(user)...); err != nil {
+		s.log.InfoContext(ctx, op, "password refused", sl.Err(err))
+		return err
+	}
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to hash password", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if err := s.users.ChangePassword(ctx, userID, hashedPassword, sessionID); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to change password", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "password changed", slog.Uint64("user_id", userID))
+	return nil
+}
+
+func (s *PasswordService) resetMessage(email, token string) notify.Message {
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+
+	return notify.Message{
+		Channel: notify.Email,
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account.\n\n"+
+			"Follow the link within %s to choose a new one:\n%s\n\n"+
+			"If it wasn't you, ignore this message, your password stays the same.", s.resetTTL, link),
+	}
+}
+
+// personal are the fields of the user a new password must not be
+func personal(user *models.User) []string {
+	return []string{util.Deref(user.Email), util.Deref(user.Name), util.Deref(user.Lastname), util.Deref(user.Phone)}
+}
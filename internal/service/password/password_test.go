@@ -0,0 +1,395 @@
+package password_service_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	mocks "sdt-bicycle-rental/internal/service/password/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/passhash"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const validEmail = "valid@email.com"
+
+var authConfig = config.Auth{
+	PasswordResetURL: "https://app.example.com/reset-password",
+	PasswordResetTTL: 30 * time.Minute,
+}
+
+// cheap argon2id, the strength of the hashes is not under test
+var passwordConfig = config.Password{Algorithm: passhash.Argon2id, Memory: 64, Iterations: 1, Parallelism: 1, MinLength: 8, MaxLength: 128}
+
+func newHasher(t *testing.T) *password_service.Hasher {
+	h, err := password_service.NewHasher(passwordConfig)
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	return h
+}
+
+func verifies(password string) func(hash string) bool {
+	return func(hash string) bool {
+		ok, err := passhash.Verify(hash, password)
+		return err == nil && ok
+	}
+}
+
+func TestPasswordService_Forgot(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		user    *models.User
+		getErr  error
+		sendErr error
+		sent    bool
+		wantErr error
+	}{
+		{
+			name:  "success",
+			email: validEmail,
+			user:  &models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(models.UserStatusActive)},
+			sent:  true,
+		},
+		{
+			name:    "invalid email",
+			email:   "invalid-email",
+			wantErr: service.ErrValidation,
+		},
+		{
+			name:   "unknown email looks like success",
+			email:  validEmail,
+			getErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:  "banned user looks like success",
+			email: validEmail,
+			user:  &models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(models.UserStatusBanned)},
+		},
+		{
+			name:    "failed delivery looks like success",
+			email:   validEmail,
+			user:    &models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(models.UserStatusActive)},
+			sendErr: errors.New("smtp unavailable"),
+			sent:    true,
+		},
+		{
+			name:    "database error",
+			email:   validEmail,
+			getErr:  errors.New("connection refused"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewResetRepository(t)
+			users := mocks.NewUserRepository(t)
+			notifier := mocks.NewNotifier(t)
+			s := password_service.New(repo, users, notifier, newHasher(t), mocks.NewLimiter(t), slogdiscard.NewDiscardLogger(), authConfig)
+
+			if tt.wantErr != service.ErrValidation {
+				users.On("GetByEmail", mock.Anything, tt.email).Return(tt.user, tt.getErr).Once()
+			}
+
+			var saved *models.PasswordResetToken
+			var msg notify.Message
+			if tt.sent {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(token *models.PasswordResetToken) bool {
+					saved = token
+					window := time.Until(*token.ExpiresAt)
+					return token.UserID == 1 && window > 29*time.Minute && window <= 30*time.Minute
+				})).Return(nil).Once()
+				notifier.On("Send", mock.Anything, mock.MatchedBy(func(m notify.Message) bool {
+					msg = m
+					return m.To == validEmail
+				})).Return(tt.sendErr).Once()
+			}
+
+			err := s.Forgot(context.Background(), tt.email)
+			s.Wait()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PasswordService.Forgot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.sent {
+				return
+			}
+
+			// the link carries the raw token, only its hash is stored
+			_, link, found := strings.Cut(msg.Body, authConfig.PasswordResetURL+"?token=")
+			if !found {
+				t.Fatalf("PasswordService.Forgot() message has no reset link: %q", msg.Body)
+			}
+			raw, err := url.QueryUnescape(strings.Fields(link)[0])
+			if err != nil {
+				t.Fatalf("reset link token: %v", err)
+			}
+			if saved.TokenHash != secure.HashToken(raw) {
+				t.Errorf("PasswordService.Forgot() stored hash doesn't match the emailed token")
+			}
+		})
+	}
+}
+
+func TestPasswordService_Forgot_AnswersBeforeSending(t *testing.T) {
+	repo := mocks.NewResetRepository(t)
+	users := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+	s := password_service.New(repo, users, notifier, newHasher(t), mocks.NewLimiter(t), slogdiscard.NewDiscardLogger(), authConfig)
+
+	users.On("GetByEmail", mock.Anything, validEmail).Return(&models.User{ID: 1, Email: util.Ptr(validEmail), Status: util.Ptr(models.UserStatusActive)}, nil).Once()
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	delivered := make(chan time.Time)
+	notifier.On("Send", mock.Anything, mock.Anything).WaitUntil(delivered).Return(nil).Once()
+
+	// a cancelled request doesn't cancel the delivery either
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.Forgot(ctx, validEmail); err != nil {
+		t.Fatalf("PasswordService.Forgot() error = %v", err)
+	}
+	cancel()
+
+	close(delivered)
+	s.Wait()
+	notifier.AssertCalled(t, "Send", mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), mock.Anything)
+}
+
+func TestPasswordService_Reset(t *testing.T) {
+	resetToken := func() *models.PasswordResetToken {
+		return &models.PasswordResetToken{
+			UserID: 1,
+			User:   &models.User{ID: 1, Email: util.Ptr("john.doe@email.com"), Name: util.Ptr("Johnathan"), Phone: util.Ptr("380501234567")},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		getErr   error
+		reset    bool
+		resetErr error
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			token:    "token",
+			password: "new-password",
+			reset:    true,
+		},
+		{
+			name:     "short password",
+			token:    "token",
+			password: "short",
+			wantErr:  service.ErrValidation,
+		},
+		{
+			name:     "common password",
+			token:    "token",
+			password: "password123",
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:     "password is the email",
+			token:    "token",
+			password: "John.Doe@email.com",
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:     "password is the phone",
+			token:    "token",
+			password: "380501234567",
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:     "missing token",
+			password: "new-password",
+			wantErr:  service.ErrValidation,
+		},
+		{
+			name:     "used or expired token",
+			token:    "token",
+			password: "new-password",
+			getErr:   gorm.ErrRecordNotFound,
+			wantErr:  service.ErrInvalidResetToken,
+		},
+		{
+			name:     "token used concurrently",
+			token:    "token",
+			password: "new-password",
+			reset:    true,
+			resetErr: gorm.ErrRecordNotFound,
+			wantErr:  service.ErrInvalidResetToken,
+		},
+		{
+			name:     "database error",
+			token:    "token",
+			password: "new-password",
+			reset:    true,
+			resetErr: errors.New("connection refused"),
+			wantErr:  service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewResetRepository(t)
+			s := password_service.New(repo, mocks.NewUserRepository(t), mocks.NewNotifier(t), newHasher(t), mocks.NewLimiter(t), slogdiscard.NewDiscardLogger(), authConfig)
+
+			if tt.token != "" && tt.wantErr != service.ErrValidation {
+				token := resetToken()
+				if tt.getErr != nil {
+					token = nil
+				}
+				repo.On("GetValid", mock.Anything, secure.HashToken(tt.token)).Return(token, tt.getErr).Once()
+			}
+			if tt.reset {
+				repo.On("Reset", mock.Anything, secure.HashToken(tt.token), mock.MatchedBy(verifies(tt.password))).
+					Return(uint64(1), tt.resetErr).Once()
+			}
+
+			if err := s.Reset(context.Background(), tt.token, tt.password); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PasswordService.Reset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordService_Change(t *testing.T) {
+	hasher := newHasher(t)
+	current, err := hasher.Hash("old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := func(password *string) *models.User {
+		return &models.User{ID: 1, Email: util.Ptr(validEmail), Name: util.Ptr("John"), Password: password, Status: util.Ptr(models.UserStatusActive)}
+	}
+
+	tests := []struct {
+		name      string
+		current   string
+		password  string
+		user      *models.User
+		getErr    error
+		checkErr  error
+		changeErr error
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			current:  "old-password",
+			password: "new-password",
+			user:     user(&current),
+		},
+		{
+			name:     "missing current password",
+			password: "new-password",
+			wantErr:  service.ErrValidation,
+		},
+		{
+			name:     "short password",
+			current:  "old-password",
+			password: "short",
+			wantErr:  service.ErrValidation,
+		},
+		{
+			name:     "unknown user",
+			current:  "old-password",
+			password: "new-password",
+			getErr:   gorm.ErrRecordNotFound,
+			wantErr:  service.ErrUserNotFound,
+		},
+		{
+			name:     "locked out",
+			current:  "old-password",
+			password: "new-password",
+			user:     user(&current),
+			checkErr: service.ErrTooManyAttempts.WithRetryAfter(time.Minute),
+			wantErr:  service.ErrTooManyAttempts,
+		},
+		{
+			name:     "wrong current password",
+			current:  "guessed-password",
+			password: "new-password",
+			user:     user(&current),
+			wantErr:  service.ErrWrongPassword,
+		},
+		{
+			name:     "no password to change",
+			current:  "old-password",
+			password: "new-password",
+			user:     user(nil),
+			wantErr:  service.ErrWrongPassword,
+		},
+		{
+			name:     "same password",
+			current:  "old-password",
+			password: "old-password",
+			user:     user(&current),
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:     "common password",
+			current:  "old-password",
+			password: "qwerty123",
+			user:     user(&current),
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:     "password is the email",
+			current:  "old-password",
+			password: "VALID@email.com",
+			user:     user(&current),
+			wantErr:  service.ErrWeakPassword,
+		},
+		{
+			name:      "database error",
+			current:   "old-password",
+			password:  "new-password",
+			user:      user(&current),
+			changeErr: errors.New("connection refused"),
+			wantErr:   service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			limiter := mocks.NewLimiter(t)
+			s := password_service.New(mocks.NewResetRepository(t), users, mocks.NewNotifier(t), hasher, limiter, slogdiscard.NewDiscardLogger(), authConfig)
+
+			if tt.wantErr != service.ErrValidation {
+				users.On("GetByID", mock.Anything, uint64(1)).Return(tt.user, tt.getErr).Once()
+			}
+			if tt.user != nil {
+				limiter.On("Check", mock.Anything, validEmail, "192.0.2.1").Return(tt.checkErr).Once()
+			}
+			if tt.user != nil && tt.checkErr == nil {
+				if tt.wantErr == service.ErrWrongPassword {
+					limiter.On("Fail", mock.Anything, validEmail, "192.0.2.1").Once()
+				} else {
+					limiter.On("Succeed", mock.Anything, validEmail).Once()
+				}
+			}
+			if tt.wantErr == nil || tt.changeErr != nil {
+				// the session of the request stays signed in
+				users.On("ChangePassword", mock.Anything, uint64(1), mock.MatchedBy(verifies(tt.password)), "phone").
+					Return(tt.changeErr).Once()
+			}
+
+			err := s.Change(context.Background(), 1, "phone", tt.current, tt.password, "192.0.2.1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PasswordService.Change() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
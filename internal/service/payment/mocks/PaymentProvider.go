@@ -0,0 +1,110 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	payment "sdt-bicycle-rental/internal/payment"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentProvider is an autogenerated mock type for the PaymentProvider type
+type PaymentProvider struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: charge
+func (_m *PaymentProvider) Authorize(charge payment.Charge) (string, error) {
+	ret := _m.Called(charge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(payment.Charge) (string, error)); ok {
+		return rf(charge)
+	}
+	if rf, ok := ret.Get(0).(func(payment.Charge) string); ok {
+		r0 = rf(charge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(payment.Charge) error); ok {
+		r1 = rf(charge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Capture provides a mock function with given fields: transactionID, amount
+func (_m *PaymentProvider) Capture(transactionID string, amount int64) error {
+	ret := _m.Called(transactionID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Capture")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(transactionID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refund provides a mock function with given fields: transactionID, amount
+func (_m *PaymentProvider) Refund(transactionID string, amount int64) error {
+	ret := _m.Called(transactionID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(transactionID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Void provides a mock function with given fields: transactionID
+func (_m *PaymentProvider) Void(transactionID string) error {
+	ret := _m.Called(transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Void")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(transactionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPaymentProvider creates a new instance of PaymentProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentProvider {
+	mock := &PaymentProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,277 @@
+package payment_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/payment"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=PaymentRepository
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	UpdateStatus(ctx context.Context, payment *models.Payment, from string) error
+	GetByID(ctx context.Context, id uint64) (*models.Payment, error)
+	ListByUserID(ctx context.Context, userID uint64, offset, limit int) ([]models.Payment, error)
+	CountByUserID(ctx context.Context, userID uint64) (int64, error)
+}
+
+// PaymentProvider is a payment gateway. Amounts are in cents, an authorization
+// holds money until it is captured or voided.
+//
+//go:generate mockery --name=PaymentProvider
+type PaymentProvider interface {
+	Authorize(charge payment.Charge) (transactionID string, err error)
+	Capture(transactionID string, amount int64) error
+	Refund(transactionID string, amount int64) error
+	Void(transactionID string) error
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type PaymentService struct {
+	repo     PaymentRepository
+	provider PaymentProvider
+	log      *slog.Logger
+	cfg      config.Payment
+	currency string
+}
+
+func New(repo PaymentRepository, provider PaymentProvider, log *slog.Logger, cfg config.Payment, currency string) *PaymentService {
+	return &PaymentService{repo: repo, provider: provider, log: log, cfg: cfg, currency: currency}
+}
+
+// ChargeRental takes the cost of a completed ride. Free rides are not charged and return nil.
+func (s *PaymentService) ChargeRental(ctx context.Context, rental *models.Rental) (*models.Payment, error) {
+	const op = "services.PaymentService.ChargeRental"
+
+	if rental.TotalCost == nil || *rental.TotalCost == 0 {
+		return nil, nil
+	}
+
+	p := &models.Payment{
+		UserID:   rental.UserID,
+		RentalID: &rental.ID,
+		Method:   s.cfg.Method,
+		Amount:   *rental.TotalCost,
+		Currency: s.currency,
+		Status:   models.PaymentStatusPending,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to create payment", slog.Uint64("rental_id", rental.ID), sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	if err := s.authorize(ctx, op, p, fmt.Sprintf("rental:%d", rental.ID)); err != nil {
+		return p, err
+	}
+
+	if err := s.provider.Capture(p.TransactionID, p.Amount); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to capture payment", slog.Uint64("id", p.ID), sl.Err(err))
+		return p, service.ErrInternalError
+	}
+	metrics.Revenue.WithLabelValues(p.Currency).Add(float64(p.Amount))
+	if err := s.transition(ctx, p, models.PaymentStatusCaptured); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save payment status", slog.Uint64("id", p.ID), sl.Err(err))
+		return p, service.ErrInternalError
+	}
+
+	return p, nil
+}
+
+// AuthorizeBooking holds the booking fee on the rider's payment method.
+// It returns nil when bookings are free.
+func (s *PaymentService) AuthorizeBooking(ctx context.Context, booking *models.Booking) (*models.Payment, error) {
+	const op = "services.PaymentService.AuthorizeBooking"
+
+	if s.cfg.BookingFee == 0 {
+		return nil, nil
+	}
+
+	p := &models.Payment{
+		UserID:    booking.UserID,
+		BookingID: &booking.ID,
+		Method:    s.cfg.Method,
+		Amount:    s.cfg.BookingFee,
+		Currency:  s.currency,
+		Status:    models.PaymentStatusPending,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to create payment", slog.Uint64("booking_id", booking.ID), sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	if err := s.authorize(ctx, op, p, fmt.Sprintf("booking:%d", booking.ID)); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+// SettleBooking captures the booking fee when capture is set (the hold was not used)
+// or releases it otherwise. Bookings without an authorized fee are left alone.
+func (s *PaymentService) SettleBooking(ctx context.Context, booking *models.Booking, capture bool) error {
+	const op = "services.PaymentService.SettleBooking"
+
+	if booking.PaymentID == nil {
+		return nil
+	}
+
+	p, err := s.ByID(ctx, *booking.PaymentID)
+	if err != nil {
+		return err
+	}
+	if p.Status != models.PaymentStatusAuthorized {
+		s.log.InfoContext(ctx, op, "booking fee already settled", slog.Uint64("id", p.ID), slog.String("status", p.Status))
+		return nil
+	}
+
+	status := models.PaymentStatusVoided
+	if capture {
+		status = models.PaymentStatusCaptured
+		err = s.provider.Capture(p.TransactionID, p.Amount)
+	} else {
+		err = s.provider.Void(p.TransactionID)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to settle booking fee", slog.Uint64("id", p.ID), slog.Bool("capture", capture), sl.Err(err))
+		return service.ErrInternalError
+	}
+	if capture {
+		metrics.Revenue.WithLabelValues(p.Currency).Add(float64(p.Amount))
+	}
+
+	if err := s.transition(ctx, p, status); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save payment status", slog.Uint64("id", p.ID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// Refund returns a captured payment in full
+func (s *PaymentService) Refund(ctx context.Context, id uint64) (*models.Payment, error) {
+	const op = "services.PaymentService.Refund"
+
+	p, err := s.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentStatusCaptured {
+		s.log.InfoContext(ctx, op, "payment is not captured", slog.Uint64("id", id), slog.String("status", p.Status))
+		return nil, service.ErrPaymentNotRefundable
+	}
+
+	if err := s.provider.Refund(p.TransactionID, p.Amount); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to refund payment", slog.Uint64("id", id), sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	metrics.Refunds.WithLabelValues(p.Currency).Add(float64(p.Amount))
+	if err := s.transition(ctx, p, models.PaymentStatusRefunded); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "payment refunded concurrently", slog.Uint64("id", id))
+			return nil, service.ErrPaymentNotRefundable
+		}
+		s.log.ErrorContext(ctx, op, "failed to save payment status", slog.Uint64("id", id), sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return p, nil
+}
+
+func (s *PaymentService) ByID(ctx context.Context, id uint64) (*models.Payment, error) {
+	const op = "services.PaymentService.ByID"
+
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "payment not found", slog.Uint64("id", id))
+			return nil, service.ErrPaymentNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get payment", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return p, nil
+}
+
+// History returns a page of the user's payments, newest first, and their total number.
+// Pages start at 1, limit is clamped to MaxPageSize.
+func (s *PaymentService) History(ctx context.Context, userID uint64, page, limit int) ([]models.Payment, int64, error) {
+	const op = "services.PaymentService.History"
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	payments, err := s.repo.ListByUserID(ctx, userID, (page-1)*limit, limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list payments", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	total, err := s.repo.CountByUserID(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to count payments", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	return payments, total, nil
+}
+
+// authorize reserves the payment amount with the provider and records the outcome
+func (s *PaymentService) authorize(ctx context.Context, op string, p *models.Payment, reference string) error {
+	transactionID, err := s.provider.Authorize(payment.Charge{
+		Amount:    p.Amount,
+		Currency:  p.Currency,
+		Method:    p.Method,
+		Reference: reference,
+	})
+	if err != nil {
+		if err := s.transition(ctx, p, models.PaymentStatusFailed); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to save payment status", slog.Uint64("id", p.ID), sl.Err(err))
+		}
+		if errors.Is(err, payment.ErrDeclined) {
+			s.log.InfoContext(ctx, op, "payment declined", slog.Uint64("id", p.ID), slog.String("reference", reference))
+			return service.ErrPaymentDeclined
+		}
+		s.log.ErrorContext(ctx, op, "failed to authorize payment", slog.Uint64("id", p.ID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	p.TransactionID = transactionID
+	if err := s.transition(ctx, p, models.PaymentStatusAuthorized); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save payment status", slog.Uint64("id", p.ID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// transition persists the payment status change, the current status must still be in the database
+func (s *PaymentService) transition(ctx context.Context, p *models.Payment, status string) error {
+	from := p.Status
+	p.Status = status
+	if err := s.repo.UpdateStatus(ctx, p, from); err != nil {
+		p.Status = from
+		return err
+	}
+	return nil
+}
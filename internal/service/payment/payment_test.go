@@ -0,0 +1,211 @@
+package payment_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/payment"
+	"sdt-bicycle-rental/internal/payment/fake"
+	"sdt-bicycle-rental/internal/service"
+	payment_service "sdt-bicycle-rental/internal/service/payment"
+	mocks "sdt-bicycle-rental/internal/service/payment/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var cfg = config.Payment{Provider: "fake", Method: "card", BookingFee: 500}
+
+// statuses records every status the payment is moved to
+func statuses(repo *mocks.PaymentRepository) *[]string {
+	var seen []string
+	repo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*models.Payment"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { seen = append(seen, args.Get(1).(*models.Payment).Status) }).
+		Return(nil).Maybe()
+	return &seen
+}
+
+func TestPaymentService_ChargeRental(t *testing.T) {
+	tests := []struct {
+		name         string
+		cost         int64
+		declineAbove int64
+		wantStatuses []string
+		wantErr      error
+	}{
+		{
+			name:         "captured",
+			cost:         3200,
+			wantStatuses: []string{models.PaymentStatusAuthorized, models.PaymentStatusCaptured},
+		},
+		{
+			name:         "declined",
+			cost:         3200,
+			declineAbove: 3000,
+			wantStatuses: []string{models.PaymentStatusFailed},
+			wantErr:      service.ErrPaymentDeclined,
+		},
+		{
+			name: "free ride",
+			cost: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewPaymentRepository(t)
+			s := payment_service.New(repo, fake.New(tt.declineAbove), slogdiscard.NewDiscardLogger(), cfg, "UAH")
+
+			rental := &models.Rental{ID: 10, UserID: 1, TotalCost: util.Ptr(tt.cost)}
+			if tt.cost > 0 {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.Payment) bool {
+					return p.UserID == 1 && *p.RentalID == 10 && p.Amount == tt.cost && p.Currency == "UAH" &&
+						p.Status == models.PaymentStatusPending
+				})).Return(nil).Once()
+			}
+			seen := statuses(repo)
+
+			got, err := s.ChargeRental(context.Background(), rental)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentService.ChargeRental() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(*seen, tt.wantStatuses) {
+				t.Errorf("PaymentService.ChargeRental() statuses = %v, want %v", *seen, tt.wantStatuses)
+			}
+			if tt.cost == 0 && got != nil {
+				t.Errorf("PaymentService.ChargeRental() charged a free ride")
+			}
+		})
+	}
+}
+
+func TestPaymentService_Booking(t *testing.T) {
+	tests := []struct {
+		name         string
+		capture      bool
+		wantStatuses []string
+	}{
+		{
+			name:         "no-show fee captured",
+			capture:      true,
+			wantStatuses: []string{models.PaymentStatusAuthorized, models.PaymentStatusCaptured},
+		},
+		{
+			name:         "hold voided",
+			wantStatuses: []string{models.PaymentStatusAuthorized, models.PaymentStatusVoided},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewPaymentRepository(t)
+			s := payment_service.New(repo, fake.New(0), slogdiscard.NewDiscardLogger(), cfg, "UAH")
+
+			booking := &models.Booking{ID: 7, UserID: 1}
+			repo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.Payment) bool {
+				return *p.BookingID == 7 && p.Amount == cfg.BookingFee
+			})).Run(func(args mock.Arguments) { args.Get(1).(*models.Payment).ID = 3 }).Return(nil).Once()
+			seen := statuses(repo)
+
+			p, err := s.AuthorizeBooking(context.Background(), booking)
+			if err != nil {
+				t.Fatalf("PaymentService.AuthorizeBooking() error = %v", err)
+			}
+
+			booking.PaymentID = &p.ID
+			repo.On("GetByID", mock.Anything, uint64(3)).Return(p, nil).Once()
+			if err := s.SettleBooking(context.Background(), booking, tt.capture); err != nil {
+				t.Fatalf("PaymentService.SettleBooking() error = %v", err)
+			}
+			if !slices.Equal(*seen, tt.wantStatuses) {
+				t.Errorf("booking payment statuses = %v, want %v", *seen, tt.wantStatuses)
+			}
+		})
+	}
+
+	t.Run("free bookings", func(t *testing.T) {
+		s := payment_service.New(mocks.NewPaymentRepository(t), mocks.NewPaymentProvider(t), slogdiscard.NewDiscardLogger(), config.Payment{}, "UAH")
+
+		if p, err := s.AuthorizeBooking(context.Background(), &models.Booking{ID: 7}); p != nil || err != nil {
+			t.Errorf("PaymentService.AuthorizeBooking() = %v, %v, want nothing", p, err)
+		}
+		if err := s.SettleBooking(context.Background(), &models.Booking{ID: 7}, true); err != nil {
+			t.Errorf("PaymentService.SettleBooking() error = %v", err)
+		}
+	})
+}
+
+func TestPaymentService_Refund(t *testing.T) {
+	tests := []struct {
+		name       string
+		payment    *models.Payment
+		getErr     error
+		refundErr  error
+		refundCall bool
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			payment:    &models.Payment{ID: 1, TransactionID: "tx", Amount: 3200, Status: models.PaymentStatusCaptured},
+			refundCall: true,
+		},
+		{
+			name:    "not found",
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrPaymentNotFound,
+		},
+		{
+			name:    "not captured",
+			payment: &models.Payment{ID: 1, TransactionID: "tx", Amount: 3200, Status: models.PaymentStatusVoided},
+			wantErr: service.ErrPaymentNotRefundable,
+		},
+		{
+			name:       "provider error",
+			payment:    &models.Payment{ID: 1, TransactionID: "tx", Amount: 3200, Status: models.PaymentStatusCaptured},
+			refundCall: true,
+			refundErr:  payment.ErrUnknownTransaction,
+			wantErr:    service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewPaymentRepository(t)
+			provider := mocks.NewPaymentProvider(t)
+			s := payment_service.New(repo, provider, slogdiscard.NewDiscardLogger(), cfg, "UAH")
+
+			repo.On("GetByID", mock.Anything, uint64(1)).Return(tt.payment, tt.getErr).Once()
+			if tt.refundCall {
+				provider.On("Refund", "tx", int64(3200)).Return(tt.refundErr).Once()
+			}
+			if tt.refundCall && tt.refundErr == nil {
+				repo.On("UpdateStatus", mock.Anything, tt.payment, models.PaymentStatusCaptured).Return(nil).Once()
+			}
+
+			got, err := s.Refund(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentService.Refund() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Status != models.PaymentStatusRefunded {
+				t.Errorf("PaymentService.Refund() status = %v, want %v", got.Status, models.PaymentStatusRefunded)
+			}
+		})
+	}
+}
+
+func TestPaymentService_History(t *testing.T) {
+	repo := mocks.NewPaymentRepository(t)
+	s := payment_service.New(repo, mocks.NewPaymentProvider(t), slogdiscard.NewDiscardLogger(), cfg, "UAH")
+
+	repo.On("ListByUserID", mock.Anything, uint64(1), 0, payment_service.MaxPageSize).Return([]models.Payment{{ID: 1}}, nil).Once()
+	repo.On("CountByUserID", mock.Anything, uint64(1)).Return(int64(1), nil).Once()
+
+	payments, total, err := s.History(context.Background(), 1, 0, 1000)
+	if err != nil || len(payments) != 1 || total != 1 {
+		t.Errorf("PaymentService.History() = %v, %v, %v", payments, total, err)
+	}
+}
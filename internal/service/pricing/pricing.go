@@ -0,0 +1,218 @@
+package pricing_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/pricing"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/util"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=TariffRepository
+type TariffRepository interface {
+	Create(ctx context.Context, tariff *models.Tariff) error
+	GetByID(ctx context.Context, id uint64) (*models.Tariff, error)
+	GetCurrent(ctx context.Context, now time.Time) (*models.Tariff, error)
+	List(ctx context.Context, offset, limit int) ([]models.Tariff, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	// MaxQuoteMinutes bounds ride estimates to a week
+	MaxQuoteMinutes = 7 * 24 * 60
+)
+
+// Quote is an estimated ride cost under the current tariff
+type Quote struct {
+	TariffID    uint64 `json:"tariff_id"`
+	BicycleType string `json:"bicycle_type"`
+	Minutes     int    `json:"minutes"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+}
+
+type PricingService struct {
+	repo     TariffRepository
+	log      *slog.Logger
+	cfg      config.Pricing
+	location *time.Location
+}
+
+func New(repo TariffRepository, log *slog.Logger, cfg config.Pricing) *PricingService {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		log.Error("services.PricingService.New", "unknown time zone, falling back to UTC", slog.String("time_zone", cfg.TimeZone))
+		location = time.UTC
+	}
+	return &PricingService{repo: repo, log: log, cfg: cfg, location: location}
+}
+
+// Create adds a new tariff version. It takes effect at ActiveFrom, or immediately when unset
+func (s *PricingService) Create(ctx context.Context, input *dto.CreateTariff) (*models.Tariff, error) {
+	const op = "services.PricingService.Create"
+
+	err := service.Validate.Struct(input)
+	if err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return nil, service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	now := time.Now()
+	tariff := input.Model()
+	if tariff.ActiveFrom == nil {
+		tariff.ActiveFrom = &now
+	}
+	// Rides already started keep their tariff, but quotes and reports must not change retroactively
+	if tariff.ActiveFrom.Before(now.Add(-time.Minute)) {
+		s.log.InfoContext(ctx, op, "tariff activation is in the past", slog.Time("active_from", *tariff.ActiveFrom))
+		return nil, service.ErrTariffActiveFromPast
+	}
+
+	err = s.repo.Create(ctx, tariff)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to create tariff", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return tariff, nil
+}
+
+// Current returns the tariff in effect at now
+func (s *PricingService) Current(ctx context.Context, now time.Time) (*models.Tariff, error) {
+	const op = "services.PricingService.Current"
+
+	tariff, err := s.repo.GetCurrent(ctx, now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.WarnContext(ctx, op, "no tariff in effect", slog.Time("now", now))
+			return nil, service.ErrTariffNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get current tariff", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return tariff, nil
+}
+
+func (s *PricingService) ByID(ctx context.Context, id uint64) (*models.Tariff, error) {
+	const op = "services.PricingService.ByID"
+
+	tariff, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "tariff not found", slog.Uint64("id", id))
+			return nil, service.ErrTariffNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get tariff", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return tariff, nil
+}
+
+// List returns a page of tariffs, newest first, and the total number of tariffs.
+// Pages start at 1, limit is clamped to MaxPageSize.
+func (s *PricingService) List(ctx context.Context, page, limit int) ([]models.Tariff, int64, error) {
+	const op = "services.PricingService.List"
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	tariffs, err := s.repo.List(ctx, (page-1)*limit, limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list tariffs", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	total, err := s.repo.Count(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to count tariffs", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	return tariffs, total, nil
+}
+
+// Quote estimates the cost of a ride of the given length starting now
+func (s *PricingService) Quote(ctx context.Context, bicycleType string, minutes int) (*Quote, error) {
+	const op = "services.PricingService.Quote"
+
+	if bicycleType != models.BicycleTypeStandard && bicycleType != models.BicycleTypeElectric {
+		s.log.InfoContext(ctx, op, "invalid bicycle type", slog.String("bicycle_type", bicycleType))
+		return nil, service.ErrInvalidBicycleType
+	}
+
+	now := time.Now()
+	tariff, err := s.Current(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	start := now.In(s.location)
+	return &Quote{
+		TariffID:    tariff.ID,
+		BicycleType: bicycleType,
+		Minutes:     minutes,
+		Amount:      pricing.Cost(tariff, bicycleType, start, start.Add(time.Duration(minutes)*time.Minute)),
+		Currency:    s.cfg.Currency,
+	}, nil
+}
+
+// Cost prices a ride under the tariff it started with
+func (s *PricingService) Cost(ctx context.Context, tariffID uint64, bicycleType string, start, end time.Time) (int64, error) {
+	tariff, err := s.ByID(ctx, tariffID)
+	if err != nil {
+		return 0, err
+	}
+
+	return pricing.Cost(tariff, bicycleType, start.In(s.location), end.In(s.location)), nil
+}
+
+// EnsureDefault creates a tariff from the configuration when there are none yet
+func (s *PricingService) EnsureDefault(ctx context.Context) error {
+	const op = "services.PricingService.EnsureDefault"
+
+	count, err := s.repo.Count(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to count tariffs", sl.Err(err))
+		return service.ErrInternalError
+	}
+	if count > 0 {
+		return nil
+	}
+
+	tariff := &models.Tariff{
+		Name:        "default",
+		UnlockFee:   s.cfg.UnlockFee,
+		PerMinute:   s.cfg.PerMinute,
+		DailyCap:    s.cfg.DailyCap,
+		FreeMinutes: s.cfg.FreeMinutes,
+		ActiveFrom:  util.Ptr(time.Unix(0, 0).UTC()), // in effect for everything that came before it
+	}
+	if err := s.repo.Create(ctx, tariff); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to create default tariff", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "default tariff created", slog.Uint64("id", tariff.ID))
+	return nil
+}
@@ -0,0 +1,208 @@
+package pricing_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
+	pricing_service "sdt-bicycle-rental/internal/service/pricing"
+	mocks "sdt-bicycle-rental/internal/service/pricing/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var cfg = config.Pricing{Currency: "UAH", TimeZone: "Europe/Kyiv", UnlockFee: 1000, PerMinute: 200, DailyCap: 30000}
+
+func TestPricingService_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    dto.CreateTariff
+		mockCall bool
+		mockErr  error
+		wantErr  error
+		anyErr   bool
+	}{
+		{
+			name:     "success",
+			input:    dto.CreateTariff{Name: "summer", PerMinute: 250, Rates: []dto.CreateTariffRate{{BicycleType: "electric", PerMinute: 400}}},
+			mockCall: true,
+		},
+		{
+			name:     "scheduled",
+			input:    dto.CreateTariff{Name: "winter", PerMinute: 150, ActiveFrom: util.Ptr(time.Now().Add(24 * time.Hour))},
+			mockCall: true,
+		},
+		{
+			name:   "negative rate",
+			input:  dto.CreateTariff{Name: "broken", Rates: []dto.CreateTariffRate{{PerMinute: -5}}},
+			anyErr: true,
+		},
+		{
+			name:   "unknown bicycle type",
+			input:  dto.CreateTariff{Name: "broken", Rates: []dto.CreateTariffRate{{BicycleType: "tandem"}}},
+			anyErr: true,
+		},
+		{
+			name:    "in the past",
+			input:   dto.CreateTariff{Name: "late", ActiveFrom: util.Ptr(time.Now().Add(-time.Hour))},
+			wantErr: service.ErrTariffActiveFromPast,
+		},
+		{
+			name:     "repository error",
+			input:    dto.CreateTariff{Name: "summer"},
+			mockCall: true,
+			mockErr:  errors.New("unexpected error"),
+			wantErr:  service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewTariffRepository(t)
+			s := pricing_service.New(repo, slogdiscard.NewDiscardLogger(), cfg)
+
+			if tt.mockCall {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(tariff *models.Tariff) bool {
+					return tariff.Name == tt.input.Name && tariff.ActiveFrom != nil && len(tariff.Rates) == len(tt.input.Rates)
+				})).Return(tt.mockErr).Once()
+			}
+
+			got, err := s.Create(context.Background(), &tt.input)
+			if tt.anyErr {
+				if err == nil {
+					t.Errorf("PricingService.Create() expected validation error")
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PricingService.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got == nil {
+				t.Errorf("PricingService.Create() returned no tariff")
+			}
+		})
+	}
+}
+
+func TestPricingService_Quote(t *testing.T) {
+	tariff := &models.Tariff{
+		ID: 4, UnlockFee: 1000, PerMinute: 200,
+		Rates: []models.TariffRate{{BicycleType: models.BicycleTypeElectric, PerMinute: 300}},
+	}
+
+	tests := []struct {
+		name        string
+		bicycleType string
+		mockCall    bool
+		mockErr     error
+		want        int64
+		wantErr     error
+	}{
+		{
+			name:        "standard",
+			bicycleType: models.BicycleTypeStandard,
+			mockCall:    true,
+			want:        1000 + 10*200,
+		},
+		{
+			name:        "electric",
+			bicycleType: models.BicycleTypeElectric,
+			mockCall:    true,
+			want:        1000 + 10*300,
+		},
+		{
+			name:        "unknown type",
+			bicycleType: "tandem",
+			wantErr:     service.ErrInvalidBicycleType,
+		},
+		{
+			name:        "no tariff",
+			bicycleType: models.BicycleTypeStandard,
+			mockCall:    true,
+			mockErr:     gorm.ErrRecordNotFound,
+			wantErr:     service.ErrTariffNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewTariffRepository(t)
+			s := pricing_service.New(repo, slogdiscard.NewDiscardLogger(), cfg)
+
+			if tt.mockCall {
+				var current *models.Tariff
+				if tt.mockErr == nil {
+					current = tariff
+				}
+				repo.On("GetCurrent", mock.Anything, mock.AnythingOfType("time.Time")).Return(current, tt.mockErr).Once()
+			}
+
+			got, err := s.Quote(context.Background(), tt.bicycleType, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PricingService.Quote() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Amount != tt.want || got.TariffID != 4 || got.Currency != "UAH" {
+				t.Errorf("PricingService.Quote() = %+v, want amount %v under tariff 4", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPricingService_Cost(t *testing.T) {
+	// 08:00 in Kyiv is 05:00 UTC in summer, the rush hour rate applies in local time
+	tariff := &models.Tariff{ID: 4, PerMinute: 200, Rates: []models.TariffRate{{StartHour: 7, EndHour: 10, PerMinute: 500}}}
+	start := time.Date(2025, 6, 2, 5, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewTariffRepository(t)
+	s := pricing_service.New(repo, slogdiscard.NewDiscardLogger(), cfg)
+
+	repo.On("GetByID", mock.Anything, uint64(4)).Return(tariff, nil).Once()
+	got, err := s.Cost(context.Background(), 4, models.BicycleTypeStandard, start, start.Add(10*time.Minute))
+	if err != nil || got != 10*500 {
+		t.Errorf("PricingService.Cost() = %v, %v, want %v", got, err, 10*500)
+	}
+
+	repo.On("GetByID", mock.Anything, uint64(5)).Return(nil, gorm.ErrRecordNotFound).Once()
+	if _, err := s.Cost(context.Background(), 5, models.BicycleTypeStandard, start, start.Add(time.Minute)); !errors.Is(err, service.ErrTariffNotFound) {
+		t.Errorf("PricingService.Cost() error = %v, wantErr %v", err, service.ErrTariffNotFound)
+	}
+}
+
+func TestPricingService_EnsureDefault(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int64
+		wantCreate bool
+	}{
+		{name: "seeds empty table", count: 0, wantCreate: true},
+		{name: "keeps existing tariffs", count: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewTariffRepository(t)
+			s := pricing_service.New(repo, slogdiscard.NewDiscardLogger(), cfg)
+
+			repo.On("Count", mock.Anything).Return(tt.count, nil).Once()
+			if tt.wantCreate {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(tariff *models.Tariff) bool {
+					return tariff.UnlockFee == cfg.UnlockFee && tariff.PerMinute == cfg.PerMinute &&
+						tariff.DailyCap == cfg.DailyCap && tariff.ActiveFrom.Before(time.Now())
+				})).Return(nil).Once()
+			}
+
+			if err := s.EnsureDefault(context.Background()); err != nil {
+				t.Errorf("PricingService.EnsureDefault() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Charger is an autogenerated mock type for the Charger type
+type Charger struct {
+	mock.Mock
+}
+
+// ChargeRental provides a mock function with given fields: ctx, rental
+func (_m *Charger) ChargeRental(ctx context.Context, rental *models.Rental) (*models.Payment, error) {
+	ret := _m.Called(ctx, rental)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChargeRental")
+	}
+
+	var r0 *models.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Rental) (*models.Payment, error)); ok {
+		return rf(ctx, rental)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Rental) *models.Payment); ok {
+		r0 = rf(ctx, rental)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Rental) error); ok {
+		r1 = rf(ctx, rental)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCharger creates a new instance of Charger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCharger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Charger {
+	mock := &Charger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,207 @@
+package rental_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=RentalRepository
+type RentalRepository interface {
+	Start(ctx context.Context, rental *models.Rental) error
+	End(ctx context.Context, rental *models.Rental) error
+	GetByID(ctx context.Context, id uint64) (*models.Rental, error)
+	GetActiveByUserID(ctx context.Context, userID uint64) (*models.Rental, error)
+}
+
+// Pricer resolves the tariff a ride starts under and prices it when it ends
+//
+//go:generate mockery --name=Pricer
+type Pricer interface {
+	Current(ctx context.Context, now time.Time) (*models.Tariff, error)
+	Cost(ctx context.Context, tariffID uint64, bicycleType string, start, end time.Time) (int64, error)
+}
+
+// AccountPolicy decides whether the user may ride
+//
+//go:generate mockery --name=AccountPolicy
+type AccountPolicy interface {
+	RequireVerified(ctx context.Context, userID uint64) error
+}
+
+// Charger takes payment for a completed ride
+//
+//go:generate mockery --name=Charger
+type Charger interface {
+	ChargeRental(ctx context.Context, rental *models.Rental) (*models.Payment, error)
+}
+
+type RentalService struct {
+	repo     RentalRepository
+	pricer   Pricer
+	payments Charger
+	accounts AccountPolicy
+	log      *slog.Logger
+}
+
+func New(repo RentalRepository, pricer Pricer, payments Charger, accounts AccountPolicy, log *slog.Logger) *RentalService {
+	return &RentalService{repo: repo, pricer: pricer, payments: payments, accounts: accounts, log: log}
+}
+
+// Start rents an available bicycle at the station to the user
+func (s *RentalService) Start(ctx context.Context, userID, bicycleID, stationID uint64) (*models.Rental, error) {
+	const op = "services.RentalService.Start"
+
+	if err := s.accounts.RequireVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tariff, err := s.pricer.Current(ctx, now)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to resolve tariff", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	rental := &models.Rental{
+		UserID:         userID,
+		BicycleID:      bicycleID,
+		StationStartID: stationID,
+		TariffID:       &tariff.ID,
+		Status:         models.RentalStatusActive,
+		StartTime:      &now,
+	}
+
+	err = s.repo.Start(ctx, rental)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "bicycle is not available at station", slog.Uint64("bicycle_id", bicycleID), slog.Uint64("station_id", stationID))
+			return nil, service.ErrBicycleUnavailable
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			s.log.InfoContext(ctx, op, "user already has an active rental", slog.Uint64("user_id", userID))
+			return nil, service.ErrRentalInProgress
+		}
+		s.log.ErrorContext(ctx, op, "failed to start rental", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	metrics.RidesStarted.Inc()
+
+	return rental, nil
+}
+
+// End completes the user's active rental at the station and charges the ride cost
+func (s *RentalService) End(ctx context.Context, userID, rentalID, stationID uint64) (*models.Rental, error) {
+	const op = "services.RentalService.End"
+
+	rental, err := s.ByID(ctx, userID, rentalID)
+	if err != nil {
+		return nil, err
+	}
+	if rental.Status != models.RentalStatusActive {
+		s.log.InfoContext(ctx, op, "rental already ended", slog.Uint64("id", rentalID))
+		return nil, service.ErrRentalNotActive
+	}
+
+	end := time.Now()
+	cost, err := s.cost(ctx, rental, end)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to compute rental cost", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	rental.Status = models.RentalStatusCompleted
+	rental.StationEndID = &stationID
+	rental.EndTime = &end
+	rental.TotalCost = &cost
+
+	err = s.repo.End(ctx, rental)
+	if err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("station_id", stationID))
+			return nil, service.ErrStationNotFound
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "rental ended concurrently", slog.Uint64("id", rentalID))
+			return nil, service.ErrRentalNotActive
+		}
+		s.log.ErrorContext(ctx, op, "failed to end rental", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	metrics.RidesEnded.Inc()
+	if rental.Bicycle != nil {
+		rental.Bicycle.Status = models.BicycleStatusAvailable
+		rental.Bicycle.StationID = stationID
+	}
+
+	// The ride is over either way, a failed charge stays recorded on the payment for follow-up
+	if _, err := s.payments.ChargeRental(ctx, rental); err != nil {
+		s.log.WarnContext(ctx, op, "rental not charged", slog.Uint64("id", rentalID), sl.Err(err))
+	}
+
+	return rental, nil
+}
+
+// cost prices the ride under the tariff it started with.
+// Rentals that predate tariffs are priced under the current one.
+func (s *RentalService) cost(ctx context.Context, rental *models.Rental, end time.Time) (int64, error) {
+	var bicycleType string
+	if rental.Bicycle != nil {
+		bicycleType = rental.Bicycle.Type
+	}
+
+	tariffID := rental.TariffID
+	if tariffID == nil {
+		tariff, err := s.pricer.Current(ctx, end)
+		if err != nil {
+			return 0, err
+		}
+		tariffID = &tariff.ID
+	}
+
+	return s.pricer.Cost(ctx, *tariffID, bicycleType, *rental.StartTime, end)
+}
+
+// ByID returns the user's rental, rentals of other users are reported as not found
+func (s *RentalService) ByID(ctx context.Context, userID, rentalID uint64) (*models.Rental, error) {
+	const op = "services.RentalService.ByID"
+
+	rental, err := s.repo.GetByID(ctx, rentalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "rental not found", slog.Uint64("id", rentalID))
+			return nil, service.ErrRentalNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get rental", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if rental.UserID != userID {
+		s.log.InfoContext(ctx, op, "rental belongs to another user", slog.Uint64("id", rentalID), slog.Uint64("user_id", userID))
+		return nil, service.ErrRentalNotFound
+	}
+
+	return rental, nil
+}
+
+// Active returns the user's ride in progress
+func (s *RentalService) Active(ctx context.Context, userID uint64) (*models.Rental, error) {
+	const op = "services.RentalService.Active"
+
+	rental, err := s.repo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.ErrRentalNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get active rental", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return rental, nil
+}
@@ -0,0 +1,219 @@
+package rental_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/metrics"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	rental_service "sdt-bicycle-rental/internal/service/rental"
+	mocks "sdt-bicycle-rental/internal/service/rental/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRentalService_Start(t *testing.T) {
+	tests := []struct {
+		name      string
+		policyErr error
+		tariffErr error
+		mockCall  bool
+		mockErr   error
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			mockCall: true,
+		},
+		{
+			name:      "account not verified",
+			policyErr: service.ErrAccountNotVerified,
+			wantErr:   service.ErrAccountNotVerified,
+		},
+		{
+			name:      "no tariff in effect",
+			tariffErr: service.ErrTariffNotFound,
+			wantErr:   service.ErrInternalError,
+		},
+		{
+			name:     "bicycle not available",
+			mockCall: true,
+			mockErr:  gorm.ErrRecordNotFound,
+			wantErr:  service.ErrBicycleUnavailable,
+		},
+		{
+			name:     "ride in progress",
+			mockCall: true,
+			mockErr:  gorm.ErrDuplicatedKey,
+			wantErr:  service.ErrRentalInProgress,
+		},
+		{
+			name:     "unexpected error",
+			mockCall: true,
+			mockErr:  errors.New("unexpected error"),
+			wantErr:  service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRentalRepository(t)
+			pricer := mocks.NewPricer(t)
+			accounts := mocks.NewAccountPolicy(t)
+			s := rental_service.New(repo, pricer, mocks.NewCharger(t), accounts, slogdiscard.NewDiscardLogger())
+
+			accounts.On("RequireVerified", mock.Anything, uint64(1)).Return(tt.policyErr).Once()
+			if tt.policyErr == nil {
+				var tariff *models.Tariff
+				if tt.tariffErr == nil {
+					tariff = &models.Tariff{ID: 7}
+				}
+				pricer.On("Current", mock.Anything, mock.AnythingOfType("time.Time")).Return(tariff, tt.tariffErr).Once()
+			}
+			if tt.mockCall {
+				repo.On("Start", mock.Anything, mock.MatchedBy(func(r *models.Rental) bool {
+					return r.UserID == 1 && r.BicycleID == 2 && r.StationStartID == 3 && *r.TariffID == 7 &&
+						r.Status == models.RentalStatusActive && r.StartTime != nil && r.EndTime == nil
+				})).Return(tt.mockErr).Once()
+			}
+
+			started := testutil.ToFloat64(metrics.RidesStarted)
+
+			got, err := s.Start(context.Background(), 1, 2, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RentalService.Start() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got == nil {
+				t.Errorf("RentalService.Start() returned no rental")
+			}
+
+			wantStarted := started
+			if tt.wantErr == nil {
+				wantStarted++
+			}
+			if got := testutil.ToFloat64(metrics.RidesStarted); got != wantStarted {
+				t.Errorf("rides started = %v, want %v", got, wantStarted)
+			}
+		})
+	}
+}
+
+func TestRentalService_End(t *testing.T) {
+	active := func() *models.Rental {
+		return &models.Rental{
+			ID:             10,
+			UserID:         1,
+			BicycleID:      2,
+			StationStartID: 3,
+			TariffID:       util.Ptr(uint64(7)),
+			Status:         models.RentalStatusActive,
+			StartTime:      util.Ptr(time.Now().Add(-15 * time.Minute)),
+			Bicycle:        &models.Bicycle{ID: 2, Type: models.BicycleTypeElectric},
+		}
+	}
+	legacy := func() *models.Rental {
+		rental := active()
+		rental.TariffID = nil
+		return rental
+	}
+
+	tests := []struct {
+		name      string
+		userID    uint64
+		rental    *models.Rental
+		getErr    error
+		mockCall  bool
+		mockErr   error
+		chargeErr error
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			userID:   1,
+			rental:   active(),
+			mockCall: true,
+		},
+		{
+			name:     "rental without tariff priced under current one",
+			userID:   1,
+			rental:   legacy(),
+			mockCall: true,
+		},
+		{
+			name:      "charge declined",
+			userID:    1,
+			rental:    active(),
+			mockCall:  true,
+			chargeErr: service.ErrPaymentDeclined,
+		},
+		{
+			name:    "not found",
+			userID:  1,
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrRentalNotFound,
+		},
+		{
+			name:    "other user's rental",
+			userID:  2,
+			rental:  active(),
+			wantErr: service.ErrRentalNotFound,
+		},
+		{
+			name:    "already ended",
+			userID:  1,
+			rental:  &models.Rental{ID: 10, UserID: 1, Status: models.RentalStatusCompleted},
+			wantErr: service.ErrRentalNotActive,
+		},
+		{
+			name:     "end station not found",
+			userID:   1,
+			rental:   active(),
+			mockCall: true,
+			mockErr:  gorm.ErrForeignKeyViolated,
+			wantErr:  service.ErrStationNotFound,
+		},
+		{
+			name:     "ended concurrently",
+			userID:   1,
+			rental:   active(),
+			mockCall: true,
+			mockErr:  gorm.ErrRecordNotFound,
+			wantErr:  service.ErrRentalNotActive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRentalRepository(t)
+			pricer := mocks.NewPricer(t)
+			payments := mocks.NewCharger(t)
+			s := rental_service.New(repo, pricer, payments, mocks.NewAccountPolicy(t), slogdiscard.NewDiscardLogger())
+
+			repo.On("GetByID", mock.Anything, uint64(10)).Return(tt.rental, tt.getErr).Once()
+			if tt.mockCall {
+				if tt.rental.TariffID == nil {
+					pricer.On("Current", mock.Anything, mock.AnythingOfType("time.Time")).Return(&models.Tariff{ID: 7}, nil).Once()
+				}
+				pricer.On("Cost", mock.Anything, uint64(7), models.BicycleTypeElectric, *tt.rental.StartTime, mock.AnythingOfType("time.Time")).
+					Return(int64(4000), nil).Once()
+				repo.On("End", mock.Anything, mock.MatchedBy(func(r *models.Rental) bool {
+					return r.Status == models.RentalStatusCompleted && *r.StationEndID == 4 && *r.TotalCost == 4000 && r.EndTime != nil
+				})).Return(tt.mockErr).Once()
+			}
+			if tt.mockCall && tt.mockErr == nil {
+				// the ride ends even when the charge fails
+				payments.On("ChargeRental", mock.Anything, tt.rental).Return(nil, tt.chargeErr).Once()
+			}
+
+			_, err := s.End(context.Background(), tt.userID, 10, 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RentalService.End() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
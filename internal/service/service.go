@@ -1,7 +1,23 @@
 package service
 
 import (
+	"sdt-bicycle-rental/lib/validation"
+
 	"github.com/go-playground/validator/v10"
 )
 
-var Validate = validator.New(validator.WithRequiredStructEnabled())
+// Validate reports fields by their JSON names, Messages holds its translated messages
+var (
+	Validate = validator.New(validator.WithRequiredStructEnabled())
+	Messages = mustTranslator(Validate)
+)
+
+func mustTranslator(v *validator.Validate) *validation.Translator {
+	v.RegisterTagNameFunc(validation.JSONFieldName)
+
+	t, err := validation.NewTranslator(v)
+	if err != nil {
+		panic("service: validation messages: " + err.Error())
+	}
+	return t
+}
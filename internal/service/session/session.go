@@ -0,0 +1,65 @@
+package session_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/internal/tracing"
+	"sdt-bicycle-rental/lib/logger/sl"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=Repository
+type Repository interface {
+	ListActive(ctx context.Context, userID uint64) ([]models.Session, error)
+	Revoke(ctx context.Context, userID uint64, id string) error
+}
+
+// SessionService lets users see the devices they are signed in on and sign them out
+type SessionService struct {
+	repo Repository
+	log  *slog.Logger
+}
+
+func New(repo Repository, log *slog.Logger) *SessionService {
+	return &SessionService{repo: repo, log: log}
+}
+
+// List returns the active sessions of the user, last seen first
+func (s *SessionService) List(ctx context.Context, userID uint64) ([]models.Session, error) {
+	const op = "services.SessionService.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	sessions, err := s.repo.ListActive(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list sessions", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return sessions, nil
+}
+
+// Revoke signs the device out: its refresh token stops working and so do its access tokens
+func (s *SessionService) Revoke(ctx context.Context, userID uint64, id string) error {
+	const op = "services.SessionService.Revoke"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := s.repo.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "session not found", slog.Uint64("user_id", userID))
+			return service.ErrSessionNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to revoke session", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "session revoked", slog.Uint64("user_id", userID))
+	return nil
+}
@@ -0,0 +1,86 @@
+package session_service_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	session_service "sdt-bicycle-rental/internal/service/session"
+	mocks "sdt-bicycle-rental/internal/service/session/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSessionService_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "repository error",
+			repoErr: errors.New("db is down"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			s := session_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			var sessions []models.Session
+			if tt.repoErr == nil {
+				sessions = []models.Session{{ID: "a", UserID: 1}, {ID: "b", UserID: 1}}
+			}
+			repo.On("ListActive", mock.Anything, uint64(1)).Return(sessions, tt.repoErr).Once()
+
+			got, err := s.List(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SessionService.List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(sessions) {
+				t.Errorf("SessionService.List() = %v, want %v", got, sessions)
+			}
+		})
+	}
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "not found or of another user",
+			repoErr: gorm.ErrRecordNotFound,
+			wantErr: service.ErrSessionNotFound,
+		},
+		{
+			name:    "repository error",
+			repoErr: errors.New("db is down"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			s := session_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			repo.On("Revoke", mock.Anything, uint64(1), "session").Return(tt.repoErr).Once()
+
+			if err := s.Revoke(context.Background(), 1, "session"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SessionService.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	models "sdt-bicycle-rental/internal/models"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,45 @@ type StationRepositoty struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: station
-func (_m *StationRepositoty) Create(station *models.Station) error {
-	ret := _m.Called(station)
+// Count provides a mock function with given fields: ctx
+func (_m *StationRepositoty) Count(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, station
+func (_m *StationRepositoty) Create(ctx context.Context, station *models.Station) error {
+	ret := _m.Called(ctx, station)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.Station) error); ok {
-		r0 = rf(station)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Station) error); ok {
+		r0 = rf(ctx, station)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,17 +60,17 @@ func (_m *StationRepositoty) Create(station *models.Station) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *StationRepositoty) Delete(id uint64) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *StationRepositoty) Delete(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,9 +78,9 @@ func (_m *StationRepositoty) Delete(id uint64) error {
 	return r0
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *StationRepositoty) GetByID(id uint64) (*models.Station, error) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *StationRepositoty) GetByID(ctx context.Context, id uint64) (*models.Station, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -59,19 +88,49 @@ func (_m *StationRepositoty) GetByID(id uint64) (*models.Station, error) {
 
 	var r0 *models.Station
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (*models.Station, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Station, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint64) *models.Station); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Station); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Station)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, offset, limit
+func (_m *StationRepositoty) List(ctx context.Context, offset int, limit int) ([]models.Station, error) {
+	ret := _m.Called(ctx, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.Station
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Station, error)); ok {
+		return rf(ctx, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Station); ok {
+		r0 = rf(ctx, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Station)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, offset, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,17 +138,17 @@ func (_m *StationRepositoty) GetByID(id uint64) (*models.Station, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: station
-func (_m *StationRepositoty) Update(station *models.Station) error {
-	ret := _m.Called(station)
+// Update provides a mock function with given fields: ctx, station
+func (_m *StationRepositoty) Update(ctx context.Context, station *models.Station) error {
+	ret := _m.Called(ctx, station)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.Station) error); ok {
-		r0 = rf(station)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Station) error); ok {
+		r0 = rf(ctx, station)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1,12 +1,13 @@
 package station_service
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/internal/tracing"
 	"sdt-bicycle-rental/lib/logger/sl"
-	"sdt-bicycle-rental/lib/validation"
 
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
@@ -14,14 +15,21 @@ import (
 
 //go:generate mockery --name=StationRepositoty
 type StationRepositoty interface {
-	Create(station *models.Station) error
-	GetByID(id uint64) (*models.Station, error)
+	Create(ctx context.Context, station *models.Station) error
+	GetByID(ctx context.Context, id uint64) (*models.Station, error)
+	List(ctx context.Context, offset, limit int) ([]models.Station, error)
+	Count(ctx context.Context) (int64, error)
 	UpdateBikesAvailable(id uint64, delta int) error
 	UpdateBikesTotal(id uint64, delta int) error
-	Update(station *models.Station) error
-	Delete(id uint64) error
+	Update(ctx context.Context, station *models.Station) error
+	Delete(ctx context.Context, id uint64) error
 }
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
 type StationService struct {
 	repo StationRepositoty
 	log  *slog.Logger
@@ -31,44 +39,86 @@ func New(repo StationRepositoty, log *slog.Logger) *StationService {
 	return &StationService{repo, log}
 }
 
-func (s *StationService) Create(station *models.Station) (*models.Station, error) {
+func (s *StationService) Create(ctx context.Context, station *models.Station) (*models.Station, error) {
 	const op = "services.StationService.Create"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	// Validate station data
 	err := service.Validate.Struct(station)
 	if err != nil {
-		s.log.Info(op, "validation error", sl.Err(err))
-		return nil, validation.PrettyError(err.(validator.ValidationErrors))
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return nil, service.Invalid(err.(validator.ValidationErrors))
 	}
 
-	err = s.repo.Create(station)
+	err = s.repo.Create(ctx, station)
 	if err != nil {
-		s.log.Error(op, "failed to create station", sl.Err(err))
+		s.log.ErrorContext(ctx, op, "failed to create station", sl.Err(err))
 		return nil, service.ErrInternalError
 	}
 
 	return station, nil
 }
 
-func (s *StationService) ByID(id uint64) (*models.Station, error) {
+func (s *StationService) ByID(ctx context.Context, id uint64) (*models.Station, error) {
 	const op = "services.StationService.ByID"
 
-	station, err := s.repo.GetByID(id)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	station, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.log.Info(op, "station not found", slog.Uint64("id", id))
-			return nil, service.ErrInvalidCredentials
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("id", id))
+			return nil, service.ErrStationNotFound
 		}
-		s.log.Error(op, "failed to get station", sl.Err(err))
+		s.log.ErrorContext(ctx, op, "failed to get station", sl.Err(err))
 		return nil, service.ErrInternalError
 	}
 
 	return station, nil
 }
 
-func (s *StationService) UpdateLocation(id uint64, location string) error {
+// List returns a page of stations ordered by ID and the total number of stations.
+// Pages start at 1, limit is clamped to MaxPageSize.
+func (s *StationService) List(ctx context.Context, page, limit int) ([]models.Station, int64, error) {
+	const op = "services.StationService.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	stations, err := s.repo.List(ctx, (page-1)*limit, limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to list stations", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	total, err := s.repo.Count(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to count stations", sl.Err(err))
+		return nil, 0, service.ErrInternalError
+	}
+
+	return stations, total, nil
+}
+
+func (s *StationService) UpdateLocation(ctx context.Context, id uint64, location string) error {
 	const op = "services.StationService.UpdateLocation"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	station := &models.Station{
 		ID:             id,
 		LocationStreet: location,
@@ -77,29 +127,40 @@ func (s *StationService) UpdateLocation(id uint64, location string) error {
 	// Validate station data
 	err := service.Validate.Struct(station)
 	if err != nil {
-		s.log.Info(op, "validation error", sl.Err(err))
-		return validation.PrettyError(err.(validator.ValidationErrors))
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
 	}
 
-	err = s.repo.Update(station)
+	err = s.repo.Update(ctx, station)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.log.Info(op, "station not found", slog.Uint64("id", id), slog.String("location", location))
-			return service.ErrInvalidCredentials
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("id", id), slog.String("location", location))
+			return service.ErrStationNotFound
 		}
-		s.log.Error(op, "failed to udpate station", sl.Err(err))
+		s.log.ErrorContext(ctx, op, "failed to udpate station", sl.Err(err))
 		return service.ErrInternalError
 	}
 
 	return nil
 }
 
-func (s *StationService) Delete(id uint64) error {
+func (s *StationService) Delete(ctx context.Context, id uint64) error {
 	const op = "services.StationService.Delete"
 
-	err := s.repo.Delete(id)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := s.repo.Delete(ctx, id)
 	if err != nil {
-		s.log.Error(op, "failed to delete station", slog.Uint64("id", id), sl.Err(err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "station not found", slog.Uint64("id", id))
+			return service.ErrStationNotFound
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			s.log.InfoContext(ctx, op, "station has bicycles", slog.Uint64("id", id))
+			return service.ErrStationInUse
+		}
+		s.log.ErrorContext(ctx, op, "failed to delete station", slog.Uint64("id", id), sl.Err(err))
 		return service.ErrInternalError
 	}
 
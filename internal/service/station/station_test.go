@@ -1,6 +1,7 @@
 package station_service_test
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"reflect"
@@ -11,6 +12,7 @@ import (
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
@@ -68,12 +70,12 @@ func TestStationService_Create(t *testing.T) {
 
 			switch tt.name {
 			case "success":
-				tt.fields.repo.(*mocks.StationRepositoty).On("Create", tt.argStation).Return(nil).Once()
+				tt.fields.repo.(*mocks.StationRepositoty).On("Create", mock.Anything, tt.argStation).Return(nil).Once()
 			case "repository error":
-				tt.fields.repo.(*mocks.StationRepositoty).On("Create", tt.argStation).Return(service.ErrInternalError).Once()
+				tt.fields.repo.(*mocks.StationRepositoty).On("Create", mock.Anything, tt.argStation).Return(service.ErrInternalError).Once()
 			}
 
-			got, err := s.Create(tt.argStation)
+			got, err := s.Create(context.Background(), tt.argStation)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("StationService.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -144,10 +146,10 @@ func TestStationService_UpdateLocation(t *testing.T) {
 			s := station_service.New(tt.fields.repo, tt.fields.log)
 
 			if !tt.mock.notNeeded {
-				tt.fields.repo.(*mocks.StationRepositoty).On("Update", tt.mock.arg).Return(tt.mock.resp).Once()
+				tt.fields.repo.(*mocks.StationRepositoty).On("Update", mock.Anything, tt.mock.arg).Return(tt.mock.resp).Once()
 			}
 
-			if err := s.UpdateLocation(tt.args.id, tt.args.location); (err != nil) != tt.wantErr {
+			if err := s.UpdateLocation(context.Background(), tt.args.id, tt.args.location); (err != nil) != tt.wantErr {
 				t.Errorf("StationService.UpdateLocation() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -209,10 +211,10 @@ func TestStationService_ByID(t *testing.T) {
 			s := station_service.New(tt.fields.repo, tt.fields.log)
 
 			if !tt.mock.notNeeded {
-				tt.fields.repo.(*mocks.StationRepositoty).On("GetByID", tt.argID).Return(tt.want, tt.mock.err).Once()
+				tt.fields.repo.(*mocks.StationRepositoty).On("GetByID", mock.Anything, tt.argID).Return(tt.want, tt.mock.err).Once()
 			}
 
-			got, err := s.ByID(tt.argID)
+			got, err := s.ByID(context.Background(), tt.argID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("StationService.ByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -223,3 +225,126 @@ func TestStationService_ByID(t *testing.T) {
 		})
 	}
 }
+
+func TestStationService_List(t *testing.T) {
+	type args struct {
+		page  int
+		limit int
+	}
+	type mockData struct {
+		offset   int
+		limit    int
+		stations []models.Station
+		listErr  error
+		total    int64
+		countErr error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    mockData
+		want    []models.Station
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{page: 2, limit: 10},
+			mock: mockData{
+				offset:   10,
+				limit:    10,
+				stations: []models.Station{{ID: 11}, {ID: 12}},
+				total:    12,
+			},
+			want:    []models.Station{{ID: 11}, {ID: 12}},
+			wantErr: false,
+		},
+		{
+			name: "defaults and clamping",
+			args: args{page: 0, limit: 1000},
+			mock: mockData{
+				offset: 0,
+				limit:  station_service.MaxPageSize,
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "repository error",
+			args: args{page: 1, limit: 10},
+			mock: mockData{
+				offset:  0,
+				limit:   10,
+				listErr: errors.New("unexpected error"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewStationRepositoty(t)
+			s := station_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			repo.On("List", mock.Anything, tt.mock.offset, tt.mock.limit).Return(tt.mock.stations, tt.mock.listErr).Once()
+			if tt.mock.listErr == nil {
+				repo.On("Count", mock.Anything).Return(tt.mock.total, tt.mock.countErr).Once()
+			}
+
+			got, total, err := s.List(context.Background(), tt.args.page, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StationService.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StationService.List() = %v, want %v", got, tt.want)
+			}
+			if total != tt.mock.total {
+				t.Errorf("StationService.List() total = %v, want %v", total, tt.mock.total)
+			}
+		})
+	}
+}
+
+func TestStationService_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		argID   uint64
+		mockErr error
+		wantErr error
+	}{
+		{
+			name:  "success",
+			argID: 1,
+		},
+		{
+			name:    "not found",
+			argID:   404,
+			mockErr: gorm.ErrRecordNotFound,
+			wantErr: service.ErrStationNotFound,
+		},
+		{
+			name:    "station has bicycles",
+			argID:   1,
+			mockErr: gorm.ErrForeignKeyViolated,
+			wantErr: service.ErrStationInUse,
+		},
+		{
+			name:    "unexpected error",
+			argID:   1,
+			mockErr: errors.New("unexpected error"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewStationRepositoty(t)
+			s := station_service.New(repo, slogdiscard.NewDiscardLogger())
+
+			repo.On("Delete", mock.Anything, tt.argID).Return(tt.mockErr).Once()
+
+			if err := s.Delete(context.Background(), tt.argID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("StationService.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
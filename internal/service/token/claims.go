@@ -0,0 +1,17 @@
+package token_service
+
+import "github.com/golang-jwt/jwt/v5"
+
+// PurposeMFAChallenge marks a token that only proves the password step of a login
+const PurposeMFAChallenge = "mfa_challenge"
+
+// Claims is the payload of the access token issued by TokenService.
+// Tokens with a Purpose are not access tokens and can't authenticate requests.
+type Claims struct {
+	UserID    uint64   `json:"user_id"`
+	Email     string   `json:"email"`
+	Roles     []string `json:"roles,omitempty"`
+	SessionID string   `json:"sid,omitempty"` // the session the token was issued to
+	Purpose   string   `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
@@ -0,0 +1,432 @@
+package token_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/keyset"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	refreshTokenSize = 32
+	familyIDSize     = 16
+
+	deviceNameLength = 64
+	userAgentLength  = 255
+	ipLength         = 64
+)
+
+//go:generate mockery --name=RefreshTokenRepository
+type RefreshTokenRepository interface {
+	CreateSession(ctx context.Context, session *models.Session, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error
+	SessionActive(ctx context.Context, id string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID uint64) error
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+}
+
+//go:generate mockery --name=RoleProvider
+type RoleProvider interface {
+	Roles(ctx context.Context, userID uint64) ([]string, error)
+}
+
+//go:generate mockery --name=SecondFactor
+type SecondFactor interface {
+	Status(ctx context.Context, userID uint64) (enabled, required bool, err error)
+}
+
+// Pair is a short-lived access token with the refresh token used to renew it.
+type Pair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// Device is where a session is signed in from, as the user sees it in the list of sessions
+type Device struct {
+	Name      string // given by the client, e.g. "Pixel 8"
+	UserAgent string
+	IP        string
+}
+
+type TokenService struct {
+	repo            RefreshTokenRepository
+	userRepo        UserRepository
+	roles           RoleProvider
+	mfa             SecondFactor
+	log             *slog.Logger
+	keys            *keyset.Set
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	challengeTTL    time.Duration
+}
+
+func New(repo RefreshTokenRepository, userRepo UserRepository, roles RoleProvider, mfa SecondFactor, log *slog.Logger, keys *keyset.Set, cfg config.Auth) *TokenService {
+	return &TokenService{
+		repo:            repo,
+		userRepo:        userRepo,
+		roles:           roles,
+		mfa:             mfa,
+		log:             log,
+		keys:            keys,
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+		challengeTTL:    cfg.MFAChallengeTTL,
+	}
+}
+
+// Issue starts a new session on the device, with a new refresh token family.
+// MFA tells that the user passed the second factor to sign in, the session keeps it for refreshes.
+func (s *TokenService) Issue(ctx context.Context, user *models.User, device Device, mfa bool) (*Pair, error) {
+	const op = "services.TokenService.Issue"
+
+	familyID, err := secure.RandomToken(familyIDSize)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate family id", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	refreshToken, err := s.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate refresh token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	session := &models.Session{
+		ID:         familyID,
+		UserID:     user.ID,
+		DeviceName: truncate(device.Name, deviceNameLength),
+		UserAgent:  truncate(device.UserAgent, userAgentLength),
+		IP:         truncate(device.IP, ipLength),
+		LastSeenAt: util.Ptr(time.Now()),
+		ExpiresAt:  refreshToken.model.ExpiresAt,
+	}
+	if mfa {
+		session.MFAAt = session.LastSeenAt
+	}
+	if err := s.repo.CreateSession(ctx, session, refreshToken.model); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to save session", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	accessToken, err := s.generateAccessToken(ctx, user, familyID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate access token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return &Pair{AccessToken: accessToken, RefreshToken: refreshToken.raw, ExpiresIn: s.accessTokenTTL}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair. The presented token is single-use:
+// presenting it again revokes the whole family it belongs to.
+func (s *TokenService) Refresh(ctx context.Context, token string) (*Pair, error) {
+	const op = "services.TokenService.Refresh"
+
+	current, err := s.repo.GetByHash(ctx, secure.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "refresh token not found", sl.Err(err))
+			return nil, service.ErrInvalidToken
+		}
+		s.log.ErrorContext(ctx, op, "failed to get refresh token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		s.log.WarnContext(ctx, op, "refresh token reuse detected", slog.Uint64("user_id", current.UserID), slog.String("family_id", current.FamilyID))
+		return nil, s.revokeReused(ctx, current)
+	}
+
+	if current.ExpiresAt.Before(time.Now()) {
+		s.log.InfoContext(ctx, op, "refresh token expired", slog.Uint64("user_id", current.UserID))
+		return nil, service.ErrExpiredToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if !user.CanSignIn() {
+		s.log.InfoContext(ctx, op, "user is not active", slog.Uint64("user_id", user.ID))
+		if err := s.repo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to revoke token family", sl.Err(err))
+		}
+		return nil, service.ErrInvalidToken
+	}
+
+	// MFA may have become required after the session was signed in without it,
+	// when the user enabled it or was made an admin under RequireForAdmins
+	passed, err := s.passedMFA(ctx, current.FamilyID, user.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to check mfa", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if !passed {
+		s.log.InfoContext(ctx, op, "session was signed in without required mfa", slog.Uint64("user_id", user.ID), slog.String("family_id", current.FamilyID))
+		if err := s.repo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to revoke token family", sl.Err(err))
+		}
+		return nil, service.ErrInvalidToken
+	}
+
+	next, err := s.newRefreshToken(current.UserID, current.FamilyID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate refresh token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	if err := s.repo.Rotate(ctx, current, next.model); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Token was used by a concurrent request
+			s.log.WarnContext(ctx, op, "refresh token reuse detected", slog.Uint64("user_id", current.UserID), slog.String("family_id", current.FamilyID))
+			return nil, s.revokeReused(ctx, current)
+		}
+		s.log.ErrorContext(ctx, op, "failed to rotate refresh token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	accessToken, err := s.generateAccessToken(ctx, user, current.FamilyID)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to generate access token", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+
+	return &Pair{AccessToken: accessToken, RefreshToken: next.raw, ExpiresIn: s.accessTokenTTL}, nil
+}
+
+// Revoke revokes the session of the refresh token, logging out the device that holds it.
+func (s *TokenService) Revoke(ctx context.Context, token string) error {
+	const op = "services.TokenService.Revoke"
+
+	current, err := s.repo.GetByHash(ctx, secure.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "refresh token not found", sl.Err(err))
+			return service.ErrInvalidToken
+		}
+		s.log.ErrorContext(ctx, op, "failed to get refresh token", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if err := s.repo.RevokeFamily(ctx, current.FamilyID); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to revoke token family", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// RevokeAll revokes every session of the user.
+func (s *TokenService) RevokeAll(ctx context.Context, userID uint64) error {
+	const op = "services.TokenService.RevokeAll"
+
+	if err := s.repo.RevokeAllForUser(ctx, userID); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to revoke user tokens", slog.Uint64("user_id", userID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// IssueChallenge signs a short-lived token for a user who passed the password step
+// and still has to present a second factor. It can't be used as an access token.
+func (s *TokenService) IssueChallenge(user *models.User) (string, time.Duration, error) {
+	const op = "services.TokenService.IssueChallenge"
+
+	now := time.Now()
+	claims := &Claims{
+		UserID:  user.ID,
+		Purpose: PurposeMFAChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.challengeTTL)),
+		},
+	}
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		s.log.Error(op, "failed to sign challenge", sl.Err(err))
+		return "", 0, service.ErrInternalError
+	}
+
+	return token, s.challengeTTL, nil
+}
+
+// ValidateChallenge returns the user a challenge token was issued to.
+func (s *TokenService) ValidateChallenge(tokenString string) (uint64, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if claims.Purpose != PurposeMFAChallenge {
+		s.log.Info("not a challenge token", slog.Uint64("user_id", claims.UserID))
+		return 0, service.ErrInvalidToken
+	}
+	return claims.UserID, nil
+}
+
+// ValidateToken parses the access token and returns its claims. The session of the token
+// is looked up on every call, so a revoked device is refused before its token expires.
+// It returns service.ErrExpiredToken for expired tokens and service.ErrInvalidToken otherwise.
+func (s *TokenService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		s.log.InfoContext(ctx, "not an access token", slog.String("purpose", claims.Purpose))
+		return nil, service.ErrInvalidToken
+	}
+
+	// Tokens issued before sessions were tracked have no sid, their refresh tokens get one
+	if claims.SessionID == "" {
+		s.log.InfoContext(ctx, "access token without session", slog.Uint64("user_id", claims.UserID))
+		return nil, service.ErrInvalidToken
+	}
+	active, err := s.repo.SessionActive(ctx, claims.SessionID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to check session", sl.Err(err))
+		return nil, service.ErrInternalError
+	}
+	if !active {
+		s.log.InfoContext(ctx, "session revoked", slog.Uint64("user_id", claims.UserID))
+		return nil, service.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (s *TokenService) parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	// Parse the token with the key named by its kid, the algorithm has to match the key
+	token, err := s.keys.Parse(tokenString, claims)
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			s.log.Info("token expired", slog.Uint64("user_id", claims.UserID))
+			return nil, service.ErrExpiredToken
+		}
+		s.log.Info("failed to parse token", sl.Err(err))
+		return nil, service.ErrInvalidToken
+	}
+
+	if !token.Valid {
+		s.log.Info("invalid token")
+		return nil, service.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (s *TokenService) revokeReused(ctx context.Context, token *models.RefreshToken) error {
+	if err := s.repo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		s.log.ErrorContext(ctx, "failed to revoke token family", slog.String("family_id", token.FamilyID), sl.Err(err))
+		return service.ErrInternalError
+	}
+	return service.ErrTokenReused
+}
+
+type refreshToken struct {
+	raw   string
+	model *models.RefreshToken
+}
+
+func (s *TokenService) newRefreshToken(userID uint64, familyID string) (*refreshToken, error) {
+	raw, err := secure.RandomToken(refreshTokenSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &refreshToken{
+		raw: raw,
+		model: &models.RefreshToken{
+			UserID:    userID,
+			FamilyID:  familyID,
+			TokenHash: secure.HashToken(raw),
+			ExpiresAt: util.Ptr(time.Now().Add(s.refreshTokenTTL)),
+		},
+	}, nil
+}
+
+// passedMFA tells whether the session passed the second factor or the user doesn't need one
+func (s *TokenService) passedMFA(ctx context.Context, sessionID string, userID uint64) (bool, error) {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if session.MFAAt != nil {
+		return true, nil
+	}
+
+	_, required, err := s.mfa.Status(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return !required, nil
+}
+
+func (s *TokenService) generateAccessToken(ctx context.Context, user *models.User, sessionID string) (string, error) {
+	// Define expiration time for the token
+	now := time.Now()
+	expirationTime := now.Add(s.accessTokenTTL)
+
+	var email string
+	if user.Email != nil {
+		email = *user.Email
+	}
+
+	// Roles are resolved on every issue so grants and revocations apply on the next refresh
+	roles, err := s.roles.Roles(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// Create claims (payload) for the token
+	claims := &Claims{
+		UserID:    user.ID,
+		Email:     email,
+		Roles:     roles,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+		},
+	}
+
+	// Sign the token with the current key of the set
+	return s.keys.Sign(claims)
+}
+
+// truncate cuts the value to max runes, an empty value is not stored
+func truncate(value string, max int) *string {
+	if value == "" {
+		return nil
+	}
+	if r := []rune(value); len(r) > max {
+		value = string(r[:max])
+	}
+	return &value
+}
@@ -0,0 +1,469 @@
+package token_service_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"reflect"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/service"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	mocks "sdt-bicycle-rental/internal/service/token/mocks"
+	"sdt-bicycle-rental/lib/keyset"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	secret     = "secret"
+	validEmail = "valid@email.com"
+)
+
+var authConfig = config.Auth{
+	AccessTokenTTL:  15 * time.Minute,
+	RefreshTokenTTL: 24 * time.Hour,
+	MFAChallengeTTL: 5 * time.Minute,
+}
+
+var device = token_service.Device{Name: "Pixel 8", UserAgent: "Mozilla/5.0", IP: "192.0.2.1"}
+
+func activeUser() *models.User {
+	return &models.User{
+		ID:     1,
+		Email:  util.Ptr(validEmail),
+		Status: util.Ptr(models.UserStatusActive),
+	}
+}
+
+// sharedKeys signs with the HS256 secret, as when no key pairs are configured
+func sharedKeys(t *testing.T) *keyset.Set {
+	keys, err := keyset.New(keyset.HMAC(secret))
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+	return keys
+}
+
+// riderRoles returns a role provider that may be asked for roles of any user.
+func riderRoles(t *testing.T) *mocks.RoleProvider {
+	roles := mocks.NewRoleProvider(t)
+	roles.On("Roles", mock.Anything, mock.Anything).Return([]string{models.RoleRider}, nil).Maybe()
+	return roles
+}
+
+func TestTokenService_Issue(t *testing.T) {
+	repo := mocks.NewRefreshTokenRepository(t)
+	s := token_service.New(repo, mocks.NewUserRepository(t), riderRoles(t), mocks.NewSecondFactor(t), slogdiscard.NewDiscardLogger(), sharedKeys(t), authConfig)
+
+	var saved *models.RefreshToken
+	var session *models.Session
+	repo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *models.Session) bool {
+		session = s
+		return s.UserID == 1 && s.ID != "" && *s.DeviceName == "Pixel 8" && *s.UserAgent == "Mozilla/5.0" && *s.IP == "192.0.2.1" && s.MFAAt == nil
+	}), mock.MatchedBy(func(token *models.RefreshToken) bool {
+		saved = token
+		return token.UserID == 1 && token.FamilyID != ""
+	})).Return(nil).Once()
+	repo.On("SessionActive", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	got, err := s.Issue(context.Background(), activeUser(), device, false)
+	if err != nil {
+		t.Fatalf("TokenService.Issue() error = %v", err)
+	}
+
+	if saved.TokenHash != secure.HashToken(got.RefreshToken) {
+		t.Errorf("TokenService.Issue() stored hash does not match refresh token")
+	}
+	if got.ExpiresIn != authConfig.AccessTokenTTL {
+		t.Errorf("TokenService.Issue() expires in = %v, want %v", got.ExpiresIn, authConfig.AccessTokenTTL)
+	}
+
+	claims, err := s.ValidateToken(context.Background(), got.AccessToken)
+	if err != nil {
+		t.Fatalf("TokenService.Issue() token validation error = %v", err)
+	}
+	if claims.UserID != 1 || claims.Email != validEmail || !reflect.DeepEqual(claims.Roles, []string{models.RoleRider}) {
+		t.Errorf("TokenService.Issue() claims = %v", claims)
+	}
+	// the session is the refresh token family
+	if claims.SessionID != session.ID || saved.FamilyID != session.ID {
+		t.Errorf("TokenService.Issue() sid = %v, family = %v, want %v", claims.SessionID, saved.FamilyID, session.ID)
+	}
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	const token = "refresh-token"
+	hash := secure.HashToken(token)
+
+	stored := func() *models.RefreshToken {
+		return &models.RefreshToken{
+			ID:        10,
+			UserID:    1,
+			FamilyID:  "family",
+			TokenHash: hash,
+			ExpiresAt: util.Ptr(time.Now().Add(time.Hour)),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				current := stored()
+				repo.On("GetByHash", mock.Anything, hash).Return(current, nil).Once()
+				userRepo.On("GetByID", mock.Anything, uint64(1)).Return(activeUser(), nil).Once()
+				repo.On("GetSession", mock.Anything, "family").Return(&models.Session{ID: "family"}, nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(false, false, nil).Once()
+				repo.On("Rotate", mock.Anything, current, mock.MatchedBy(func(next *models.RefreshToken) bool {
+					return next.FamilyID == "family" && next.TokenHash != hash
+				})).Return(nil).Once()
+				repo.On("SessionActive", mock.Anything, "family").Return(true, nil).Once()
+			},
+		},
+		{
+			name: "session passed mfa",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				current := stored()
+				repo.On("GetByHash", mock.Anything, hash).Return(current, nil).Once()
+				userRepo.On("GetByID", mock.Anything, uint64(1)).Return(activeUser(), nil).Once()
+				repo.On("GetSession", mock.Anything, "family").Return(&models.Session{ID: "family", MFAAt: util.Ptr(time.Now())}, nil).Once()
+				repo.On("Rotate", mock.Anything, current, mock.Anything).Return(nil).Once()
+				repo.On("SessionActive", mock.Anything, "family").Return(true, nil).Once()
+			},
+		},
+		{
+			// e.g. the user became an admin under RequireForAdmins after signing in
+			name: "mfa required since sign in revokes family",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				repo.On("GetByHash", mock.Anything, hash).Return(stored(), nil).Once()
+				userRepo.On("GetByID", mock.Anything, uint64(1)).Return(activeUser(), nil).Once()
+				repo.On("GetSession", mock.Anything, "family").Return(&models.Session{ID: "family"}, nil).Once()
+				mfa.On("Status", mock.Anything, uint64(1)).Return(false, true, nil).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "not found",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				repo.On("GetByHash", mock.Anything, hash).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				current := stored()
+				current.ExpiresAt = util.Ptr(time.Now().Add(-time.Hour))
+				repo.On("GetByHash", mock.Anything, hash).Return(current, nil).Once()
+			},
+			wantErr: service.ErrExpiredToken,
+		},
+		{
+			name: "reused token revokes family",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				current := stored()
+				current.UsedAt = util.Ptr(time.Now().Add(-time.Minute))
+				repo.On("GetByHash", mock.Anything, hash).Return(current, nil).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+			},
+			wantErr: service.ErrTokenReused,
+		},
+		{
+			name: "concurrent rotation revokes family",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				current := stored()
+				repo.On("GetByHash", mock.Anything, hash).Return(current, nil).Once()
+				userRepo.On("GetByID", mock.Anything, uint64(1)).Return(activeUser(), nil).Once()
+				repo.On("GetSession", mock.Anything, "family").Return(&models.Session{ID: "family", MFAAt: util.Ptr(time.Now())}, nil).Once()
+				repo.On("Rotate", mock.Anything, current, mock.Anything).Return(gorm.ErrRecordNotFound).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+			},
+			wantErr: service.ErrTokenReused,
+		},
+		{
+			name: "banned user",
+			setup: func(repo *mocks.RefreshTokenRepository, userRepo *mocks.UserRepository, mfa *mocks.SecondFactor) {
+				user := activeUser()
+				user.Status = util.Ptr(models.UserStatusBanned)
+				repo.On("GetByHash", mock.Anything, hash).Return(stored(), nil).Once()
+				userRepo.On("GetByID", mock.Anything, uint64(1)).Return(user, nil).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRefreshTokenRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+			mfa := mocks.NewSecondFactor(t)
+			tt.setup(repo, userRepo, mfa)
+
+			s := token_service.New(repo, userRepo, riderRoles(t), mfa, slogdiscard.NewDiscardLogger(), sharedKeys(t), authConfig)
+
+			got, err := s.Refresh(context.Background(), token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TokenService.Refresh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			if got.RefreshToken == "" || got.RefreshToken == token {
+				t.Errorf("TokenService.Refresh() refresh token was not rotated")
+			}
+			// the access token stays in the session of the family
+			if claims, err := s.ValidateToken(context.Background(), got.AccessToken); err != nil || claims.SessionID != "family" {
+				t.Errorf("TokenService.Refresh() access token = %+v, %v, want sid family", claims, err)
+			}
+		})
+	}
+}
+
+func TestTokenService_Revoke(t *testing.T) {
+	const token = "refresh-token"
+	hash := secure.HashToken(token)
+
+	tests := []struct {
+		name    string
+		setup   func(repo *mocks.RefreshTokenRepository)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(repo *mocks.RefreshTokenRepository) {
+				repo.On("GetByHash", mock.Anything, hash).Return(&models.RefreshToken{FamilyID: "family"}, nil).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(nil).Once()
+			},
+		},
+		{
+			name: "not found",
+			setup: func(repo *mocks.RefreshTokenRepository) {
+				repo.On("GetByHash", mock.Anything, hash).Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "repository error",
+			setup: func(repo *mocks.RefreshTokenRepository) {
+				repo.On("GetByHash", mock.Anything, hash).Return(&models.RefreshToken{FamilyID: "family"}, nil).Once()
+				repo.On("RevokeFamily", mock.Anything, "family").Return(errors.New("db is down")).Once()
+			},
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRefreshTokenRepository(t)
+			tt.setup(repo)
+
+			s := token_service.New(repo, mocks.NewUserRepository(t), riderRoles(t), mocks.NewSecondFactor(t), slogdiscard.NewDiscardLogger(), sharedKeys(t), authConfig)
+
+			if err := s.Revoke(context.Background(), token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TokenService.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenService_ValidateToken(t *testing.T) {
+	sign := func(t *testing.T, claims *token_service.Claims, key string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return token
+	}
+
+	validClaims := &token_service.Claims{
+		UserID:    1,
+		Email:     validEmail,
+		SessionID: "session",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	expiredClaims := &token_service.Claims{
+		UserID:    1,
+		Email:     validEmail,
+		SessionID: "session",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		revoked bool
+		wantErr error
+	}{
+		{
+			name:  "success",
+			token: sign(t, validClaims, secret),
+		},
+		{
+			name:    "revoked session",
+			token:   sign(t, validClaims, secret),
+			revoked: true,
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "no session",
+			token: sign(t, &token_service.Claims{
+				UserID: 1,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			}, secret),
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name:    "expired",
+			token:   sign(t, expiredClaims, secret),
+			wantErr: service.ErrExpiredToken,
+		},
+		{
+			name:    "wrong secret",
+			token:   sign(t, validClaims, "another secret"),
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name:    "malformed",
+			token:   "not a token",
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "mfa challenge",
+			token: sign(t, &token_service.Claims{
+				UserID:  1,
+				Purpose: token_service.PurposeMFAChallenge,
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+			}, secret),
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewRefreshTokenRepository(t)
+			if tt.name == "success" || tt.revoked {
+				repo.On("SessionActive", mock.Anything, "session").Return(!tt.revoked, nil).Once()
+			}
+			s := token_service.New(repo, mocks.NewUserRepository(t), mocks.NewRoleProvider(t), mocks.NewSecondFactor(t), slogdiscard.NewDiscardLogger(), sharedKeys(t), authConfig)
+
+			got, err := s.ValidateToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TokenService.ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr == nil && (got.UserID != validClaims.UserID || got.Email != validClaims.Email) {
+				t.Errorf("TokenService.ValidateToken() got = %v, want %v", got, validClaims)
+			}
+		})
+	}
+}
+
+func TestTokenService_Challenge(t *testing.T) {
+	s := token_service.New(mocks.NewRefreshTokenRepository(t), mocks.NewUserRepository(t), mocks.NewRoleProvider(t), mocks.NewSecondFactor(t), slogdiscard.NewDiscardLogger(), sharedKeys(t), authConfig)
+
+	challenge, expiresIn, err := s.IssueChallenge(activeUser())
+	if err != nil {
+		t.Fatalf("TokenService.IssueChallenge() error = %v", err)
+	}
+	if expiresIn != authConfig.MFAChallengeTTL {
+		t.Errorf("TokenService.IssueChallenge() expires in = %v, want %v", expiresIn, authConfig.MFAChallengeTTL)
+	}
+
+	userID, err := s.ValidateChallenge(challenge)
+	if err != nil || userID != 1 {
+		t.Errorf("TokenService.ValidateChallenge() = %v, %v, want 1", userID, err)
+	}
+
+	// an access token is not a challenge
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &token_service.Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, err := s.ValidateChallenge(access); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("TokenService.ValidateChallenge() access token error = %v, want %v", err, service.ErrInvalidToken)
+	}
+}
+
+func TestTokenService_KeyPair(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	key, err := keyset.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("keyset.ParsePEM() error = %v", err)
+	}
+	key.ID = "2025-01"
+	keys, err := keyset.New(key)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+
+	repo := mocks.NewRefreshTokenRepository(t)
+	repo.On("CreateSession", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	repo.On("SessionActive", mock.Anything, mock.Anything).Return(true, nil).Once()
+	s := token_service.New(repo, mocks.NewUserRepository(t), riderRoles(t), mocks.NewSecondFactor(t), slogdiscard.NewDiscardLogger(), keys, authConfig)
+
+	pair, err := s.Issue(context.Background(), activeUser(), device, false)
+	if err != nil {
+		t.Fatalf("TokenService.Issue() error = %v", err)
+	}
+	if _, err := s.ValidateToken(context.Background(), pair.AccessToken); err != nil {
+		t.Errorf("TokenService.ValidateToken() error = %v", err)
+	}
+
+	// another service checks the token with the public key alone
+	claims := &token_service.Claims{}
+	token, err := jwt.ParseWithClaims(pair.AccessToken, claims, func(token *jwt.Token) (any, error) {
+		if token.Header["kid"] != "2025-01" {
+			t.Errorf("TokenService.Issue() kid = %v, want 2025-01", token.Header["kid"])
+		}
+		return pub, nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	if err != nil || !token.Valid || claims.UserID != 1 {
+		t.Errorf("access token checked with the public key: %v, claims %+v", err, claims)
+	}
+
+	// tokens signed with the former shared secret are no longer accepted
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, err := s.ValidateToken(context.Background(), legacy); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("TokenService.ValidateToken() HS256 token error = %v, want %v", err, service.ErrInvalidToken)
+	}
+}
@@ -1,13 +1,15 @@
 package user_service
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/dto"
 	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/internal/tracing"
 	"sdt-bicycle-rental/lib/logger/sl"
-	"sdt-bicycle-rental/lib/validation"
+	"sdt-bicycle-rental/lib/util"
 
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
@@ -15,77 +17,180 @@ import (
 
 //go:generate mockery --name=UserRepository
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByID(id uint64) (*models.User, error)
-	GetByIDWithRelations(id uint64) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
-	AnonymizeAndMarkDeleted(id uint64) error
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+	GetByIDWithRelations(ctx context.Context, id uint64) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	AnonymizeAndMarkDeleted(ctx context.Context, id uint64) error
+}
+
+// Verifier sends a code to a changed email or phone
+//
+//go:generate mockery --name=Verifier
+type Verifier interface {
+	Restart(ctx context.Context, user *models.User, channel string) error
 }
 
 type UserService struct {
-	repo UserRepository
-	log  *slog.Logger
+	repo     UserRepository
+	verifier Verifier
+	log      *slog.Logger
 }
 
-func New(repo UserRepository, log *slog.Logger) *UserService {
-	return &UserService{repo: repo, log: log}
+func New(repo UserRepository, verifier Verifier, log *slog.Logger) *UserService {
+	return &UserService{repo: repo, verifier: verifier, log: log}
 }
 
-func (s *UserService) ProfileByID(id uint64) (*models.User, error) {
+func (s *UserService) ProfileByID(ctx context.Context, id uint64) (*models.User, error) {
 	const op = "services.UserService.ProfileByID"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	// Get user by ID
-	user, err := s.repo.GetByIDWithRelations(id)
+	user, err := s.repo.GetByIDWithRelations(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.log.Info(op, "user not found", slog.Uint64("id", id))
-			return nil, service.ErrInvalidCredentials
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+			return nil, service.ErrUserNotFound
 		}
 		// Handle other errors
-		s.log.Error(op, "failed to get user", sl.Err(err))
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
 		return nil, service.ErrInternalError
 	}
 
 	return user, nil
 }
 
-// TODO: return updated user
-func (s *UserService) Update(id uint64, user *dto.UpdateUser) error {
+// RequireVerified allows only active users, pending ones have to verify their email and phone first
+func (s *UserService) RequireVerified(ctx context.Context, id uint64) error {
+	const op = "services.UserService.RequireVerified"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	switch util.Deref(user.Status) {
+	case models.UserStatusActive:
+		return nil
+	case models.UserStatusPending:
+		s.log.InfoContext(ctx, op, "user is not verified", slog.Uint64("id", id))
+		return service.ErrAccountNotVerified
+	default:
+		s.log.InfoContext(ctx, op, "user is not active", slog.Uint64("id", id), slog.String("status", util.Deref(user.Status)))
+		return service.ErrForbidden
+	}
+}
+
+// Update applies the non-nil fields of user and returns the updated user. A changed email
+// or phone has to be verified again, the account is pending until it is.
+func (s *UserService) Update(ctx context.Context, id uint64, user *dto.UpdateUser) (*models.User, error) {
 	const op = "services.UserService.Update"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if user.Email != nil {
+		user.Email = util.Ptr(models.NormalizeEmail(*user.Email))
+	}
+
 	// Validate user
 	err := service.Validate.Struct(user)
 	if err != nil {
-		s.log.Error(op, "validation failed", slog.String("error", err.Error()))
-		return validation.PrettyError(err.(validator.ValidationErrors))
+		s.log.ErrorContext(ctx, op, "validation failed", slog.String("error", err.Error()))
+		return nil, service.Invalid(err.(validator.ValidationErrors))
 	}
 
-	updateUser := models.User{
-		ID:       id,
-		Name:     user.Name,
-		Lastname: user.Lastname,
-		Email:    user.Email,
-		Phone:    user.Phone,
+	var unverified []string
+
+	// Nothing to update, gorm would report zero affected rows
+	if !user.IsEmpty() {
+		current, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+				return nil, service.ErrUserNotFound
+			}
+			s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+			return nil, service.ErrInternalError
+		}
+		if user.Email != nil && *user.Email != util.Deref(current.Email) {
+			unverified = append(unverified, models.VerificationChannelEmail)
+		}
+		if user.Phone != nil && *user.Phone != util.Deref(current.Phone) {
+			unverified = append(unverified, models.VerificationChannelPhone)
+		}
+
+		updateUser := models.User{
+			ID:       id,
+			Name:     user.Name,
+			Lastname: user.Lastname,
+			Email:    user.Email,
+			Phone:    user.Phone,
+		}
+
+		// Update user
+		err = s.repo.Update(ctx, &updateUser)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+				return nil, service.ErrUserNotFound
+			}
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				s.log.InfoContext(ctx, op, "email or phone already taken", slog.Uint64("id", id))
+				return nil, service.ErrUserAlreadyExists
+			}
+			s.log.ErrorContext(ctx, op, "failed to update user", slog.String("error", err.Error()))
+			return nil, service.ErrInternalError
+		}
 	}
 
-	// Update user
-	err = s.repo.Update(&updateUser)
+	// Return updated user
+	updated, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		s.log.Error(op, "failed to update user", slog.String("error", err.Error()))
-		return service.ErrInternalError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+			return nil, service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get updated user", sl.Err(err))
+		return nil, service.ErrInternalError
 	}
 
-	return nil
+	// The user can ask for the code again, a failed delivery doesn't fail the update
+	for _, channel := range unverified {
+		s.log.InfoContext(ctx, op, "contact changed, verification restarted", slog.Uint64("id", id), slog.String("channel", channel))
+		if err := s.verifier.Restart(ctx, updated, channel); err != nil {
+			s.log.WarnContext(ctx, op, "verification not sent", slog.Uint64("id", id), sl.Err(err))
+		}
+	}
+
+	return updated, nil
 }
 
-func (s *UserService) Delete(id uint64) error {
+func (s *UserService) Delete(ctx context.Context, id uint64) error {
 	const op = "services.UserService.Delete"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	// Delete user
-	err := s.repo.AnonymizeAndMarkDeleted(id)
+	err := s.repo.AnonymizeAndMarkDeleted(ctx, id)
 	if err != nil {
-		s.log.Error(op, "failed to delete user", slog.String("error", err.Error()))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "user not found", slog.Uint64("id", id))
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to delete user", slog.String("error", err.Error()))
 		return service.ErrInternalError
 	}
 
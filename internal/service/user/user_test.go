@@ -1,16 +1,21 @@
 package user_service_test
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"reflect"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/service"
 	user_service "sdt-bicycle-rental/internal/service/user"
 	mocks "sdt-bicycle-rental/internal/service/user/mocks"
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
 	"sdt-bicycle-rental/lib/util"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
@@ -54,16 +59,16 @@ func TestUserService_ProfileByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := user_service.New(tt.fields.repo, tt.fields.log)
+			s := user_service.New(tt.fields.repo, mocks.NewVerifier(t), tt.fields.log)
 
 			switch tt.name {
 			case "success":
-				tt.fields.repo.(*mocks.UserRepository).On("GetByIDWithRelations", tt.argID).Return(tt.want, nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByIDWithRelations", mock.Anything, tt.argID).Return(tt.want, nil).Once()
 			case "not found":
-				tt.fields.repo.(*mocks.UserRepository).On("GetByIDWithRelations", tt.argID).Return(tt.want, gorm.ErrRecordNotFound).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByIDWithRelations", mock.Anything, tt.argID).Return(tt.want, gorm.ErrRecordNotFound).Once()
 			}
 
-			got, err := s.ProfileByID(tt.argID)
+			got, err := s.ProfileByID(context.Background(), tt.argID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UserService.ProfileByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -76,6 +81,52 @@ func TestUserService_ProfileByID(t *testing.T) {
 	}
 }
 
+func TestUserService_RequireVerified(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *models.User
+		getErr  error
+		wantErr error
+	}{
+		{
+			name: "active",
+			user: &models.User{ID: 1, Status: util.Ptr(models.UserStatusActive)},
+		},
+		{
+			name:    "pending",
+			user:    &models.User{ID: 1, Status: util.Ptr(models.UserStatusPending)},
+			wantErr: service.ErrAccountNotVerified,
+		},
+		{
+			name:    "banned",
+			user:    &models.User{ID: 1, Status: util.Ptr(models.UserStatusBanned)},
+			wantErr: service.ErrForbidden,
+		},
+		{
+			name:    "not found",
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrUserNotFound,
+		},
+		{
+			name:    "database error",
+			getErr:  errors.New("connection refused"),
+			wantErr: service.ErrInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewUserRepository(t)
+			s := user_service.New(repo, mocks.NewVerifier(t), slogdiscard.NewDiscardLogger())
+
+			repo.On("GetByID", mock.Anything, uint64(1)).Return(tt.user, tt.getErr).Once()
+
+			if err := s.RequireVerified(context.Background(), 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("UserService.RequireVerified() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestUserService_Update(t *testing.T) {
 	type fields struct {
 		repo user_service.UserRepository
@@ -110,6 +161,38 @@ func TestUserService_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "email and phone changed",
+			fields: defaultFields,
+			args: args{
+				id: 1,
+				user: &dto.UpdateUser{
+					Email: util.Ptr("new@email.com"),
+					Phone: util.Ptr("+380509876543"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "nothing to update",
+			fields: defaultFields,
+			args: args{
+				id:   1,
+				user: &dto.UpdateUser{},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "email taken",
+			fields: defaultFields,
+			args: args{
+				id: 1,
+				user: &dto.UpdateUser{
+					Email: util.Ptr("taken@email.com"),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:   "empty name",
 			fields: defaultFields,
@@ -137,7 +220,8 @@ func TestUserService_Update(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := user_service.New(tt.fields.repo, tt.fields.log)
+			verifier := mocks.NewVerifier(t)
+			s := user_service.New(tt.fields.repo, verifier, tt.fields.log)
 
 			updateModel := models.User{
 				ID:       tt.args.id,
@@ -147,13 +231,45 @@ func TestUserService_Update(t *testing.T) {
 				Phone:    tt.args.user.Phone,
 			}
 
+			current := &models.User{
+				ID:              tt.args.id,
+				Email:           util.Ptr("valid@email.com"),
+				Phone:           util.Ptr("+380501234567"),
+				Status:          util.Ptr(models.UserStatusActive),
+				EmailVerifiedAt: util.Ptr(time.Now()),
+				PhoneVerifiedAt: util.Ptr(time.Now()),
+			}
+			updated := &models.User{ID: tt.args.id, Name: util.Ptr("John")}
+
 			switch tt.name {
 			case "successfully":
-				tt.fields.repo.(*mocks.UserRepository).On("Update", &updateModel).Return(nil).Once()
+				// the email is the verified one, it stays verified
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(current, nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("Update", mock.Anything, &updateModel).Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(updated, nil).Once()
+			case "email and phone changed":
+				// the repository has made the user pending, both new addresses get a code
+				updated = &models.User{ID: tt.args.id, Email: tt.args.user.Email, Phone: tt.args.user.Phone, Status: util.Ptr(models.UserStatusPending)}
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(current, nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("Update", mock.Anything, &updateModel).Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(updated, nil).Once()
+				verifier.On("Restart", mock.Anything, updated, models.VerificationChannelEmail).Return(nil).Once()
+				verifier.On("Restart", mock.Anything, updated, models.VerificationChannelPhone).Return(service.ErrInternalError).Once()
+			case "nothing to update":
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(updated, nil).Once()
+			case "email taken":
+				tt.fields.repo.(*mocks.UserRepository).On("GetByID", mock.Anything, tt.args.id).Return(current, nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("Update", mock.Anything, &updateModel).Return(gorm.ErrDuplicatedKey).Once()
 			}
 
-			if err := s.Update(tt.args.id, tt.args.user); (err != nil) != tt.wantErr {
+			got, err := s.Update(context.Background(), tt.args.id, tt.args.user)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("UserService.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, updated) {
+				t.Errorf("UserService.Update() = %v, want %v", got, updated)
 			}
 		})
 	}
@@ -191,16 +307,16 @@ func TestUserService_Delete(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := user_service.New(tt.fields.repo, tt.fields.log)
+			s := user_service.New(tt.fields.repo, mocks.NewVerifier(t), tt.fields.log)
 
 			switch tt.name {
 			case "success":
-				tt.fields.repo.(*mocks.UserRepository).On("AnonymizeAndMarkDeleted", tt.argID).Return(nil).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("AnonymizeAndMarkDeleted", mock.Anything, tt.argID).Return(nil).Once()
 			case "not found":
-				tt.fields.repo.(*mocks.UserRepository).On("AnonymizeAndMarkDeleted", tt.argID).Return(gorm.ErrRecordNotFound).Once()
+				tt.fields.repo.(*mocks.UserRepository).On("AnonymizeAndMarkDeleted", mock.Anything, tt.argID).Return(gorm.ErrRecordNotFound).Once()
 			}
 
-			if err := s.Delete(tt.argID); (err != nil) != tt.wantErr {
+			if err := s.Delete(context.Background(), tt.argID); (err != nil) != tt.wantErr {
 				t.Errorf("UserService.Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
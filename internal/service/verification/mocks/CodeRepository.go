@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "sdt-bicycle-rental/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CodeRepository is an autogenerated mock type for the CodeRepository type
+type CodeRepository struct {
+	mock.Mock
+}
+
+// AddAttempt provides a mock function with given fields: ctx, id
+func (_m *CodeRepository) AddAttempt(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, code
+func (_m *CodeRepository) Create(ctx context.Context, code *models.VerificationCode) error {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.VerificationCode) error); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByHash provides a mock function with given fields: ctx, channel, hash
+func (_m *CodeRepository) GetByHash(ctx context.Context, channel string, hash string) (*models.VerificationCode, error) {
+	ret := _m.Called(ctx, channel, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *models.VerificationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.VerificationCode, error)); ok {
+		return rf(ctx, channel, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.VerificationCode); ok {
+		r0 = rf(ctx, channel, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.VerificationCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, channel, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Latest provides a mock function with given fields: ctx, userID, channel
+func (_m *CodeRepository) Latest(ctx context.Context, userID uint64, channel string) (*models.VerificationCode, error) {
+	ret := _m.Called(ctx, userID, channel)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Latest")
+	}
+
+	var r0 *models.VerificationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (*models.VerificationCode, error)); ok {
+		return rf(ctx, userID, channel)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) *models.VerificationCode); ok {
+		r0 = rf(ctx, userID, channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.VerificationCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = rf(ctx, userID, channel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, code
+func (_m *CodeRepository) Verify(ctx context.Context, code *models.VerificationCode) error {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.VerificationCode) error); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCodeRepository creates a new instance of CodeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCodeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CodeRepository {
+	mock := &CodeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,273 @@
+package verification_service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	emailTokenSize  = 32
+	phoneCodeDigits = 6
+)
+
+//go:generate mockery --name=CodeRepository
+type CodeRepository interface {
+	Create(ctx context.Context, code *models.VerificationCode) error
+	Latest(ctx context.Context, userID uint64, channel string) (*models.VerificationCode, error)
+	GetByHash(ctx context.Context, channel, hash string) (*models.VerificationCode, error)
+	AddAttempt(ctx context.Context, id uint64) error
+	Verify(ctx context.Context, code *models.VerificationCode) error
+}
+
+//go:generate mockery --name=UserRepository
+type UserRepository interface {
+	GetByID(ctx context.Context, id uint64) (*models.User, error)
+}
+
+// Notifier delivers the email link and the phone code
+//
+//go:generate mockery --name=Notifier
+type Notifier interface {
+	Send(ctx context.Context, msg notify.Message) error
+}
+
+type VerificationService struct {
+	repo     CodeRepository
+	users    UserRepository
+	notifier Notifier
+	log      *slog.Logger
+	cfg      config.Verify
+}
+
+func New(repo CodeRepository, users UserRepository, notifier Notifier, log *slog.Logger, cfg config.Verify) *VerificationService {
+	return &VerificationService{repo: repo, users: users, notifier: notifier, log: log, cfg: cfg}
+}
+
+type resendRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+}
+
+type emailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type phoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// Start sends the email link and the phone code to a newly registered user
+func (s *VerificationService) Start(ctx context.Context, user *models.User) error {
+	const op = "services.VerificationService.Start"
+
+	err := errors.Join(
+		s.send(ctx, user, models.VerificationChannelEmail),
+		s.send(ctx, user, models.VerificationChannelPhone),
+	)
+	if err != nil {
+		s.log.ErrorContext(ctx, op, "failed to send verification", slog.Uint64("user_id", user.ID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// Restart sends a code on the channel after the user changed its email or phone,
+// the new address has to be verified like the one given at registration
+func (s *VerificationService) Restart(ctx context.Context, user *models.User, channel string) error {
+	const op = "services.VerificationService.Restart"
+
+	if err := s.send(ctx, user, channel); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to send verification", slog.Uint64("user_id", user.ID), slog.String("channel", channel), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// Resend sends a new code on the channel, at most once per the resend interval
+func (s *VerificationService) Resend(ctx context.Context, userID uint64, channel string) error {
+	const op = "services.VerificationService.Resend"
+
+	if err := service.Validate.Struct(resendRequest{Channel: channel}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return service.ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, op, "failed to get user", sl.Err(err))
+		return service.ErrInternalError
+	}
+	if verifiedAt(user, channel) != nil {
+		s.log.InfoContext(ctx, op, "already verified", slog.Uint64("user_id", userID), slog.String("channel", channel))
+		return service.ErrAlreadyVerified
+	}
+
+	last, err := s.repo.Latest(ctx, userID, channel)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.log.ErrorContext(ctx, op, "failed to get last code", sl.Err(err))
+		return service.ErrInternalError
+	}
+	if last != nil && last.CreatedAt != nil && time.Since(*last.CreatedAt) < s.cfg.ResendInterval {
+		s.log.InfoContext(ctx, op, "resend throttled", slog.Uint64("user_id", userID), slog.String("channel", channel))
+		return service.ErrVerificationThrottled
+	}
+
+	if err := s.send(ctx, user, channel); err != nil {
+		s.log.ErrorContext(ctx, op, "failed to send verification", slog.Uint64("user_id", userID), sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return nil
+}
+
+// VerifyEmail confirms the email with the token from the emailed link
+func (s *VerificationService) VerifyEmail(ctx context.Context, token string) error {
+	const op = "services.VerificationService.VerifyEmail"
+
+	if err := service.Validate.Struct(emailRequest{Token: token}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	code, err := s.repo.GetByHash(ctx, models.VerificationChannelEmail, secure.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "unknown email token", slog.String("channel", models.VerificationChannelEmail))
+			return service.ErrInvalidVerificationCode
+		}
+		s.log.ErrorContext(ctx, op, "failed to get code", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	return s.verify(ctx, op, code)
+}
+
+// VerifyPhone confirms the phone of the user with the code sent by SMS.
+// A code accepts a limited number of wrong guesses, then a new one must be requested.
+func (s *VerificationService) VerifyPhone(ctx context.Context, userID uint64, code string) error {
+	const op = "services.VerificationService.VerifyPhone"
+
+	if err := service.Validate.Struct(phoneRequest{Code: code}); err != nil {
+		s.log.InfoContext(ctx, op, "validation error", sl.Err(err))
+		return service.Invalid(err.(validator.ValidationErrors))
+	}
+
+	last, err := s.repo.Latest(ctx, userID, models.VerificationChannelPhone)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "no phone code sent", slog.Uint64("user_id", userID))
+			return service.ErrInvalidVerificationCode
+		}
+		s.log.ErrorContext(ctx, op, "failed to get code", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	if last.Attempts >= s.cfg.MaxAttempts {
+		s.log.InfoContext(ctx, op, "phone code attempts exhausted", slog.Uint64("user_id", userID))
+		return service.ErrInvalidVerificationCode
+	}
+	if subtle.ConstantTimeCompare([]byte(last.CodeHash), []byte(secure.HashToken(code))) != 1 {
+		s.log.InfoContext(ctx, op, "wrong phone code", slog.Uint64("user_id", userID), slog.Int("attempts", last.Attempts+1))
+		if err := s.repo.AddAttempt(ctx, last.ID); err != nil {
+			s.log.ErrorContext(ctx, op, "failed to record attempt", sl.Err(err))
+			return service.ErrInternalError
+		}
+		return service.ErrInvalidVerificationCode
+	}
+
+	return s.verify(ctx, op, last)
+}
+
+func (s *VerificationService) verify(ctx context.Context, op string, code *models.VerificationCode) error {
+	if code.UsedAt != nil || code.ExpiresAt.Before(time.Now()) {
+		s.log.InfoContext(ctx, op, "code used or expired", slog.Uint64("user_id", code.UserID), slog.String("channel", code.Channel))
+		return service.ErrInvalidVerificationCode
+	}
+
+	if err := s.repo.Verify(ctx, code); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.InfoContext(ctx, op, "code used concurrently", slog.Uint64("user_id", code.UserID))
+			return service.ErrInvalidVerificationCode
+		}
+		s.log.ErrorContext(ctx, op, "failed to verify", sl.Err(err))
+		return service.ErrInternalError
+	}
+
+	s.log.InfoContext(ctx, op, "verified", slog.Uint64("user_id", code.UserID), slog.String("channel", code.Channel))
+	return nil
+}
+
+// send stores a new code for the channel and delivers it
+func (s *VerificationService) send(ctx context.Context, user *models.User, channel string) error {
+	var (
+		secret string
+		ttl    time.Duration
+		msg    notify.Message
+		err    error
+	)
+
+	switch channel {
+	case models.VerificationChannelEmail:
+		if secret, err = secure.RandomToken(emailTokenSize); err != nil {
+			return err
+		}
+		ttl = s.cfg.EmailTTL
+		msg = notify.Message{
+			Channel: notify.Email,
+			To:      util.Deref(user.Email),
+			Subject: "Confirm your email",
+			Body: fmt.Sprintf("Follow the link within %s to confirm your email:\n%s?token=%s",
+				ttl, s.cfg.EmailURL, url.QueryEscape(secret)),
+		}
+	case models.VerificationChannelPhone:
+		if secret, err = secure.RandomDigits(phoneCodeDigits); err != nil {
+			return err
+		}
+		ttl = s.cfg.PhoneTTL
+		msg = notify.Message{
+			Channel: notify.SMS,
+			To:      util.Deref(user.Phone),
+			Body:    fmt.Sprintf("Your bicycle rental code: %s. It expires in %s.", secret, ttl),
+		}
+	default:
+		return fmt.Errorf("unknown verification channel %q", channel)
+	}
+
+	code := &models.VerificationCode{
+		UserID:    user.ID,
+		Channel:   channel,
+		CodeHash:  secure.HashToken(secret),
+		ExpiresAt: util.Ptr(time.Now().Add(ttl)),
+	}
+	if err := s.repo.Create(ctx, code); err != nil {
+		return err
+	}
+
+	return s.notifier.Send(ctx, msg)
+}
+
+func verifiedAt(user *models.User, channel string) *time.Time {
+	if channel == models.VerificationChannelPhone {
+		return user.PhoneVerifiedAt
+	}
+	return user.EmailVerifiedAt
+}
@@ -0,0 +1,346 @@
+package verification_service_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/service"
+	verification_service "sdt-bicycle-rental/internal/service/verification"
+	mocks "sdt-bicycle-rental/internal/service/verification/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/secure"
+	"sdt-bicycle-rental/lib/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var verifyConfig = config.Verify{
+	EmailURL:       "https://app.example.com/verify-email",
+	EmailTTL:       24 * time.Hour,
+	PhoneTTL:       10 * time.Minute,
+	ResendInterval: time.Minute,
+	MaxAttempts:    3,
+}
+
+func newUser() *models.User {
+	return &models.User{
+		ID:     1,
+		Email:  util.Ptr("valid@email.com"),
+		Phone:  util.Ptr("+380501234567"),
+		Status: util.Ptr(models.UserStatusPending),
+	}
+}
+
+func TestVerificationService_Start(t *testing.T) {
+	repo := mocks.NewCodeRepository(t)
+	notifier := mocks.NewNotifier(t)
+	s := verification_service.New(repo, mocks.NewUserRepository(t), notifier, slogdiscard.NewDiscardLogger(), verifyConfig)
+
+	saved := map[string]*models.VerificationCode{}
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*models.VerificationCode")).
+		Run(func(args mock.Arguments) {
+			code := args.Get(1).(*models.VerificationCode)
+			saved[code.Channel] = code
+		}).Return(nil).Twice()
+
+	sent := map[string]notify.Message{}
+	notifier.On("Send", mock.Anything, mock.AnythingOfType("notify.Message")).
+		Run(func(args mock.Arguments) {
+			msg := args.Get(1).(notify.Message)
+			sent[msg.Channel] = msg
+		}).Return(nil).Twice()
+
+	if err := s.Start(context.Background(), newUser()); err != nil {
+		t.Fatalf("VerificationService.Start() error = %v", err)
+	}
+
+	// the link and the code are sent raw, only their hashes are stored
+	email := sent[notify.Email]
+	if email.To != "valid@email.com" {
+		t.Errorf("VerificationService.Start() email sent to %q", email.To)
+	}
+	_, link, found := strings.Cut(email.Body, verifyConfig.EmailURL+"?token=")
+	if !found {
+		t.Fatalf("VerificationService.Start() email has no link: %q", email.Body)
+	}
+	token, err := url.QueryUnescape(strings.Fields(link)[0])
+	if err != nil {
+		t.Fatalf("verification link token: %v", err)
+	}
+	if saved[models.VerificationChannelEmail].CodeHash != secure.HashToken(token) {
+		t.Errorf("VerificationService.Start() stored hash doesn't match the emailed token")
+	}
+
+	sms := sent[notify.SMS]
+	if sms.To != "+380501234567" {
+		t.Errorf("VerificationService.Start() SMS sent to %q", sms.To)
+	}
+	phoneCode := saved[models.VerificationChannelPhone]
+	if window := time.Until(*phoneCode.ExpiresAt); window > verifyConfig.PhoneTTL || window < verifyConfig.PhoneTTL-time.Minute {
+		t.Errorf("VerificationService.Start() phone code expires in %s", window)
+	}
+	var matched bool
+	for _, word := range strings.Fields(sms.Body) {
+		if phoneCode.CodeHash == secure.HashToken(strings.TrimSuffix(word, ".")) {
+			matched = true
+		}
+	}
+	if !matched {
+		t.Errorf("VerificationService.Start() stored hash doesn't match the SMS code: %q", sms.Body)
+	}
+}
+
+func TestVerificationService_Restart(t *testing.T) {
+	repo := mocks.NewCodeRepository(t)
+	notifier := mocks.NewNotifier(t)
+	s := verification_service.New(repo, mocks.NewUserRepository(t), notifier, slogdiscard.NewDiscardLogger(), verifyConfig)
+
+	// only the changed channel, and no resend throttle
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(code *models.VerificationCode) bool {
+		return code.Channel == models.VerificationChannelPhone && code.UserID == 1
+	})).Return(nil).Once()
+	notifier.On("Send", mock.Anything, mock.MatchedBy(func(msg notify.Message) bool {
+		return msg.Channel == notify.SMS && msg.To == "+380501234567"
+	})).Return(nil).Once()
+
+	if err := s.Restart(context.Background(), newUser(), models.VerificationChannelPhone); err != nil {
+		t.Fatalf("VerificationService.Restart() error = %v", err)
+	}
+
+	notifier.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable")).Once()
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	if err := s.Restart(context.Background(), newUser(), models.VerificationChannelEmail); !errors.Is(err, service.ErrInternalError) {
+		t.Errorf("VerificationService.Restart() error = %v, want %v", err, service.ErrInternalError)
+	}
+}
+
+func TestVerificationService_Resend(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  string
+		user     *models.User
+		getErr   error
+		lastSent time.Duration
+		sent     bool
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			channel:  models.VerificationChannelPhone,
+			user:     newUser(),
+			lastSent: 2 * time.Minute,
+			sent:     true,
+		},
+		{
+			name:    "nothing sent yet",
+			channel: models.VerificationChannelEmail,
+			user:    newUser(),
+			sent:    true,
+		},
+		{
+			name:    "unknown channel",
+			channel: "pigeon",
+			wantErr: service.ErrValidation,
+		},
+		{
+			name:    "already verified",
+			channel: models.VerificationChannelEmail,
+			user: func() *models.User {
+				u := newUser()
+				u.EmailVerifiedAt = util.Ptr(time.Now())
+				return u
+			}(),
+			wantErr: service.ErrAlreadyVerified,
+		},
+		{
+			name:     "too soon",
+			channel:  models.VerificationChannelPhone,
+			user:     newUser(),
+			lastSent: 10 * time.Second,
+			wantErr:  service.ErrVerificationThrottled,
+		},
+		{
+			name:    "user not found",
+			channel: models.VerificationChannelPhone,
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewCodeRepository(t)
+			users := mocks.NewUserRepository(t)
+			notifier := mocks.NewNotifier(t)
+			s := verification_service.New(repo, users, notifier, slogdiscard.NewDiscardLogger(), verifyConfig)
+
+			if tt.wantErr != service.ErrValidation {
+				users.On("GetByID", mock.Anything, uint64(1)).Return(tt.user, tt.getErr).Once()
+			}
+			if tt.user != nil && tt.wantErr != service.ErrAlreadyVerified {
+				var last *models.VerificationCode
+				lastErr := gorm.ErrRecordNotFound
+				if tt.lastSent != 0 {
+					last, lastErr = &models.VerificationCode{CreatedAt: util.Ptr(time.Now().Add(-tt.lastSent))}, nil
+				}
+				repo.On("Latest", mock.Anything, uint64(1), tt.channel).Return(last, lastErr).Once()
+			}
+			if tt.sent {
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.VerificationCode) bool {
+					return c.UserID == 1 && c.Channel == tt.channel
+				})).Return(nil).Once()
+				notifier.On("Send", mock.Anything, mock.AnythingOfType("notify.Message")).Return(nil).Once()
+			}
+
+			if err := s.Resend(context.Background(), 1, tt.channel); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerificationService.Resend() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerificationService_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		code      *models.VerificationCode
+		getErr    error
+		verifyErr error
+		verified  bool
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			token:    "token",
+			code:     &models.VerificationCode{ID: 5, UserID: 1, Channel: models.VerificationChannelEmail, ExpiresAt: util.Ptr(time.Now().Add(time.Hour))},
+			verified: true,
+		},
+		{
+			name:    "missing token",
+			wantErr: service.ErrValidation,
+		},
+		{
+			name:    "unknown token",
+			token:   "token",
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+		{
+			name:    "expired token",
+			token:   "token",
+			code:    &models.VerificationCode{ID: 5, UserID: 1, Channel: models.VerificationChannelEmail, ExpiresAt: util.Ptr(time.Now().Add(-time.Minute))},
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+		{
+			name:    "used token",
+			token:   "token",
+			code:    &models.VerificationCode{ID: 5, UserID: 1, Channel: models.VerificationChannelEmail, ExpiresAt: util.Ptr(time.Now().Add(time.Hour)), UsedAt: util.Ptr(time.Now())},
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+		{
+			name:      "used concurrently",
+			token:     "token",
+			code:      &models.VerificationCode{ID: 5, UserID: 1, Channel: models.VerificationChannelEmail, ExpiresAt: util.Ptr(time.Now().Add(time.Hour))},
+			verifyErr: gorm.ErrRecordNotFound,
+			verified:  true,
+			wantErr:   service.ErrInvalidVerificationCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewCodeRepository(t)
+			s := verification_service.New(repo, mocks.NewUserRepository(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), verifyConfig)
+
+			if tt.token != "" {
+				repo.On("GetByHash", mock.Anything, models.VerificationChannelEmail, secure.HashToken(tt.token)).Return(tt.code, tt.getErr).Once()
+			}
+			if tt.verified {
+				repo.On("Verify", mock.Anything, tt.code).Return(tt.verifyErr).Once()
+			}
+
+			if err := s.VerifyEmail(context.Background(), tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerificationService.VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerificationService_VerifyPhone(t *testing.T) {
+	latest := func(attempts int) *models.VerificationCode {
+		return &models.VerificationCode{
+			ID:        7,
+			UserID:    1,
+			Channel:   models.VerificationChannelPhone,
+			CodeHash:  secure.HashToken("123456"),
+			Attempts:  attempts,
+			ExpiresAt: util.Ptr(time.Now().Add(5 * time.Minute)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		latest   *models.VerificationCode
+		getErr   error
+		attempt  bool
+		verified bool
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			code:     "123456",
+			latest:   latest(0),
+			verified: true,
+		},
+		{
+			name:    "not a code",
+			code:    "12ab56",
+			wantErr: service.ErrValidation,
+		},
+		{
+			name:    "wrong code counts an attempt",
+			code:    "654321",
+			latest:  latest(1),
+			attempt: true,
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+		{
+			name:    "attempts exhausted",
+			code:    "123456",
+			latest:  latest(verifyConfig.MaxAttempts),
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+		{
+			name:    "nothing sent",
+			code:    "123456",
+			getErr:  gorm.ErrRecordNotFound,
+			wantErr: service.ErrInvalidVerificationCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewCodeRepository(t)
+			s := verification_service.New(repo, mocks.NewUserRepository(t), mocks.NewNotifier(t), slogdiscard.NewDiscardLogger(), verifyConfig)
+
+			if tt.wantErr != service.ErrValidation {
+				repo.On("Latest", mock.Anything, uint64(1), models.VerificationChannelPhone).Return(tt.latest, tt.getErr).Once()
+			}
+			if tt.attempt {
+				repo.On("AddAttempt", mock.Anything, uint64(7)).Return(nil).Once()
+			}
+			if tt.verified {
+				repo.On("Verify", mock.Anything, tt.latest).Return(nil).Once()
+			}
+
+			if err := s.VerifyPhone(context.Background(), 1, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerificationService.VerifyPhone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
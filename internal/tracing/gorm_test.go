@@ -0,0 +1,46 @@
+package tracing_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGormPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// DryRun builds statements without a database, the callbacks still run
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(tracing.GormPlugin{}))
+
+	// outside of a traced call
+	var station models.Station
+	db.First(&station, 1)
+	require.Empty(t, recorder.Ended())
+
+	ctx, parent := tracing.Start(context.Background(), "services.StationService.ByID")
+	db.WithContext(ctx).First(&station, 1)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "gorm.query stations", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), query.SpanContext().TraceID())
+}
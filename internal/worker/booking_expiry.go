@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sdt-bicycle-rental/internal/health"
+	"sdt-bicycle-rental/lib/logger/sl"
+	"time"
+)
+
+//go:generate mockery --name=BookingExpirer
+type BookingExpirer interface {
+	ExpireDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// BookingExpiry periodically releases bookings whose hold has ended
+type BookingExpiry struct {
+	expirer   BookingExpirer
+	interval  time.Duration
+	heartbeat *health.Heartbeat
+	log       *slog.Logger
+}
+
+func NewBookingExpiry(expirer BookingExpirer, interval time.Duration, log *slog.Logger) *BookingExpiry {
+	return &BookingExpiry{
+		expirer:   expirer,
+		interval:  interval,
+		heartbeat: health.NewHeartbeat(3 * interval), // tolerate a couple of slow sweeps
+		log:       log,
+	}
+}
+
+// Health reports whether the worker is running and its last sweep succeeded
+func (w *BookingExpiry) Health() health.Checker {
+	return w.heartbeat
+}
+
+// Run releases expired bookings every interval until ctx is cancelled
+func (w *BookingExpiry) Run(ctx context.Context) {
+	const op = "worker.BookingExpiry.Run"
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.Info(op, "booking expiry worker started", slog.Duration("interval", w.interval))
+	w.heartbeat.Beat(nil)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Info(op, "booking expiry worker stopped", sl.Err(ctx.Err()))
+			return
+		case now := <-ticker.C:
+			_, err := w.expirer.ExpireDue(ctx, now)
+			if err != nil {
+				w.log.Error(op, "failed to expire bookings", sl.Err(err))
+			}
+			w.heartbeat.Beat(err)
+		}
+	}
+}
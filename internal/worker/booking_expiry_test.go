@@ -0,0 +1,69 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"sdt-bicycle-rental/internal/health"
+	"sdt-bicycle-rental/internal/worker"
+	"sdt-bicycle-rental/internal/worker/mocks"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBookingExpiry_Run(t *testing.T) {
+	expirer := mocks.NewBookingExpirer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ticks := 0
+	expirer.On("ExpireDue", mock.Anything, mock.AnythingOfType("time.Time")).Return(1, nil).Run(func(args mock.Arguments) {
+		ticks++
+		if ticks == 2 {
+			cancel()
+		}
+	})
+
+	w := worker.NewBookingExpiry(expirer, 5*time.Millisecond, slogdiscard.NewDiscardLogger())
+	if err := w.Health().Check(context.Background()); !errors.Is(err, health.ErrNotStarted) {
+		t.Errorf("Health() before Run = %v, want %v", err, health.ErrNotStarted)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancellation")
+	}
+
+	if ticks != 2 {
+		t.Errorf("ExpireDue called %d times, want 2", ticks)
+	}
+	if err := w.Health().Check(context.Background()); err != nil {
+		t.Errorf("Health() after Run = %v, want nil", err)
+	}
+}
+
+func TestBookingExpiry_HealthReportsFailedSweep(t *testing.T) {
+	expirer := mocks.NewBookingExpirer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sweepErr := errors.New("connection refused")
+	expirer.On("ExpireDue", mock.Anything, mock.AnythingOfType("time.Time")).Return(0, sweepErr).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	w := worker.NewBookingExpiry(expirer, 5*time.Millisecond, slogdiscard.NewDiscardLogger())
+	w.Run(ctx)
+
+	if err := w.Health().Check(context.Background()); !errors.Is(err, sweepErr) {
+		t.Errorf("Health() = %v, want %v", err, sweepErr)
+	}
+}
@@ -0,0 +1,283 @@
+// Package keyset signs and validates JWTs with a set of keys identified by kid.
+// Several keys can be valid at once so that tokens signed before a rotation keep
+// validating until they expire.
+package keyset
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing keys
+const minRSABits = 2048
+
+var (
+	ErrNoSigningKey = errors.New("no key can sign now")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Key is one key of the set with its rotation window. The newest key whose SignFrom
+// has passed signs new tokens, every key validates until its VerifyUntil (zero is forever).
+type Key struct {
+	ID          string
+	SignFrom    time.Time
+	VerifyUntil time.Time
+
+	method  jwt.SigningMethod
+	private crypto.PrivateKey // nil when only the public key is known
+	public  crypto.PublicKey
+}
+
+// HMAC returns a shared secret key (HS256). It is never published in the JWKS,
+// every party validating its tokens has to hold the secret.
+func HMAC(secret string) *Key {
+	return &Key{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+}
+
+// ParsePEM reads an RSA (RS256) or Ed25519 (EdDSA) key. A private key both signs and validates,
+// a public key only validates, which is enough for a retired key.
+func ParsePEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key is %d bits, at least %d required", k.N.BitLen(), minRSABits)
+		}
+		return &Key{method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key is %d bits, at least %d required", k.N.BitLen(), minRSABits)
+		}
+		return &Key{method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// ParseJWK reads a public key published by another issuer, RSA (RS256) and Ed25519 (EdDSA) are supported
+func ParseJWK(jwk JWK) (*Key, error) {
+	switch {
+	case jwk.Kty == "RSA" && (jwk.Alg == "" || jwk.Alg == jwt.SigningMethodRS256.Alg()):
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < minRSABits || pub.E < 3 {
+			return nil, fmt.Errorf("weak RSA key %q", jwk.Kid)
+		}
+		return &Key{ID: jwk.Kid, method: jwt.SigningMethodRS256, public: pub}, nil
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key %q", jwk.Kid)
+		}
+		return &Key{ID: jwk.Kid, method: jwt.SigningMethodEdDSA, public: ed25519.PublicKey(x)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key %q: kty %s alg %s", jwk.Kid, jwk.Kty, jwk.Alg)
+	}
+}
+
+// LoadFile reads a PEM key from the file
+func LoadFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePEM(data)
+}
+
+// Alg is the JWT alg the key signs with
+func (k *Key) Alg() string {
+	return k.method.Alg()
+}
+
+func (k *Key) canSign(now time.Time) bool {
+	return k.private != nil && !k.SignFrom.After(now) && k.validAt(now)
+}
+
+func (k *Key) validAt(now time.Time) bool {
+	return k.VerifyUntil.IsZero() || now.Before(k.VerifyUntil)
+}
+
+type Set struct {
+	keys []*Key
+}
+
+// New checks the keys and builds the set. Key ids must be unique, only a lone
+// HMAC key may go without one. Some key has to be able to sign right away.
+func New(keys ...*Key) (*Set, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" && (len(keys) > 1 || k.method != jwt.SigningMethodHS256) {
+			return nil, fmt.Errorf("%s key without kid", k.Alg())
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate kid %q", k.ID)
+		}
+		seen[k.ID] = true
+	}
+
+	s := &Set{keys: keys}
+	if _, err := s.signer(time.Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Verifier returns a set that only validates tokens, e.g. with the keys of another issuer
+func Verifier(keys ...*Key) *Set {
+	return &Set{keys: keys}
+}
+
+// Sign signs the claims with the current signing key and names it in the kid header
+func (s *Set) Sign(claims jwt.Claims) (string, error) {
+	key, err := s.signer(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.private)
+}
+
+// Parse validates the token with the key named by its kid and fills the claims
+func (s *Set) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods(s.algs()))
+	return jwt.ParseWithClaims(tokenString, claims, s.keyfunc, opts...)
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that still validate, including ones not signing yet,
+// so that other services have them cached before the first token signed with them
+func (s *Set) JWKS() JWKS {
+	now := time.Now()
+	jwks := JWKS{Keys: []JWK{}}
+	for _, k := range s.keys {
+		if !k.validAt(now) {
+			continue
+		}
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: k.ID,
+				Use: "sig",
+				Alg: k.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: k.ID,
+				Use: "sig",
+				Alg: k.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return jwks
+}
+
+func (s *Set) signer(now time.Time) (*Key, error) {
+	var signer *Key
+	for _, k := range s.keys {
+		if k.canSign(now) && (signer == nil || k.SignFrom.After(signer.SignFrom)) {
+			signer = k
+		}
+	}
+	if signer == nil {
+		return nil, ErrNoSigningKey
+	}
+	return signer, nil
+}
+
+func (s *Set) keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	i := slices.IndexFunc(s.keys, func(k *Key) bool { return k.ID == kid })
+	if i < 0 {
+		return nil, ErrUnknownKey
+	}
+
+	key := s.keys[i]
+	if key.Alg() != token.Method.Alg() || !key.validAt(time.Now()) {
+		return nil, ErrUnknownKey
+	}
+	return key.public, nil
+}
+
+func (s *Set) algs() []string {
+	algs := make([]string, 0, len(s.keys))
+	for _, k := range s.keys {
+		if !slices.Contains(algs, k.Alg()) {
+			algs = append(algs, k.Alg())
+		}
+	}
+	return algs
+}
@@ -0,0 +1,247 @@
+package keyset_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sdt-bicycle-rental/lib/keyset"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func rsaKey(t *testing.T) (*rsa.PrivateKey, *keyset.Key) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	key, err := keyset.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("keyset.ParsePEM() error = %v", err)
+	}
+	return priv, key
+}
+
+func edKey(t *testing.T, public bool) (ed25519.PublicKey, *keyset.Key) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	block := &pem.Block{Type: "PRIVATE KEY"}
+	if public {
+		block.Type = "PUBLIC KEY"
+		block.Bytes, err = x509.MarshalPKIXPublicKey(pub)
+	} else {
+		block.Bytes, err = x509.MarshalPKCS8PrivateKey(priv)
+	}
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	key, err := keyset.ParsePEM(pem.EncodeToMemory(block))
+	if err != nil {
+		t.Fatalf("keyset.ParsePEM() error = %v", err)
+	}
+	return pub, key
+}
+
+func claims() *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+}
+
+func TestSet_Rotation(t *testing.T) {
+	_, old := rsaKey(t)
+	old.ID = "old"
+	_, current := edKey(t, false)
+	current.ID, current.SignFrom = "current", time.Now().Add(-time.Minute)
+	_, next := edKey(t, false)
+	next.ID, next.SignFrom = "next", time.Now().Add(time.Hour)
+
+	oldSet, err := keyset.New(old)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+	oldToken, err := oldSet.Sign(claims())
+	if err != nil {
+		t.Fatalf("Set.Sign() error = %v", err)
+	}
+
+	// after the rotation the old key still validates, the newest started key signs
+	set, err := keyset.New(old, current, next)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+	token, err := set.Sign(claims())
+	if err != nil {
+		t.Fatalf("Set.Sign() error = %v", err)
+	}
+	parsed, err := set.Parse(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("Set.Parse() error = %v", err)
+	}
+	if parsed.Header["kid"] != "current" || parsed.Method.Alg() != "EdDSA" {
+		t.Errorf("Set.Sign() signed with %v %v, want current EdDSA", parsed.Header["kid"], parsed.Method.Alg())
+	}
+	if _, err := set.Parse(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Errorf("Set.Parse() token of the old key error = %v", err)
+	}
+
+	// once retired it doesn't
+	old.VerifyUntil = time.Now().Add(-time.Second)
+	if _, err := set.Parse(oldToken, &jwt.RegisteredClaims{}); !errors.Is(err, keyset.ErrUnknownKey) {
+		t.Errorf("Set.Parse() token of a retired key error = %v, want %v", err, keyset.ErrUnknownKey)
+	}
+}
+
+func TestSet_Parse(t *testing.T) {
+	_, signer := edKey(t, false)
+	signer.ID = "a"
+	set, err := keyset.New(signer)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+
+	_, other := edKey(t, false)
+	other.ID = "b"
+	otherSet, _ := keyset.New(other)
+	unknown, _ := otherSet.Sign(claims())
+
+	// a token signed with the shared secret can't pass for one of ours
+	hmac, _ := keyset.New(keyset.HMAC("secret"))
+	hmacToken, _ := hmac.Sign(claims())
+
+	other.ID = "a"
+	forged, _ := otherSet.Sign(claims())
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "unknown kid", token: unknown},
+		{name: "wrong algorithm", token: hmacToken},
+		{name: "wrong key", token: forged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := set.Parse(tt.token, &jwt.RegisteredClaims{}); err == nil {
+				t.Errorf("Set.Parse() accepted the token")
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, public := edKey(t, true)
+	public.ID = "public"
+	_, future := edKey(t, false)
+	future.ID, future.SignFrom = "future", time.Now().Add(time.Hour)
+	_, noID := edKey(t, false)
+	_, a := edKey(t, false)
+	a.ID = "a"
+	_, b := edKey(t, false)
+	b.ID = "a"
+
+	tests := []struct {
+		name    string
+		keys    []*keyset.Key
+		wantErr bool
+	}{
+		{name: "shared secret", keys: []*keyset.Key{keyset.HMAC("secret")}},
+		{name: "public key with a signer", keys: []*keyset.Key{public, a}},
+		{name: "no keys", wantErr: true},
+		{name: "only a public key", keys: []*keyset.Key{public}, wantErr: true},
+		{name: "signer not started", keys: []*keyset.Key{future}, wantErr: true},
+		{name: "missing kid", keys: []*keyset.Key{noID}, wantErr: true},
+		{name: "duplicate kid", keys: []*keyset.Key{a, b}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := keyset.New(tt.keys...); (err != nil) != tt.wantErr {
+				t.Errorf("keyset.New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSet_JWKS(t *testing.T) {
+	rsaPriv, rsaK := rsaKey(t)
+	rsaK.ID = "rsa"
+	edPub, edK := edKey(t, false)
+	edK.ID = "ed"
+	_, retired := edKey(t, true)
+	retired.ID, retired.VerifyUntil = "retired", time.Now().Add(-time.Second)
+
+	set, err := keyset.New(rsaK, edK, retired)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Set.JWKS() = %d keys, want 2", len(jwks.Keys))
+	}
+	for _, k := range jwks.Keys {
+		switch k.Kid {
+		case "rsa":
+			n, _ := base64.RawURLEncoding.DecodeString(k.N)
+			e, _ := base64.RawURLEncoding.DecodeString(k.E)
+			if k.Kty != "RSA" || k.Alg != "RS256" || new(big.Int).SetBytes(n).Cmp(rsaPriv.N) != 0 || new(big.Int).SetBytes(e).Int64() != int64(rsaPriv.E) {
+				t.Errorf("Set.JWKS() rsa key = %+v", k)
+			}
+		case "ed":
+			x, _ := base64.RawURLEncoding.DecodeString(k.X)
+			if k.Kty != "OKP" || k.Crv != "Ed25519" || k.Alg != "EdDSA" || !edPub.Equal(ed25519.PublicKey(x)) {
+				t.Errorf("Set.JWKS() ed25519 key = %+v", k)
+			}
+		default:
+			t.Errorf("Set.JWKS() unexpected key %q", k.Kid)
+		}
+	}
+}
+
+func TestParseJWK(t *testing.T) {
+	_, rsaK := rsaKey(t)
+	rsaK.ID = "rsa"
+	_, edK := edKey(t, false)
+	edK.ID = "ed"
+	signer, err := keyset.New(rsaK, edK)
+	if err != nil {
+		t.Fatalf("keyset.New() error = %v", err)
+	}
+	token, err := signer.Sign(claims())
+	if err != nil {
+		t.Fatalf("Set.Sign() error = %v", err)
+	}
+
+	// the published keys are enough to validate the tokens
+	var keys []*keyset.Key
+	for _, jwk := range signer.JWKS().Keys {
+		key, err := keyset.ParseJWK(jwk)
+		if err != nil {
+			t.Fatalf("keyset.ParseJWK(%s) error = %v", jwk.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+	verifier := keyset.Verifier(keys...)
+	if _, err := verifier.Parse(token, &jwt.RegisteredClaims{}); err != nil {
+		t.Errorf("Set.Parse() with published keys error = %v", err)
+	}
+	if _, err := verifier.Sign(claims()); !errors.Is(err, keyset.ErrNoSigningKey) {
+		t.Errorf("Set.Sign() with public keys error = %v, want %v", err, keyset.ErrNoSigningKey)
+	}
+
+	if _, err := keyset.ParseJWK(keyset.JWK{Kty: "EC", Kid: "ec", Crv: "P-256"}); err == nil {
+		t.Errorf("keyset.ParseJWK() accepted an unsupported key")
+	}
+}
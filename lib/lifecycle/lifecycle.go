@@ -0,0 +1,175 @@
+// Package lifecycle runs the long-lived parts of a process and stops them in order
+// on SIGINT/SIGTERM or when one of them fails.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var ErrShutdownTimeout = errors.New("shutdown deadline exceeded")
+
+type worker struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager starts servers and workers, waits for a stop signal and shuts everything down in phases:
+// first the servers drain in-flight requests within drain, then the workers are cancelled
+// and awaited within timeout, then the closers run in reverse registration order.
+// A shutdown so takes up to drain plus timeout.
+type Manager struct {
+	log     *slog.Logger
+	drain   time.Duration
+	timeout time.Duration
+	servers []worker
+	workers []worker
+	closers []closer
+}
+
+func New(log *slog.Logger, drain, timeout time.Duration) *Manager {
+	return &Manager{log: log, drain: drain, timeout: timeout}
+}
+
+// Serve registers an HTTP server. It is drained before the workers are cancelled,
+// so requests in flight still have them and the resources they use.
+func (m *Manager) Serve(name string, srv *http.Server) {
+	m.servers = append(m.servers, worker{name: name, run: ServeHTTP(srv, m.drain)})
+}
+
+// Go registers a worker. It must return soon after ctx is cancelled,
+// returning early with an error shuts the whole process down.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	m.workers = append(m.workers, worker{name: name, run: run})
+}
+
+// OnClose registers a resource to release once all workers have stopped, e.g. the database pool
+func (m *Manager) OnClose(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run blocks until ctx is cancelled, a stop signal arrives or a worker fails, then shuts down.
+// It returns nil only for a clean stop on request.
+func (m *Manager) Run(ctx context.Context) error {
+	const op = "lifecycle.Manager.Run"
+
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveCtx, cancelServers := context.WithCancel(context.Background())
+	defer cancelServers()
+	workCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		errs      []error
+		servingWg sync.WaitGroup
+		wg        sync.WaitGroup
+		failed    = make(chan struct{}, len(m.servers)+len(m.workers))
+	)
+	start := func(ctx context.Context, wg *sync.WaitGroup, w worker) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.run(ctx)
+			if err == nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			m.log.Error(op, "worker failed", slog.String("name", w.name), slog.String("error", err.Error()))
+
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", w.name, err))
+			mu.Unlock()
+			failed <- struct{}{}
+		}()
+	}
+	for _, srv := range m.servers {
+		start(serveCtx, &servingWg, srv)
+	}
+	for _, w := range m.workers {
+		start(workCtx, &wg, w)
+	}
+
+	select {
+	case <-ctx.Done():
+		m.log.Info(op, "shutting down", slog.String("cause", context.Cause(ctx).Error()))
+	case <-failed:
+		m.log.Info(op, "shutting down after a worker failure", slog.Int("workers", len(m.workers)))
+	}
+
+	// ServeHTTP gives up on the requests left after drain itself
+	cancelServers()
+	servingWg.Wait()
+	cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(m.timeout):
+		m.log.Error(op, "workers did not stop in time", slog.Duration("timeout", m.timeout))
+		mu.Lock()
+		errs = append(errs, ErrShutdownTimeout)
+		mu.Unlock()
+	}
+
+	mu.Lock()
+	runErr := errors.Join(errs...)
+	mu.Unlock()
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.close(); err != nil {
+			m.log.Error(op, "failed to close", slog.String("name", c.name), slog.String("error", err.Error()))
+			runErr = errors.Join(runErr, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+
+	if runErr == nil {
+		m.log.Info(op, "stopped", slog.Int("servers", len(m.servers)), slog.Int("workers", len(m.workers)))
+	}
+	return runErr
+}
+
+// ServeHTTP runs srv as a worker: on cancellation it stops accepting connections
+// and waits up to drain for in-flight requests to finish, then closes the connections left,
+// which cancels the contexts of their requests.
+func ServeHTTP(srv *http.Server, drain time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		served := make(chan error, 1)
+		go func() { served <- srv.ListenAndServe() }()
+
+		select {
+		case err := <-served:
+			return err // failed to listen
+		case <-ctx.Done():
+		}
+
+		drainCtx, cancel := context.WithTimeout(context.Background(), drain)
+		defer cancel()
+		if err := srv.Shutdown(drainCtx); err != nil {
+			// the requests still running must not outlive the resources closed after the servers
+			return errors.Join(fmt.Errorf("failed to drain requests: %w", err), srv.Close())
+		}
+		if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
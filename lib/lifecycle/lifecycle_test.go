@@ -0,0 +1,242 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sdt-bicycle-rental/lib/lifecycle"
+	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the shutdown order
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestManager_Run(t *testing.T) {
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		failing    bool
+		stuck      bool
+		wantErr    error
+		wantEvents []string
+	}{
+		{
+			name:       "stop on request",
+			wantEvents: []string{"worker stopped", "close cache", "close db"},
+		},
+		{
+			name:       "worker failure stops the rest",
+			failing:    true,
+			wantErr:    boom,
+			wantEvents: []string{"worker stopped", "close cache", "close db"},
+		},
+		{
+			name:       "stuck worker",
+			stuck:      true,
+			wantErr:    lifecycle.ErrShutdownTimeout,
+			wantEvents: []string{"close cache", "close db"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			m := lifecycle.New(slogdiscard.NewDiscardLogger(), time.Second, 50*time.Millisecond)
+
+			m.Go("ticker", func(ctx context.Context) error {
+				<-ctx.Done()
+				if tt.stuck {
+					time.Sleep(time.Second)
+					return nil
+				}
+				rec.add("worker stopped")
+				return nil
+			})
+			if tt.failing {
+				m.Go("failing", func(ctx context.Context) error { return boom })
+			}
+			m.OnClose("db", func() error { rec.add("close db"); return nil })
+			m.OnClose("cache", func() error { rec.add("close cache"); return nil })
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if !tt.failing {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+			defer cancel()
+
+			err := m.Run(ctx)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Manager.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			if !slices.Equal(rec.events, tt.wantEvents) {
+				t.Errorf("shutdown order = %v, want %v", rec.events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestServeHTTP_Drains(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	started := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- lifecycle.ServeHTTP(srv, time.Second)(ctx) }()
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		defer close(responses)
+		// retry until the listener is up
+		for i := 0; i < 50; i++ {
+			if resp, err := http.Get("http://" + addr); err == nil {
+				responses <- resp
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	<-started
+	cancel() // in-flight request must still complete
+
+	resp, ok := <-responses
+	if !ok {
+		t.Fatal("in-flight request failed")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeHTTP() error = %v", err)
+	}
+}
+
+func TestServeHTTP_DrainTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(cancelled)
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- lifecycle.ServeHTTP(srv, 20*time.Millisecond)(ctx) }()
+
+	go func() {
+		// retry until the listener is up
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get("http://" + addr)
+			if err == nil {
+				resp.Body.Close()
+			}
+			select {
+			case <-started:
+				return // the connection is closed by the server
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	<-started
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Error("ServeHTTP() error = nil, want the drain to fail")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("request still running after the server was closed")
+	}
+}
+
+func TestManager_Run_DrainsFirst(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	rec := &recorder{}
+	started := make(chan struct{})
+	// the drain may take longer than the workers are given to stop
+	m := lifecycle.New(slogdiscard.NewDiscardLogger(), time.Second, 10*time.Millisecond)
+	m.Serve("http", &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		rec.add("request done")
+		w.WriteHeader(http.StatusNoContent)
+	})})
+	m.Go("ticker", func(ctx context.Context) error {
+		<-ctx.Done()
+		rec.add("worker stopped")
+		return nil
+	})
+	m.OnClose("db", func() error { rec.add("close db"); return nil })
+
+	go func() {
+		// retry until the listener is up
+		for i := 0; i < 50; i++ {
+			if resp, err := http.Get("http://" + addr); err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	defer cancel()
+
+	if err := m.Run(ctx); err != nil {
+		t.Errorf("Manager.Run() error = %v", err)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	want := []string{"request done", "worker stopped", "close db"}
+	if !slices.Equal(rec.events, want) {
+		t.Errorf("shutdown order = %v, want %v", rec.events, want)
+	}
+}
@@ -0,0 +1,57 @@
+package slogtrace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sdt-bicycle-rental/lib/logger/handlers/slogtrace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantTrace string
+		wantSpan  string
+	}{
+		{
+			name:      "span in context",
+			ctx:       trace.ContextWithSpanContext(context.Background(), sc),
+			wantTrace: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpan:  "00f067aa0ba902b7",
+		},
+		{
+			name: "no span",
+			ctx:  context.Background(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slogtrace.NewTraceHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("op", "test"))
+
+			log.InfoContext(tt.ctx, "message")
+
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			assert.Equal(t, "test", record["op"])
+			if tt.wantTrace == "" {
+				assert.NotContains(t, record, "trace_id")
+				return
+			}
+			assert.Equal(t, tt.wantTrace, record["trace_id"])
+			assert.Equal(t, tt.wantSpan, record["span_id"])
+		})
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"log/slog"
 	"os"
+	"sdt-bicycle-rental/lib/logger/handlers/slogtrace"
 )
 
 const (
@@ -15,16 +16,17 @@ func InitLogger(env string) *slog.Logger {
 	// Initialize the logger based on the environment
 	var logger *slog.Logger
 
+	// Every handler is wrapped by slogtrace: records logged with a context carry its trace and span ids
 	switch env {
 	case envLocal:
 		// Initialize local logger
-		logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		logger = slog.New(slogtrace.NewTraceHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	case envDevelopment:
 		// Initialize development logger
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		logger = slog.New(slogtrace.NewTraceHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	case envProduction:
 		// Initialize production logger
-		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		logger = slog.New(slogtrace.NewTraceHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
 	default:
 		panic("failed to initialize logger: unknown environment")
 	}
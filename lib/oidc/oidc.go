@@ -0,0 +1,338 @@
+// Package oidc is an OpenID Connect relying party for the authorization code flow with PKCE.
+// Provider metadata and signing keys are discovered from the issuer and cached.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sdt-bicycle-rental/lib/keyset"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// leeway tolerates clock drift between us and the provider
+	leeway = 30 * time.Second
+	// keysRefreshInterval limits refetching the JWKS for tokens signed with an unknown kid,
+	// so tokens with made up kids can't make us hammer the provider
+	keysRefreshInterval = time.Minute
+)
+
+var (
+	// ErrRejected means the provider or its ID token did not authenticate the user
+	ErrRejected = errors.New("oidc: authentication rejected")
+	// ErrUnavailable means the provider could not be reached or answered unexpectedly
+	ErrUnavailable = errors.New("oidc: provider unavailable")
+)
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Identity is what the provider asserts about the user in the ID token
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu          sync.Mutex
+	meta        *metadata
+	keys        *keyset.Set
+	refreshedAt time.Time // last refetch for an unknown kid
+}
+
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// NewVerifier returns a random PKCE code verifier
+func NewVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge is the S256 PKCE challenge of the verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL is where the user is sent to sign in at the provider
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: authorization endpoint: %v", ErrUnavailable, err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", Challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Identify exchanges the authorization code and returns the identity from the verified ID token
+func (p *Provider) Identify(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.exchange(ctx, meta, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.verify(ctx, meta, rawIDToken, nonce)
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+func (p *Provider) exchange(ctx context.Context, meta *metadata, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: token endpoint: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: token response: %v", ErrUnavailable, err)
+	}
+	// an expired, reused or forged code
+	if resp.StatusCode == http.StatusBadRequest && body.Error == "invalid_grant" {
+		return "", fmt.Errorf("%w: %s", ErrRejected, body.Error)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("%w: token endpoint answered %d %s", ErrUnavailable, resp.StatusCode, body.Error)
+	}
+
+	return body.IDToken, nil
+}
+
+type idTokenClaims struct {
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	Email           string `json:"email"`
+	EmailVerified   flag   `json:"email_verified"`
+	GivenName       string `json:"given_name"`
+	FamilyName      string `json:"family_name"`
+	Name            string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+func (p *Provider) verify(ctx context.Context, meta *metadata, rawIDToken, nonce string) (*Identity, error) {
+	parse := func(keys *keyset.Set) (*idTokenClaims, error) {
+		claims := &idTokenClaims{}
+		_, err := keys.Parse(rawIDToken, claims,
+			jwt.WithIssuer(meta.Issuer),
+			jwt.WithAudience(p.cfg.ClientID),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(leeway),
+		)
+		return claims, err
+	}
+
+	keys, err := p.signingKeys(ctx, meta, false)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := parse(keys)
+	// the provider may have rotated its keys since they were fetched
+	if errors.Is(err, keyset.ErrUnknownKey) {
+		if keys, err = p.signingKeys(ctx, meta, true); err != nil {
+			return nil, err
+		}
+		claims, err = parse(keys)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: id token: %v", ErrRejected, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrRejected)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: issued to %q", ErrRejected, claims.AuthorizedParty)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrRejected)
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) metadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	var meta metadata
+	if err := p.get(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, err
+	}
+	// the issuer must be exactly the one configured, so tokens of another issuer are not accepted
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: discovery names issuer %q", ErrUnavailable, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete discovery document", ErrUnavailable)
+	}
+
+	p.meta = &meta
+	return p.meta, nil
+}
+
+func (p *Provider) signingKeys(ctx context.Context, meta *metadata, refresh bool) (*keyset.Set, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && (!refresh || time.Since(p.refreshedAt) < keysRefreshInterval) {
+		return p.keys, nil
+	}
+	if refresh {
+		p.refreshedAt = time.Now()
+	}
+
+	var jwks keyset.JWKS
+	if err := p.get(ctx, meta.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*keyset.Key, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of other types may be published next to the ones in use
+		key, err := keyset.ParseJWK(jwk)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no usable signing keys", ErrUnavailable)
+	}
+
+	p.keys = keyset.Verifier(keys...)
+	return p.keys, nil
+}
+
+func (p *Provider) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s answered %d", ErrUnavailable, url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrUnavailable, url, err)
+	}
+	return nil
+}
+
+// flag is a boolean claim that some providers send as a string, e.g. "email_verified": "true"
+type flag bool
+
+func (f *flag) UnmarshalJSON(data []byte) error {
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	v, err := strconv.ParseBool(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid boolean claim %s", data)
+	}
+	*f = flag(v)
+	return nil
+}
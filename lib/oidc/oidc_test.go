@@ -0,0 +1,161 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sdt-bicycle-rental/lib/oidc"
+	"sdt-bicycle-rental/lib/oidc/oidctest"
+	"testing"
+)
+
+const redirectURL = "http://localhost:8080/auth/oidc/test/callback"
+
+var identity = oidc.Identity{
+	Subject:       "1234567890",
+	Email:         "jane@example.com",
+	EmailVerified: true,
+	GivenName:     "Jane",
+	FamilyName:    "Doe",
+}
+
+func newProvider(iss *oidctest.Issuer, secret string) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       iss.URL,
+		ClientID:     "client",
+		ClientSecret: secret,
+		RedirectURL:  redirectURL,
+	}, http.DefaultClient)
+}
+
+// login starts a login and returns the code the issuer redirected back with
+func login(t *testing.T, iss *oidctest.Issuer, p *oidc.Provider, nonce, verifier string) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(context.Background(), "state", nonce, verifier)
+	if err != nil {
+		t.Fatalf("Provider.AuthCodeURL() error = %v", err)
+	}
+	redirect, err := iss.Authorize(authURL, identity)
+	if err != nil {
+		t.Fatalf("Issuer.Authorize() error = %v", err)
+	}
+	u, err := url.Parse(redirect)
+	if err != nil {
+		t.Fatalf("redirect: %v", err)
+	}
+	if u.Query().Get("state") != "state" {
+		t.Fatalf("redirect state = %q", u.Query().Get("state"))
+	}
+	return u.Query().Get("code")
+}
+
+func TestProvider_Identify(t *testing.T) {
+	iss := oidctest.NewIssuer("client", "secret")
+	defer iss.Close()
+	p := newProvider(iss, "secret")
+
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		t.Fatalf("oidc.NewVerifier() error = %v", err)
+	}
+
+	code := login(t, iss, p, "nonce", verifier)
+	got, err := p.Identify(context.Background(), code, verifier, "nonce")
+	if err != nil {
+		t.Fatalf("Provider.Identify() error = %v", err)
+	}
+	if *got != identity {
+		t.Errorf("Provider.Identify() = %+v, want %+v", got, identity)
+	}
+
+	// codes are single use
+	if _, err := p.Identify(context.Background(), code, verifier, "nonce"); !errors.Is(err, oidc.ErrRejected) {
+		t.Errorf("Provider.Identify() reused code error = %v, want %v", err, oidc.ErrRejected)
+	}
+
+	// tokens of a rotated key are validated after refetching the keys
+	iss.RotateKey()
+	code = login(t, iss, p, "nonce", verifier)
+	if _, err := p.Identify(context.Background(), code, verifier, "nonce"); err != nil {
+		t.Errorf("Provider.Identify() after key rotation error = %v", err)
+	}
+}
+
+func TestProvider_IdentifyRejected(t *testing.T) {
+	iss := oidctest.NewIssuer("client", "secret")
+	defer iss.Close()
+
+	tests := []struct {
+		name     string
+		secret   string
+		nonce    string
+		verifier string
+		wantErr  error
+	}{
+		{
+			name:     "nonce mismatch",
+			secret:   "secret",
+			nonce:    "another nonce",
+			verifier: "verifier-verifier-verifier-verifier-verifier",
+			wantErr:  oidc.ErrRejected,
+		},
+		{
+			name:     "wrong verifier",
+			secret:   "secret",
+			nonce:    "nonce",
+			verifier: "another-verifier-verifier-verifier-verifier",
+			wantErr:  oidc.ErrRejected,
+		},
+		{
+			name:     "wrong client secret",
+			secret:   "wrong",
+			nonce:    "nonce",
+			verifier: "verifier-verifier-verifier-verifier-verifier",
+			wantErr:  oidc.ErrUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProvider(iss, tt.secret)
+			code := login(t, iss, p, "nonce", "verifier-verifier-verifier-verifier-verifier")
+
+			if _, err := p.Identify(context.Background(), code, tt.verifier, tt.nonce); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Provider.Identify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProvider_Discovery(t *testing.T) {
+	iss := oidctest.NewIssuer("client", "secret")
+	defer iss.Close()
+
+	// the configured issuer has to match the one the discovery document names
+	p := oidc.NewProvider(oidc.Config{Issuer: iss.URL + "/", ClientID: "client", RedirectURL: redirectURL}, http.DefaultClient)
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); !errors.Is(err, oidc.ErrUnavailable) {
+		t.Errorf("Provider.AuthCodeURL() error = %v, want %v", err, oidc.ErrUnavailable)
+	}
+
+	p = newProvider(iss, "secret")
+	authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatalf("Provider.AuthCodeURL() error = %v", err)
+	}
+	q, _ := url.Parse(authURL)
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client",
+		"redirect_uri":          redirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        oidc.Challenge("verifier"),
+		"code_challenge_method": "S256",
+	}
+	for k, v := range want {
+		if got := q.Query().Get(k); got != v {
+			t.Errorf("Provider.AuthCodeURL() %s = %q, want %q", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package oidctest runs a local OpenID Connect issuer for tests. Instead of a login page
+// the test calls Authorize with the URL the relying party redirected to.
+package oidctest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sdt-bicycle-rental/lib/keyset"
+	"sdt-bicycle-rental/lib/oidc"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type grant struct {
+	identity    oidc.Identity
+	nonce       string
+	challenge   string
+	redirectURI string
+}
+
+type Issuer struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu    sync.Mutex
+	keys  []*keyset.Key
+	codes map[string]grant
+	next  int
+}
+
+// NewIssuer starts an issuer that knows one client
+func NewIssuer(clientID, clientSecret string) *Issuer {
+	iss := &Issuer{ClientID: clientID, ClientSecret: clientSecret, codes: map[string]grant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", iss.discovery)
+	mux.HandleFunc("GET /jwks", iss.jwks)
+	mux.HandleFunc("POST /token", iss.token)
+	iss.Server = httptest.NewServer(mux)
+
+	iss.RotateKey()
+	return iss
+}
+
+// RotateKey signs the next ID tokens with a new key, published next to the old ones
+func (iss *Issuer) RotateKey() {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: generate key: %v", err))
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: marshal key: %v", err))
+	}
+	key, err := keyset.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: parse key: %v", err))
+	}
+
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	key.ID = "key-" + strconv.Itoa(len(iss.keys)+1)
+	// ordered in the past, so the latest key signs
+	key.SignFrom = time.Unix(int64(len(iss.keys)), 0)
+	iss.keys = append(iss.keys, key)
+}
+
+// Authorize plays the user signing in at the provider: it checks the authorization request
+// and returns the redirect back to the relying party with the code and the state
+func (iss *Issuer) Authorize(authURL string, identity oidc.Identity) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	switch {
+	case q.Get("response_type") != "code":
+		return "", errors.New("oidctest: response_type is not code")
+	case q.Get("client_id") != iss.ClientID:
+		return "", errors.New("oidctest: unknown client")
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		return "", errors.New("oidctest: PKCE S256 challenge missing")
+	case q.Get("redirect_uri") == "" || q.Get("state") == "":
+		return "", errors.New("oidctest: redirect_uri or state missing")
+	}
+
+	iss.mu.Lock()
+	iss.next++
+	code := "code-" + strconv.Itoa(iss.next)
+	iss.codes[code] = grant{
+		identity:    identity,
+		nonce:       q.Get("nonce"),
+		challenge:   q.Get("code_challenge"),
+		redirectURI: q.Get("redirect_uri"),
+	}
+	iss.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		return "", err
+	}
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	return redirect.String(), nil
+}
+
+func (iss *Issuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 iss.URL,
+		"authorization_endpoint": iss.URL + "/authorize",
+		"token_endpoint":         iss.URL + "/token",
+		"jwks_uri":               iss.URL + "/jwks",
+	})
+}
+
+func (iss *Issuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, iss.keySet().JWKS())
+}
+
+func (iss *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != iss.ClientID || subtle.ConstantTimeCompare([]byte(secret), []byte(iss.ClientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	iss.mu.Lock()
+	code := r.PostFormValue("code")
+	g, found := iss.codes[code]
+	delete(iss.codes, code) // single use
+	iss.mu.Unlock()
+
+	if !found || g.redirectURI != r.PostFormValue("redirect_uri") || oidc.Challenge(r.PostFormValue("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := iss.keySet().Sign(jwt.MapClaims{
+		"iss":            iss.URL,
+		"aud":            iss.ClientID,
+		"sub":            g.identity.Subject,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.identity.Email,
+		"email_verified": g.identity.EmailVerified,
+		"given_name":     g.identity.GivenName,
+		"family_name":    g.identity.FamilyName,
+		"name":           g.identity.Name,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access-" + code,
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (iss *Issuer) keySet() *keyset.Set {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	keys, err := keyset.New(iss.keys...)
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: %v", err))
+	}
+	return keys
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,266 @@
+// Package passhash hashes passwords with argon2id or bcrypt into PHC strings,
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key> and $bcrypt$v=97$r=12$<salt>$<key>,
+// so the algorithm and the parameters a password was hashed with are read back from its hash.
+// Bcrypt hashes in their own $2a$<cost>$ format are still verified, they need a rehash.
+package passhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+
+	// BcryptMaxBytes is the longest password bcrypt hashes, longer ones would be truncated silently
+	BcryptMaxBytes = 72
+
+	saltSize = 16
+	keySize  = 32
+
+	bcryptSaltLength  = 22 // of the 16 byte salt in bcrypt's base64
+	bcryptTotalLength = 53 // salt and key in bcrypt's base64
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("passhash: unknown algorithm")
+	ErrInvalidParams    = errors.New("passhash: invalid parameters")
+	ErrMalformedHash    = errors.New("passhash: malformed hash")
+	ErrPasswordTooLong  = errors.New("passhash: password too long for bcrypt")
+)
+
+// PHC strings encode salts and keys in base64 without padding,
+// bcrypt's own format uses another alphabet
+var (
+	encoding       = base64.RawStdEncoding
+	bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+)
+
+// Params of new hashes. Memory (KiB), Iterations and Parallelism are for argon2id, Cost for bcrypt.
+type Params struct {
+	Algorithm   string
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	Cost        int
+}
+
+// Hasher hashes passwords with the configured algorithm and parameters
+type Hasher struct {
+	params Params
+}
+
+func New(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case Argon2id:
+		if params.Iterations < 1 || params.Parallelism < 1 || params.Memory < 8*uint32(params.Parallelism) {
+			return nil, fmt.Errorf("%w: argon2id needs t >= 1, p >= 1 and m >= 8*p KiB", ErrInvalidParams)
+		}
+	case Bcrypt:
+		if params.Cost < bcrypt.MinCost || params.Cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("%w: bcrypt cost must be within %d..%d", ErrInvalidParams, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, params.Algorithm)
+	}
+
+	return &Hasher{params: params}, nil
+}
+
+// MaxBytes is the longest password the algorithm takes in full, 0 if there is no limit
+func (h *Hasher) MaxBytes() int {
+	if h.params.Algorithm == Bcrypt {
+		return BcryptMaxBytes
+	}
+	return 0
+}
+
+// Hash returns the encoded hash of the password with a random salt
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == Bcrypt {
+		if len(password) > BcryptMaxBytes {
+			return "", ErrPasswordTooLong
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.Cost)
+		if err != nil {
+			return "", err
+		}
+		return bcryptToPHC(string(hash))
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := h.params
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, keySize)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		Argon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// NeedsRehash tells whether the hash was made with another algorithm or other parameters
+// than the hasher uses now, so the password should be hashed again once it is known
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if isLegacyBcrypt(encoded) {
+		return true
+	}
+	if strings.HasPrefix(encoded, "$"+Bcrypt+"$") {
+		hash, err := parseBcrypt(encoded)
+		if err != nil || h.params.Algorithm != Bcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost(hash)
+		return err != nil || cost != h.params.Cost
+	}
+
+	hash, err := parseArgon2id(encoded)
+	if err != nil || h.params.Algorithm != Argon2id {
+		return true
+	}
+	p := h.params
+	return hash.memory != p.Memory || hash.iterations != p.Iterations ||
+		hash.parallelism != p.Parallelism || len(hash.key) != keySize
+}
+
+// Verify checks the password against a hash of either algorithm, whatever the hasher is configured with.
+// An error means the hash itself can't be read.
+func Verify(encoded, password string) (bool, error) {
+	if isLegacyBcrypt(encoded) {
+		return verifyBcrypt([]byte(encoded), password)
+	}
+	if strings.HasPrefix(encoded, "$"+Bcrypt+"$") {
+		hash, err := parseBcrypt(encoded)
+		if err != nil {
+			return false, err
+		}
+		return verifyBcrypt(hash, password)
+	}
+
+	hash, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+
+	return subtle.ConstantTimeCompare(key, hash.key) == 1, nil
+}
+
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	// "", algorithm, version, parameters, salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" {
+		return nil, ErrMalformedHash
+	}
+	if parts[1] != Argon2id {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrMalformedHash
+	}
+
+	var hash argon2idHash
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism)
+	if err != nil || hash.iterations < 1 || hash.parallelism < 1 {
+		return nil, ErrMalformedHash
+	}
+
+	if hash.salt, err = encoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if hash.key, err = encoding.DecodeString(parts[5]); err != nil || len(hash.key) == 0 {
+		return nil, ErrMalformedHash
+	}
+
+	return &hash, nil
+}
+
+func verifyBcrypt(hash []byte, password string) (bool, error) {
+	// bcrypt compares only the first 72 bytes, no such password could have been hashed
+	if len(password) > BcryptMaxBytes {
+		return false, nil
+	}
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// bcryptToPHC rewrites $2a$<cost>$<salt><key> in bcrypt's base64 as a PHC string
+func bcryptToPHC(hash string) (string, error) {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil || !isLegacyBcrypt(hash) {
+		return "", ErrMalformedHash
+	}
+	// $2a$, two digits of the cost and $
+	body := hash[7:]
+	if len(body) != bcryptTotalLength {
+		return "", ErrMalformedHash
+	}
+	salt, err := bcryptEncoding.DecodeString(body[:bcryptSaltLength])
+	if err != nil {
+		return "", ErrMalformedHash
+	}
+	key, err := bcryptEncoding.DecodeString(body[bcryptSaltLength:])
+	if err != nil {
+		return "", ErrMalformedHash
+	}
+
+	return fmt.Sprintf("$%s$v=%d$r=%d$%s$%s", Bcrypt, hash[2], cost,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// parseBcrypt reads a PHC bcrypt string back into the $2a$ form the bcrypt package compares.
+// The version is the letter after $2, 97 for $2a$.
+func parseBcrypt(encoded string) ([]byte, error) {
+	// "", algorithm, version, cost, salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != Bcrypt {
+		return nil, ErrMalformedHash
+	}
+
+	var version, cost int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || !strings.ContainsRune("aby", rune(version)) {
+		return nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "r=%d", &cost); err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, ErrMalformedHash
+	}
+
+	salt, err := encoding.DecodeString(parts[4])
+	if err != nil || len(salt) != saltSize {
+		return nil, ErrMalformedHash
+	}
+	key, err := encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, ErrMalformedHash
+	}
+
+	return []byte(fmt.Sprintf("$2%c$%02d$%s%s", version, cost,
+		bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(key))), nil
+}
+
+// isLegacyBcrypt tells a hash stored by bcrypt itself, before hashes were PHC strings
+func isLegacyBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
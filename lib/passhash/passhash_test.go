@@ -0,0 +1,171 @@
+package passhash_test
+
+import (
+	"sdt-bicycle-rental/lib/passhash"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters, the strength of the hash is not under test
+var (
+	argon2id = passhash.Params{Algorithm: passhash.Argon2id, Memory: 64, Iterations: 1, Parallelism: 1}
+	bcrypt4  = passhash.Params{Algorithm: passhash.Bcrypt, Cost: bcrypt.MinCost}
+)
+
+func TestNew(t *testing.T) {
+	_, err := passhash.New(argon2id)
+	assert.NoError(t, err)
+	_, err = passhash.New(bcrypt4)
+	assert.NoError(t, err)
+
+	_, err = passhash.New(passhash.Params{Algorithm: "md5"})
+	assert.ErrorIs(t, err, passhash.ErrUnknownAlgorithm)
+	_, err = passhash.New(passhash.Params{Algorithm: passhash.Argon2id, Memory: 64, Parallelism: 1})
+	assert.ErrorIs(t, err, passhash.ErrInvalidParams)
+	_, err = passhash.New(passhash.Params{Algorithm: passhash.Bcrypt, Cost: 40})
+	assert.ErrorIs(t, err, passhash.ErrInvalidParams)
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for _, params := range []passhash.Params{argon2id, bcrypt4} {
+		t.Run(params.Algorithm, func(t *testing.T) {
+			h, err := passhash.New(params)
+			require.NoError(t, err)
+
+			hash, err := h.Hash("correct horse battery staple")
+			require.NoError(t, err)
+
+			ok, err := passhash.Verify(hash, "correct horse battery staple")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = passhash.Verify(hash, "correct horse battery stapler")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			// salted, the same password never hashes the same
+			again, err := h.Hash("correct horse battery staple")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, again)
+
+			assert.False(t, h.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHash_PHC(t *testing.T) {
+	h, err := passhash.New(argon2id)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+
+	parts := strings.Split(hash, "$")
+	require.Len(t, parts, 6)
+	assert.Equal(t, "argon2id", parts[1])
+	assert.Equal(t, "v=19", parts[2])
+	assert.Equal(t, "m=64,t=1,p=1", parts[3])
+}
+
+func TestHash_BcryptPHC(t *testing.T) {
+	h, err := passhash.New(bcrypt4)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+
+	parts := strings.Split(hash, "$")
+	require.Len(t, parts, 6)
+	assert.Equal(t, "bcrypt", parts[1])
+	assert.Equal(t, "v=97", parts[2])
+	assert.Equal(t, "r=4", parts[3])
+}
+
+func TestVerify_LegacyBcrypt(t *testing.T) {
+	h, err := passhash.New(bcrypt4)
+	require.NoError(t, err)
+
+	// hashes stored before bcrypt was written as PHC
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ok, err := passhash.Verify(string(legacy), "password")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = passhash.Verify(string(legacy), "passw0rd")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, h.NeedsRehash(string(legacy)), "same cost, old format")
+}
+
+func TestBcryptLength(t *testing.T) {
+	h, err := passhash.New(bcrypt4)
+	require.NoError(t, err)
+	assert.Equal(t, passhash.BcryptMaxBytes, h.MaxBytes())
+
+	_, err = h.Hash(strings.Repeat("a", passhash.BcryptMaxBytes+1))
+	assert.ErrorIs(t, err, passhash.ErrPasswordTooLong)
+
+	// bcrypt ignores everything after 72 bytes, a longer password must not match on its prefix
+	hash, err := h.Hash(strings.Repeat("a", passhash.BcryptMaxBytes))
+	require.NoError(t, err)
+	ok, err := passhash.Verify(hash, strings.Repeat("a", passhash.BcryptMaxBytes)+"b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	a, err := passhash.New(argon2id)
+	require.NoError(t, err)
+	assert.Zero(t, a.MaxBytes())
+}
+
+func TestNeedsRehash(t *testing.T) {
+	a, err := passhash.New(argon2id)
+	require.NoError(t, err)
+	b, err := passhash.New(bcrypt4)
+	require.NoError(t, err)
+
+	argonHash, err := a.Hash("password")
+	require.NoError(t, err)
+	bcryptHash, err := b.Hash("password")
+	require.NoError(t, err)
+
+	stronger, err := passhash.New(passhash.Params{Algorithm: passhash.Argon2id, Memory: 128, Iterations: 1, Parallelism: 1})
+	require.NoError(t, err)
+	costlier, err := passhash.New(passhash.Params{Algorithm: passhash.Bcrypt, Cost: bcrypt.MinCost + 1})
+	require.NoError(t, err)
+
+	assert.True(t, a.NeedsRehash(bcryptHash), "other algorithm")
+	assert.True(t, b.NeedsRehash(argonHash), "other algorithm")
+	assert.True(t, stronger.NeedsRehash(argonHash), "other memory")
+	assert.True(t, costlier.NeedsRehash(bcryptHash), "other cost")
+	assert.True(t, a.NeedsRehash("plaintext"), "unreadable")
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	cases := []string{
+		"",
+		"password",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$not base64$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$",
+		"$2b$04$short",
+		"$bcrypt$v=97$r=4$c2FsdHNhbHQ$a2V5",
+		"$bcrypt$v=99$r=4$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$bcrypt$v=97$r=40$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$bcrypt$v=97$r=4$c2FsdHNhbHRzYWx0c2FsdA$",
+	}
+
+	for _, hash := range cases {
+		ok, err := passhash.Verify(hash, "password")
+		assert.Error(t, err, hash)
+		assert.False(t, ok, hash)
+	}
+}
@@ -0,0 +1,92 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"sdt-bicycle-rental/lib/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the SHA1 seed of RFC 6238 appendix B
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B vectors, the 8 digit values cut to the last 6
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tc := range cases {
+		got, err := totp.Code(rfcSecret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "at %d", tc.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := totp.Code(rfcSecret, now)
+	require.NoError(t, err)
+
+	step, ok := totp.Validate(rfcSecret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	// the previous step is accepted with a skew of one, and reported as such
+	step, ok = totp.Validate(rfcSecret, code, now.Add(totp.Period), 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now), step)
+
+	_, ok = totp.Validate(rfcSecret, code, now.Add(2*totp.Period), 1)
+	assert.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, "000000", now, 1)
+	assert.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, code+"0", now, 1)
+	assert.False(t, ok)
+	_, ok = totp.Validate("not base32!", code, now, 1)
+	assert.False(t, ok)
+
+	// secrets are accepted as apps and users write them
+	_, ok = totp.Validate(strings.ToLower(rfcSecret), code, now, 0)
+	assert.True(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	b, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, a, b)
+
+	_, err = totp.Code(a, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("Bicycle Rental", "john@example.com", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Bicycle Rental:john@example.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "Bicycle Rental", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
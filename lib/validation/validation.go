@@ -1,29 +1,155 @@
 package validation
 
 import (
-	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
-	"errors"
-
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/uk"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	uk_translations "github.com/go-playground/validator/v10/translations/uk"
 )
 
-func PrettyError(validationErrs ...validator.ValidationErrors) error {
-	var errMsgs []string
+// DefaultLocale is used when the client accepts none of the supported locales
+const DefaultLocale = "en"
+
+// FieldError describes why a single field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Translator holds the message catalogs of every supported locale
+type Translator struct {
+	uni *ut.UniversalTranslator
+}
+
+type catalog struct {
+	locale   locales.Translator
+	register func(v *validator.Validate, trans ut.Translator) error
+}
+
+var catalogs = []catalog{
+	{locale: en.New(), register: en_translations.RegisterDefaultTranslations},
+	{locale: uk.New(), register: uk_translations.RegisterDefaultTranslations},
+}
+
+// NewTranslator registers the messages of all supported locales on v
+func NewTranslator(v *validator.Validate) (*Translator, error) {
+	supported := make([]locales.Translator, 0, len(catalogs))
+	for _, c := range catalogs {
+		supported = append(supported, c.locale)
+	}
+	uni := ut.New(catalogs[0].locale, supported...)
+
+	for _, c := range catalogs {
+		trans, _ := uni.GetTranslator(c.locale.Locale())
+		if err := c.register(v, trans); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Translator{uni: uni}, nil
+}
+
+// Locale picks the translator for an Accept-Language header value,
+// falling back to DefaultLocale when none of the languages is supported
+func (t *Translator) Locale(acceptLanguage string) ut.Translator {
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if trans, found := t.uni.FindTranslator(lang); found {
+			return trans
+		}
+		// uk-UA is served by uk
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			if trans, found := t.uni.FindTranslator(base); found {
+				return trans
+			}
+		}
+	}
+
+	trans, _ := t.uni.GetTranslator(DefaultLocale)
+	return trans
+}
+
+// Fields converts validator errors to one FieldError per failed field, in validation order,
+// with messages in the language of trans
+func Fields(trans ut.Translator, validationErrs ...validator.ValidationErrors) []FieldError {
+	var fields []FieldError
 
 	for _, vErr := range validationErrs {
 		for _, err := range vErr {
-			switch err.ActualTag() {
-			case "required":
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
-			case "email":
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid email", err.Field()))
-			default:
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
+			fields = append(fields, FieldError{
+				Field:   fieldPath(err),
+				Rule:    err.ActualTag(),
+				Message: err.Translate(trans),
+			})
+		}
+	}
+
+	return fields
+}
+
+// JSONFieldName reports struct fields by their JSON names, register it with RegisterTagNameFunc
+func JSONFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+// fieldPath is the field namespace without the root struct, e.g. hours[0].from
+func fieldPath(err validator.FieldError) string {
+	if _, path, ok := strings.Cut(err.Namespace(), "."); ok {
+		return path
+	}
+	return err.Field()
+}
+
+// parseAcceptLanguage returns the language tags by descending quality, "*" and q=0 are dropped
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+
+	var langs []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
 			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
 		}
+
+		langs = append(langs, language{tag: tag, q: q})
 	}
 
-	return errors.New(strings.Join(errMsgs, ", "))
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
 }
@@ -0,0 +1,99 @@
+package validation_test
+
+import (
+	"sdt-bicycle-rental/lib/validation"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValidator(t *testing.T) (*validator.Validate, *validation.Translator) {
+	t.Helper()
+
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(validation.JSONFieldName)
+	tr, err := validation.NewTranslator(v)
+	require.NoError(t, err)
+
+	return v, tr
+}
+
+func TestTranslator_Locale(t *testing.T) {
+	_, tr := newValidator(t)
+
+	cases := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "empty", acceptLanguage: "", want: "en"},
+		{name: "exact", acceptLanguage: "uk", want: "uk"},
+		{name: "region falls back to language", acceptLanguage: "uk-UA", want: "uk"},
+		{name: "case insensitive", acceptLanguage: "UK", want: "uk"},
+		{name: "first supported", acceptLanguage: "de, uk, en", want: "uk"},
+		{name: "by quality", acceptLanguage: "en;q=0.5, uk;q=0.9", want: "uk"},
+		{name: "refused language", acceptLanguage: "uk;q=0, en;q=0.1", want: "en"},
+		{name: "unsupported", acceptLanguage: "de, fr;q=0.8, *;q=0.1", want: "en"},
+		{name: "malformed quality", acceptLanguage: "uk;q=high", want: "en"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tr.Locale(tc.acceptLanguage).Locale())
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	v, tr := newValidator(t)
+
+	type hours struct {
+		From int `json:"from" validate:"gte=0,lte=23"`
+	}
+	// every rule used by the models and DTOs
+	type request struct {
+		Name     string  `json:"name" validate:"required"`
+		Email    string  `json:"email" validate:"omitempty,email"`
+		Password string  `json:"password" validate:"omitempty,min=8,max=255"`
+		Street   string  `json:"street" validate:"max=4"`
+		Type     string  `json:"type" validate:"oneof=standard electric"`
+		Price    int64   `json:"price" validate:"gte=0"`
+		Hours    []hours `json:"hours" validate:"dive"`
+		Internal string  `json:"-" validate:"required"`
+	}
+
+	err := v.Struct(request{
+		Email:    "not-an-email",
+		Password: "short",
+		Street:   "Khreshchatyk",
+		Type:     "tandem",
+		Price:    -1,
+		Hours:    []hours{{From: 24}},
+	})
+	require.Error(t, err)
+	errs := err.(validator.ValidationErrors)
+
+	wantFields := []string{"name", "email", "password", "street", "type", "price", "hours[0].from", "Internal"}
+	wantRules := []string{"required", "email", "min", "max", "oneof", "gte", "lte", "required"}
+
+	for _, locale := range []string{"en", "uk"} {
+		t.Run(locale, func(t *testing.T) {
+			fields := validation.Fields(tr.Locale(locale), errs)
+			require.Len(t, fields, len(wantFields))
+
+			for i, f := range fields {
+				assert.Equal(t, wantFields[i], f.Field)
+				assert.Equal(t, wantRules[i], f.Rule)
+				// untranslated rules fall back to the raw validator error
+				assert.NotEqual(t, errs[i].Error(), f.Message, "rule %s has no %s message", f.Rule, locale)
+			}
+		})
+	}
+
+	assert.Equal(t, "email must be a valid email address", validation.Fields(tr.Locale("en"), errs)[1].Message)
+	assert.Equal(t, "email має бути email адресою", validation.Fields(tr.Locale("uk"), errs)[1].Message)
+}
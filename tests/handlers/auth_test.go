@@ -1,22 +1,50 @@
 package handlers_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sdt-bicycle-rental/internal/config"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/login"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/confirm"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/enroll"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/setup"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/mfa/verify"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/oidc/start"
+	"sdt-bicycle-rental/internal/http-server/handlers/auth/refresh"
 	"sdt-bicycle-rental/internal/http-server/handlers/auth/register"
+	"sdt-bicycle-rental/internal/http-server/middleware/jwtauth"
+	"sdt-bicycle-rental/internal/http-server/problem"
 	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/notify"
+	"sdt-bicycle-rental/internal/repository/memory"
 	"sdt-bicycle-rental/internal/repository/postgres"
 	"sdt-bicycle-rental/internal/service"
+	access_service "sdt-bicycle-rental/internal/service/access"
+	auth_service "sdt-bicycle-rental/internal/service/auth"
+	lockout_service "sdt-bicycle-rental/internal/service/lockout"
+	mfa_service "sdt-bicycle-rental/internal/service/mfa"
+	oidc_service "sdt-bicycle-rental/internal/service/oidc"
+	password_service "sdt-bicycle-rental/internal/service/password"
+	token_service "sdt-bicycle-rental/internal/service/token"
+	verification_service "sdt-bicycle-rental/internal/service/verification"
+	"sdt-bicycle-rental/lib/keyset"
 	"sdt-bicycle-rental/lib/logger/handlers/slogdiscard"
+	"sdt-bicycle-rental/lib/oidc"
+	"sdt-bicycle-rental/lib/oidc/oidctest"
+	"sdt-bicycle-rental/lib/totp"
 	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAuthHandler(t *testing.T) {
@@ -25,15 +53,80 @@ func TestAuthHandler(t *testing.T) {
 
 	test_postgres.ClearTable(t, db, "users")
 
+	log := slogdiscard.NewDiscardLogger()
 	userRepo := postgres.NewUserRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+
+	accessService := access_service.New(postgres.NewRoleRepository(db), postgres.NewUserRepository(db), postgres.NewAuditRepository(db), log)
+
+	keys, err := keyset.New(keyset.HMAC("secret"))
+	require.NoError(t, err)
+	outbox := &outbox{}
+	mfaService := mfa_service.New(postgres.NewMFARepository(db), userRepo, accessService, outbox, log, config.MFA{
+		Issuer:           "Bicycle Rental",
+		RequireForAdmins: true,
+		Skew:             1,
+		RecoveryCodes:    10,
+		SetupURL:         "http://localhost:3000/mfa-setup",
+		SetupTTL:         time.Hour,
+	})
+	tokenService := token_service.New(refreshTokenRepo, userRepo, accessService, mfaService, log, keys, config.Auth{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	})
+	verificationService := verification_service.New(postgres.NewVerificationRepository(db), userRepo, outbox, log, config.Verify{
+		EmailURL:       "http://localhost:3000/verify-email",
+		EmailTTL:       time.Hour,
+		PhoneTTL:       10 * time.Minute,
+		ResendInterval: time.Minute,
+		MaxAttempts:    5,
+	})
+	lockoutService := lockout_service.New(memory.NewLoginAttemptRepository(time.Hour), postgres.NewAuditRepository(db), log, config.Lockout{
+		AccountThreshold: 3,
+		IPThreshold:      100,
+		Window:           15 * time.Minute,
+		Cooldown:         time.Minute,
+		MaxCooldown:      time.Hour,
+		ResetAfter:       24 * time.Hour,
+	})
+	passwords, err := password_service.NewHasher(config.Password{Algorithm: "argon2id", Memory: 64, Iterations: 1, Parallelism: 1, MinLength: 8, MaxLength: 128})
+	require.NoError(t, err)
+	authService := auth_service.New(userRepo, tokenService, verificationService, lockoutService, mfaService, passwords, log)
+	passwordService := password_service.New(postgres.NewPasswordResetRepository(db), userRepo, outbox, passwords, lockoutService, log, config.Auth{
+		PasswordResetURL: "http://localhost:3000/reset-password",
+		PasswordResetTTL: 30 * time.Minute,
+	})
+	issuer := oidctest.NewIssuer("rental", "client-secret")
+	defer issuer.Close()
+	oidcService := oidc_service.New(postgres.NewOIDCRepository(db), userRepo, map[string]oidc_service.Provider{
+		"test": oidc.NewProvider(oidc.Config{
+			Issuer:       issuer.URL,
+			ClientID:     issuer.ClientID,
+			ClientSecret: issuer.ClientSecret,
+			RedirectURL:  "http://localhost:8080/auth/oidc/test/callback",
+		}, issuer.Client()),
+	}, authService, log, config.OIDC{StateTTL: 10 * time.Minute})
 
 	r := chi.NewRouter()
-	r.Route("/auth", auth.AuthRoute(slogdiscard.NewDiscardLogger(), userRepo, "secret"))
+	r.Route("/auth", auth.AuthRoute(log, authService, tokenService, passwordService, verificationService, mfaService, oidcService, jwtauth.New(tokenService, log)))
+
+	post := func(path, body string, bearer ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for _, token := range bearer {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
 
 	t.Run("register", func(t *testing.T) {
 		type resp struct {
-			Code  int
-			Error string
+			Code   int
+			Error  string
+			Fields []string
 		}
 
 		tests := []struct {
@@ -43,28 +136,33 @@ func TestAuthHandler(t *testing.T) {
 		}{
 			{
 				name:     "success",
-				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"12345678"}}`,
+				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"ride-a-bike-1"}}`,
 				wantResp: resp{Code: http.StatusCreated},
 			},
 			{
 				name:     "invalid name",
-				body:     `{"user":{"name":"","lastname":"Doe","email":"john@example.com","phone":"123456","password":"12345678"}}`,
-				wantResp: resp{Code: http.StatusBadRequest, Error: "field Name is a required field"},
+				body:     `{"user":{"name":"","lastname":"Doe","email":"john@example.com","phone":"123456","password":"ride-a-bike-1"}}`,
+				wantResp: resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error(), Fields: []string{"name"}},
 			},
 			{
 				name:     "invalid email",
-				body:     `{"user":{"name":"John","lastname":"Doe","email":"example.com","phone":"123456","password":"12345678"}}`,
-				wantResp: resp{Code: http.StatusBadRequest, Error: "field Email is not a valid email"},
+				body:     `{"user":{"name":"John","lastname":"Doe","email":"example.com","phone":"123456","password":"ride-a-bike-1"}}`,
+				wantResp: resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error(), Fields: []string{"email"}},
 			},
 			{
 				name:     "invalid password",
 				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"1234"}}`,
-				wantResp: resp{Code: http.StatusBadRequest, Error: "field Password is not valid"},
+				wantResp: resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error(), Fields: []string{"password"}},
+			},
+			{
+				name:     "common password",
+				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"qwerty123"}}`,
+				wantResp: resp{Code: http.StatusBadRequest, Error: "password is too common"},
 			},
 			{
 				name:     "invalid name and email",
-				body:     `{"user":{"name":"","lastname":"Doe","email":"example.com","phone":"123456","password":"12345678"}}`,
-				wantResp: resp{Code: http.StatusBadRequest, Error: "field Name is a required field, field Email is not a valid email"},
+				body:     `{"user":{"name":"","lastname":"Doe","email":"example.com","phone":"123456","password":"ride-a-bike-1"}}`,
+				wantResp: resp{Code: http.StatusBadRequest, Error: service.ErrValidation.Error(), Fields: []string{"name", "email"}},
 			},
 			{
 				name:     "invalid body",
@@ -73,7 +171,7 @@ func TestAuthHandler(t *testing.T) {
 			},
 			{
 				name:     "user exists error",
-				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"12345678"}}`,
+				body:     `{"user":{"name":"John","lastname":"Doe","email":"john@example.com","phone":"123456","password":"ride-a-bike-1"}}`,
 				wantResp: resp{Code: http.StatusConflict, Error: service.ErrUserAlreadyExists.Error()},
 			},
 		}
@@ -102,11 +200,416 @@ func TestAuthHandler(t *testing.T) {
 					return
 				}
 
-				var response register.ErrorResponse
+				var response problem.Problem
 				require.NoError(t, render.DecodeJSON(resp.Body, &response))
-				assert.Equal(t, tt.wantResp.Error, response.Error)
+				assert.Equal(t, tt.wantResp.Error, response.Detail)
+
+				var fields []string
+				for _, f := range response.Errors {
+					fields = append(fields, f.Field)
+				}
+				assert.Equal(t, tt.wantResp.Fields, fields)
 
 			})
 		}
 	})
+
+	t.Run("verification", func(t *testing.T) {
+		// registration sent the email link and the phone code
+		emailMsg, ok := outbox.last(notify.Email)
+		require.True(t, ok)
+		smsMsg, ok := outbox.last(notify.SMS)
+		require.True(t, ok)
+		assert.Equal(t, "123456", smsMsg.To)
+
+		loginResp := post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		require.Equal(t, http.StatusOK, loginResp.Code)
+		var login register.SuccessResponse
+		require.NoError(t, render.DecodeJSON(loginResp.Body, &login))
+		assert.Equal(t, models.UserStatusPending, *login.User.Status)
+
+		// resending right away is throttled
+		resp := post("/auth/verify/resend", `{"channel":"phone"}`, login.Token)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+		resp = post("/auth/verify/resend", `{"channel":"phone"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		_, link, _ := strings.Cut(emailMsg.Body, "?token=")
+		token, err := url.QueryUnescape(strings.Fields(link)[0])
+		require.NoError(t, err)
+		resp = post("/auth/verify/email", `{"token":"`+token+`"}`)
+		require.Equal(t, http.StatusNoContent, resp.Code)
+		// the link is single-use
+		resp = post("/auth/verify/email", `{"token":"`+token+`"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		code := strings.TrimSuffix(strings.Fields(smsMsg.Body)[4], ".")
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+		resp = post("/auth/verify/phone", `{"code":"`+wrong+`"}`, login.Token)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		resp = post("/auth/verify/phone", `{"code":"`+code+`"}`, login.Token)
+		require.Equal(t, http.StatusNoContent, resp.Code)
+
+		// both verified, the account is active
+		var user models.User
+		require.NoError(t, db.First(&user, "email = ?", "john@example.com").Error)
+		assert.Equal(t, models.UserStatusActive, *user.Status)
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.NotNil(t, user.PhoneVerifiedAt)
+
+		resp = post("/auth/verify/resend", `{"channel":"email"}`, login.Token)
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		lockouts := func() int64 {
+			var n int64
+			require.NoError(t, db.Model(&models.AuditEvent{}).Where("action = ?", models.AuditActionLoginLocked).Count(&n).Error)
+			return n
+		}
+		before := lockouts()
+
+		registerResp := post("/auth/register", `{"user":{"name":"Jane","lastname":"Doe","email":"jane@example.com","phone":"654321","password":"ride-a-bike-1"}}`)
+		require.Equal(t, http.StatusCreated, registerResp.Code)
+
+		for range 3 {
+			resp := post("/auth/login", `{"email":"jane@example.com","password":"wrong-password"}`)
+			require.Equal(t, http.StatusUnauthorized, resp.Code)
+		}
+
+		// even the right password is refused until the cooldown ends
+		resp := post("/auth/login", `{"email":"jane@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+		assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+
+		assert.Equal(t, before+1, lockouts())
+
+		// other accounts from the same address still sign in
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		// banned users are refused with the right password
+		require.NoError(t, db.Model(&models.User{}).Where("email = ?", "john@example.com").Update("status", models.UserStatusBanned).Error)
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		require.NoError(t, db.Model(&models.User{}).Where("email = ?", "john@example.com").Update("status", models.UserStatusActive).Error)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		loginReq := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"john@example.com","password":"ride-a-bike-1"}`))
+		loginReq.Header.Set("Content-Type", "application/json")
+		loginResp := httptest.NewRecorder()
+		r.ServeHTTP(loginResp, loginReq)
+		require.Equal(t, http.StatusOK, loginResp.Code)
+
+		var login register.SuccessResponse
+		require.NoError(t, render.DecodeJSON(loginResp.Body, &login))
+		require.NotEmpty(t, login.RefreshToken)
+
+		doRefresh := func(token string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token":"`+token+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			return resp
+		}
+
+		// first use rotates the token
+		resp := doRefresh(login.RefreshToken)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var rotated refresh.SuccessResponse
+		require.NoError(t, render.DecodeJSON(resp.Body, &rotated))
+		require.NotEqual(t, login.RefreshToken, rotated.RefreshToken)
+
+		// replaying the old token revokes the family
+		resp = doRefresh(login.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		// the rotated token belongs to the revoked family
+		resp = doRefresh(rotated.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("password reset", func(t *testing.T) {
+		loginResp := post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		require.Equal(t, http.StatusOK, loginResp.Code)
+		var login register.SuccessResponse
+		require.NoError(t, render.DecodeJSON(loginResp.Body, &login))
+
+		outbox.messages = nil
+
+		// unknown emails get the same answer and no message
+		resp := post("/auth/password/forgot", `{"email":"nobody@example.com"}`)
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+		passwordService.Wait()
+		require.Empty(t, outbox.messages)
+
+		// the link is sent after the answer
+		resp = post("/auth/password/forgot", `{"email":"john@example.com"}`)
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		passwordService.Wait()
+		require.Len(t, outbox.messages, 1)
+		_, link, _ := strings.Cut(outbox.messages[0].Body, "?token=")
+		token, err := url.QueryUnescape(strings.Fields(link)[0])
+		require.NoError(t, err)
+
+		resp = post("/auth/password/reset", `{"token":"`+token+`","password":"ride-a-bike-2"}`)
+		require.Equal(t, http.StatusNoContent, resp.Code)
+
+		// the token is single-use
+		resp = post("/auth/password/reset", `{"token":"`+token+`","password":"ride-a-bike-3"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		// sessions from before the reset are gone
+		resp = post("/auth/refresh", `{"refresh_token":"`+login.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-2"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("password change", func(t *testing.T) {
+		signIn := func() register.SuccessResponse {
+			resp := post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-2"}`)
+			require.Equal(t, http.StatusOK, resp.Code)
+			var session register.SuccessResponse
+			require.NoError(t, render.DecodeJSON(resp.Body, &session))
+			return session
+		}
+		current, other := signIn(), signIn()
+
+		resp := post("/auth/password/change", `{"current_password":"ride-a-bike-2","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		resp = post("/auth/password/change", `{"current_password":"guessed","password":"ride-a-bike-1"}`, current.Token)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+
+		resp = post("/auth/password/change", `{"current_password":"ride-a-bike-2","password":"qwerty123"}`, current.Token)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		// back to the password of the other subtests
+		resp = post("/auth/password/change", `{"current_password":"ride-a-bike-2","password":"ride-a-bike-1"}`, current.Token)
+		require.Equal(t, http.StatusNoContent, resp.Code)
+
+		// the other device is signed out, the one the change was made from is not
+		resp = post("/auth/refresh", `{"refresh_token":"`+other.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		resp = post("/auth/refresh", `{"refresh_token":"`+current.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-2"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("rehash", func(t *testing.T) {
+		// a hash from before argon2id
+		legacy, err := bcrypt.GenerateFromPassword([]byte("ride-a-bike-1"), bcrypt.MinCost)
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&models.User{}).Where("email = ?", "john@example.com").Update("password", string(legacy)).Error)
+
+		resp := post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var user models.User
+		require.NoError(t, db.First(&user, "email = ?", "john@example.com").Error)
+		assert.True(t, strings.HasPrefix(*user.Password, "$argon2id$"))
+
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("mfa", func(t *testing.T) {
+		code := func(secret string) string {
+			code, err := totp.Code(secret, time.Now())
+			require.NoError(t, err)
+			return code
+		}
+		challenge := func(email string) login.ChallengeResponse {
+			resp := post("/auth/login", `{"email":"`+email+`","password":"ride-a-bike-1"}`)
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			var challenge login.ChallengeResponse
+			require.NoError(t, render.DecodeJSON(resp.Body, &challenge))
+			require.True(t, challenge.MFARequired)
+			return challenge
+		}
+
+		registerResp := post("/auth/register", `{"user":{"name":"Mike","lastname":"Doe","email":"mike@example.com","phone":"111222","password":"ride-a-bike-1"}}`)
+		require.Equal(t, http.StatusCreated, registerResp.Code)
+		var session register.SuccessResponse
+		require.NoError(t, render.DecodeJSON(registerResp.Body, &session))
+
+		resp := post("/auth/mfa/enroll", ``, session.Token)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var enrollment enroll.Response
+		require.NoError(t, render.DecodeJSON(resp.Body, &enrollment))
+		assert.Contains(t, enrollment.URI, "mike@example.com")
+
+		first := code(enrollment.Secret)
+		resp = post("/auth/mfa/confirm", `{"code":"`+first+`"}`, session.Token)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var confirmed confirm.Response
+		require.NoError(t, render.DecodeJSON(resp.Body, &confirmed))
+		require.Len(t, confirmed.RecoveryCodes, 10)
+
+		// the session the authenticator was confirmed in passed MFA and keeps refreshing
+		resp = post("/auth/refresh", `{"refresh_token":"`+session.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		// the password alone gives a challenge, which is not an access token
+		mike := challenge("mike@example.com")
+		resp = post("/auth/mfa/enroll", ``, mike.ChallengeToken)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		// a code is accepted once
+		resp = post("/auth/mfa/verify", `{"challenge_token":"`+mike.ChallengeToken+`","code":"`+first+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		resp = post("/auth/mfa/verify", `{"challenge_token":"`+mike.ChallengeToken+`","code":"`+confirmed.RecoveryCodes[0]+`"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var verified verify.SuccessResponse
+		require.NoError(t, render.DecodeJSON(resp.Body, &verified))
+		assert.NotEmpty(t, verified.Token)
+		assert.Empty(t, verified.RecoveryCodes)
+
+		resp = post("/auth/mfa/verify", `{"challenge_token":"`+mike.ChallengeToken+`","code":"`+confirmed.RecoveryCodes[0]+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		// admins have to add an authenticator at their next login
+		resp = post("/auth/login", `{"email":"john@example.com","password":"ride-a-bike-1"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var beforeAdmin login.SuccessResponse
+		require.NoError(t, render.DecodeJSON(resp.Body, &beforeAdmin))
+
+		var john models.User
+		require.NoError(t, db.Where("email = ?", "john@example.com").First(&john).Error)
+		require.NoError(t, db.Create(&models.Admin{UserID: john.ID}).Error)
+
+		// a session from before is not refreshed into admin tokens without the second factor
+		resp = post("/auth/refresh", `{"refresh_token":"`+beforeAdmin.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		// the secret comes through the emailed link, not with the password
+		admin := challenge("john@example.com")
+		require.True(t, admin.SetupLinkSent)
+		msg, ok := outbox.last(notify.Email)
+		require.True(t, ok)
+		assert.Equal(t, "john@example.com", msg.To)
+		_, link, _ := strings.Cut(msg.Body, "?token=")
+		setupToken, err := url.QueryUnescape(strings.Fields(link)[0])
+		require.NoError(t, err)
+
+		resp = post("/auth/mfa/setup", `{"token":"`+setupToken+`"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var setupResp setup.Response
+		require.NoError(t, render.DecodeJSON(resp.Body, &setupResp))
+
+		resp = post("/auth/mfa/setup", `{"token":"`+setupToken+`"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code, "the link works once")
+
+		// a later login sends a new link, the authenticator added from the first one still works
+		admin = challenge("john@example.com")
+		require.True(t, admin.SetupLinkSent)
+
+		resp = post("/auth/mfa/verify", `{"challenge_token":"`+admin.ChallengeToken+`","code":"`+code(setupResp.Secret)+`"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.NoError(t, render.DecodeJSON(resp.Body, &verified))
+		assert.Len(t, verified.RecoveryCodes, 10)
+
+		resp = post("/auth/refresh", `{"refresh_token":"`+verified.RefreshToken+`"}`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("oidc", func(t *testing.T) {
+		// signIn goes through the provider as the browser would, carrying the state cookie
+		signIn := func(identity oidc.Identity) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/auth/oidc/test", nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusFound, resp.Code)
+			cookies := resp.Result().Cookies()
+			require.Len(t, cookies, 1)
+			require.Equal(t, start.StateCookie, cookies[0].Name)
+
+			redirect, err := issuer.Authorize(resp.Header().Get("Location"), identity)
+			require.NoError(t, err)
+			callback, err := url.Parse(redirect)
+			require.NoError(t, err)
+
+			req = httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+			req.AddCookie(cookies[0])
+			resp = httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			return resp
+		}
+
+		// a new account, without a password
+		ann := oidc.Identity{Subject: "ann-1", Email: "ann@example.com", EmailVerified: true, GivenName: "Ann", FamilyName: "Lee"}
+		resp := signIn(ann)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var session login.SuccessResponse
+		require.NoError(t, render.DecodeJSON(resp.Body, &session))
+		assert.NotEmpty(t, session.Token)
+		assert.Equal(t, "Ann", *session.User.Name)
+		assert.NotNil(t, session.User.EmailVerifiedAt)
+
+		resp = post("/auth/login", `{"email":"ann@example.com","password":"ride-a-bike-1"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		// the same identity signs in to the same account, whatever email it has now
+		ann.Email = "ann.lee@example.com"
+		resp = signIn(ann)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var again login.SuccessResponse
+		require.NoError(t, render.DecodeJSON(resp.Body, &again))
+		assert.Equal(t, session.User.ID, again.User.ID)
+
+		// linked to the verified account of john, who still has to pass the second factor
+		resp = signIn(oidc.Identity{Subject: "john-1", Email: "john@example.com", EmailVerified: true})
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		var identity models.ExternalIdentity
+		require.NoError(t, db.Preload("User").Where("provider = ? AND subject = ?", "test", "john-1").First(&identity).Error)
+		assert.Equal(t, "john@example.com", *identity.User.Email)
+
+		// jane has not verified her email, whoever signs in with it can't take the account
+		resp = signIn(oidc.Identity{Subject: "jane-1", Email: "jane@example.com", EmailVerified: true})
+		assert.Equal(t, http.StatusConflict, resp.Code)
+
+		resp = signIn(oidc.Identity{Subject: "bob-1", Email: "bob@example.com"})
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+
+		// the state is good once and only with its cookie
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/test/callback?code=code-1&state=forged", nil)
+		resp = httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		var p problem.Problem
+		require.NoError(t, render.DecodeJSON(resp.Body, &p))
+		assert.Equal(t, service.ErrInvalidOIDCState.Error(), p.Detail)
+	})
+}
+
+// outbox keeps sent messages instead of delivering them
+type outbox struct {
+	messages []notify.Message
+}
+
+func (o *outbox) Send(_ context.Context, msg notify.Message) error {
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+// last returns the latest message sent through the channel
+func (o *outbox) last(channel string) (notify.Message, bool) {
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if o.messages[i].Channel == channel {
+			return o.messages[i], true
+		}
+	}
+	return notify.Message{}, false
 }
@@ -0,0 +1,114 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/dto"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBicycleRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "stations")
+
+	stations := postgres.NewStationRepository(db)
+	first := &models.Station{LocationStreet: "first street 1"}
+	second := &models.Station{LocationStreet: "second street 2"}
+	require.NoError(t, stations.Create(ctx, first))
+	require.NoError(t, stations.Create(ctx, second))
+
+	repo := postgres.NewBicycleRepository(db)
+
+	bicycle := &models.Bicycle{StationID: first.ID, Type: models.BicycleTypeStandard, Status: models.BicycleStatusAvailable}
+
+	counters := func(id uint64) (int, int) {
+		station, err := stations.GetByID(ctx, id)
+		require.NoError(t, err)
+		return station.BikesTotal, station.BikesAvailable
+	}
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, bicycle))
+		require.NotZero(t, bicycle.ID)
+
+		total, available := counters(first.ID)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, 1, available)
+
+		// station does not exist
+		err := repo.Create(ctx, &models.Bicycle{StationID: 404, Type: models.BicycleTypeStandard, Status: models.BicycleStatusAvailable})
+		assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+	})
+
+	t.Run("list and count", func(t *testing.T) {
+		filter := dto.BicycleFilter{StationID: Ptr(first.ID), Status: Ptr(models.BicycleStatusAvailable)}
+
+		bicycles, err := repo.List(ctx, filter, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, bicycles, 1)
+
+		count, err := repo.Count(ctx, dto.BicycleFilter{StationID: Ptr(second.ID)})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		bicycle.Status = models.BicycleStatusInService
+		require.NoError(t, repo.UpdateStatus(ctx, bicycle, models.BicycleStatusAvailable))
+
+		total, available := counters(first.ID)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, 0, available)
+
+		// stale from status
+		err := repo.UpdateStatus(ctx, bicycle, models.BicycleStatusAvailable)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		bicycle.Status = models.BicycleStatusAvailable
+		bicycle.LastService = Ptr(time.Now())
+		require.NoError(t, repo.UpdateStatus(ctx, bicycle, models.BicycleStatusInService))
+
+		_, available = counters(first.ID)
+		assert.Equal(t, 1, available)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		require.NoError(t, repo.Move(ctx, bicycle.ID, models.BicycleStatusAvailable, first.ID, second.ID))
+
+		total, available := counters(first.ID)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, 0, available)
+		total, available = counters(second.ID)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, 1, available)
+
+		// bike is not at the first station anymore
+		err := repo.Move(ctx, bicycle.ID, models.BicycleStatusAvailable, first.ID, second.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("retire", func(t *testing.T) {
+		// read before the move, the counters of the old station must stay as they are
+		bicycle.Status = models.BicycleStatusRetired
+		err := repo.UpdateStatus(ctx, bicycle, models.BicycleStatusAvailable)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		bicycle.StationID = second.ID
+		require.NoError(t, repo.UpdateStatus(ctx, bicycle, models.BicycleStatusAvailable))
+
+		total, available := counters(second.ID)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, 0, available)
+	})
+}
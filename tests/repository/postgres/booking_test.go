@@ -0,0 +1,137 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBookingRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+	test_postgres.ClearTable(t, db, "stations")
+
+	users := postgres.NewUserRepository(db)
+	riders := []*models.User{
+		{Email: Ptr("first@example.com"), Phone: Ptr("1"), Status: Ptr(models.UserStatusActive)},
+		{Email: Ptr("second@example.com"), Phone: Ptr("2"), Status: Ptr(models.UserStatusActive)},
+	}
+	for _, rider := range riders {
+		require.NoError(t, users.Create(ctx, rider))
+	}
+
+	stations := postgres.NewStationRepository(db)
+	station := &models.Station{LocationStreet: "booking street 1"}
+	require.NoError(t, stations.Create(ctx, station))
+
+	bicycles := postgres.NewBicycleRepository(db)
+	bicycle := &models.Bicycle{StationID: station.ID, Type: models.BicycleTypeStandard, Status: models.BicycleStatusAvailable}
+	require.NoError(t, bicycles.Create(ctx, bicycle))
+
+	repo := postgres.NewBookingRepository(db)
+
+	newBooking := func(userID uint64, expiresAt time.Time) *models.Booking {
+		return &models.Booking{
+			UserID:    userID,
+			BicycleID: bicycle.ID,
+			StationID: station.ID,
+			Status:    models.BookingStatusActive,
+			ExpiresAt: Ptr(expiresAt),
+		}
+	}
+
+	available := func() int {
+		saved, err := stations.GetByID(ctx, station.ID)
+		require.NoError(t, err)
+		return saved.BikesAvailable
+	}
+
+	var booking *models.Booking
+
+	t.Run("only one concurrent hold wins", func(t *testing.T) {
+		results := make([]error, len(riders))
+		bookings := make([]*models.Booking, len(riders))
+
+		var wg sync.WaitGroup
+		for i, rider := range riders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bookings[i] = newBooking(rider.ID, time.Now().Add(-time.Minute))
+				results[i] = repo.Hold(ctx, bookings[i])
+			}()
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for i, err := range results {
+			if err == nil {
+				succeeded++
+				booking = bookings[i]
+				continue
+			}
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		}
+		require.Equal(t, 1, succeeded)
+		assert.Equal(t, 0, available())
+	})
+
+	t.Run("expired booking can not be converted", func(t *testing.T) {
+		rental := &models.Rental{
+			UserID:         booking.UserID,
+			BicycleID:      bicycle.ID,
+			StationStartID: station.ID,
+			Status:         models.RentalStatusActive,
+			StartTime:      Ptr(time.Now()),
+		}
+		assert.ErrorIs(t, repo.Convert(ctx, booking, rental), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("list expired and release", func(t *testing.T) {
+		expired, err := repo.ListExpired(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+
+		require.NoError(t, repo.Release(ctx, &expired[0], models.BookingStatusExpired))
+		assert.Equal(t, 1, available())
+
+		// already released
+		assert.ErrorIs(t, repo.Release(ctx, &expired[0], models.BookingStatusCancelled), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		booking = newBooking(riders[0].ID, time.Now().Add(time.Hour))
+		require.NoError(t, repo.Hold(ctx, booking))
+
+		rental := &models.Rental{
+			UserID:         riders[0].ID,
+			BicycleID:      bicycle.ID,
+			StationStartID: station.ID,
+			Status:         models.RentalStatusActive,
+			StartTime:      Ptr(time.Now()),
+		}
+		require.NoError(t, repo.Convert(ctx, booking, rental))
+
+		saved, err := repo.GetByID(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BookingStatusConverted, saved.Status)
+		assert.Equal(t, rental.ID, *saved.RentalID)
+
+		bike, err := bicycles.GetByID(ctx, bicycle.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BicycleStatusRented, bike.Status)
+		assert.Equal(t, 0, available())
+	})
+}
@@ -0,0 +1,61 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "login_attempts")
+
+	repo := postgres.NewLoginAttemptRepository(db)
+	const key = "account:locked@example.com"
+
+	t.Run("unknown key has no failures", func(t *testing.T) {
+		got, err := repo.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, &models.LoginAttempt{Key: key}, got)
+	})
+
+	t.Run("concurrent updates are all counted", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Update(ctx, key, func(a *models.LoginAttempt) {
+					a.Failures++
+					a.LastFailureAt = Ptr(time.Now())
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		got, err := repo.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, 10, got.Failures)
+		assert.NotNil(t, got.LastFailureAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, key))
+
+		got, err := repo.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Zero(t, got.Failures)
+	})
+}
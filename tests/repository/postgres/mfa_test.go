@@ -0,0 +1,100 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMFARepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+
+	user := &models.User{Name: Ptr("MFA"), Lastname: Ptr("User"), Email: Ptr("mfa@example.com"), Phone: Ptr("557"), Status: Ptr(models.UserStatusActive), Password: Ptr("hash")}
+	require.NoError(t, postgres.NewUserRepository(db).Create(ctx, user))
+
+	repo := postgres.NewMFARepository(db)
+
+	session := &models.Session{ID: "mfa-phone", UserID: user.ID, ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+	require.NoError(t, postgres.NewRefreshTokenRepository(db).CreateSession(ctx, session, &models.RefreshToken{UserID: user.ID, TokenHash: "mfa-phone", ExpiresAt: session.ExpiresAt}))
+
+	t.Run("enrollment can be restarted until confirmed", func(t *testing.T) {
+		_, err := repo.GetSecret(ctx, user.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, repo.SaveSecret(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "FIRST"}))
+		require.NoError(t, repo.SaveSecret(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "SECOND"}))
+
+		secret, err := repo.GetSecret(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "SECOND", secret.Secret)
+		assert.Nil(t, secret.ConfirmedAt)
+
+		// codes are only accepted once confirmed
+		assert.ErrorIs(t, repo.UseStep(ctx, user.ID, 100), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("setup link", func(t *testing.T) {
+		expires := Ptr(time.Now().Add(time.Hour))
+		require.NoError(t, repo.SaveSetupLink(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "OTHER", SetupTokenHash: Ptr("link-1"), SetupExpiresAt: expires}))
+		require.NoError(t, repo.SaveSetupLink(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "OTHER", SetupTokenHash: Ptr("link-2"), SetupExpiresAt: expires}))
+
+		// a new link replaces the earlier one but keeps the secret
+		_, err := repo.UseSetupLink(ctx, "link-1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		secret, err := repo.UseSetupLink(ctx, "link-2")
+		require.NoError(t, err)
+		assert.Equal(t, "SECOND", secret.Secret)
+		assert.Equal(t, user.ID, secret.UserID)
+
+		_, err = repo.UseSetupLink(ctx, "link-2")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "the link works once")
+
+		require.NoError(t, repo.SaveSetupLink(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "OTHER", SetupTokenHash: Ptr("link-3"), SetupExpiresAt: Ptr(time.Now().Add(-time.Minute))}))
+		_, err = repo.UseSetupLink(ctx, "link-3")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "expired")
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		require.NoError(t, repo.Confirm(ctx, user.ID, "mfa-phone", 100, []string{"hash-1", "hash-2"}))
+		assert.ErrorIs(t, repo.Confirm(ctx, user.ID, "mfa-phone", 101, []string{"hash-3"}), gorm.ErrRecordNotFound)
+
+		secret, err := repo.GetSecret(ctx, user.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, secret.ConfirmedAt)
+		assert.Equal(t, int64(100), secret.LastStep)
+
+		// the session the code was given in passed MFA
+		var confirmedIn models.Session
+		require.NoError(t, db.First(&confirmedIn, "id = ?", "mfa-phone").Error)
+		assert.NotNil(t, confirmedIn.MFAAt)
+
+		assert.ErrorIs(t, repo.SaveSecret(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "THIRD"}), gorm.ErrDuplicatedKey)
+		assert.ErrorIs(t, repo.SaveSetupLink(ctx, &models.TOTPSecret{UserID: user.ID, Secret: "THIRD", SetupTokenHash: Ptr("link-4")}), gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("steps are used once and in order", func(t *testing.T) {
+		assert.ErrorIs(t, repo.UseStep(ctx, user.ID, 100), gorm.ErrRecordNotFound)
+		require.NoError(t, repo.UseStep(ctx, user.ID, 101))
+		assert.ErrorIs(t, repo.UseStep(ctx, user.ID, 101), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.UseStep(ctx, user.ID, 99), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("recovery codes are single use", func(t *testing.T) {
+		require.NoError(t, repo.UseRecoveryCode(ctx, user.ID, "hash-1"))
+		assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "hash-1"), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "unknown"), gorm.ErrRecordNotFound)
+		require.NoError(t, repo.UseRecoveryCode(ctx, user.ID, "hash-2"))
+	})
+}
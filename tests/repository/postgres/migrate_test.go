@@ -0,0 +1,90 @@
+package repository_postgres_test
+
+import (
+	"sdt-bicycle-rental/internal/repository"
+	"sdt-bicycle-rental/internal/repository/migrations"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+
+	migrator, err := repository.NewMigrator(db, migrations.FS)
+	require.NoError(t, err)
+
+	t.Run("setup leaves the schema current", func(t *testing.T) {
+		require.NoError(t, migrator.Check())
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt, "migration %d", status.Version)
+		}
+	})
+
+	t.Run("down and up again", func(t *testing.T) {
+		require.NoError(t, migrator.To(0))
+		assert.ErrorIs(t, migrator.Check(), repository.ErrSchemaOutdated)
+		assert.False(t, db.Migrator().HasTable("users"))
+
+		require.NoError(t, migrator.Up())
+		require.NoError(t, migrator.Check())
+		assert.True(t, db.Migrator().HasTable("users"))
+
+		// up is idempotent
+		require.NoError(t, migrator.Up())
+	})
+
+	t.Run("skipped migration", func(t *testing.T) {
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		skipped := statuses[len(statuses)/2].Migration
+		require.NoError(t, db.Delete(&repository.SchemaMigration{}, skipped.Version).Error)
+		defer func() {
+			require.NoError(t, db.Create(&repository.SchemaMigration{Version: skipped.Version, Name: skipped.Name}).Error)
+		}()
+
+		assert.ErrorIs(t, migrator.Check(), repository.ErrSchemaOutdated)
+	})
+
+	t.Run("check does not create the table", func(t *testing.T) {
+		require.NoError(t, migrator.To(0))
+		require.NoError(t, db.Migrator().DropTable("schema_migrations"))
+
+		assert.ErrorIs(t, migrator.Check(), repository.ErrSchemaOutdated)
+		assert.False(t, db.Migrator().HasTable("schema_migrations"))
+
+		require.NoError(t, migrator.Up())
+		require.NoError(t, migrator.Check())
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		assert.ErrorIs(t, migrator.To(9999), repository.ErrUnknownVersion)
+	})
+
+	t.Run("older binary refuses newer schema", func(t *testing.T) {
+		newer := fstest.MapFS{}
+		entries, err := migrations.FS.ReadDir(".")
+		require.NoError(t, err)
+		for _, entry := range entries {
+			data, err := migrations.FS.ReadFile(entry.Name())
+			require.NoError(t, err)
+			newer[entry.Name()] = &fstest.MapFile{Data: data}
+		}
+		newer["9000_probe.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE migration_probe (id INT)")}
+		newer["9000_probe.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE migration_probe")}
+
+		next, err := repository.NewMigrator(db, newer)
+		require.NoError(t, err)
+		require.NoError(t, next.Up())
+		defer func() { require.NoError(t, next.Down()) }()
+
+		assert.ErrorIs(t, migrator.Check(), repository.ErrSchemaTooNew)
+	})
+}
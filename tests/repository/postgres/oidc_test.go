@@ -0,0 +1,90 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestOIDCRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+	test_postgres.ClearTable(t, db, "oidc_states")
+
+	userRepo := postgres.NewUserRepository(db)
+	repo := postgres.NewOIDCRepository(db)
+
+	t.Run("states are taken once", func(t *testing.T) {
+		expired := &models.OIDCState{StateHash: "expired", Provider: "test", Nonce: "n", CodeVerifier: "v", ExpiresAt: Ptr(time.Now().Add(-time.Minute))}
+		require.NoError(t, db.Create(expired).Error)
+
+		state := &models.OIDCState{StateHash: "hash", Provider: "test", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: Ptr(time.Now().Add(time.Minute))}
+		require.NoError(t, repo.SaveState(ctx, state))
+
+		// saving drops the expired states
+		_, err := repo.TakeState(ctx, "expired")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		taken, err := repo.TakeState(ctx, "hash")
+		require.NoError(t, err)
+		assert.Equal(t, "nonce", taken.Nonce)
+		assert.Equal(t, "verifier", taken.CodeVerifier)
+
+		_, err = repo.TakeState(ctx, "hash")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("link", func(t *testing.T) {
+		user := &models.User{Name: Ptr("Linked"), Lastname: Ptr("User"), Email: Ptr("linked@example.com"), Phone: Ptr("558"), Status: Ptr(models.UserStatusActive), Password: Ptr("hash")}
+		require.NoError(t, userRepo.Create(ctx, user))
+
+		_, err := repo.GetIdentity(ctx, "test", "sub-1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, repo.Link(ctx, &models.ExternalIdentity{UserID: user.ID, Provider: "test", Subject: "sub-1", Email: user.Email}))
+		assert.ErrorIs(t, repo.Link(ctx, &models.ExternalIdentity{UserID: user.ID, Provider: "test", Subject: "sub-1"}), gorm.ErrDuplicatedKey)
+
+		identity, err := repo.GetIdentity(ctx, "test", "sub-1")
+		require.NoError(t, err)
+		require.NotNil(t, identity.User)
+		assert.Equal(t, user.ID, identity.User.ID)
+
+		// the same subject at another provider is another account
+		_, err = repo.GetIdentity(ctx, "other", "sub-1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		// deleting the account unlinks it
+		require.NoError(t, userRepo.AnonymizeAndMarkDeleted(ctx, user.ID))
+		_, err = repo.GetIdentity(ctx, "test", "sub-1")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("create user", func(t *testing.T) {
+		user := &models.User{Name: Ptr("External"), Email: Ptr("external@example.com"), Status: Ptr(models.UserStatusPending), EmailVerifiedAt: Ptr(time.Now())}
+		identity := &models.ExternalIdentity{Provider: "test", Subject: "sub-2", Email: user.Email}
+		require.NoError(t, repo.CreateUser(ctx, user, identity))
+		assert.NotZero(t, user.ID)
+		assert.Equal(t, user.ID, identity.UserID)
+
+		got, err := repo.GetIdentity(ctx, "test", "sub-2")
+		require.NoError(t, err)
+		assert.Nil(t, got.User.Password)
+
+		// a taken email leaves no identity behind
+		err = repo.CreateUser(ctx, &models.User{Email: Ptr("external@example.com")}, &models.ExternalIdentity{Provider: "test", Subject: "sub-3"})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+		_, err = repo.GetIdentity(ctx, "test", "sub-3")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,83 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPasswordResetRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+
+	user := &models.User{Name: Ptr("Reset"), Lastname: Ptr("User"), Email: Ptr("reset@example.com"), Phone: Ptr("555"), Status: Ptr("active"), Password: Ptr("old")}
+	require.NoError(t, postgres.NewUserRepository(db).Create(ctx, user))
+	session := &models.Session{ID: "reset-family", UserID: user.ID, ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+	refresh := &models.RefreshToken{UserID: user.ID, TokenHash: "session", ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+	require.NoError(t, postgres.NewRefreshTokenRepository(db).CreateSession(ctx, session, refresh))
+
+	repo := postgres.NewPasswordResetRepository(db)
+	newToken := func(hash string, ttl time.Duration) {
+		require.NoError(t, repo.Create(ctx, &models.PasswordResetToken{UserID: user.ID, TokenHash: hash, ExpiresAt: Ptr(time.Now().Add(ttl))}))
+	}
+
+	t.Run("expired token", func(t *testing.T) {
+		newToken("expired", -time.Minute)
+
+		_, err := repo.GetValid(ctx, "expired")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.Reset(ctx, "expired", "new")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("newer token retires older ones", func(t *testing.T) {
+		newToken("first", time.Hour)
+		newToken("second", time.Hour)
+
+		_, err := repo.GetValid(ctx, "first")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.Reset(ctx, "first", "new")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		// the user comes with the token, a new password must not be their email or name
+		token, err := repo.GetValid(ctx, "second")
+		require.NoError(t, err)
+		require.NotNil(t, token.User)
+		assert.Equal(t, "reset@example.com", *token.User.Email)
+
+		userID, err := repo.Reset(ctx, "second", "new")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, userID)
+
+		var saved models.User
+		require.NoError(t, db.First(&saved, user.ID).Error)
+		assert.Equal(t, "new", *saved.Password)
+
+		var revoked models.RefreshToken
+		require.NoError(t, db.First(&revoked, refresh.ID).Error)
+		assert.NotNil(t, revoked.RevokedAt)
+		// so are the access tokens of the session
+		var signedOut models.Session
+		require.NoError(t, db.First(&signedOut, "id = ?", session.ID).Error)
+		assert.NotNil(t, signedOut.RevokedAt)
+
+		// single use
+		_, err = repo.GetValid(ctx, "second")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.Reset(ctx, "second", "newer")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
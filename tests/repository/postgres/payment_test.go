@@ -0,0 +1,98 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPaymentRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+	test_postgres.ClearTable(t, db, "stations")
+	test_postgres.ClearTable(t, db, "payments")
+
+	users := postgres.NewUserRepository(db)
+	rider := &models.User{Email: Ptr("payer@example.com"), Phone: Ptr("1"), Status: Ptr(models.UserStatusActive)}
+	require.NoError(t, users.Create(ctx, rider))
+
+	stations := postgres.NewStationRepository(db)
+	station := &models.Station{LocationStreet: "payment street 1"}
+	require.NoError(t, stations.Create(ctx, station))
+
+	bicycles := postgres.NewBicycleRepository(db)
+	bicycle := &models.Bicycle{StationID: station.ID, Type: models.BicycleTypeStandard, Status: models.BicycleStatusAvailable}
+	require.NoError(t, bicycles.Create(ctx, bicycle))
+
+	bookings := postgres.NewBookingRepository(db)
+	booking := &models.Booking{
+		UserID: rider.ID, BicycleID: bicycle.ID, StationID: station.ID,
+		Status: models.BookingStatusActive, ExpiresAt: Ptr(time.Now().Add(15 * time.Minute)),
+	}
+	require.NoError(t, bookings.Hold(ctx, booking))
+
+	repo := postgres.NewPaymentRepository(db)
+
+	fee := &models.Payment{
+		UserID: rider.ID, BookingID: &booking.ID, Method: "card", Amount: 500, Currency: "UAH",
+		Status: models.PaymentStatusPending,
+	}
+	ride := &models.Payment{
+		UserID: rider.ID, RentalID: Ptr(uint64(42)), Method: "card", Amount: 3200, Currency: "UAH",
+		Status: models.PaymentStatusPending,
+	}
+
+	t.Run("create links booking", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, fee))
+		require.NoError(t, repo.Create(ctx, ride))
+
+		saved, err := bookings.GetByID(ctx, booking.ID)
+		require.NoError(t, err)
+		require.NotNil(t, saved.PaymentID)
+		assert.Equal(t, fee.ID, *saved.PaymentID)
+	})
+
+	t.Run("rental is charged once", func(t *testing.T) {
+		err := repo.Create(ctx, &models.Payment{
+			UserID: rider.ID, RentalID: Ptr(uint64(42)), Method: "card", Amount: 3200, Currency: "UAH",
+			Status: models.PaymentStatusPending,
+		})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		ride.Status, ride.TransactionID = models.PaymentStatusAuthorized, "fake_000001"
+		require.NoError(t, repo.UpdateStatus(ctx, ride, models.PaymentStatusPending))
+
+		// stale transition
+		ride.Status = models.PaymentStatusVoided
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, ride, models.PaymentStatusPending), gorm.ErrRecordNotFound)
+
+		saved, err := repo.GetByID(ctx, ride.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusAuthorized, saved.Status)
+		assert.Equal(t, "fake_000001", saved.TransactionID)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		payments, err := repo.ListByUserID(ctx, rider.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, payments, 2)
+		assert.Equal(t, ride.ID, payments[0].ID)
+
+		count, err := repo.CountByUserID(ctx, rider.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
@@ -0,0 +1,107 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestRefreshTokenRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+
+	user := &models.User{Email: Ptr("tokens@example.com"), Status: Ptr(models.UserStatusActive)}
+	require.NoError(t, postgres.NewUserRepository(db).Create(ctx, user))
+
+	repo := postgres.NewRefreshTokenRepository(db)
+
+	session := &models.Session{ID: "family", UserID: user.ID, DeviceName: Ptr("Pixel 8"), ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+	first := &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: "first",
+		ExpiresAt: Ptr(time.Now().Add(time.Hour)),
+	}
+
+	t.Run("create and get by hash", func(t *testing.T) {
+		require.NoError(t, repo.CreateSession(ctx, session, first))
+		require.NotZero(t, first.ID)
+		assert.Equal(t, "family", first.FamilyID)
+
+		active, err := repo.SessionActive(ctx, "family")
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		got, err := repo.GetSession(ctx, "family")
+		require.NoError(t, err)
+		assert.Equal(t, "Pixel 8", *got.DeviceName)
+		assert.Nil(t, got.MFAAt)
+
+		saved, err := repo.GetByHash(ctx, "first")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, saved.ID)
+
+		_, err = repo.GetByHash(ctx, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		second := &models.RefreshToken{UserID: user.ID, FamilyID: "family", TokenHash: "second", ExpiresAt: Ptr(time.Now().Add(2 * time.Hour))}
+		require.NoError(t, repo.Rotate(ctx, first, second))
+
+		// the session lasts as long as its latest token
+		var seen models.Session
+		require.NoError(t, db.First(&seen, "id = ?", "family").Error)
+		assert.NotNil(t, seen.LastSeenAt)
+		assert.WithinDuration(t, *second.ExpiresAt, *seen.ExpiresAt, time.Second)
+
+		saved, err := repo.GetByHash(ctx, "first")
+		require.NoError(t, err)
+		require.NotNil(t, saved.UsedAt)
+		assert.Equal(t, second.ID, *saved.ReplacedByID)
+
+		// token can be rotated only once
+		third := &models.RefreshToken{UserID: user.ID, FamilyID: "family", TokenHash: "third", ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+		require.ErrorIs(t, repo.Rotate(ctx, first, third), gorm.ErrRecordNotFound)
+
+		_, err = repo.GetByHash(ctx, "third")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("revoke family", func(t *testing.T) {
+		require.NoError(t, repo.RevokeFamily(ctx, "family"))
+
+		saved, err := repo.GetByHash(ctx, "second")
+		require.NoError(t, err)
+		assert.NotNil(t, saved.RevokedAt)
+
+		active, err := repo.SessionActive(ctx, "family")
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("revoke all for user", func(t *testing.T) {
+		other := &models.Session{ID: "other", UserID: user.ID, ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+		token := &models.RefreshToken{UserID: user.ID, TokenHash: "other", ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+		require.NoError(t, repo.CreateSession(ctx, other, token))
+
+		require.NoError(t, repo.RevokeAllForUser(ctx, user.ID))
+
+		saved, err := repo.GetByHash(ctx, "other")
+		require.NoError(t, err)
+		assert.NotNil(t, saved.RevokedAt)
+		active, err := repo.SessionActive(ctx, "other")
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+}
@@ -0,0 +1,113 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestRentalRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+	test_postgres.ClearTable(t, db, "stations")
+
+	users := postgres.NewUserRepository(db)
+	riders := []*models.User{
+		{Email: Ptr("first@example.com"), Phone: Ptr("1"), Status: Ptr(models.UserStatusActive)},
+		{Email: Ptr("second@example.com"), Phone: Ptr("2"), Status: Ptr(models.UserStatusActive)},
+	}
+	for _, rider := range riders {
+		require.NoError(t, users.Create(ctx, rider))
+	}
+
+	stations := postgres.NewStationRepository(db)
+	start := &models.Station{LocationStreet: "start street 1"}
+	finish := &models.Station{LocationStreet: "finish street 2"}
+	require.NoError(t, stations.Create(ctx, start))
+	require.NoError(t, stations.Create(ctx, finish))
+
+	bicycle := &models.Bicycle{StationID: start.ID, Type: models.BicycleTypeStandard, Status: models.BicycleStatusAvailable}
+	require.NoError(t, postgres.NewBicycleRepository(db).Create(ctx, bicycle))
+
+	repo := postgres.NewRentalRepository(db)
+
+	var rental *models.Rental
+
+	t.Run("only one concurrent start wins", func(t *testing.T) {
+		results := make([]error, len(riders))
+		rentals := make([]*models.Rental, len(riders))
+
+		var wg sync.WaitGroup
+		for i, rider := range riders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rentals[i] = &models.Rental{
+					UserID:         rider.ID,
+					BicycleID:      bicycle.ID,
+					StationStartID: start.ID,
+					Status:         models.RentalStatusActive,
+					StartTime:      Ptr(time.Now()),
+				}
+				results[i] = repo.Start(ctx, rentals[i])
+			}()
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for i, err := range results {
+			if err == nil {
+				succeeded++
+				rental = rentals[i]
+				continue
+			}
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		}
+		require.Equal(t, 1, succeeded)
+
+		saved, err := stations.GetByID(ctx, start.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, saved.BikesAvailable)
+	})
+
+	t.Run("get active", func(t *testing.T) {
+		active, err := repo.GetActiveByUserID(ctx, rental.UserID)
+		require.NoError(t, err)
+		assert.Equal(t, rental.ID, active.ID)
+		assert.Nil(t, active.EndTime)
+	})
+
+	t.Run("end at another station", func(t *testing.T) {
+		rental.StationEndID = Ptr(finish.ID)
+		rental.EndTime = Ptr(time.Now())
+		rental.TotalCost = Ptr(int64(1200))
+		require.NoError(t, repo.End(ctx, rental))
+
+		saved, err := repo.GetByID(ctx, rental.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RentalStatusCompleted, saved.Status)
+
+		from, err := stations.GetByID(ctx, start.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, from.BikesTotal)
+		to, err := stations.GetByID(ctx, finish.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, to.BikesTotal)
+		assert.Equal(t, 1, to.BikesAvailable)
+
+		// ending twice
+		assert.ErrorIs(t, repo.End(ctx, rental), gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,82 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSessionRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+
+	userRepo := postgres.NewUserRepository(db)
+	owner := &models.User{Email: Ptr("owner@example.com"), Status: Ptr(models.UserStatusActive)}
+	require.NoError(t, userRepo.Create(ctx, owner))
+	stranger := &models.User{Email: Ptr("stranger@example.com"), Status: Ptr(models.UserStatusActive)}
+	require.NoError(t, userRepo.Create(ctx, stranger))
+
+	tokens := postgres.NewRefreshTokenRepository(db)
+	start := func(id string, userID uint64, lastSeen, expiresIn time.Duration) {
+		session := &models.Session{ID: id, UserID: userID, LastSeenAt: Ptr(time.Now().Add(-lastSeen)), ExpiresAt: Ptr(time.Now().Add(expiresIn))}
+		require.NoError(t, tokens.CreateSession(ctx, session, &models.RefreshToken{UserID: userID, TokenHash: id, ExpiresAt: session.ExpiresAt}))
+	}
+	start("laptop", owner.ID, time.Hour, time.Hour)
+	start("phone", owner.ID, time.Minute, time.Hour)
+	start("expired", owner.ID, 2*time.Hour, -time.Minute)
+	start("strangers", stranger.ID, time.Minute, time.Hour)
+
+	repo := postgres.NewSessionRepository(db)
+
+	t.Run("list active", func(t *testing.T) {
+		sessions, err := repo.ListActive(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, "phone", sessions[0].ID)
+		assert.Equal(t, "laptop", sessions[1].ID)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		// only the own sessions
+		assert.ErrorIs(t, repo.Revoke(ctx, owner.ID, "strangers"), gorm.ErrRecordNotFound)
+
+		require.NoError(t, repo.Revoke(ctx, owner.ID, "laptop"))
+		assert.ErrorIs(t, repo.Revoke(ctx, owner.ID, "laptop"), gorm.ErrRecordNotFound)
+
+		token, err := tokens.GetByHash(ctx, "laptop")
+		require.NoError(t, err)
+		assert.NotNil(t, token.RevokedAt)
+
+		sessions, err := repo.ListActive(ctx, owner.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "phone", sessions[0].ID)
+	})
+
+	t.Run("deleting the user revokes every session", func(t *testing.T) {
+		require.NoError(t, userRepo.AnonymizeAndMarkDeleted(ctx, owner.ID))
+
+		sessions, err := repo.ListActive(ctx, owner.ID)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+
+		token, err := tokens.GetByHash(ctx, "phone")
+		require.NoError(t, err)
+		assert.NotNil(t, token.RevokedAt)
+
+		active, err := tokens.SessionActive(ctx, "strangers")
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+}
@@ -0,0 +1,76 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTariffRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "tariffs")
+
+	repo := postgres.NewTariffRepository(db)
+	now := time.Now()
+
+	base := &models.Tariff{
+		Name: "base", UnlockFee: 1000, PerMinute: 200, ActiveFrom: Ptr(now.Add(-time.Hour)),
+		Rates: []models.TariffRate{
+			{BicycleType: models.BicycleTypeElectric, PerMinute: 300},
+			{StartHour: 7, EndHour: 10, PerMinute: 250},
+		},
+	}
+	scheduled := &models.Tariff{Name: "scheduled", PerMinute: 150, ActiveFrom: Ptr(now.Add(time.Hour))}
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, base))
+		require.NoError(t, repo.Create(ctx, scheduled))
+		require.NotZero(t, base.ID)
+		require.NotZero(t, base.Rates[0].ID)
+	})
+
+	t.Run("get by id keeps rate order", func(t *testing.T) {
+		tariff, err := repo.GetByID(ctx, base.ID)
+		require.NoError(t, err)
+		require.Len(t, tariff.Rates, 2)
+		assert.Equal(t, models.BicycleTypeElectric, tariff.Rates[0].BicycleType)
+
+		_, err = repo.GetByID(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("current ignores scheduled tariffs", func(t *testing.T) {
+		tariff, err := repo.GetCurrent(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, base.ID, tariff.ID)
+
+		tariff, err = repo.GetCurrent(ctx, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, scheduled.ID, tariff.ID)
+
+		_, err = repo.GetCurrent(ctx, now.Add(-2*time.Hour))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("list and count", func(t *testing.T) {
+		tariffs, err := repo.List(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, tariffs, 2)
+		assert.Equal(t, scheduled.ID, tariffs[0].ID)
+
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
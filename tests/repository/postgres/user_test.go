@@ -1,11 +1,13 @@
 package repository_postgres_test
 
 import (
+	"context"
 	"sdt-bicycle-rental/internal/models"
 	"sdt-bicycle-rental/internal/repository/postgres"
 	. "sdt-bicycle-rental/lib/util"
 	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +17,7 @@ import (
 func TestUserRepository(t *testing.T) {
 	db, cleanup := test_postgres.SetupTestDB(t)
 	defer cleanup()
+	ctx := context.Background()
 
 	test_postgres.ClearTable(t, db, "users")
 
@@ -30,7 +33,7 @@ func TestUserRepository(t *testing.T) {
 	}
 
 	t.Run("create", func(t *testing.T) {
-		err := repo.Create(user)
+		err := repo.Create(ctx, user)
 		require.NoError(t, err)
 		require.NotZero(t, user.ID)
 
@@ -42,40 +45,148 @@ func TestUserRepository(t *testing.T) {
 	})
 
 	t.Run("get by id", func(t *testing.T) {
-		saved, err := repo.GetByID(user.ID)
+		saved, err := repo.GetByID(ctx, user.ID)
 		require.NoError(t, err)
 		assert.Equal(t, *user.Email, *saved.Email)
 
 		// user not found
-		_, err = repo.GetByID(404)
+		_, err = repo.GetByID(ctx, 404)
 		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("create duplicate", func(t *testing.T) {
-		err := repo.Create(user)
+		err := repo.Create(ctx, user)
 		require.ErrorIs(t, err, gorm.ErrDuplicatedKey)
 	})
 
 	t.Run("update", func(t *testing.T) {
 		user.Name = Ptr("Updated")
-		err := repo.Update(user)
+		err := repo.Update(ctx, user)
 		require.NoError(t, err)
 
-		updated, err := repo.GetByID(user.ID)
+		updated, err := repo.GetByID(ctx, user.ID)
 		require.NoError(t, err)
 		require.Equal(t, *user.Name, *updated.Name)
 	})
 
+	t.Run("changed email is not verified", func(t *testing.T) {
+		now := time.Now()
+		require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+			Updates(map[string]interface{}{"email_verified_at": now, "phone_verified_at": now}).Error)
+		// sent to the old address, it must not verify the new one
+		code := &models.VerificationCode{UserID: user.ID, Channel: models.VerificationChannelEmail, CodeHash: "old-link", ExpiresAt: Ptr(now.Add(time.Hour))}
+		require.NoError(t, postgres.NewVerificationRepository(db).Create(ctx, code))
+
+		// the same phone keeps its verification
+		require.NoError(t, repo.Update(ctx, &models.User{ID: user.ID, Email: Ptr("changed@example.com"), Phone: user.Phone}))
+
+		saved, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "changed@example.com", *saved.Email)
+		assert.Nil(t, saved.EmailVerifiedAt)
+		assert.NotNil(t, saved.PhoneVerifiedAt)
+		assert.Equal(t, models.UserStatusPending, *saved.Status)
+
+		var void models.VerificationCode
+		require.NoError(t, db.First(&void, code.ID).Error)
+		assert.NotNil(t, void.UsedAt)
+
+		// back to the original address for the next subtests
+		require.NoError(t, repo.Update(ctx, &models.User{ID: user.ID, Email: user.Email}))
+		require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).Update("status", models.UserStatusActive).Error)
+
+		assert.ErrorIs(t, repo.Update(ctx, &models.User{ID: 404, Name: Ptr("Nobody")}), gorm.ErrRecordNotFound)
+	})
+
 	t.Run("get by email", func(t *testing.T) {
-		saved, err := repo.GetByEmail(*user.Email)
+		saved, err := repo.GetByEmail(ctx, *user.Email)
 		require.NoError(t, err)
 		assert.Equal(t, *user.Email, *saved.Email)
+
+		// emails don't differ by case
+		saved, err = repo.GetByEmail(ctx, "Test@Example.COM")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, saved.ID)
+	})
+
+	t.Run("update password hash", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePasswordHash(ctx, user.ID, "password123", "rehashed"))
+
+		// a password changed meanwhile is kept
+		err := repo.UpdatePasswordHash(ctx, user.ID, "password123", "stale")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		saved, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "rehashed", *saved.Password)
+	})
+
+	t.Run("change password", func(t *testing.T) {
+		tokens := postgres.NewRefreshTokenRepository(db)
+		for _, id := range []string{"current", "other"} {
+			session := &models.Session{ID: id, UserID: user.ID, ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+			require.NoError(t, tokens.CreateSession(ctx, session, &models.RefreshToken{UserID: user.ID, TokenHash: id, ExpiresAt: session.ExpiresAt}))
+		}
+
+		require.NoError(t, repo.ChangePassword(ctx, user.ID, "changed", "current"))
+
+		saved, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "changed", *saved.Password)
+
+		active, err := tokens.SessionActive(ctx, "current")
+		require.NoError(t, err)
+		assert.True(t, active)
+		active, err = tokens.SessionActive(ctx, "other")
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		assert.ErrorIs(t, repo.ChangePassword(ctx, 404, "changed", "current"), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("ban and unban", func(t *testing.T) {
+		banned, err := repo.Ban(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, banned)
+
+		saved, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusBanned, *saved.Status)
+
+		active, err := postgres.NewRefreshTokenRepository(db).SessionActive(ctx, "current")
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		banned, err = repo.Ban(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, banned)
+
+		// the user is active again only with a verified email and phone
+		require.NoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+			Updates(map[string]interface{}{"email_verified_at": time.Now(), "phone_verified_at": nil}).Error)
+
+		unbanned, err := repo.Unban(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, unbanned)
+
+		saved, err = repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusPending, *saved.Status)
+
+		unbanned, err = repo.Unban(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, unbanned)
+
+		_, err = repo.Ban(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.Unban(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("anonymize and mark deleted", func(t *testing.T) {
-		err := repo.AnonymizeAndMarkDeleted(user.ID)
+		err := repo.AnonymizeAndMarkDeleted(ctx, user.ID)
 		require.NoError(t, err)
-		deletedUser, err := repo.GetByID(user.ID)
+		deletedUser, err := repo.GetByID(ctx, user.ID)
 		require.NoError(t, err)
 		assert.NotZero(t, deletedUser.ID)
 		assert.Empty(t, deletedUser.Name)
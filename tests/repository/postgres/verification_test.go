@@ -0,0 +1,81 @@
+package repository_postgres_test
+
+import (
+	"context"
+	"sdt-bicycle-rental/internal/models"
+	"sdt-bicycle-rental/internal/repository/postgres"
+	. "sdt-bicycle-rental/lib/util"
+	test_postgres "sdt-bicycle-rental/tests/util/db/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestVerificationRepository(t *testing.T) {
+	db, cleanup := test_postgres.SetupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	test_postgres.ClearTable(t, db, "users")
+
+	user := &models.User{Name: Ptr("Verify"), Lastname: Ptr("User"), Email: Ptr("verify@example.com"), Phone: Ptr("556"), Status: Ptr(models.UserStatusPending), Password: Ptr("hash")}
+	require.NoError(t, postgres.NewUserRepository(db).Create(ctx, user))
+
+	repo := postgres.NewVerificationRepository(db)
+	newCode := func(channel, hash string) *models.VerificationCode {
+		code := &models.VerificationCode{UserID: user.ID, Channel: channel, CodeHash: hash, ExpiresAt: Ptr(time.Now().Add(time.Hour))}
+		require.NoError(t, repo.Create(ctx, code))
+		return code
+	}
+	status := func() *models.User {
+		var saved models.User
+		require.NoError(t, db.First(&saved, user.ID).Error)
+		return &saved
+	}
+
+	t.Run("newer code retires older ones", func(t *testing.T) {
+		first := newCode(models.VerificationChannelPhone, "first")
+		newCode(models.VerificationChannelPhone, "second")
+
+		assert.ErrorIs(t, repo.Verify(ctx, first), gorm.ErrRecordNotFound)
+
+		latest, err := repo.Latest(ctx, user.ID, models.VerificationChannelPhone)
+		require.NoError(t, err)
+		assert.Equal(t, "second", latest.CodeHash)
+	})
+
+	t.Run("attempts", func(t *testing.T) {
+		latest, err := repo.Latest(ctx, user.ID, models.VerificationChannelPhone)
+		require.NoError(t, err)
+		require.NoError(t, repo.AddAttempt(ctx, latest.ID))
+
+		latest, err = repo.Latest(ctx, user.ID, models.VerificationChannelPhone)
+		require.NoError(t, err)
+		assert.Equal(t, 1, latest.Attempts)
+	})
+
+	t.Run("pending until both verified", func(t *testing.T) {
+		email := newCode(models.VerificationChannelEmail, "email")
+		found, err := repo.GetByHash(ctx, models.VerificationChannelEmail, "email")
+		require.NoError(t, err)
+		assert.Equal(t, email.ID, found.ID)
+
+		require.NoError(t, repo.Verify(ctx, found))
+		saved := status()
+		assert.NotNil(t, saved.EmailVerifiedAt)
+		assert.Equal(t, models.UserStatusPending, *saved.Status)
+
+		// single use
+		assert.ErrorIs(t, repo.Verify(ctx, found), gorm.ErrRecordNotFound)
+
+		phone, err := repo.Latest(ctx, user.ID, models.VerificationChannelPhone)
+		require.NoError(t, err)
+		require.NoError(t, repo.Verify(ctx, phone))
+		saved = status()
+		assert.NotNil(t, saved.PhoneVerifiedAt)
+		assert.Equal(t, models.UserStatusActive, *saved.Status)
+	})
+}
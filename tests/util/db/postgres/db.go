@@ -2,6 +2,7 @@ package test_postgres
 
 import (
 	"sdt-bicycle-rental/internal/repository"
+	"sdt-bicycle-rental/internal/repository/migrations"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -14,8 +15,9 @@ func SetupTestDB(t *testing.T) (*gorm.DB, func()) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = repository.Migrate(db)
+	migrator, err := repository.NewMigrator(db, migrations.FS)
 	require.NoError(t, err)
+	require.NoError(t, migrator.Up())
 
 	// Cleanup fucntion
 	cleanup := func() {